@@ -30,12 +30,28 @@ func NewDOClient() (*DOClient, error) {
 		return nil, fmt.Errorf("DIGITALOCEAN_ACCESS_TOKEN environment variable is required")
 	}
 
+	return NewDOClientWithBaseURL(token, os.Getenv("DIGITALOCEAN_API_URL"))
+}
+
+// NewDOClientWithBaseURL creates a client that talks to baseURL instead of the
+// public DigitalOcean API, e.g. a local fake in tests. An empty baseURL uses
+// the default endpoint.
+func NewDOClientWithBaseURL(token, baseURL string) (*DOClient, error) {
 	tokenSource := &TokenSource{
 		AccessToken: token,
 	}
 
 	oauthClient := oauth2.NewClient(context.Background(), tokenSource)
-	client := godo.NewClient(oauthClient)
+
+	var opts []godo.ClientOpt
+	if baseURL != "" {
+		opts = append(opts, godo.SetBaseURL(baseURL))
+	}
+
+	client, err := godo.New(oauthClient, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DigitalOcean client: %w", err)
+	}
 
 	return &DOClient{
 		client: client,
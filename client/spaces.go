@@ -0,0 +1,630 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSpacesRegion = "nyc3"
+	// unsignedPayload is the payload hash of presigned URLs, whose body is not
+	// known when they are signed.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// MaxPresignExpiry is the longest a presigned URL can be valid for.
+	MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// SpacesClient is a minimal S3 client for DigitalOcean Spaces, or any other
+// S3-compatible store such as MinIO. Requests are signed with AWS Signature
+// Version 4 and use path-style URLs (endpoint/bucket/key).
+type SpacesClient struct {
+	endpoint   *url.URL
+	region     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+	// now is replaced in tests to sign with a fixed time.
+	now func() time.Time
+}
+
+// SpacesError is an error returned by the S3 API.
+type SpacesError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *SpacesError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Bucket is a Spaces bucket.
+type Bucket struct {
+	Name         string    `xml:"Name" json:"name"`
+	CreationDate time.Time `xml:"CreationDate" json:"creation_date"`
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string            `xml:"Key" json:"key"`
+	Size         int64             `xml:"Size" json:"size"`
+	ETag         string            `xml:"ETag" json:"etag"`
+	LastModified time.Time         `xml:"LastModified" json:"last_modified"`
+	StorageClass string            `xml:"StorageClass" json:"storage_class,omitempty"`
+	ContentType  string            `xml:"-" json:"content_type,omitempty"`
+	Metadata     map[string]string `xml:"-" json:"metadata,omitempty"`
+}
+
+// ListObjectsOptions filters ListObjects. Cursor is the NextCursor of a
+// previous page.
+type ListObjectsOptions struct {
+	Prefix    string
+	Delimiter string
+	Cursor    string
+	MaxKeys   int
+}
+
+// ListObjectsResult is one page of objects.
+type ListObjectsResult struct {
+	Objects        []ObjectInfo `json:"objects"`
+	CommonPrefixes []string     `json:"common_prefixes,omitempty"`
+	Truncated      bool         `json:"truncated"`
+	NextCursor     string       `json:"next_cursor,omitempty"`
+}
+
+// CORSRule is a bucket CORS rule.
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty" json:"id,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"allowed_origins"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"allowed_methods"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"allowed_headers,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"expose_headers,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"max_age_seconds,omitempty"`
+}
+
+// LifecycleRule expires objects under a prefix, or aborts multipart uploads
+// left incomplete, after a number of days.
+type LifecycleRule struct {
+	ID                                 string `json:"id,omitempty"`
+	Prefix                             string `json:"prefix"`
+	Enabled                            bool   `json:"enabled"`
+	ExpirationDays                     int    `json:"expiration_days,omitempty"`
+	AbortIncompleteMultipartUploadDays int    `json:"abort_incomplete_multipart_upload_days,omitempty"`
+}
+
+type lifecycleRuleXML struct {
+	ID         string `xml:"ID,omitempty"`
+	Prefix     string `xml:"Prefix"`
+	Status     string `xml:"Status"`
+	Expiration *struct {
+		Days int `xml:"Days"`
+	} `xml:"Expiration,omitempty"`
+	AbortIncompleteMultipartUpload *struct {
+		DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+	} `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// NewSpacesClientFromEnv reads SPACES_ACCESS_KEY_ID, SPACES_SECRET_ACCESS_KEY,
+// SPACES_REGION and SPACES_ENDPOINT. It returns nil without an error when no
+// keys are set, since Spaces is optional.
+func NewSpacesClientFromEnv() (*SpacesClient, error) {
+	accessKey := os.Getenv("SPACES_ACCESS_KEY_ID")
+	secretKey := os.Getenv("SPACES_SECRET_ACCESS_KEY")
+	if accessKey == "" && secretKey == "" {
+		return nil, nil
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("SPACES_ACCESS_KEY_ID and SPACES_SECRET_ACCESS_KEY must be set together")
+	}
+
+	return NewSpacesClient(os.Getenv("SPACES_ENDPOINT"), os.Getenv("SPACES_REGION"), accessKey, secretKey)
+}
+
+// NewSpacesClient creates a client for endpoint, e.g. a local MinIO in
+// tests. An empty endpoint uses https://<region>.digitaloceanspaces.com and
+// an empty region nyc3.
+func NewSpacesClient(endpoint, region, accessKey, secretKey string) (*SpacesClient, error) {
+	if region == "" {
+		region = defaultSpacesRegion
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.digitaloceanspaces.com", region)
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Spaces endpoint %q: expected a URL such as https://nyc3.digitaloceanspaces.com", endpoint)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	return &SpacesClient{
+		endpoint:   parsed,
+		region:     region,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		now:        time.Now,
+	}, nil
+}
+
+// Region returns the region requests are signed for.
+func (c *SpacesClient) Region() string {
+	return c.region
+}
+
+// AccessKey returns the access key ID requests are signed with.
+func (c *SpacesClient) AccessKey() string {
+	return c.accessKey
+}
+
+func (c *SpacesClient) ListBuckets(ctx context.Context) ([]Bucket, error) {
+	var result struct {
+		Buckets []Bucket `xml:"Buckets>Bucket"`
+	}
+	if _, err := c.doXML(ctx, http.MethodGet, "", "", nil, nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Buckets, nil
+}
+
+// CreateBucket creates a bucket with a canned ACL ("private" or
+// "public-read").
+func (c *SpacesClient) CreateBucket(ctx context.Context, bucket, acl string) error {
+	header := http.Header{}
+	if acl != "" {
+		header.Set("x-amz-acl", acl)
+	}
+	_, err := c.doXML(ctx, http.MethodPut, bucket, "", nil, header, nil, nil)
+	return err
+}
+
+func (c *SpacesClient) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := c.doXML(ctx, http.MethodDelete, bucket, "", nil, nil, nil, nil)
+	return err
+}
+
+func (c *SpacesClient) ListObjects(ctx context.Context, bucket string, opts ListObjectsOptions) (*ListObjectsResult, error) {
+	query := url.Values{"list-type": {"2"}}
+	if opts.Prefix != "" {
+		query.Set("prefix", opts.Prefix)
+	}
+	if opts.Delimiter != "" {
+		query.Set("delimiter", opts.Delimiter)
+	}
+	if opts.Cursor != "" {
+		query.Set("continuation-token", opts.Cursor)
+	}
+	if opts.MaxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(opts.MaxKeys))
+	}
+
+	var page struct {
+		Contents       []ObjectInfo `xml:"Contents"`
+		CommonPrefixes []string     `xml:"CommonPrefixes>Prefix"`
+		IsTruncated    bool         `xml:"IsTruncated"`
+		NextToken      string       `xml:"NextContinuationToken"`
+	}
+	if _, err := c.doXML(ctx, http.MethodGet, bucket, "", query, nil, nil, &page); err != nil {
+		return nil, err
+	}
+
+	result := &ListObjectsResult{
+		Objects:        []ObjectInfo{},
+		CommonPrefixes: page.CommonPrefixes,
+		Truncated:      page.IsTruncated,
+		NextCursor:     page.NextToken,
+	}
+	for _, object := range page.Contents {
+		object.ETag = strings.Trim(object.ETag, `"`)
+		result.Objects = append(result.Objects, object)
+	}
+	return result, nil
+}
+
+func (c *SpacesClient) HeadObject(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	response, err := c.do(ctx, http.MethodHead, bucket, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	return objectInfo(key, response), nil
+}
+
+// GetObject downloads at most maxBytes of an object. The returned info has
+// the object's full size, so a short body means it was truncated.
+func (c *SpacesClient) GetObject(ctx context.Context, bucket, key string, maxBytes int64) (*ObjectInfo, []byte, error) {
+	header := http.Header{}
+	if maxBytes > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=0-%d", maxBytes-1))
+	}
+	response, err := c.do(ctx, http.MethodGet, bucket, key, nil, header, nil)
+	if err != nil {
+		var spacesErr *SpacesError
+		// An empty object has no byte 0 to start a range at
+		if maxBytes > 0 && errors.As(err, &spacesErr) && spacesErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return c.GetObject(ctx, bucket, key, 0)
+		}
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := objectInfo(key, response)
+	// A ranged response's Content-Length is the range; the total follows
+	// the slash in Content-Range
+	if contentRange := response.Header.Get("Content-Range"); contentRange != "" {
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if total, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				info.Size = total
+			}
+		}
+	}
+	return info, body, nil
+}
+
+// PutObject uploads body with a content type and canned ACL and returns the
+// stored object's ETag.
+func (c *SpacesClient) PutObject(ctx context.Context, bucket, key string, body []byte, contentType, acl string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	if acl != "" {
+		header.Set("x-amz-acl", acl)
+	}
+	response, err := c.do(ctx, http.MethodPut, bucket, key, nil, header, body)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	return strings.Trim(response.Header.Get("ETag"), `"`), nil
+}
+
+func (c *SpacesClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.doXML(ctx, http.MethodDelete, bucket, key, nil, nil, nil, nil)
+	return err
+}
+
+// PresignURL returns a URL that allows method (GET or PUT) on an object
+// without credentials until it expires.
+func (c *SpacesClient) PresignURL(method, bucket, key string, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", fmt.Errorf("expiry must be between 1 second and %s", MaxPresignExpiry)
+	}
+	request, err := http.NewRequest(method, c.objectURL(bucket, key, nil), nil)
+	if err != nil {
+		return "", err
+	}
+	c.presign(request, expires, c.now().UTC())
+	return request.URL.String(), nil
+}
+
+// GetBucketCORS returns a bucket's CORS rules, or none when it has no CORS
+// configuration.
+func (c *SpacesClient) GetBucketCORS(ctx context.Context, bucket string) ([]CORSRule, error) {
+	var config struct {
+		Rules []CORSRule `xml:"CORSRule"`
+	}
+	_, err := c.doXML(ctx, http.MethodGet, bucket, "", url.Values{"cors": {""}}, nil, nil, &config)
+	if isSpacesCode(err, "NoSuchCORSConfiguration") {
+		return []CORSRule{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config.Rules, nil
+}
+
+// PutBucketCORS replaces a bucket's CORS rules; no rules removes the
+// configuration.
+func (c *SpacesClient) PutBucketCORS(ctx context.Context, bucket string, rules []CORSRule) error {
+	query := url.Values{"cors": {""}}
+	if len(rules) == 0 {
+		_, err := c.doXML(ctx, http.MethodDelete, bucket, "", query, nil, nil, nil)
+		return err
+	}
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name   `xml:"CORSConfiguration"`
+		Rules   []CORSRule `xml:"CORSRule"`
+	}{Rules: rules})
+	if err != nil {
+		return err
+	}
+	_, err = c.doXML(ctx, http.MethodPut, bucket, "", query, contentMD5(body), body, nil)
+	return err
+}
+
+// GetBucketLifecycle returns a bucket's lifecycle rules, or none when it has
+// no lifecycle configuration.
+func (c *SpacesClient) GetBucketLifecycle(ctx context.Context, bucket string) ([]LifecycleRule, error) {
+	var config struct {
+		Rules []lifecycleRuleXML `xml:"Rule"`
+	}
+	_, err := c.doXML(ctx, http.MethodGet, bucket, "", url.Values{"lifecycle": {""}}, nil, nil, &config)
+	if isSpacesCode(err, "NoSuchLifecycleConfiguration") {
+		return []LifecycleRule{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []LifecycleRule{}
+	for _, rule := range config.Rules {
+		converted := LifecycleRule{ID: rule.ID, Prefix: rule.Prefix, Enabled: rule.Status == "Enabled"}
+		if rule.Expiration != nil {
+			converted.ExpirationDays = rule.Expiration.Days
+		}
+		if rule.AbortIncompleteMultipartUpload != nil {
+			converted.AbortIncompleteMultipartUploadDays = rule.AbortIncompleteMultipartUpload.DaysAfterInitiation
+		}
+		rules = append(rules, converted)
+	}
+	return rules, nil
+}
+
+// PutBucketLifecycle replaces a bucket's lifecycle rules; no rules removes
+// the configuration.
+func (c *SpacesClient) PutBucketLifecycle(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	query := url.Values{"lifecycle": {""}}
+	if len(rules) == 0 {
+		_, err := c.doXML(ctx, http.MethodDelete, bucket, "", query, nil, nil, nil)
+		return err
+	}
+
+	config := struct {
+		XMLName xml.Name           `xml:"LifecycleConfiguration"`
+		Rules   []lifecycleRuleXML `xml:"Rule"`
+	}{}
+	for _, rule := range rules {
+		converted := lifecycleRuleXML{ID: rule.ID, Prefix: rule.Prefix, Status: "Disabled"}
+		if rule.Enabled {
+			converted.Status = "Enabled"
+		}
+		if rule.ExpirationDays > 0 {
+			converted.Expiration = &struct {
+				Days int `xml:"Days"`
+			}{Days: rule.ExpirationDays}
+		}
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			converted.AbortIncompleteMultipartUpload = &struct {
+				DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+			}{DaysAfterInitiation: rule.AbortIncompleteMultipartUploadDays}
+		}
+		config.Rules = append(config.Rules, converted)
+	}
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	_, err = c.doXML(ctx, http.MethodPut, bucket, "", query, contentMD5(body), body, nil)
+	return err
+}
+
+// IsSpacesNotFound reports whether err is a 404 from the S3 API.
+func IsSpacesNotFound(err error) bool {
+	var spacesErr *SpacesError
+	return errors.As(err, &spacesErr) && spacesErr.StatusCode == http.StatusNotFound
+}
+
+// doXML sends a request and decodes an XML response body into v, if given.
+func (c *SpacesClient) doXML(ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body []byte, v interface{}) (*http.Response, error) {
+	response, err := c.do(ctx, method, bucket, key, query, header, body)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if v != nil {
+		if err := xml.NewDecoder(response.Body).Decode(v); err != nil {
+			return nil, fmt.Errorf("decoding %s response: %w", method, err)
+		}
+	}
+	return response, nil
+}
+
+// do sends a signed request. Responses other than 2xx are returned as a
+// *SpacesError.
+func (c *SpacesClient) do(ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, c.objectURL(bucket, key, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	c.sign(request, body, c.now().UTC())
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+
+	spacesErr := &SpacesError{StatusCode: response.StatusCode}
+	// HEAD responses have no body, so the status is all there is
+	if data, _ := io.ReadAll(response.Body); len(data) > 0 {
+		xml.Unmarshal(data, spacesErr)
+	}
+	if spacesErr.Code == "" {
+		spacesErr.Code = http.StatusText(response.StatusCode)
+	}
+	return nil, spacesErr
+}
+
+func (c *SpacesClient) objectURL(bucket, key string, query url.Values) string {
+	u := *c.endpoint
+	u.Path = c.endpoint.Path + "/"
+	if bucket != "" {
+		u.Path += bucket
+		if key != "" {
+			u.Path += "/" + key
+		}
+	}
+	// Encode the path strictly, as S3 does when it checks the signature
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	u.RawPath = strings.Join(segments, "/")
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	return u.String()
+}
+
+// sign adds an AWS Signature Version 4 Authorization header covering the
+// host, the body hash and the headers already set on request.
+func (c *SpacesClient) sign(request *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders, canonicalHeaders := canonicalHeaders(request)
+	scope := c.scope(now)
+	signature := c.signature(now, scope, canonicalRequest(request, canonicalHeaders, signedHeaders, payloadHash))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+// presign adds the query parameters of a presigned URL to request.
+func (c *SpacesClient) presign(request *http.Request, expires time.Duration, now time.Time) {
+	scope := c.scope(now)
+	query := request.URL.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", c.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	request.URL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonical := canonicalRequest(request, "host:"+request.URL.Host+"\n", "host", unsignedPayload)
+	request.URL.RawQuery += "&X-Amz-Signature=" + c.signature(now, scope, canonical)
+}
+
+func (c *SpacesClient) scope(now time.Time) string {
+	return fmt.Sprintf("%s/%s/s3/aws4_request", now.Format("20060102"), c.region)
+}
+
+func (c *SpacesClient) signature(now time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), now.Format("20060102"))
+	for _, part := range []string{c.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalHeaders returns the signed header names and the canonical header
+// block: host plus every header set on the request.
+func canonicalHeaders(request *http.Request) (string, string) {
+	values := map[string]string{"host": request.URL.Host}
+	for name, headerValues := range request.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(headerValues, ","))
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + values[name] + "\n")
+	}
+	return strings.Join(names, ";"), block.String()
+}
+
+func canonicalRequest(request *http.Request, canonicalHeaders, signedHeaders, payloadHash string) string {
+	return strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalQuery sorts and strictly percent-encodes query parameters.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+func uriEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func objectInfo(key string, response *http.Response) *ObjectInfo {
+	info := &ObjectInfo{
+		Key:         key,
+		ETag:        strings.Trim(response.Header.Get("ETag"), `"`),
+		ContentType: response.Header.Get("Content-Type"),
+	}
+	info.Size, _ = strconv.ParseInt(response.Header.Get("Content-Length"), 10, 64)
+	info.LastModified, _ = http.ParseTime(response.Header.Get("Last-Modified"))
+	for name, values := range response.Header {
+		if metaName, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+			if info.Metadata == nil {
+				info.Metadata = map[string]string{}
+			}
+			info.Metadata[metaName] = strings.Join(values, ",")
+		}
+	}
+	return info
+}
+
+func contentMD5(body []byte) http.Header {
+	sum := md5.Sum(body)
+	return http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(sum[:])}}
+}
+
+func isSpacesCode(err error, code string) bool {
+	var spacesErr *SpacesError
+	return errors.As(err, &spacesErr) && spacesErr.Code == code
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func (h *Handler) GetAccount() (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	account, _, err := client.Account.Get(context.Background())
+	if err != nil {
+		return h.HandleError(err, "get_account")
+	}
+
+	return h.HandleSuccess(account, "get_account")
+}
+
+// GetBalance returns the account balance and month-to-date usage.
+func (h *Handler) GetBalance() (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	balance, _, err := client.Balance.Get(context.Background())
+	if err != nil {
+		return h.HandleError(err, "get_balance")
+	}
+
+	return h.HandleSuccess(balance, "get_balance")
+}
+
+func (h *Handler) ListBillingHistory(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	entries, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.BillingHistoryEntry, *godo.Response, error) {
+		history, resp, err := client.BillingHistory.List(context.Background(), opt)
+		if err != nil {
+			return nil, resp, err
+		}
+		return history.BillingHistory, resp, nil
+	})
+	if err != nil {
+		return h.HandleError(err, "list_billing_history")
+	}
+
+	return h.HandleSuccess(listResult("billing_history", entries, meta), "list_billing_history")
+}
+
+// ListInvoices lists issued invoices together with the invoice preview, which
+// holds the month-to-date usage that has not been invoiced yet.
+func (h *Handler) ListInvoices(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	var preview godo.InvoiceListItem
+	invoices, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.InvoiceListItem, *godo.Response, error) {
+		list, resp, err := client.Invoices.List(context.Background(), opt)
+		if err != nil {
+			return nil, resp, err
+		}
+		preview = list.InvoicePreview
+		return list.Invoices, resp, nil
+	})
+	if err != nil {
+		return h.HandleError(err, "list_invoices")
+	}
+
+	result := listResult("invoices", invoices, meta)
+	result["invoice_preview"] = preview
+	return h.HandleSuccess(result, "list_invoices")
+}
+
+// GetInvoice lists the line items of an invoice.
+func (h *Handler) GetInvoice(invoiceUUID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	items, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.InvoiceItem, *godo.Response, error) {
+		invoice, resp, err := client.Invoices.Get(context.Background(), invoiceUUID, opt)
+		if err != nil {
+			return nil, resp, err
+		}
+		return invoice.InvoiceItems, resp, nil
+	})
+	if err != nil {
+		return h.HandleError(err, "get_invoice")
+	}
+
+	result := listResult("invoice_items", items, meta)
+	result["invoice_uuid"] = invoiceUUID
+	return h.HandleSuccess(result, "get_invoice")
+}
+
+// GetInvoiceSummary returns an invoice's totals broken down by product,
+// overages, taxes and credits.
+func (h *Handler) GetInvoiceSummary(invoiceUUID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	summary, _, err := client.Invoices.GetSummary(context.Background(), invoiceUUID)
+	if err != nil {
+		return h.HandleError(err, "get_invoice_summary")
+	}
+
+	return h.HandleSuccess(summary, "get_invoice_summary")
+}
+
+// ExportInvoiceCSV returns the invoice's line items as the CSV file the API
+// generates, ready to be saved as is.
+func (h *Handler) ExportInvoiceCSV(invoiceUUID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	data, _, err := client.Invoices.GetCSV(context.Background(), invoiceUUID)
+	if err != nil {
+		return h.HandleError(err, "export_invoice_csv")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"invoice_uuid": invoiceUUID,
+		"filename":     fmt.Sprintf("invoice-%s.csv", invoiceUUID),
+		"csv":          string(data),
+	}, "export_invoice_csv")
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func TestGetAccountAndBalance(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.Balance.MonthToDateUsage = "42.50"
+	fake.Balance.AccountBalance = "-10.00"
+
+	var account godo.Account
+	resp, err := h.GetAccount()
+	decodeResponse(t, resp, err, &account)
+	if account.Email != "test@example.com" || account.DropletLimit != 25 {
+		t.Errorf("account = %+v", account)
+	}
+
+	var balance godo.Balance
+	resp, err = h.GetBalance()
+	decodeResponse(t, resp, err, &balance)
+	if balance.MonthToDateUsage != "42.50" || balance.AccountBalance != "-10.00" {
+		t.Errorf("balance = %+v", balance)
+	}
+
+	fake.FailNext(http.MethodGet, "/v2/customers/my/balance", http.StatusForbidden, "billing access required")
+	resp, err = h.GetBalance()
+	expectError(t, resp, err, "get_balance", "403", "billing access required")
+}
+
+func TestListBillingHistory(t *testing.T) {
+	h, fake := newTestHandler(t)
+	for i, entry := range []godo.BillingHistoryEntry{
+		{Description: "Invoice for March 2024", Amount: "12.34", Type: "Invoice"},
+		{Description: "Payment (MC 1234)", Amount: "-12.34", Type: "Payment"},
+		{Description: "Invoice for April 2024", Amount: "20.00", Type: "Invoice"},
+	} {
+		fake.BillingHistory.Put(i, entry)
+	}
+
+	var entries []godo.BillingHistoryEntry
+	resp, err := h.ListBillingHistory(0, 0)
+	decodeList(t, resp, err, "billing_history", &entries)
+	if len(entries) != 3 || entries[1].Type != "Payment" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	resp, err = h.ListBillingHistory(2, 2)
+	meta := decodeList(t, resp, err, "billing_history", &entries)
+	if len(entries) != 1 || entries[0].Description != "Invoice for April 2024" || meta.Total != 3 {
+		t.Errorf("page 2 = %+v, meta = %+v", entries, meta)
+	}
+}
+
+func TestInvoices(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.InvoicePreview.Amount = "7.25"
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	id := fake.AddInvoice("2024-03",
+		godo.InvoiceItem{Product: "Droplets", Description: "web-1", Amount: "12.00", Duration: "744", StartTime: start, EndTime: end},
+		godo.InvoiceItem{Product: "Droplets", Description: "web-2", Amount: "6.00", Duration: "744", StartTime: start, EndTime: end},
+		godo.InvoiceItem{Product: "Volumes", Description: "data, 100GB", Amount: "10.00", Duration: "744", StartTime: start, EndTime: end},
+	)
+
+	var invoices []godo.InvoiceListItem
+	var list struct {
+		Preview godo.InvoiceListItem `json:"invoice_preview"`
+	}
+	resp, err := h.ListInvoices(0, 0)
+	decodeList(t, resp, err, "invoices", &invoices)
+	decodeResponse(t, resp, err, &list)
+	if len(invoices) != 1 || invoices[0].InvoiceUUID != id || invoices[0].Amount != "28.00" {
+		t.Errorf("invoices = %+v", invoices)
+	}
+	if list.Preview.Amount != "7.25" {
+		t.Errorf("invoice preview = %+v", list.Preview)
+	}
+
+	var items []godo.InvoiceItem
+	resp, err = h.GetInvoice(id, 0, 0)
+	decodeList(t, resp, err, "invoice_items", &items)
+	if len(items) != 3 || items[2].Product != "Volumes" {
+		t.Errorf("items = %+v", items)
+	}
+
+	var summary godo.InvoiceSummary
+	resp, err = h.GetInvoiceSummary(id)
+	decodeResponse(t, resp, err, &summary)
+	charges := summary.ProductCharges.Items
+	if summary.Amount != "28.00" || len(charges) != 2 || charges[0].Name != "Droplets" || charges[0].Amount != "18.00" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	var export map[string]string
+	resp, err = h.ExportInvoiceCSV(id)
+	decodeResponse(t, resp, err, &export)
+	rows, err := csv.NewReader(strings.NewReader(export["csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing exported CSV: %v", err)
+	}
+	if export["filename"] != "invoice-"+id+".csv" || len(rows) != 4 || rows[0][0] != "product" || rows[3][2] != "data, 100GB" {
+		t.Errorf("export = %+v", export)
+	}
+
+	tests := []struct {
+		op   string
+		call func() (*mcp_golang.ToolResponse, error)
+	}{
+		{op: "get_invoice", call: func() (*mcp_golang.ToolResponse, error) { return h.GetInvoice("missing", 0, 0) }},
+		{op: "get_invoice_summary", call: func() (*mcp_golang.ToolResponse, error) { return h.GetInvoiceSummary("missing") }},
+		{op: "export_invoice_csv", call: func() (*mcp_golang.ToolResponse, error) { return h.ExportInvoiceCSV("missing") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			resp, err := tt.call()
+			expectError(t, resp, err, tt.op, "404")
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+const (
+	defaultPollInterval    = 2 * time.Second
+	defaultMaxPollInterval = 15 * time.Second
+)
+
+func (h *Handler) GetAction(actionID int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	action, _, err := client.Actions.Get(context.Background(), actionID)
+	if err != nil {
+		return h.HandleError(err, "get_action")
+	}
+
+	return h.HandleSuccess(action, "get_action")
+}
+
+// ListActions lists the account's actions, or only those of a droplet when
+// dropletID is set.
+func (h *Handler) ListActions(dropletID, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	actions, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Action, *godo.Response, error) {
+		if dropletID > 0 {
+			return client.Droplets.Actions(context.Background(), dropletID, opt)
+		}
+		return client.Actions.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_actions")
+	}
+
+	return h.HandleSuccess(listResult("actions", actions, meta), "list_actions")
+}
+
+func (h *Handler) WaitForAction(actionID int, timeout time.Duration) (*mcp_golang.ToolResponse, error) {
+	action, err := h.waitForAction(actionID, timeout)
+	if err != nil {
+		return h.HandleError(err, "wait_for_action")
+	}
+
+	return h.HandleSuccess(action, "wait_for_action")
+}
+
+// actionResult returns the action a tool started. With a non-zero wait it
+// first blocks until the action finishes, so the caller sees the final state.
+func (h *Handler) actionResult(action *godo.Action, wait time.Duration, operation string) (*mcp_golang.ToolResponse, error) {
+	if wait > 0 && action != nil {
+		done, err := h.waitForAction(action.ID, wait)
+		if err != nil {
+			return h.HandleError(err, operation)
+		}
+		action = done
+	}
+
+	return h.HandleSuccess(action, operation)
+}
+
+// actionsResult is actionResult for tools that start several actions at once,
+// such as the by-tag droplet actions. All actions share one wait deadline.
+func (h *Handler) actionsResult(actions []godo.Action, wait time.Duration, operation string) (*mcp_golang.ToolResponse, error) {
+	if actions == nil {
+		actions = []godo.Action{}
+	}
+	if wait > 0 {
+		deadline := time.Now().Add(wait)
+		for i := range actions {
+			done, err := h.waitForAction(actions[i].ID, time.Until(deadline))
+			if err != nil {
+				return h.HandleError(err, operation)
+			}
+			actions[i] = *done
+		}
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"actions": actions}, operation)
+}
+
+// waitForAction polls an action with exponential backoff until it completes,
+// errors or timeout elapses. An errored action is reported as an error.
+func (h *Handler) waitForAction(actionID int, timeout time.Duration) (*godo.Action, error) {
+	client := h.doClient.GetClient()
+
+	deadline := time.Now().Add(timeout)
+	interval := h.pollInterval
+	for {
+		action, _, err := client.Actions.Get(context.Background(), actionID)
+		if err != nil {
+			return nil, err
+		}
+		switch action.Status {
+		case godo.ActionCompleted:
+			return action, nil
+		case "errored":
+			return nil, fmt.Errorf("action %d (%s) errored", action.ID, action.Type)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, fmt.Errorf("timed out after %s waiting for action %d (%s), last status %q; call wait_for_action to keep waiting", timeout, action.ID, action.Type, action.Status)
+		}
+		time.Sleep(min(interval, remaining))
+		interval = min(interval*2, h.maxPollInterval)
+	}
+}
+
+// waitForCreate waits for the create actions linked from a droplet create
+// response and returns the droplets' current state.
+func (h *Handler) waitForCreate(droplets []godo.Droplet, response *godo.Response, timeout time.Duration) ([]godo.Droplet, error) {
+	client := h.doClient.GetClient()
+
+	var actionIDs []int
+	if response != nil && response.Links != nil {
+		for _, action := range response.Links.Actions {
+			if action.Rel == "create" || action.Rel == "multiple_create" {
+				actionIDs = append(actionIDs, action.ID)
+			}
+		}
+	}
+	if len(actionIDs) == 0 {
+		return nil, fmt.Errorf("droplets were created but their create actions are unknown; poll get_droplet until they are active")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, actionID := range actionIDs {
+		if _, err := h.waitForAction(actionID, time.Until(deadline)); err != nil {
+			return nil, err
+		}
+	}
+
+	current := make([]godo.Droplet, 0, len(droplets))
+	for _, droplet := range droplets {
+		latest, _, err := client.Droplets.Get(context.Background(), droplet.ID)
+		if err != nil {
+			return nil, err
+		}
+		current = append(current, *latest)
+	}
+	return current, nil
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestGetAndListActions(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 2)
+	for _, id := range ids {
+		if _, err := h.RebootDroplet(id, 0); err != nil {
+			t.Fatalf("RebootDroplet: %v", err)
+		}
+	}
+	if _, err := h.PowerOffDroplet(ids[1], 0); err != nil {
+		t.Fatalf("PowerOffDroplet: %v", err)
+	}
+
+	var actions []godo.Action
+	resp, err := h.ListActions(0, 0, 0)
+	decodeList(t, resp, err, "actions", &actions)
+	if len(actions) != 3 {
+		t.Fatalf("listed %d actions, want 3", len(actions))
+	}
+
+	resp, err = h.ListActions(ids[1], 0, 0)
+	decodeList(t, resp, err, "actions", &actions)
+	if len(actions) != 2 || actions[1].Type != "power_off" {
+		t.Errorf("droplet actions = %+v", actions)
+	}
+
+	var action godo.Action
+	resp, err = h.GetAction(actions[0].ID)
+	decodeResponse(t, resp, err, &action)
+	if action.Type != "reboot" || action.ResourceID != ids[1] {
+		t.Errorf("action = %+v", action)
+	}
+
+	resp, err = h.GetAction(1)
+	expectError(t, resp, err, "get_action", "404")
+}
+
+func TestWaitForAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		polls   int
+		errored bool
+		timeout time.Duration
+		wantErr string
+	}{
+		{name: "already completed", timeout: time.Second},
+		{name: "completes after polling", polls: 3, timeout: time.Second},
+		{name: "errored", polls: 3, errored: true, timeout: time.Second, wantErr: "(reboot) errored"},
+		{name: "times out", polls: 1000, timeout: 20 * time.Millisecond, wantErr: `last status "in-progress"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := seedDroplets(fake, 1)[0]
+			fake.ActionPolls = tt.polls
+
+			var started godo.Action
+			resp, err := h.RebootDroplet(id, 0)
+			decodeResponse(t, resp, err, &started)
+			if tt.errored {
+				fake.Actions.Update(started.ID, func(a *godo.Action) { a.Status = "errored" })
+			}
+
+			resp, err = h.WaitForAction(started.ID, tt.timeout)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "wait_for_action", tt.wantErr)
+				return
+			}
+			var action godo.Action
+			decodeResponse(t, resp, err, &action)
+			if action.Status != godo.ActionCompleted || action.ID != started.ID {
+				t.Errorf("action = %+v", action)
+			}
+		})
+	}
+}
+
+func TestActionToolsWait(t *testing.T) {
+	t.Run("action tool", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		id := seedDroplets(fake, 1)[0]
+		fake.ActionPolls = 2
+
+		var action godo.Action
+		resp, err := h.PowerCycleDroplet(id, 0)
+		decodeResponse(t, resp, err, &action)
+		if action.Status != godo.ActionInProgress {
+			t.Errorf("without wait the action should still be in progress, got %q", action.Status)
+		}
+
+		resp, err = h.PowerCycleDroplet(id, time.Second)
+		decodeResponse(t, resp, err, &action)
+		if action.Status != godo.ActionCompleted {
+			t.Errorf("with wait the action should be completed, got %q", action.Status)
+		}
+	})
+
+	t.Run("create droplet", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		seedImages(fake)
+		fake.ActionPolls = 2
+
+		var droplet godo.Droplet
+		resp, err := h.CreateDroplet(CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-24-04-x64"}, 0)
+		decodeResponse(t, resp, err, &droplet)
+		if droplet.Status != "new" {
+			t.Errorf("without wait status = %q, want new", droplet.Status)
+		}
+
+		resp, err = h.CreateDroplet(CreateDropletOptions{Name: "api", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-24-04-x64"}, time.Second)
+		decodeResponse(t, resp, err, &droplet)
+		if droplet.Name != "api" || droplet.Status != "active" {
+			t.Errorf("with wait droplet = %s/%s, want api/active", droplet.Name, droplet.Status)
+		}
+	})
+
+	t.Run("timeout reports the action", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		id := seedDroplets(fake, 1)[0]
+		fake.ActionPolls = 1000
+
+		resp, err := h.ShutdownDroplet(id, 10*time.Millisecond)
+		expectError(t, resp, err, "shutdown_droplet", "call wait_for_action to keep waiting")
+	})
+}
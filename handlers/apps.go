@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+	"gopkg.in/yaml.v3"
+)
+
+// appLogTypes maps the log_type argument to the API's log types.
+var appLogTypes = map[string]godo.AppLogType{
+	"build":         godo.AppLogTypeBuild,
+	"deploy":        godo.AppLogTypeDeploy,
+	"run":           godo.AppLogTypeRun,
+	"run_restarted": godo.AppLogTypeRunRestarted,
+}
+
+func (h *Handler) ListApps(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	apps, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.App, *godo.Response, error) {
+		return client.Apps.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_apps")
+	}
+
+	return h.HandleSuccess(listResult("apps", apps, meta), "list_apps")
+}
+
+func (h *Handler) GetApp(appID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	app, _, err := client.Apps.Get(context.Background(), appID)
+	if err != nil {
+		return h.HandleError(err, "get_app")
+	}
+
+	return h.HandleSuccess(app, "get_app")
+}
+
+// CreateApp creates an app from a YAML or JSON app spec and starts its first
+// deployment.
+func (h *Handler) CreateApp(spec, projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	appSpec, err := parseAppSpec(spec)
+	if err != nil {
+		return h.HandleError(err, "create_app")
+	}
+
+	app, _, err := client.Apps.Create(context.Background(), &godo.AppCreateRequest{Spec: appSpec, ProjectID: projectID})
+	if err != nil {
+		return h.HandleError(err, "create_app")
+	}
+
+	return h.HandleSuccess(app, "create_app")
+}
+
+// UpdateApp replaces the app's spec, which triggers a new deployment. Source
+// versions of unchanged components are only refreshed when
+// updateAllSourceVersions is set.
+func (h *Handler) UpdateApp(appID, spec string, updateAllSourceVersions bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	appSpec, err := parseAppSpec(spec)
+	if err != nil {
+		return h.HandleError(err, "update_app")
+	}
+
+	app, _, err := client.Apps.Update(context.Background(), appID, &godo.AppUpdateRequest{
+		Spec:                    appSpec,
+		UpdateAllSourceVersions: updateAllSourceVersions,
+	})
+	if err != nil {
+		return h.HandleError(err, "update_app")
+	}
+
+	return h.HandleSuccess(app, "update_app")
+}
+
+func (h *Handler) DeleteApp(appID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Apps.Delete(context.Background(), appID)
+	if err != nil {
+		return h.HandleError(err, "delete_app")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("App %s deleted successfully", appID),
+	}, "delete_app")
+}
+
+// PreviewDeleteApp lists the app's components and domains. Dev databases live
+// inside the app and go with it; managed clusters it connects to are kept.
+func (h *Handler) PreviewDeleteApp(appID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	app, _, err := client.Apps.Get(context.Background(), appID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "app",
+		ID:           app.ID,
+	}
+	if app.Region != nil {
+		preview.Region = app.Region.Slug
+	}
+	if app.Spec == nil {
+		return preview, nil
+	}
+	preview.Name = app.Spec.Name
+
+	addComponent := func(componentType, name string) {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": componentType,
+			"name": name,
+		})
+	}
+	for _, service := range app.Spec.Services {
+		addComponent("service", service.Name)
+	}
+	for _, site := range app.Spec.StaticSites {
+		addComponent("static_site", site.Name)
+	}
+	for _, worker := range app.Spec.Workers {
+		addComponent("worker", worker.Name)
+	}
+	for _, job := range app.Spec.Jobs {
+		addComponent("job", job.Name)
+	}
+	for _, function := range app.Spec.Functions {
+		addComponent("function", function.Name)
+	}
+	for _, database := range app.Spec.Databases {
+		addComponent("database", database.Name)
+		if database.ClusterName == "" {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Dev database %s and its data are deleted with the app", database.Name))
+		}
+	}
+	for _, domain := range app.Spec.Domains {
+		addComponent("domain", domain.Domain)
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("Domain %s stops serving the app", domain.Domain))
+	}
+	if app.LiveURL != "" {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The live URL %s stops serving the app", app.LiveURL))
+	}
+
+	return preview, nil
+}
+
+// ListAppDeployments lists the app's deployments, newest first.
+func (h *Handler) ListAppDeployments(appID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	deployments, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.Deployment, *godo.Response, error) {
+		return client.Apps.ListDeployments(context.Background(), appID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_app_deployments")
+	}
+
+	return h.HandleSuccess(listResult("deployments", deployments, meta), "list_app_deployments")
+}
+
+func (h *Handler) GetAppDeployment(appID, deploymentID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	deployment, _, err := client.Apps.GetDeployment(context.Background(), appID, deploymentID)
+	if err != nil {
+		return h.HandleError(err, "get_app_deployment")
+	}
+
+	return h.HandleSuccess(deployment, "get_app_deployment")
+}
+
+// CreateAppDeployment redeploys the app's current spec. With forceBuild the
+// components are rebuilt even when their source has not changed.
+func (h *Handler) CreateAppDeployment(appID string, forceBuild bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	deployment, _, err := client.Apps.CreateDeployment(context.Background(), appID, &godo.DeploymentCreateRequest{ForceBuild: forceBuild})
+	if err != nil {
+		return h.HandleError(err, "create_app_deployment")
+	}
+
+	return h.HandleSuccess(deployment, "create_app_deployment")
+}
+
+// CancelAppDeployment stops a deployment that is still building or deploying.
+// godo has no call for it, so the request is built directly.
+func (h *Handler) CancelAppDeployment(appID, deploymentID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, fmt.Sprintf("/v2/apps/%s/deployments/%s/cancel", appID, deploymentID), nil)
+	if err != nil {
+		return h.HandleError(err, "cancel_app_deployment")
+	}
+	var root struct {
+		Deployment *godo.Deployment `json:"deployment"`
+	}
+	if _, err := client.Do(context.Background(), req, &root); err != nil {
+		return h.HandleError(err, "cancel_app_deployment")
+	}
+
+	return h.HandleSuccess(root.Deployment, "cancel_app_deployment")
+}
+
+// GetAppLogs returns URLs to fetch logs from rather than the logs themselves.
+// live asks for a URL that streams new lines; otherwise the URLs of the
+// stored logs are returned. Without a deployment the active one is used.
+func (h *Handler) GetAppLogs(appID, deploymentID, component, logType string, live bool, tailLines int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	apiLogType, ok := appLogTypes[strings.ToLower(logType)]
+	if !ok {
+		return h.HandleError(fmt.Errorf("unsupported log type %q (expected build, deploy, run or run_restarted)", logType), "get_app_logs")
+	}
+	if apiLogType == godo.AppLogTypeBuild && deploymentID == "" {
+		// Build logs belong to a deployment; default to the latest one
+		deployments, _, err := client.Apps.ListDeployments(context.Background(), appID, &godo.ListOptions{PerPage: 1})
+		if err != nil {
+			return h.HandleError(err, "get_app_logs")
+		}
+		if len(deployments) == 0 {
+			return h.HandleError(fmt.Errorf("app %s has no deployments", appID), "get_app_logs")
+		}
+		deploymentID = deployments[0].ID
+	}
+	if tailLines <= 0 {
+		// -1 asks for every stored line
+		tailLines = -1
+	}
+
+	logs, _, err := client.Apps.GetLogs(context.Background(), appID, deploymentID, component, apiLogType, live, tailLines)
+	if err != nil {
+		return h.HandleError(err, "get_app_logs")
+	}
+
+	return h.HandleSuccess(logs, "get_app_logs")
+}
+
+func (h *Handler) ListAppAlerts(appID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	alerts, _, err := client.Apps.ListAlerts(context.Background(), appID)
+	if err != nil {
+		return h.HandleError(err, "list_app_alerts")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"alerts": alerts}, "list_app_alerts")
+}
+
+// parseAppSpec reads an app spec written as JSON or YAML. YAML is converted
+// to JSON first so both go through the spec's JSON field names, and unknown
+// fields are rejected instead of silently dropped.
+func parseAppSpec(spec string) (*godo.AppSpec, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("spec is required")
+	}
+
+	data := []byte(spec)
+	if !strings.HasPrefix(spec, "{") {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("spec is neither valid JSON nor YAML: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+		data = converted
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var appSpec godo.AppSpec
+	if err := decoder.Decode(&appSpec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if appSpec.Name == "" {
+		return nil, fmt.Errorf("invalid spec: name is required")
+	}
+
+	return &appSpec, nil
+}
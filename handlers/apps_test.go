@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"digitalocean-mcp-server/internal/fakedo"
+
+	"github.com/digitalocean/godo"
+)
+
+const yamlAppSpec = `
+name: storefront
+region: ams
+services:
+  - name: api
+    github:
+      repo: acme/storefront
+      branch: main
+      deploy_on_push: true
+    http_port: 8080
+    instance_count: 2
+    instance_size_slug: apps-s-1vcpu-1gb
+    envs:
+      - key: LOG_LEVEL
+        value: "debug"
+    alerts:
+      - rule: CPU_UTILIZATION
+        operator: GREATER_THAN
+        value: 80
+        window: FIVE_MINUTES
+static_sites:
+  - name: web
+    github:
+      repo: acme/storefront-web
+      branch: main
+databases:
+  - name: db
+    engine: PG
+domains:
+  - domain: shop.example.com
+    type: PRIMARY
+alerts:
+  - rule: DEPLOYMENT_FAILED
+`
+
+const jsonAppSpec = `{
+  "name": "storefront",
+  "services": [{"name": "api", "image": {"registry_type": "DOCR", "repository": "api", "tag": "v1"}}]
+}`
+
+func TestParseAppSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		wantName  string
+		wantParts int
+		wantErr   string
+	}{
+		{name: "YAML", spec: yamlAppSpec, wantName: "storefront", wantParts: 2},
+		{name: "JSON", spec: jsonAppSpec, wantName: "storefront", wantParts: 1},
+		{name: "empty", spec: "  \n", wantErr: "spec is required"},
+		{name: "not YAML", spec: "name: [unclosed", wantErr: "neither valid JSON nor YAML"},
+		{name: "bad JSON", spec: `{"name": "storefront",}`, wantErr: "invalid spec"},
+		{name: "unknown field", spec: "name: storefront\nservice:\n  - name: api\n", wantErr: `unknown field "service"`},
+		{name: "wrong type", spec: "name: storefront\nservices: api\n", wantErr: "invalid spec"},
+		{name: "no name", spec: "services:\n  - name: api\n", wantErr: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parseAppSpec(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAppSpec: %v", err)
+			}
+			if spec.Name != tt.wantName || len(spec.Services)+len(spec.StaticSites) != tt.wantParts {
+				t.Errorf("spec = %+v", spec)
+			}
+		})
+	}
+
+	spec, err := parseAppSpec(yamlAppSpec)
+	if err != nil {
+		t.Fatalf("parseAppSpec: %v", err)
+	}
+	api := spec.Services[0]
+	if api.HTTPPort != 8080 || api.InstanceCount != 2 || api.GitHub.Repo != "acme/storefront" || !api.GitHub.DeployOnPush {
+		t.Errorf("api service = %+v", api)
+	}
+	if len(api.Alerts) != 1 || api.Alerts[0].Value != 80 || spec.Databases[0].Engine != godo.AppDatabaseSpecEngine_PG {
+		t.Errorf("alerts = %+v, databases = %+v", api.Alerts, spec.Databases)
+	}
+}
+
+func TestCreateApp(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "YAML spec", spec: yamlAppSpec},
+		{name: "JSON spec", spec: jsonAppSpec},
+		{name: "no components", spec: "name: empty\n", wantErr: "at least one service"},
+		{name: "unknown region", spec: "name: storefront\nregion: mars\nservices:\n  - name: api\n", wantErr: `"mars" is not a valid`},
+		{name: "duplicate component", spec: "name: storefront\nservices:\n  - name: api\nworkers:\n  - name: api\n", wantErr: "used more than once"},
+		{name: "invalid spec", spec: "name: storefront\nservices: {}\n", wantErr: "invalid spec"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			resp, err := h.CreateApp(tt.spec, "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_app", tt.wantErr)
+				if fake.Apps.Len() != 0 {
+					t.Errorf("an app was created")
+				}
+				return
+			}
+			var app godo.App
+			decodeResponse(t, resp, err, &app)
+			if app.Spec.Name != "storefront" || app.LiveURL == "" || app.InProgressDeployment == nil {
+				t.Errorf("created app = %+v", app)
+			}
+			if app.InProgressDeployment.Phase != godo.DeploymentPhase_PendingBuild {
+				t.Errorf("first deployment phase = %s", app.InProgressDeployment.Phase)
+			}
+
+			resp, err = h.CreateApp(tt.spec, "")
+			expectError(t, resp, err, "create_app", "409", "already exists")
+		})
+	}
+}
+
+func TestUpdateApp(t *testing.T) {
+	h, fake := newTestHandler(t)
+	spec, err := parseAppSpec(yamlAppSpec)
+	if err != nil {
+		t.Fatalf("parseAppSpec: %v", err)
+	}
+	id := fake.AddApp(spec)
+
+	updated := strings.Replace(yamlAppSpec, "instance_count: 2", "instance_count: 4", 1)
+	var app godo.App
+	resp, err := h.UpdateApp(id, updated, false)
+	decodeResponse(t, resp, err, &app)
+	if app.Spec.Services[0].InstanceCount != 4 || app.ActiveDeployment == nil || app.InProgressDeployment == nil {
+		t.Errorf("updated app = %+v", app)
+	}
+	if app.InProgressDeployment.Cause != "app spec updated" {
+		t.Errorf("deployment cause = %q", app.InProgressDeployment.Cause)
+	}
+
+	resp, err = h.UpdateApp(id, strings.Replace(updated, "region: ams", "region: fra", 1), false)
+	expectError(t, resp, err, "update_app", "400", "region of an app cannot be changed")
+	resp, err = h.UpdateApp(id, "name: storefront\nservices: [{name: api, bogus: 1}]", false)
+	expectError(t, resp, err, "update_app", `unknown field "bogus"`)
+	resp, err = h.UpdateApp("app-missing", jsonAppSpec, false)
+	expectError(t, resp, err, "update_app", "404")
+}
+
+func TestAppDeployments(t *testing.T) {
+	h, fake := newTestHandler(t)
+	spec, _ := parseAppSpec(jsonAppSpec)
+	id := fake.AddApp(spec)
+
+	var first, second godo.Deployment
+	resp, err := h.CreateAppDeployment(id, true)
+	decodeResponse(t, resp, err, &first)
+	if first.Phase != godo.DeploymentPhase_PendingBuild || !strings.Contains(first.Cause, "forced rebuild") || len(first.Services) != 1 {
+		t.Errorf("created deployment = %+v", first)
+	}
+	resp, err = h.CreateAppDeployment(id, false)
+	decodeResponse(t, resp, err, &second)
+
+	var deployments []godo.Deployment
+	resp, err = h.ListAppDeployments(id, 0, 0)
+	decodeList(t, resp, err, "deployments", &deployments)
+	if len(deployments) != 3 || deployments[0].ID != second.ID || deployments[1].Phase != godo.DeploymentPhase_Superseded || deployments[2].Phase != godo.DeploymentPhase_Active {
+		t.Errorf("deployments = %+v", deployments)
+	}
+
+	var canceled godo.Deployment
+	resp, err = h.CancelAppDeployment(id, second.ID)
+	decodeResponse(t, resp, err, &canceled)
+	if canceled.Phase != godo.DeploymentPhase_Canceled {
+		t.Errorf("canceled deployment phase = %s", canceled.Phase)
+	}
+	resp, err = h.GetAppDeployment(id, second.ID)
+	decodeResponse(t, resp, err, &canceled)
+	if canceled.Phase != godo.DeploymentPhase_Canceled {
+		t.Errorf("fetched deployment phase = %s", canceled.Phase)
+	}
+
+	resp, err = h.CancelAppDeployment(id, second.ID)
+	expectError(t, resp, err, "cancel_app_deployment", "409", "can no longer be canceled")
+	resp, err = h.CancelAppDeployment(id, "deployment-missing")
+	expectError(t, resp, err, "cancel_app_deployment", "404")
+	resp, err = h.GetAppDeployment("app-other", first.ID)
+	expectError(t, resp, err, "get_app_deployment", "404")
+}
+
+func TestGetAppLogs(t *testing.T) {
+	tests := []struct {
+		name string
+		// latest is the phase of a second deployment made after the active one
+		latest        godo.DeploymentPhase
+		useDeployment string
+		component     string
+		logType       string
+		live          bool
+		want          string
+		wantErr       string
+	}{
+		{name: "live runtime", logType: "run", live: true, want: "/RUN/live"},
+		{name: "stored runtime of component", logType: "RUN", component: "api", want: "/RUN/api/1.log"},
+		{name: "build of latest deployment", latest: godo.DeploymentPhase_Deploying, logType: "build", want: "/BUILD/1.log"},
+		{name: "build of active deployment", latest: godo.DeploymentPhase_PendingBuild, useDeployment: "active", logType: "build", live: true, want: "/BUILD/live"},
+		{name: "build not started", latest: godo.DeploymentPhase_PendingBuild, logType: "build", wantErr: "has not started building"},
+		{name: "unknown component", logType: "deploy", component: "web", wantErr: "component web not found"},
+		{name: "unknown type", logType: "access", wantErr: `unsupported log type "access"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			spec, _ := parseAppSpec(jsonAppSpec)
+			id := fake.AddApp(spec)
+			deploymentID := ""
+			if tt.useDeployment == "active" {
+				deploymentID = fake.AppDeployments.List()[0].ID
+			}
+			if tt.latest != "" {
+				var latest godo.Deployment
+				resp, err := h.CreateAppDeployment(id, false)
+				decodeResponse(t, resp, err, &latest)
+				fake.AppDeployments.Update(latest.ID, func(d *fakedo.AppDeployment) { d.Phase = tt.latest })
+			}
+
+			resp, err := h.GetAppLogs(id, deploymentID, tt.component, tt.logType, tt.live, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "get_app_logs", tt.wantErr)
+				return
+			}
+			var logs godo.AppLogs
+			decodeResponse(t, resp, err, &logs)
+			url := logs.LiveURL
+			if !tt.live {
+				if len(logs.HistoricURLs) != 1 {
+					t.Fatalf("logs = %+v", logs)
+				}
+				url = logs.HistoricURLs[0]
+			}
+			if !strings.HasSuffix(url, tt.want) {
+				t.Errorf("log URL = %s, want suffix %s", url, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAppAlerts(t *testing.T) {
+	h, fake := newTestHandler(t)
+	spec, _ := parseAppSpec(yamlAppSpec)
+	id := fake.AddApp(spec)
+
+	var result struct {
+		Alerts []godo.AppAlert `json:"alerts"`
+	}
+	resp, err := h.ListAppAlerts(id)
+	decodeResponse(t, resp, err, &result)
+	if len(result.Alerts) != 2 {
+		t.Fatalf("alerts = %+v", result.Alerts)
+	}
+	if result.Alerts[0].ComponentName != "" || result.Alerts[0].Spec.Rule != godo.AppAlertSpecRule_DeploymentFailed {
+		t.Errorf("app alert = %+v", result.Alerts[0])
+	}
+	if result.Alerts[1].ComponentName != "api" || result.Alerts[1].Spec.Rule != godo.AppAlertSpecRule_CPUUtilization {
+		t.Errorf("component alert = %+v", result.Alerts[1])
+	}
+
+	resp, err = h.ListAppAlerts("app-missing")
+	expectError(t, resp, err, "list_app_alerts", "404")
+}
+
+func TestDeleteApp(t *testing.T) {
+	h, fake := newTestHandler(t)
+	spec, _ := parseAppSpec(yamlAppSpec)
+	spec.Databases = append(spec.Databases, &godo.AppDatabaseSpec{Name: "orders", Engine: godo.AppDatabaseSpecEngine_PG, ClusterName: "orders-db", Production: true})
+	id := fake.AddApp(spec)
+
+	preview, err := h.PreviewDeleteApp(id)
+	if err != nil {
+		t.Fatalf("PreviewDeleteApp: %v", err)
+	}
+	if preview.ResourceType != "app" || preview.Name != "storefront" || preview.Region != "ams" {
+		t.Errorf("preview = %+v", preview)
+	}
+	// api, web, two databases and the domain
+	if len(preview.AttachedResources) != 5 {
+		t.Errorf("attached resources = %+v", preview.AttachedResources)
+	}
+	warnings := strings.Join(preview.Warnings, "\n")
+	if !strings.Contains(warnings, "Dev database db") || strings.Contains(warnings, "orders") || !strings.Contains(warnings, "shop.example.com") {
+		t.Errorf("warnings = %v", preview.Warnings)
+	}
+
+	resp, err := h.DeleteApp(id)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.Apps.Len() != 0 || fake.AppDeployments.Len() != 0 {
+		t.Errorf("app or deployments left behind")
+	}
+
+	if _, err := h.PreviewDeleteApp(id); err == nil {
+		t.Errorf("expected an error for a deleted app")
+	}
+}
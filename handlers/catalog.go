@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// maxSuggestions caps how many alternatives a validation error lists.
+const maxSuggestions = 5
+
+// SizeFilter narrows list_sizes. Zero values match every size.
+type SizeFilter struct {
+	Region          string
+	AvailableOnly   bool
+	MinVCPUs        int
+	MinMemoryMB     int
+	MaxPriceMonthly float64
+}
+
+func (f SizeFilter) matches(size godo.Size) bool {
+	switch {
+	case f.AvailableOnly && !size.Available:
+		return false
+	case f.Region != "" && !slices.Contains(size.Regions, f.Region):
+		return false
+	case size.Vcpus < f.MinVCPUs, size.Memory < f.MinMemoryMB:
+		return false
+	case f.MaxPriceMonthly > 0 && size.PriceMonthly > f.MaxPriceMonthly:
+		return false
+	}
+	return true
+}
+
+// ListRegions lists regions, optionally only those accepting new resources
+// or offering a feature such as "storage".
+func (h *Handler) ListRegions(availableOnly bool, feature string) (*mcp_golang.ToolResponse, error) {
+	regions, err := h.listRegions()
+	if err != nil {
+		return h.HandleError(err, "list_regions")
+	}
+
+	matching := []godo.Region{}
+	for _, region := range regions {
+		if availableOnly && !region.Available {
+			continue
+		}
+		if feature != "" && !slices.Contains(region.Features, feature) {
+			continue
+		}
+		matching = append(matching, region)
+	}
+
+	return h.HandleSuccess(filteredListResult("regions", matching), "list_regions")
+}
+
+// ListSizes lists droplet sizes matching filter, in the order the API
+// returns them (cheapest first).
+func (h *Handler) ListSizes(filter SizeFilter) (*mcp_golang.ToolResponse, error) {
+	sizes, err := h.listSizes()
+	if err != nil {
+		return h.HandleError(err, "list_sizes")
+	}
+
+	matching := []godo.Size{}
+	for _, size := range sizes {
+		if filter.matches(size) {
+			matching = append(matching, size)
+		}
+	}
+
+	return h.HandleSuccess(filteredListResult("sizes", matching), "list_sizes")
+}
+
+// ListK8SOptions lists the Kubernetes versions, regions and node sizes
+// create_k8s_cluster accepts.
+func (h *Handler) ListK8SOptions() (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	options, _, err := client.Kubernetes.GetOptions(context.Background())
+	if err != nil {
+		return h.HandleError(err, "list_k8s_options")
+	}
+
+	return h.HandleSuccess(options, "list_k8s_options")
+}
+
+func (h *Handler) listRegions() ([]godo.Region, error) {
+	client := h.doClient.GetClient()
+
+	regions, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Region, *godo.Response, error) {
+		return client.Regions.List(context.Background(), opt)
+	})
+	return regions, err
+}
+
+func (h *Handler) listSizes() ([]godo.Size, error) {
+	client := h.doClient.GetClient()
+
+	sizes, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+		return client.Sizes.List(context.Background(), opt)
+	})
+	return sizes, err
+}
+
+// validateDropletPlacement checks that region accepts new droplets, that size
+// is offered there and that image can be used there. The errors name the
+// closest valid alternatives so the caller can retry without guessing.
+func (h *Handler) validateDropletPlacement(region, size, image string) error {
+	regions, err := h.listRegions()
+	if err != nil {
+		return err
+	}
+	target, err := findRegion(regions, region)
+	if err != nil {
+		return err
+	}
+
+	sizes, err := h.listSizes()
+	if err != nil {
+		return err
+	}
+	offered := target.Sizes
+	i := slices.IndexFunc(sizes, func(s godo.Size) bool { return s.Slug == size })
+	switch {
+	case i < 0:
+		return fmt.Errorf("invalid size %q; sizes available in %s include: %s", size, region, strings.Join(suggest(size, offered), ", "))
+	case !sizes[i].Available:
+		return fmt.Errorf("invalid size %q: it is no longer available; sizes available in %s include: %s", size, region, strings.Join(suggest(size, offered), ", "))
+	case !slices.Contains(offered, size):
+		return fmt.Errorf("invalid size %q for region %s: it is offered in %s; sizes available in %s include: %s",
+			size, region, strings.Join(availableRegions(regions, sizes[i].Regions), ", "), region, strings.Join(suggest(size, offered), ", "))
+	}
+
+	return h.validateImageRegion(image, region)
+}
+
+// validateImageRegion checks that image exists and, for images stored in
+// specific regions, that region is one of them.
+func (h *Handler) validateImageRegion(image, region string) error {
+	client := h.doClient.GetClient()
+
+	var found *godo.Image
+	var err error
+	if id, convErr := strconv.Atoi(image); convErr == nil {
+		found, _, err = client.Images.GetByID(context.Background(), id)
+	} else {
+		found, _, err = client.Images.GetBySlug(context.Background(), image)
+	}
+	if isNotFound(err) {
+		distributions, _, listErr := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+			return client.Images.ListDistribution(context.Background(), opt)
+		})
+		if listErr != nil {
+			return listErr
+		}
+		var slugs []string
+		for _, distribution := range distributions {
+			if distribution.Slug != "" {
+				slugs = append(slugs, distribution.Slug)
+			}
+		}
+		if len(slugs) == 0 {
+			return fmt.Errorf("image %q not found", image)
+		}
+		return fmt.Errorf("image %q not found; distribution images include: %s", image, strings.Join(suggest(image, slugs), ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(found.Regions) > 0 && !slices.Contains(found.Regions, region) {
+		return fmt.Errorf("image %q is not available in %s, only in %s; create the droplet there or copy the image with transfer_image",
+			image, region, strings.Join(found.Regions, ", "))
+	}
+	return nil
+}
+
+// validateVolumeRegion checks that region accepts new resources and offers
+// block storage.
+func (h *Handler) validateVolumeRegion(region string) error {
+	regions, err := h.listRegions()
+	if err != nil {
+		return err
+	}
+	target, err := findRegion(regions, region)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(target.Features, "storage") {
+		var withStorage []string
+		for _, r := range regions {
+			if r.Available && slices.Contains(r.Features, "storage") {
+				withStorage = append(withStorage, r.Slug)
+			}
+		}
+		return fmt.Errorf("region %s does not offer block storage; regions with volumes: %s", region, strings.Join(withStorage, ", "))
+	}
+	return nil
+}
+
+// validateK8SPlacement checks region, version and node size against the
+// options DOKS reports. "latest" is accepted as a version.
+func (h *Handler) validateK8SPlacement(region, version, nodeSize string) error {
+	client := h.doClient.GetClient()
+
+	options, _, err := client.Kubernetes.GetOptions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var regions, versions, sizes []string
+	for _, r := range options.Regions {
+		regions = append(regions, r.Slug)
+	}
+	for _, v := range options.Versions {
+		versions = append(versions, v.Slug)
+	}
+	for _, s := range options.Sizes {
+		sizes = append(sizes, s.Slug)
+	}
+
+	switch {
+	case !slices.Contains(regions, region):
+		return fmt.Errorf("invalid region %q for Kubernetes; try: %s", region, strings.Join(suggest(region, regions), ", "))
+	case version != "latest" && !slices.Contains(versions, version):
+		return fmt.Errorf("invalid Kubernetes version %q; supported versions: %s (or latest)", version, strings.Join(versions, ", "))
+	case !slices.Contains(sizes, nodeSize):
+		return fmt.Errorf("invalid node pool size %q; try: %s", nodeSize, strings.Join(suggest(nodeSize, sizes), ", "))
+	}
+	return nil
+}
+
+// findRegion returns the region with slug if it accepts new resources.
+func findRegion(regions []godo.Region, slug string) (godo.Region, error) {
+	var available []string
+	for _, region := range regions {
+		if region.Available {
+			available = append(available, region.Slug)
+		}
+	}
+
+	i := slices.IndexFunc(regions, func(r godo.Region) bool { return r.Slug == slug })
+	if i < 0 {
+		return godo.Region{}, fmt.Errorf("invalid region %q; try: %s", slug, strings.Join(suggest(slug, available), ", "))
+	}
+	if !regions[i].Available {
+		return godo.Region{}, fmt.Errorf("region %s is not accepting new resources; try: %s", slug, strings.Join(suggest(slug, available), ", "))
+	}
+	return regions[i], nil
+}
+
+// availableRegions returns the slugs in slugs whose region accepts new
+// resources.
+func availableRegions(regions []godo.Region, slugs []string) []string {
+	var available []string
+	for _, region := range regions {
+		if region.Available && slices.Contains(slugs, region.Slug) {
+			available = append(available, region.Slug)
+		}
+	}
+	return available
+}
+
+// suggest returns the candidates closest to value by edit distance, nearest
+// first. When none is nearest it returns the first candidates unchanged.
+func suggest(value string, candidates []string) []string {
+	threshold := len(value)/3 + 1
+	var nearest []string
+	for _, candidate := range candidates {
+		if editDistance(value, candidate) <= threshold {
+			nearest = append(nearest, candidate)
+		}
+	}
+	if len(nearest) == 0 {
+		nearest = slices.Clone(candidates)
+	} else {
+		slices.SortStableFunc(nearest, func(a, b string) int {
+			return editDistance(value, a) - editDistance(value, b)
+		})
+	}
+	if len(nearest) > maxSuggestions {
+		nearest = nearest[:maxSuggestions]
+	}
+	return nearest
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// filteredListResult is listResult for lists filtered after fetching every
+// page.
+func filteredListResult[T any](key string, items []T) map[string]interface{} {
+	return listResult(key, items, &ListMeta{
+		Total:    len(items),
+		Count:    len(items),
+		PerPage:  maxPerPage,
+		Pages:    1,
+		AllPages: true,
+	})
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestListRegions(t *testing.T) {
+	tests := []struct {
+		name      string
+		available bool
+		feature   string
+		want      []string
+	}{
+		{name: "all", want: []string{"nyc1", "nyc2", "nyc3", "sfo3", "ams3"}},
+		{name: "available", available: true, want: []string{"nyc1", "nyc3", "sfo3", "ams3"}},
+		{name: "with storage", available: true, feature: "storage", want: []string{"nyc3", "sfo3", "ams3"}},
+		{name: "unknown feature", feature: "gpu", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestHandler(t)
+
+			var regions []godo.Region
+			resp, err := h.ListRegions(tt.available, tt.feature)
+			meta := decodeList(t, resp, err, "regions", &regions)
+			slugs := []string{}
+			for _, region := range regions {
+				slugs = append(slugs, region.Slug)
+			}
+			if !slices.Equal(slugs, tt.want) || meta.Total != len(tt.want) {
+				t.Errorf("regions = %v (total %d), want %v", slugs, meta.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestListSizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter SizeFilter
+		want   []string
+	}{
+		{name: "all", want: []string{"s-1vcpu-1gb", "s-1vcpu-2gb", "s-2vcpu-2gb", "s-2vcpu-4gb", "s-4vcpu-8gb", "s-8vcpu-16gb"}},
+		{name: "available", filter: SizeFilter{AvailableOnly: true}, want: []string{"s-1vcpu-1gb", "s-1vcpu-2gb", "s-2vcpu-2gb", "s-2vcpu-4gb", "s-4vcpu-8gb"}},
+		{name: "region", filter: SizeFilter{Region: "nyc1"}, want: []string{"s-1vcpu-1gb", "s-1vcpu-2gb", "s-2vcpu-2gb"}},
+		{name: "min vcpus and memory", filter: SizeFilter{MinVCPUs: 2, MinMemoryMB: 4096}, want: []string{"s-2vcpu-4gb", "s-4vcpu-8gb", "s-8vcpu-16gb"}},
+		{name: "max price", filter: SizeFilter{MaxPriceMonthly: 12}, want: []string{"s-1vcpu-1gb", "s-1vcpu-2gb"}},
+		{name: "combined", filter: SizeFilter{Region: "ams3", AvailableOnly: true, MinVCPUs: 2, MaxPriceMonthly: 30}, want: []string{"s-2vcpu-2gb", "s-2vcpu-4gb"}},
+		{name: "nothing matches", filter: SizeFilter{MinVCPUs: 64}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestHandler(t)
+
+			var sizes []godo.Size
+			resp, err := h.ListSizes(tt.filter)
+			decodeList(t, resp, err, "sizes", &sizes)
+			slugs := []string{}
+			for _, size := range sizes {
+				slugs = append(slugs, size.Slug)
+			}
+			if !slices.Equal(slugs, tt.want) {
+				t.Errorf("sizes = %v, want %v", slugs, tt.want)
+			}
+		})
+	}
+}
+
+func TestListK8SOptions(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var options godo.KubernetesOptions
+	resp, err := h.ListK8SOptions()
+	decodeResponse(t, resp, err, &options)
+	if len(options.Versions) != 2 || options.Versions[0].Slug != "1.30.2-do.0" || len(options.Regions) != 4 || len(options.Sizes) != 4 {
+		t.Errorf("options = %+v", options)
+	}
+
+	fake.FailNext(http.MethodGet, "/v2/kubernetes/options", http.StatusServiceUnavailable, "options unavailable")
+	resp, err = h.ListK8SOptions()
+	expectError(t, resp, err, "list_k8s_options", "503", "options unavailable")
+}
+
+func TestCreateDropletPlacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		region  string
+		size    string
+		image   string
+		wantErr []string
+	}{
+		{name: "valid", region: "nyc3", size: "s-2vcpu-4gb", image: "ubuntu-22-04-x64"},
+		{name: "custom image in its region", region: "nyc3", size: "s-1vcpu-1gb", image: "4321"},
+		{name: "region typo", region: "nyc", size: "s-1vcpu-1gb", image: "ubuntu-22-04-x64", wantErr: []string{`invalid region "nyc"`, "try: nyc1, nyc3"}},
+		{name: "unavailable region", region: "nyc2", size: "s-1vcpu-1gb", image: "ubuntu-22-04-x64", wantErr: []string{"nyc2 is not accepting new resources", "try: nyc1, nyc3"}},
+		{name: "size typo", region: "nyc3", size: "s-1vcpu-1g", image: "ubuntu-22-04-x64", wantErr: []string{`invalid size "s-1vcpu-1g"`, "include: s-1vcpu-1gb, s-1vcpu-2gb"}},
+		{name: "size not in region", region: "sfo3", size: "s-4vcpu-8gb", image: "ubuntu-22-04-x64", wantErr: []string{"for region sfo3", "offered in nyc3, ams3"}},
+		{name: "retired size", region: "nyc3", size: "s-8vcpu-16gb", image: "ubuntu-22-04-x64", wantErr: []string{"no longer available"}},
+		{name: "image typo", region: "nyc3", size: "s-1vcpu-1gb", image: "ubuntu-22-04", wantErr: []string{`image "ubuntu-22-04" not found`, "include: ubuntu-22-04-x64, ubuntu-24-04-x64"}},
+		{name: "custom image elsewhere", region: "sfo3", size: "s-1vcpu-1gb", image: "4321", wantErr: []string{"not available in sfo3, only in nyc3", "transfer_image"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedImages(fake)
+
+			resp, err := h.CreateDroplet(CreateDropletOptions{Name: "web", Region: tt.region, Size: tt.size, Image: tt.image}, 0)
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_droplet", tt.wantErr...)
+				if fake.Droplets.Len() != 0 {
+					t.Errorf("no droplet should have been created")
+				}
+				return
+			}
+			decodeResponse(t, resp, err, &godo.Droplet{})
+		})
+	}
+}
+
+func TestCreateVolumePlacement(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	resp, err := h.CreateVolume("data", "nyc1", 10, "", "")
+	expectError(t, resp, err, "create_volume", "nyc1 does not offer block storage", "regions with volumes: nyc3, sfo3, ams3")
+	resp, err = h.CreateVolume("data", "ams", 10, "", "")
+	expectError(t, resp, err, "create_volume", `invalid region "ams"`, "try: ams3")
+	if fake.Volumes.Len() != 0 {
+		t.Errorf("no volume should have been created")
+	}
+
+	fake.FailNext(http.MethodGet, "/v2/regions", http.StatusInternalServerError, "regions unavailable")
+	resp, err = h.CreateVolume("data", "nyc3", 10, "", "")
+	expectError(t, resp, err, "create_volume", "500", "regions unavailable")
+}
+
+func TestCreateK8SClusterPlacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		region  string
+		version string
+		size    string
+		wantErr []string
+	}{
+		{name: "latest version", region: "ams3", version: "latest", size: "s-2vcpu-2gb"},
+		{name: "region", region: "nyc2", version: "1.29.1-do.0", size: "s-2vcpu-4gb", wantErr: []string{`invalid region "nyc2" for Kubernetes`, "try: nyc1, nyc3"}},
+		{name: "version", region: "nyc3", version: "1.28.2-do.0", size: "s-2vcpu-4gb", wantErr: []string{`version "1.28.2-do.0"`, "supported versions: 1.30.2-do.0, 1.29.1-do.0 (or latest)"}},
+		{name: "node size", region: "nyc3", version: "1.29.1-do.0", size: "s-1vcpu-1gb", wantErr: []string{`invalid node pool size "s-1vcpu-1gb"`, "try: s-1vcpu-2gb"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			resp, err := h.CreateK8SCluster("prod", tt.region, tt.version, tt.size, 1, "", "")
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_k8s_cluster", tt.wantErr...)
+				if fake.Clusters.Len() != 0 {
+					t.Errorf("no cluster should have been created")
+				}
+				return
+			}
+			decodeResponse(t, resp, err, &godo.KubernetesCluster{})
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		value      string
+		candidates []string
+		want       []string
+	}{
+		{value: "nyc", candidates: []string{"ams3", "nyc3", "sfo3", "nyc1"}, want: []string{"nyc3", "nyc1"}},
+		{value: "s-2vcpu-4g", candidates: []string{"s-1vcpu-1gb", "s-2vcpu-2gb", "s-2vcpu-4gb"}, want: []string{"s-2vcpu-4gb", "s-2vcpu-2gb", "s-1vcpu-1gb"}},
+		{value: "zzz", candidates: []string{"a", "b", "c", "d", "e", "f"}, want: []string{"a", "b", "c", "d", "e"}},
+		{value: "x", candidates: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := suggest(tt.value, tt.candidates); !slices.Equal(got, tt.want) {
+			t.Errorf("suggest(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
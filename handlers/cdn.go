@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// cdnTTLs are the cache lifetimes, in seconds, the CDN accepts.
+var cdnTTLs = []uint32{60, 600, 3600, 86400, 604800}
+
+const defaultCDNTTL = 3600
+
+// CDNUpdate holds the fields to change on a CDN endpoint. An empty
+// CustomDomain removes the custom domain and its certificate.
+type CDNUpdate struct {
+	TTL          *uint32
+	CustomDomain *string
+	Certificate  *string
+}
+
+func (h *Handler) ListCDNEndpoints(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	endpoints, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.CDN, *godo.Response, error) {
+		return client.CDNs.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_cdn_endpoints")
+	}
+
+	return h.HandleSuccess(listResult("endpoints", endpoints, meta), "list_cdn_endpoints")
+}
+
+func (h *Handler) GetCDNEndpoint(endpointID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	endpoint, _, err := client.CDNs.Get(context.Background(), endpointID)
+	if err != nil {
+		return h.HandleError(err, "get_cdn_endpoint")
+	}
+
+	return h.HandleSuccess(endpoint, "get_cdn_endpoint")
+}
+
+// CreateCDNEndpoint puts a CDN in front of a Spaces bucket. A custom domain
+// needs a certificate, given by ID or name, that covers it.
+func (h *Handler) CreateCDNEndpoint(origin string, ttl uint32, customDomain, certificate string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if !strings.HasSuffix(origin, ".digitaloceanspaces.com") {
+		return h.HandleError(fmt.Errorf("origin must be a Spaces bucket endpoint, e.g. assets.nyc3.digitaloceanspaces.com"), "create_cdn_endpoint")
+	}
+	if ttl == 0 {
+		ttl = defaultCDNTTL
+	}
+	if err := validateCDNTTL(ttl); err != nil {
+		return h.HandleError(err, "create_cdn_endpoint")
+	}
+	certificateID, err := h.cdnCertificate(customDomain, certificate)
+	if err != nil {
+		return h.HandleError(err, "create_cdn_endpoint")
+	}
+
+	endpoint, _, err := client.CDNs.Create(context.Background(), &godo.CDNCreateRequest{
+		Origin:        origin,
+		TTL:           ttl,
+		CustomDomain:  customDomain,
+		CertificateID: certificateID,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_cdn_endpoint")
+	}
+
+	return h.HandleSuccess(endpoint, "create_cdn_endpoint")
+}
+
+// UpdateCDNEndpoint changes the TTL and/or the custom domain. The API sets
+// the domain and certificate together, so an omitted one keeps its current
+// value. Everything is validated before either change is sent.
+func (h *Handler) UpdateCDNEndpoint(endpointID string, update CDNUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if update.TTL == nil && update.CustomDomain == nil && update.Certificate == nil {
+		return h.HandleError(fmt.Errorf("nothing to update: set ttl, custom_domain or certificate"), "update_cdn_endpoint")
+	}
+	if update.TTL != nil {
+		if err := validateCDNTTL(*update.TTL); err != nil {
+			return h.HandleError(err, "update_cdn_endpoint")
+		}
+	}
+
+	endpoint, _, err := client.CDNs.Get(context.Background(), endpointID)
+	if err != nil {
+		return h.HandleError(err, "update_cdn_endpoint")
+	}
+
+	var domainRequest *godo.CDNUpdateCustomDomainRequest
+	if update.CustomDomain != nil || update.Certificate != nil {
+		customDomain, certificate := endpoint.CustomDomain, endpoint.CertificateID
+		if update.CustomDomain != nil {
+			customDomain = *update.CustomDomain
+		}
+		if update.Certificate != nil {
+			certificate = *update.Certificate
+		}
+		if customDomain == "" {
+			if update.Certificate != nil && *update.Certificate != "" {
+				return h.HandleError(fmt.Errorf("a certificate needs a custom_domain to serve"), "update_cdn_endpoint")
+			}
+			certificate = ""
+		}
+		certificateID, err := h.cdnCertificate(customDomain, certificate)
+		if err != nil {
+			return h.HandleError(err, "update_cdn_endpoint")
+		}
+		domainRequest = &godo.CDNUpdateCustomDomainRequest{CustomDomain: customDomain, CertificateID: certificateID}
+	}
+
+	if update.TTL != nil {
+		endpoint, _, err = client.CDNs.UpdateTTL(context.Background(), endpointID, &godo.CDNUpdateTTLRequest{TTL: *update.TTL})
+		if err != nil {
+			return h.HandleError(err, "update_cdn_endpoint")
+		}
+	}
+	if domainRequest != nil {
+		endpoint, _, err = client.CDNs.UpdateCustomDomain(context.Background(), endpointID, domainRequest)
+		if err != nil {
+			return h.HandleError(err, "update_cdn_endpoint")
+		}
+	}
+
+	return h.HandleSuccess(endpoint, "update_cdn_endpoint")
+}
+
+func (h *Handler) DeleteCDNEndpoint(endpointID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.CDNs.Delete(context.Background(), endpointID)
+	if err != nil {
+		return h.HandleError(err, "delete_cdn_endpoint")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("CDN endpoint %s deleted successfully", endpointID),
+	}, "delete_cdn_endpoint")
+}
+
+func (h *Handler) PreviewDeleteCDNEndpoint(endpointID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	endpoint, _, err := client.CDNs.Get(context.Background(), endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "cdn_endpoint",
+		ID:           endpoint.ID,
+		Name:         endpoint.Endpoint,
+		AttachedResources: []map[string]interface{}{
+			{"type": "spaces_bucket", "origin": endpoint.Origin},
+		},
+		Warnings: []string{fmt.Sprintf("Requests to %s will stop being served; the bucket and its objects are kept", endpoint.Endpoint)},
+	}
+	if endpoint.CustomDomain != "" {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "certificate",
+			"id":   endpoint.CertificateID,
+		})
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("Point the DNS record for %s elsewhere first or it will stop resolving to content", endpoint.CustomDomain))
+	}
+
+	return preview, nil
+}
+
+// PurgeCDNCache drops cached copies of the given paths so the next request
+// fetches them from the origin. "*" purges everything; a trailing "*" purges
+// a directory.
+func (h *Handler) PurgeCDNCache(endpointID string, files []string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if len(files) == 0 {
+		return h.HandleError(fmt.Errorf("files are required, e.g. ['index.html', 'assets/*'] or ['*'] for everything"), "purge_cdn_cache")
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		path := strings.TrimLeft(file, "/")
+		if path == "" {
+			return h.HandleError(fmt.Errorf("empty path in files; use '*' to purge everything"), "purge_cdn_cache")
+		}
+		paths = append(paths, path)
+	}
+
+	_, err := client.CDNs.FlushCache(context.Background(), endpointID, &godo.CDNFlushCacheRequest{Files: paths})
+	if err != nil {
+		return h.HandleError(err, "purge_cdn_cache")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"status":  "success",
+		"message": fmt.Sprintf("Purge of %d path(s) started on CDN endpoint %s", len(paths), endpointID),
+		"files":   paths,
+	}, "purge_cdn_cache")
+}
+
+// cdnCertificate resolves the certificate for a custom domain and checks
+// that it covers the domain.
+func (h *Handler) cdnCertificate(customDomain, certificate string) (string, error) {
+	switch {
+	case customDomain == "" && certificate == "":
+		return "", nil
+	case customDomain == "":
+		return "", fmt.Errorf("a certificate needs a custom_domain to serve")
+	case certificate == "":
+		return "", fmt.Errorf("custom_domain %s needs a certificate covering it; create one with create_certificate", customDomain)
+	}
+
+	resolved, err := h.resolveCertificate(certificate)
+	if err != nil {
+		return "", err
+	}
+	if !certificateCovers(resolved, customDomain) {
+		return "", fmt.Errorf("certificate %s covers %s, not %s", resolved.Name, strings.Join(resolved.DNSNames, ", "), customDomain)
+	}
+	return resolved.ID, nil
+}
+
+func validateCDNTTL(ttl uint32) error {
+	if slices.Contains(cdnTTLs, ttl) {
+		return nil
+	}
+	allowed := make([]string, len(cdnTTLs))
+	for i, value := range cdnTTLs {
+		allowed[i] = fmt.Sprint(value)
+	}
+	return fmt.Errorf("invalid ttl %d; allowed: %s", ttl, strings.Join(allowed, ", "))
+}
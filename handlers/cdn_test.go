@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+const testOrigin = "assets.nyc3.digitaloceanspaces.com"
+
+func TestCreateCDNEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		origin       string
+		ttl          uint32
+		customDomain string
+		certificate  string
+		wantTTL      uint32
+		wantErr      string
+	}{
+		{name: "default ttl", origin: testOrigin, wantTTL: 3600},
+		{name: "custom domain by certificate name", origin: testOrigin, ttl: 86400, customDomain: "static.example.com", certificate: "wildcard", wantTTL: 86400},
+		{name: "not a bucket", origin: "example.com", wantErr: "origin must be a Spaces bucket endpoint"},
+		{name: "invalid ttl", origin: testOrigin, ttl: 120, wantErr: "invalid ttl 120; allowed: 60, 600, 3600, 86400, 604800"},
+		{name: "domain without certificate", origin: testOrigin, customDomain: "static.example.com", wantErr: "needs a certificate covering it"},
+		{name: "certificate without domain", origin: testOrigin, certificate: "wildcard", wantErr: "needs a custom_domain"},
+		{name: "certificate does not cover domain", origin: testOrigin, customDomain: "static.example.org", certificate: "wildcard", wantErr: "certificate wildcard covers *.example.com, not static.example.org"},
+		{name: "unknown certificate", origin: testOrigin, customDomain: "static.example.com", certificate: "wildcrd", wantErr: `certificate "wildcrd" not found; try: wildcard`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			certificate := fake.AddCertificate("wildcard", "*.example.com")
+
+			resp, err := h.CreateCDNEndpoint(tt.origin, tt.ttl, tt.customDomain, tt.certificate)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_cdn_endpoint", tt.wantErr)
+				if fake.CDNEndpoints.Len() != 0 {
+					t.Error("endpoint was created")
+				}
+				return
+			}
+
+			var endpoint godo.CDN
+			decodeResponse(t, resp, err, &endpoint)
+			if endpoint.TTL != tt.wantTTL || endpoint.Endpoint != "assets.nyc3.cdn.digitaloceanspaces.com" {
+				t.Errorf("endpoint = %+v", endpoint)
+			}
+			if tt.customDomain != "" && (endpoint.CustomDomain != tt.customDomain || endpoint.CertificateID != certificate.ID) {
+				t.Errorf("custom domain = %q with certificate %q, want %s", endpoint.CustomDomain, endpoint.CertificateID, certificate.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateCDNEndpoint(t *testing.T) {
+	h, fake := newTestHandler(t)
+	certificate := fake.AddCertificate("wildcard", "*.example.com")
+	other := fake.AddCertificate("static", "static.example.com")
+
+	var endpoint godo.CDN
+	resp, err := h.CreateCDNEndpoint(testOrigin, 0, "", "")
+	decodeResponse(t, resp, err, &endpoint)
+
+	ttl := uint32(600)
+	domain := "cdn.example.com"
+	staticDomain := "static.example.com"
+	orgDomain := "cdn.example.org"
+	shortTTL := uint32(60)
+	wildcard := "wildcard"
+	empty := ""
+	steps := []struct {
+		name    string
+		update  CDNUpdate
+		wantErr string
+		check   func(endpoint godo.CDN) bool
+	}{
+		{name: "nothing", wantErr: "nothing to update"},
+		{name: "invalid ttl", update: CDNUpdate{TTL: new(uint32)}, wantErr: "invalid ttl 0"},
+		{name: "certificate without domain", update: CDNUpdate{Certificate: &wildcard}, wantErr: "needs a custom_domain"},
+		{
+			name:   "ttl",
+			update: CDNUpdate{TTL: &ttl},
+			check:  func(e godo.CDN) bool { return e.TTL == 600 && e.CustomDomain == "" },
+		},
+		{
+			name:   "custom domain",
+			update: CDNUpdate{CustomDomain: &domain, Certificate: &wildcard},
+			check: func(e godo.CDN) bool {
+				return e.CustomDomain == domain && e.CertificateID == certificate.ID && e.TTL == 600
+			},
+		},
+		{
+			name:    "domain outside certificate",
+			update:  CDNUpdate{CustomDomain: &orgDomain},
+			wantErr: "certificate wildcard covers *.example.com, not cdn.example.org",
+		},
+		{
+			name:   "domain and certificate again",
+			update: CDNUpdate{CustomDomain: &staticDomain, Certificate: &other.Name},
+			check:  func(e godo.CDN) bool { return e.CustomDomain == staticDomain && e.CertificateID == other.ID },
+		},
+		{
+			name:   "certificate only keeps the domain",
+			update: CDNUpdate{Certificate: &wildcard},
+			check:  func(e godo.CDN) bool { return e.CustomDomain == staticDomain && e.CertificateID == certificate.ID },
+		},
+		{
+			// Nothing is sent when any part is invalid
+			name:    "ttl with invalid certificate",
+			update:  CDNUpdate{TTL: &shortTTL, CustomDomain: &empty, Certificate: &wildcard},
+			wantErr: "needs a custom_domain",
+		},
+		{
+			name:   "remove domain",
+			update: CDNUpdate{CustomDomain: &empty},
+			check:  func(e godo.CDN) bool { return e.CustomDomain == "" && e.CertificateID == "" },
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			resp, err := h.UpdateCDNEndpoint(endpoint.ID, step.update)
+			if step.wantErr != "" {
+				expectError(t, resp, err, "update_cdn_endpoint", step.wantErr)
+				return
+			}
+			var updated godo.CDN
+			decodeResponse(t, resp, err, &updated)
+			if !step.check(updated) {
+				t.Errorf("endpoint = %+v", updated)
+			}
+		})
+	}
+
+	if stored, _ := fake.CDNEndpoints.Get(endpoint.ID); stored.TTL != 600 {
+		t.Errorf("ttl = %d, want the rejected update not applied", stored.TTL)
+	}
+	resp, err = h.UpdateCDNEndpoint("cdn-missing", CDNUpdate{TTL: &ttl})
+	expectError(t, resp, err, "update_cdn_endpoint", "404")
+}
+
+func TestPurgeCDNCache(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var endpoint godo.CDN
+	resp, err := h.CreateCDNEndpoint(testOrigin, 0, "", "")
+	decodeResponse(t, resp, err, &endpoint)
+
+	tests := []struct {
+		name    string
+		files   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "paths", files: []string{"/index.html", "assets/*"}, want: []string{"index.html", "assets/*"}},
+		{name: "everything", files: []string{"*"}, want: []string{"*"}},
+		{name: "no files", wantErr: "files are required"},
+		{name: "root only", files: []string{"/"}, wantErr: "empty path in files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.CachePurges.Delete(endpoint.ID)
+			resp, err := h.PurgeCDNCache(endpoint.ID, tt.files)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "purge_cdn_cache", tt.wantErr)
+				return
+			}
+			decodeResponse(t, resp, err, &map[string]interface{}{})
+			if purged, _ := fake.CachePurges.Get(endpoint.ID); !slices.Equal(purged, tt.want) {
+				t.Errorf("purged = %v, want %v", purged, tt.want)
+			}
+		})
+	}
+
+	resp, err = h.PurgeCDNCache("cdn-missing", []string{"*"})
+	expectError(t, resp, err, "purge_cdn_cache", "404")
+}
+
+func TestCDNEndpointListAndDelete(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddCertificate("wildcard", "*.example.com")
+
+	var endpoint godo.CDN
+	resp, err := h.CreateCDNEndpoint(testOrigin, 0, "static.example.com", "wildcard")
+	decodeResponse(t, resp, err, &endpoint)
+
+	var endpoints []godo.CDN
+	resp, err = h.ListCDNEndpoints(0, 0)
+	meta := decodeList(t, resp, err, "endpoints", &endpoints)
+	if meta.Total != 1 || endpoints[0].ID != endpoint.ID {
+		t.Errorf("endpoints = %+v", endpoints)
+	}
+
+	var got godo.CDN
+	resp, err = h.GetCDNEndpoint(endpoint.ID)
+	decodeResponse(t, resp, err, &got)
+	if got.Origin != testOrigin {
+		t.Errorf("endpoint = %+v", got)
+	}
+
+	preview, err := h.PreviewDeleteCDNEndpoint(endpoint.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteCDNEndpoint: %v", err)
+	}
+	if preview.ResourceType != "cdn_endpoint" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 2 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	resp, err = h.DeleteCDNEndpoint(endpoint.ID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.CDNEndpoints.Len() != 0 {
+		t.Error("endpoint was not deleted")
+	}
+	resp, err = h.DeleteCDNEndpoint(endpoint.ID)
+	expectError(t, resp, err, "delete_cdn_endpoint", "404")
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// CertificateOptions describes a certificate to create. DNSNames requests a
+// Let's Encrypt certificate; LeafCertificate and PrivateKey upload a custom
+// one.
+type CertificateOptions struct {
+	Name             string
+	DNSNames         []string
+	LeafCertificate  string
+	PrivateKey       string
+	CertificateChain string
+}
+
+// ListCertificates lists TLS certificates, optionally only those with the
+// given name.
+func (h *Handler) ListCertificates(name string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	certificates, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Certificate, *godo.Response, error) {
+		if name != "" {
+			return client.Certificates.ListByName(context.Background(), name, opt)
+		}
+		return client.Certificates.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_certificates")
+	}
+
+	return h.HandleSuccess(listResult("certificates", certificates, meta), "list_certificates")
+}
+
+func (h *Handler) GetCertificate(certificateID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	certificate, _, err := client.Certificates.Get(context.Background(), certificateID)
+	if err != nil {
+		return h.HandleError(err, "get_certificate")
+	}
+
+	return h.HandleSuccess(certificate, "get_certificate")
+}
+
+// CreateCertificate requests a Let's Encrypt certificate when DNS names are
+// given, or uploads a custom one. Custom certificates are checked against
+// their private key before upload so a mismatched pair fails here rather
+// than on the first TLS handshake.
+func (h *Handler) CreateCertificate(options CertificateOptions) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if options.Name == "" {
+		return h.HandleError(fmt.Errorf("name is required"), "create_certificate")
+	}
+
+	request := &godo.CertificateRequest{Name: options.Name}
+	custom := options.LeafCertificate != "" || options.PrivateKey != ""
+	switch {
+	case len(options.DNSNames) > 0 && custom:
+		return h.HandleError(fmt.Errorf("give dns_names for Let's Encrypt or leaf_certificate and private_key for a custom certificate, not both"), "create_certificate")
+	case len(options.DNSNames) > 0:
+		request.Type = "lets_encrypt"
+		request.DNSNames = options.DNSNames
+	case custom:
+		if options.LeafCertificate == "" || options.PrivateKey == "" {
+			return h.HandleError(fmt.Errorf("custom certificates need both leaf_certificate and private_key"), "create_certificate")
+		}
+		if _, err := tls.X509KeyPair([]byte(options.LeafCertificate), []byte(options.PrivateKey)); err != nil {
+			return h.HandleError(fmt.Errorf("invalid certificate or key: %w", err), "create_certificate")
+		}
+		request.Type = "custom"
+		request.LeafCertificate = options.LeafCertificate
+		request.PrivateKey = options.PrivateKey
+		request.CertificateChain = options.CertificateChain
+	default:
+		return h.HandleError(fmt.Errorf("dns_names (Let's Encrypt) or leaf_certificate and private_key (custom) are required"), "create_certificate")
+	}
+
+	certificate, _, err := client.Certificates.Create(context.Background(), request)
+	if err != nil {
+		return h.HandleError(err, "create_certificate")
+	}
+
+	return h.HandleSuccess(certificate, "create_certificate")
+}
+
+func (h *Handler) DeleteCertificate(certificateID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Certificates.Delete(context.Background(), certificateID)
+	if err != nil {
+		return h.HandleError(err, "delete_certificate")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Certificate %s deleted successfully", certificateID),
+	}, "delete_certificate")
+}
+
+// PreviewDeleteCertificate lists the load balancer rules and CDN endpoints
+// that terminate TLS with the certificate.
+func (h *Handler) PreviewDeleteCertificate(certificateID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	certificate, _, err := client.Certificates.Get(context.Background(), certificateID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "certificate",
+		ID:           certificate.ID,
+		Name:         certificate.Name,
+	}
+
+	loadBalancers, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.LoadBalancer, *godo.Response, error) {
+		return client.LoadBalancers.List(context.Background(), opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, loadBalancer := range loadBalancers {
+		for _, rule := range loadBalancer.ForwardingRules {
+			if rule.CertificateID != certificate.ID {
+				continue
+			}
+			preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+				"type":       "load_balancer",
+				"id":         loadBalancer.ID,
+				"name":       loadBalancer.Name,
+				"entry_port": rule.EntryPort,
+			})
+		}
+	}
+
+	endpoints, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.CDN, *godo.Response, error) {
+		return client.CDNs.List(context.Background(), opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.CertificateID == certificate.ID {
+			preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+				"type":          "cdn_endpoint",
+				"id":            endpoint.ID,
+				"custom_domain": endpoint.CustomDomain,
+			})
+		}
+	}
+
+	if len(preview.AttachedResources) > 0 {
+		preview.Warnings = append(preview.Warnings, "The certificate is in use; the API refuses to delete it until the resources above stop using it")
+	}
+
+	return preview, nil
+}
+
+// resolveCertificate returns the certificate whose ID or name is ref, so
+// tools can take the name users see in the control panel.
+func (h *Handler) resolveCertificate(ref string) (*godo.Certificate, error) {
+	certificates, err := h.listAllCertificates()
+	if err != nil {
+		return nil, err
+	}
+	return findCertificate(certificates, ref)
+}
+
+// resolveRuleCertificates replaces certificate names in forwarding rules with
+// IDs. The rules are copied; the caller's slice is left alone.
+func (h *Handler) resolveRuleCertificates(rules []godo.ForwardingRule) ([]godo.ForwardingRule, error) {
+	resolved := make([]godo.ForwardingRule, len(rules))
+	copy(resolved, rules)
+
+	var certificates []godo.Certificate
+	for i, rule := range resolved {
+		if rule.CertificateID == "" {
+			continue
+		}
+		if certificates == nil {
+			var err error
+			if certificates, err = h.listAllCertificates(); err != nil {
+				return nil, err
+			}
+		}
+		certificate, err := findCertificate(certificates, rule.CertificateID)
+		if err != nil {
+			return nil, fmt.Errorf("forwarding rule %d: %w", i+1, err)
+		}
+		resolved[i].CertificateID = certificate.ID
+	}
+	return resolved, nil
+}
+
+func (h *Handler) listAllCertificates() ([]godo.Certificate, error) {
+	client := h.doClient.GetClient()
+
+	certificates, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Certificate, *godo.Response, error) {
+		return client.Certificates.List(context.Background(), opt)
+	})
+	return certificates, err
+}
+
+// findCertificate matches ref against certificate IDs first, then names.
+func findCertificate(certificates []godo.Certificate, ref string) (*godo.Certificate, error) {
+	var matches []*godo.Certificate
+	names := make([]string, 0, len(certificates))
+	for i := range certificates {
+		if certificates[i].ID == ref {
+			return &certificates[i], nil
+		}
+		if certificates[i].Name == ref {
+			matches = append(matches, &certificates[i])
+		}
+		names = append(names, certificates[i].Name)
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("certificate name %q is ambiguous; use its ID", ref)
+	case len(names) == 0:
+		return nil, fmt.Errorf("certificate %q not found; the account has no certificates", ref)
+	default:
+		return nil, fmt.Errorf("certificate %q not found; try: %s", ref, strings.Join(suggest(ref, names), ", "))
+	}
+}
+
+// certificateCovers reports whether the certificate is valid for domain,
+// matching wildcards one label deep.
+func certificateCovers(certificate *godo.Certificate, domain string) bool {
+	for _, name := range certificate.DNSNames {
+		if strings.EqualFold(name, domain) {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			if label, rest, found := strings.Cut(domain, "."); found && label != "" && strings.EqualFold(rest, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
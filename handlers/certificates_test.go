@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// selfSigned returns a PEM certificate for dnsNames and its private key.
+func selfSigned(t *testing.T, dnsNames ...string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestCertificateCRUD(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddDomain("example.com")
+
+	var letsEncrypt godo.Certificate
+	resp, err := h.CreateCertificate(CertificateOptions{Name: "web", DNSNames: []string{"example.com", "*.example.com"}})
+	decodeResponse(t, resp, err, &letsEncrypt)
+	if letsEncrypt.Type != "lets_encrypt" || len(letsEncrypt.DNSNames) != 2 || letsEncrypt.NotAfter == "" {
+		t.Errorf("Let's Encrypt certificate = %+v", letsEncrypt)
+	}
+
+	leaf, key := selfSigned(t, "api.example.org")
+	var custom godo.Certificate
+	resp, err = h.CreateCertificate(CertificateOptions{Name: "api", LeafCertificate: leaf, PrivateKey: key})
+	decodeResponse(t, resp, err, &custom)
+	if custom.Type != "custom" || len(custom.DNSNames) != 1 || custom.DNSNames[0] != "api.example.org" || custom.SHA1Fingerprint == "" {
+		t.Errorf("custom certificate = %+v", custom)
+	}
+
+	var certificates []godo.Certificate
+	resp, err = h.ListCertificates("", 0, 0)
+	meta := decodeList(t, resp, err, "certificates", &certificates)
+	if meta.Total != 2 {
+		t.Errorf("total = %d, want 2", meta.Total)
+	}
+	certificates = nil
+	resp, err = h.ListCertificates("api", 0, 0)
+	decodeList(t, resp, err, "certificates", &certificates)
+	if len(certificates) != 1 || certificates[0].ID != custom.ID {
+		t.Errorf("certificates named api = %+v", certificates)
+	}
+
+	var got godo.Certificate
+	resp, err = h.GetCertificate(letsEncrypt.ID)
+	decodeResponse(t, resp, err, &got)
+	if got.Name != "web" {
+		t.Errorf("certificate = %+v", got)
+	}
+
+	resp, err = h.DeleteCertificate(letsEncrypt.ID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.Certificates.Len() != 1 {
+		t.Errorf("certificates = %d after delete, want 1", fake.Certificates.Len())
+	}
+	resp, err = h.GetCertificate(letsEncrypt.ID)
+	expectError(t, resp, err, "get_certificate", "404")
+}
+
+func TestCreateCertificateValidation(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddDomain("example.com")
+	leaf, key := selfSigned(t, "example.com")
+	_, otherKey := selfSigned(t, "example.com")
+
+	tests := []struct {
+		name    string
+		options CertificateOptions
+		wantErr []string
+	}{
+		{name: "no name", options: CertificateOptions{DNSNames: []string{"example.com"}}, wantErr: []string{"name is required"}},
+		{name: "neither", options: CertificateOptions{Name: "web"}, wantErr: []string{"dns_names (Let's Encrypt) or leaf_certificate and private_key (custom) are required"}},
+		{name: "both", options: CertificateOptions{Name: "web", DNSNames: []string{"example.com"}, LeafCertificate: leaf, PrivateKey: key}, wantErr: []string{"not both"}},
+		{name: "no key", options: CertificateOptions{Name: "web", LeafCertificate: leaf}, wantErr: []string{"need both leaf_certificate and private_key"}},
+		{name: "mismatched key", options: CertificateOptions{Name: "web", LeafCertificate: leaf, PrivateKey: otherKey}, wantErr: []string{"invalid certificate or key"}},
+		{name: "not pem", options: CertificateOptions{Name: "web", LeafCertificate: "certificate", PrivateKey: "key"}, wantErr: []string{"invalid certificate or key"}},
+		{name: "unmanaged domain", options: CertificateOptions{Name: "web", DNSNames: []string{"example.net"}}, wantErr: []string{"422", "example.net is not managed by DigitalOcean DNS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.CreateCertificate(tt.options)
+			expectError(t, resp, err, "create_certificate", tt.wantErr...)
+		})
+	}
+	if fake.Certificates.Len() != 0 {
+		t.Errorf("certificates = %d, want none created", fake.Certificates.Len())
+	}
+}
+
+func TestPreviewDeleteCertificate(t *testing.T) {
+	h, fake := newTestHandler(t)
+	certificate := fake.AddCertificate("web", "example.com", "*.example.com")
+
+	preview, err := h.PreviewDeleteCertificate(certificate.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteCertificate: %v", err)
+	}
+	if preview.Name != "web" || len(preview.AttachedResources) != 0 || len(preview.Warnings) != 0 {
+		t.Errorf("unused certificate preview = %+v", preview)
+	}
+
+	httpsRule := godo.ForwardingRule{EntryProtocol: "https", EntryPort: 443, TargetProtocol: "http", TargetPort: 8080, CertificateID: "web"}
+	resp, err := h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpsRule}, nil, "", "")
+	decodeResponse(t, resp, err, &godo.LoadBalancer{})
+	resp, err = h.CreateCDNEndpoint("assets.nyc3.digitaloceanspaces.com", 0, "static.example.com", "web")
+	decodeResponse(t, resp, err, &godo.CDN{})
+
+	preview, err = h.PreviewDeleteCertificate(certificate.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteCertificate: %v", err)
+	}
+	if len(preview.AttachedResources) != 2 || len(preview.Warnings) != 1 {
+		t.Errorf("preview = %+v, want the load balancer and CDN endpoint", preview)
+	}
+	if preview.AttachedResources[0]["type"] != "load_balancer" || preview.AttachedResources[1]["type"] != "cdn_endpoint" {
+		t.Errorf("attached = %v", preview.AttachedResources)
+	}
+
+	if _, err := h.PreviewDeleteCertificate("cert-missing"); err == nil {
+		t.Error("PreviewDeleteCertificate succeeded for a missing certificate")
+	}
+}
+
+func TestCertificateCovers(t *testing.T) {
+	certificate := &godo.Certificate{DNSNames: []string{"example.com", "*.example.org"}}
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{domain: "example.com", want: true},
+		{domain: "EXAMPLE.com", want: true},
+		{domain: "www.example.com", want: false},
+		{domain: "static.example.org", want: true},
+		{domain: "a.static.example.org", want: false},
+		{domain: "example.org", want: false},
+	}
+	for _, tt := range tests {
+		if got := certificateCovers(certificate, tt.domain); got != tt.want {
+			t.Errorf("certificateCovers(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
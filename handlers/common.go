@@ -4,20 +4,34 @@ import (
 	"digitalocean-mcp-server/client"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
 type Handler struct {
 	doClient *client.DOClient
+	// pollInterval and maxPollInterval bound the backoff used while waiting
+	// for actions. Tests shorten them.
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+	// spaces is nil unless Spaces access keys are configured.
+	spaces *client.SpacesClient
 }
 
 func NewHandler(doClient *client.DOClient) *Handler {
 	return &Handler{
-		doClient: doClient,
+		doClient:        doClient,
+		pollInterval:    defaultPollInterval,
+		maxPollInterval: defaultMaxPollInterval,
 	}
 }
 
+// SetSpacesClient enables the Spaces object storage tools.
+func (h *Handler) SetSpacesClient(spaces *client.SpacesClient) {
+	h.spaces = spaces
+}
+
 func (h *Handler) HandleError(err error, operation string) (*mcp_golang.ToolResponse, error) {
 	return nil, fmt.Errorf("error in %s: %v", operation, err)
 }
@@ -47,4 +61,38 @@ func (h *Handler) TestConnection() (*mcp_golang.ToolResponse, error) {
 		"status": "connected",
 		"message": "Successfully connected to DigitalOcean API",
 	}, "connection test")
-}
\ No newline at end of file
+}
+
+// Storage rates used to estimate the monthly cost of resources whose price is
+// not reported by the API.
+const (
+	volumePricePerGB      = 0.10
+	snapshotPricePerGB    = 0.06
+	loadBalancerNodePrice = 12.00
+	// backupPriceRatio is the share of a droplet's price charged for backups.
+	backupPriceRatio = 0.20
+)
+
+// DeletionPreview describes what a destructive tool call would remove.
+type DeletionPreview struct {
+	ResourceType         string                   `json:"resource_type"`
+	ID                   string                   `json:"id"`
+	Name                 string                   `json:"name"`
+	Region               string                   `json:"region,omitempty"`
+	EstimatedMonthlyCost float64                  `json:"estimated_monthly_cost_usd"`
+	AttachedResources    []map[string]interface{} `json:"attached_resources,omitempty"`
+	Warnings             []string                 `json:"warnings,omitempty"`
+
+	// scope identifies resources that are looked up when the call runs
+	scope string
+}
+
+// ConfirmationScope identifies the resources a preview matched when the call
+// looks them up again as it runs, such as the droplets carrying a tag. A
+// confirmation is only honoured while the scope is unchanged.
+func (p *DeletionPreview) ConfirmationScope() string {
+	if p == nil {
+		return ""
+	}
+	return p.scope
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/client"
+	"digitalocean-mcp-server/internal/fakedo"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// newTestHandler returns a handler wired to a fresh fake DigitalOcean API.
+func newTestHandler(t *testing.T) (*Handler, *fakedo.Server) {
+	t.Helper()
+
+	fake := fakedo.New(t)
+	doClient, err := client.NewDOClientWithBaseURL("test-token", fake.URL())
+	if err != nil {
+		t.Fatalf("NewDOClientWithBaseURL: %v", err)
+	}
+	spaces, err := client.NewSpacesClient(fake.SpacesURL(), fakedo.SpacesRegion, fakedo.SpacesAccessKey, fakedo.SpacesSecretKey)
+	if err != nil {
+		t.Fatalf("NewSpacesClient: %v", err)
+	}
+	h := NewHandler(doClient)
+	h.SetSpacesClient(spaces)
+	h.pollInterval = time.Millisecond
+	h.maxPollInterval = 5 * time.Millisecond
+	return h, fake
+}
+
+// decodeResponse unmarshals the JSON text content of a tool response into v.
+func decodeResponse(t *testing.T, resp *mcp_golang.ToolResponse, err error, v interface{}) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp == nil || len(resp.Content) != 1 || resp.Content[0].TextContent == nil {
+		t.Fatalf("expected a single text content, got %+v", resp)
+	}
+	if err := json.Unmarshal([]byte(resp.Content[0].TextContent.Text), v); err != nil {
+		t.Fatalf("response is not valid JSON: %v\n%s", err, resp.Content[0].TextContent.Text)
+	}
+}
+
+// decodeList unmarshals a list tool response, storing the items found under
+// key in v and returning the pagination meta.
+func decodeList(t *testing.T, resp *mcp_golang.ToolResponse, err error, key string, v interface{}) ListMeta {
+	t.Helper()
+
+	var body map[string]json.RawMessage
+	decodeResponse(t, resp, err, &body)
+	items, ok := body[key]
+	if !ok {
+		t.Fatalf("response has no %q key: %v", key, body)
+	}
+	if err := json.Unmarshal(items, v); err != nil {
+		t.Fatalf("invalid %s: %v", key, err)
+	}
+	var meta ListMeta
+	if err := json.Unmarshal(body["meta"], &meta); err != nil {
+		t.Fatalf("invalid meta: %v", err)
+	}
+	return meta
+}
+
+// expectError checks that a handler failed and that the error names the
+// operation and carries each of the given fragments.
+func expectError(t *testing.T, resp *mcp_golang.ToolResponse, err error, operation string, fragments ...string) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("expected an error, got response %+v", resp)
+	}
+	if resp != nil {
+		t.Errorf("expected a nil response alongside the error, got %+v", resp)
+	}
+	want := append([]string{"error in " + operation}, fragments...)
+	for _, fragment := range want {
+		if !strings.Contains(err.Error(), fragment) {
+			t.Errorf("error %q does not contain %q", err, fragment)
+		}
+	}
+}
+
+func TestTestConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    int
+		wantErr string
+	}{
+		{name: "connected"},
+		{name: "unauthorized", fail: http.StatusUnauthorized, wantErr: "Unable to authenticate you"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			if tt.fail != 0 {
+				fake.FailNext("GET", "/v2/account", tt.fail, tt.wantErr)
+			}
+
+			resp, err := h.TestConnection()
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "connection test", "401", tt.wantErr)
+				return
+			}
+
+			var result map[string]string
+			decodeResponse(t, resp, err, &result)
+			if result["status"] != "connected" {
+				t.Errorf("status = %q, want connected", result["status"])
+			}
+		})
+	}
+}
+
+func TestHandleSuccessMarshalError(t *testing.T) {
+	h := NewHandler(nil)
+
+	resp, err := h.HandleSuccess(map[string]interface{}{"bad": make(chan int)}, "some_tool")
+	expectError(t, resp, err, "some_tool (JSON marshaling)")
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2024, time.May, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		cron string
+		want string
+	}{
+		{cron: "* * * * *", want: "2024-05-15T10:08:00Z"},
+		{cron: "*/15 * * * *", want: "2024-05-15T10:15:00Z"},
+		{cron: "0 * * * *", want: "2024-05-15T11:00:00Z"},
+		{cron: "30 2 * * *", want: "2024-05-16T02:30:00Z"},
+		{cron: "0 9 * * mon-fri", want: "2024-05-16T09:00:00Z"},
+		{cron: "0 9 * * SAT,sun", want: "2024-05-18T09:00:00Z"},
+		{cron: "0 0 * * 7", want: "2024-05-19T00:00:00Z"},
+		{cron: "0 0 1,15 * *", want: "2024-06-01T00:00:00Z"},
+		{cron: "10-50/20 10 * * *", want: "2024-05-15T10:10:00Z"},
+		{cron: "5/20 * * * *", want: "2024-05-15T10:25:00Z"},
+		{cron: "0 0 1 jan *", want: "2025-01-01T00:00:00Z"},
+		{cron: "0 0 29 2 *", want: "2028-02-29T00:00:00Z"},
+		// Both day fields restricted: either one fires
+		{cron: "0 12 20 * mon", want: "2024-05-20T12:00:00Z"},
+		{cron: "0 12 17 * mon", want: "2024-05-17T12:00:00Z"},
+		// A day of week starting with "*" must match along with the day of month
+		{cron: "0 12 16 * */3", want: "2024-06-16T12:00:00Z"},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.cron)
+		if err != nil {
+			t.Errorf("parseCron(%q): %v", tt.cron, err)
+			continue
+		}
+		next, ok := schedule.next(from)
+		if !ok || next.Format(time.RFC3339) != tt.want {
+			t.Errorf("next(%q) = %v, %v, want %s", tt.cron, next, ok, tt.want)
+		}
+	}
+
+	schedule, _ := parseCron("0 0 30 2 *")
+	if next, ok := schedule.next(from); ok {
+		t.Errorf("next(0 0 30 2 *) = %v, want never", next)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		cron    string
+		wantErr string
+	}{
+		{cron: "* * * *", wantErr: "expected 5 fields"},
+		{cron: "0 0 * * * 2024", wantErr: "got 6"},
+		{cron: "60 * * * *", wantErr: "minute 60 is out of range 0-59"},
+		{cron: "0 24 * * *", wantErr: "hour 24 is out of range 0-23"},
+		{cron: "0 0 0 * *", wantErr: "day of month 0 is out of range 1-31"},
+		{cron: "0 0 * jun-jan *", wantErr: "month range jun-jan runs backwards"},
+		{cron: "0 0 * * monday", wantErr: `invalid day of week "monday"`},
+		{cron: "*/0 * * * *", wantErr: `minute step "0" must be a positive number`},
+		{cron: "@daily", wantErr: "expected 5 fields"},
+	}
+	for _, tt := range tests {
+		_, err := parseCron(tt.cron)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseCron(%q) error = %v, want %q", tt.cron, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,925 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// maskedCredential replaces passwords, keys and certificates in responses
+// unless the caller asks for credentials.
+const maskedCredential = "********"
+
+var (
+	databaseEngines        = []string{"pg", "mysql", "redis", "valkey", "mongodb", "kafka", "opensearch"}
+	databasePoolModes      = []string{"session", "transaction", "statement"}
+	replicaEngines         = []string{"pg", "mysql"}
+	databaseTrustedSources = []string{"ip_addr", "droplet", "k8s", "tag", "app"}
+	maintenanceDays        = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+	maintenanceHourPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$`)
+)
+
+// DatabaseResize holds the cluster settings to change. Empty and nil values
+// keep the current setting.
+type DatabaseResize struct {
+	Size           string
+	NumNodes       *int
+	StorageSizeMib *uint64
+}
+
+// DatabasePoolUpdate holds the pool fields to change. Empty and nil values
+// keep the current setting.
+type DatabasePoolUpdate struct {
+	User     string
+	Database string
+	Mode     string
+	Size     *int
+}
+
+func (h *Handler) ListDatabaseClusters(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	clusters, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Database, *godo.Response, error) {
+		return client.Databases.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_database_clusters")
+	}
+
+	// Listing never returns credentials; use get_database_cluster for them
+	for i := range clusters {
+		maskDatabase(&clusters[i])
+	}
+
+	return h.HandleSuccess(listResult("databases", clusters, meta), "list_database_clusters")
+}
+
+func (h *Handler) GetDatabaseCluster(clusterID string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "get_database_cluster")
+	}
+
+	if !showCredentials {
+		maskDatabase(cluster)
+	}
+
+	return h.HandleSuccess(cluster, "get_database_cluster")
+}
+
+func (h *Handler) CreateDatabaseCluster(createRequest *godo.DatabaseCreateRequest, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if !slices.Contains(databaseEngines, createRequest.EngineSlug) {
+		return h.HandleError(fmt.Errorf("unsupported engine %q (expected one of %s)", createRequest.EngineSlug, strings.Join(databaseEngines, ", ")), "create_database_cluster")
+	}
+	if createRequest.NumNodes == 0 {
+		createRequest.NumNodes = 1
+	}
+	if err := validateTrustedSources(createRequest.Rules); err != nil {
+		return h.HandleError(err, "create_database_cluster")
+	}
+
+	cluster, _, err := client.Databases.Create(context.Background(), createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_database_cluster")
+	}
+
+	if !showCredentials {
+		maskDatabase(cluster)
+	}
+
+	return h.HandleSuccess(cluster, "create_database_cluster")
+}
+
+func (h *Handler) ResizeDatabaseCluster(clusterID string, resize DatabaseResize) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if resize.Size == "" && resize.NumNodes == nil && resize.StorageSizeMib == nil {
+		return h.HandleError(fmt.Errorf("nothing to resize: set size, num_nodes or storage_size_mib"), "resize_database_cluster")
+	}
+
+	// The API needs size and node count on every resize, so start from the
+	// cluster's current settings
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "resize_database_cluster")
+	}
+
+	resizeRequest := &godo.DatabaseResizeRequest{
+		SizeSlug: cluster.SizeSlug,
+		NumNodes: cluster.NumNodes,
+	}
+	if resize.Size != "" {
+		resizeRequest.SizeSlug = resize.Size
+	}
+	if resize.NumNodes != nil {
+		resizeRequest.NumNodes = *resize.NumNodes
+	}
+	if resize.StorageSizeMib != nil {
+		resizeRequest.StorageSizeMib = *resize.StorageSizeMib
+	}
+
+	_, err = client.Databases.Resize(context.Background(), clusterID, resizeRequest)
+	if err != nil {
+		return h.HandleError(err, "resize_database_cluster")
+	}
+
+	cluster, _, err = client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "resize_database_cluster")
+	}
+	maskDatabase(cluster)
+
+	return h.HandleSuccess(cluster, "resize_database_cluster")
+}
+
+func (h *Handler) DeleteDatabaseCluster(clusterID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Databases.Delete(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "delete_database_cluster")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Database cluster %s deleted successfully", clusterID),
+	}, "delete_database_cluster")
+}
+
+// PreviewDeleteDatabaseCluster lists the databases, pools and read replicas
+// that go with the cluster.
+func (h *Handler) PreviewDeleteDatabaseCluster(clusterID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "database_cluster",
+		ID:           cluster.ID,
+		Name:         cluster.Name,
+		Region:       cluster.RegionSlug,
+		Warnings:     []string{"All data, users and backups of the cluster are permanently deleted"},
+	}
+	for _, name := range cluster.DBNames {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "database",
+			"name": name,
+		})
+	}
+
+	pools, err := listPools(client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range pools {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "connection_pool",
+			"name": pool.Name,
+		})
+	}
+
+	var replicas []godo.DatabaseReplica
+	if slices.Contains(replicaEngines, cluster.EngineSlug) {
+		replicas, _, err = client.Databases.ListReplicas(context.Background(), clusterID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, replica := range replicas {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":   "read_replica",
+			"name":   replica.Name,
+			"region": replica.Region,
+		})
+	}
+	if len(replicas) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d read replica(s) are deleted with the cluster", len(replicas)))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListDatabases(clusterID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	dbs, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DatabaseDB, *godo.Response, error) {
+		return client.Databases.ListDBs(context.Background(), clusterID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_databases")
+	}
+
+	return h.HandleSuccess(listResult("dbs", dbs, meta), "list_databases")
+}
+
+func (h *Handler) CreateDatabase(clusterID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	db, _, err := client.Databases.CreateDB(context.Background(), clusterID, &godo.DatabaseCreateDBRequest{Name: name})
+	if err != nil {
+		return h.HandleError(err, "create_database")
+	}
+
+	return h.HandleSuccess(db, "create_database")
+}
+
+func (h *Handler) DeleteDatabase(clusterID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Databases.DeleteDB(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "delete_database")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Database %s deleted successfully from cluster %s", name, clusterID),
+	}, "delete_database")
+}
+
+// PreviewDeleteDatabase warns about connection pools that serve the database.
+func (h *Handler) PreviewDeleteDatabase(clusterID, name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(cluster.DBNames, name) {
+		return nil, fmt.Errorf("database %s not found in cluster %s", name, cluster.Name)
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "database",
+		ID:           name,
+		Name:         name,
+		Region:       cluster.RegionSlug,
+		Warnings:     []string{fmt.Sprintf("All tables and data in %s on cluster %s are permanently deleted", name, cluster.Name)},
+	}
+	pools, err := listPools(client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range pools {
+		if pool.Database == name {
+			preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+				"type": "connection_pool",
+				"name": pool.Name,
+			})
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Connection pool %s serves this database and stops working", pool.Name))
+		}
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListDatabaseUsers(clusterID string, page, perPage int, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	users, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DatabaseUser, *godo.Response, error) {
+		return client.Databases.ListUsers(context.Background(), clusterID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_database_users")
+	}
+
+	if !showCredentials {
+		for i := range users {
+			maskDatabaseUser(&users[i])
+		}
+	}
+
+	return h.HandleSuccess(listResult("users", users, meta), "list_database_users")
+}
+
+func (h *Handler) GetDatabaseUser(clusterID, name string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	user, _, err := client.Databases.GetUser(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "get_database_user")
+	}
+
+	if !showCredentials {
+		maskDatabaseUser(user)
+	}
+
+	return h.HandleSuccess(user, "get_database_user")
+}
+
+// CreateDatabaseUser adds a user. mysqlAuthPlugin selects the MySQL password
+// plugin (mysql_native_password or caching_sha2_password) and is ignored by
+// other engines.
+func (h *Handler) CreateDatabaseUser(clusterID, name, mysqlAuthPlugin string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	createRequest := &godo.DatabaseCreateUserRequest{Name: name}
+	if mysqlAuthPlugin != "" {
+		createRequest.MySQLSettings = &godo.DatabaseMySQLUserSettings{AuthPlugin: mysqlAuthPlugin}
+	}
+
+	user, _, err := client.Databases.CreateUser(context.Background(), clusterID, createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_database_user")
+	}
+
+	if !showCredentials {
+		maskDatabaseUser(user)
+	}
+
+	return h.HandleSuccess(user, "create_database_user")
+}
+
+func (h *Handler) ResetDatabaseUserPassword(clusterID, name string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	user, _, err := client.Databases.ResetUserAuth(context.Background(), clusterID, name, &godo.DatabaseResetUserAuthRequest{})
+	if err != nil {
+		return h.HandleError(err, "reset_database_user_password")
+	}
+
+	if !showCredentials {
+		maskDatabaseUser(user)
+	}
+
+	return h.HandleSuccess(user, "reset_database_user_password")
+}
+
+func (h *Handler) DeleteDatabaseUser(clusterID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Databases.DeleteUser(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "delete_database_user")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("User %s deleted successfully from cluster %s", name, clusterID),
+	}, "delete_database_user")
+}
+
+// PreviewDeleteDatabaseUser warns about connection pools that log in as the
+// user.
+func (h *Handler) PreviewDeleteDatabaseUser(clusterID, name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	user, _, err := client.Databases.GetUser(context.Background(), clusterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "database_user",
+		ID:           user.Name,
+		Name:         user.Name,
+		Warnings:     []string{fmt.Sprintf("Clients that log in as %s can no longer connect", user.Name)},
+	}
+	if user.Role == "primary" {
+		preview.Warnings = append(preview.Warnings, "This is the cluster's primary user; the API refuses to delete it")
+	}
+	pools, err := listPools(client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range pools {
+		if pool.User == name {
+			preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+				"type": "connection_pool",
+				"name": pool.Name,
+			})
+		}
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListDatabasePools(clusterID string, page, perPage int, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	pools, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DatabasePool, *godo.Response, error) {
+		return client.Databases.ListPools(context.Background(), clusterID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_database_pools")
+	}
+
+	if !showCredentials {
+		for i := range pools {
+			maskDatabasePool(&pools[i])
+		}
+	}
+
+	return h.HandleSuccess(listResult("pools", pools, meta), "list_database_pools")
+}
+
+func (h *Handler) CreateDatabasePool(clusterID string, createRequest *godo.DatabaseCreatePoolRequest, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validatePoolSettings(createRequest.Mode, createRequest.Size); err != nil {
+		return h.HandleError(err, "create_database_pool")
+	}
+
+	pool, _, err := client.Databases.CreatePool(context.Background(), clusterID, createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_database_pool")
+	}
+
+	if !showCredentials {
+		maskDatabasePool(pool)
+	}
+
+	return h.HandleSuccess(pool, "create_database_pool")
+}
+
+func (h *Handler) UpdateDatabasePool(clusterID, name string, update DatabasePoolUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	// The API replaces every pool setting, so start from the current ones
+	pool, _, err := client.Databases.GetPool(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "update_database_pool")
+	}
+
+	updateRequest := &godo.DatabaseUpdatePoolRequest{
+		User:     pool.User,
+		Size:     pool.Size,
+		Database: pool.Database,
+		Mode:     pool.Mode,
+	}
+	if update.User != "" {
+		updateRequest.User = update.User
+	}
+	if update.Database != "" {
+		updateRequest.Database = update.Database
+	}
+	if update.Mode != "" {
+		updateRequest.Mode = update.Mode
+	}
+	if update.Size != nil {
+		updateRequest.Size = *update.Size
+	}
+	if err := validatePoolSettings(updateRequest.Mode, updateRequest.Size); err != nil {
+		return h.HandleError(err, "update_database_pool")
+	}
+
+	_, err = client.Databases.UpdatePool(context.Background(), clusterID, name, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_database_pool")
+	}
+
+	pool, _, err = client.Databases.GetPool(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "update_database_pool")
+	}
+	maskDatabasePool(pool)
+
+	return h.HandleSuccess(pool, "update_database_pool")
+}
+
+func (h *Handler) DeleteDatabasePool(clusterID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Databases.DeletePool(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "delete_database_pool")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Connection pool %s deleted successfully from cluster %s", name, clusterID),
+	}, "delete_database_pool")
+}
+
+func (h *Handler) PreviewDeleteDatabasePool(clusterID, name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	pool, _, err := client.Databases.GetPool(context.Background(), clusterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletionPreview{
+		ResourceType: "connection_pool",
+		ID:           pool.Name,
+		Name:         pool.Name,
+		Warnings:     []string{fmt.Sprintf("Clients connecting through %s (database %s, user %s) are disconnected", pool.Name, pool.Database, pool.User)},
+	}, nil
+}
+
+func (h *Handler) ListDatabaseReplicas(clusterID string, page, perPage int, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	replicas, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DatabaseReplica, *godo.Response, error) {
+		return client.Databases.ListReplicas(context.Background(), clusterID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_database_replicas")
+	}
+
+	if !showCredentials {
+		for i := range replicas {
+			maskDatabaseReplica(&replicas[i])
+		}
+	}
+
+	return h.HandleSuccess(listResult("replicas", replicas, meta), "list_database_replicas")
+}
+
+func (h *Handler) CreateDatabaseReplica(clusterID string, createRequest *godo.DatabaseCreateReplicaRequest, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	replica, _, err := client.Databases.CreateReplica(context.Background(), clusterID, createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_database_replica")
+	}
+
+	if !showCredentials {
+		maskDatabaseReplica(replica)
+	}
+
+	return h.HandleSuccess(replica, "create_database_replica")
+}
+
+func (h *Handler) DeleteDatabaseReplica(clusterID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Databases.DeleteReplica(context.Background(), clusterID, name)
+	if err != nil {
+		return h.HandleError(err, "delete_database_replica")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Read replica %s deleted successfully from cluster %s", name, clusterID),
+	}, "delete_database_replica")
+}
+
+func (h *Handler) PreviewDeleteDatabaseReplica(clusterID, name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	replica, _, err := client.Databases.GetReplica(context.Background(), clusterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletionPreview{
+		ResourceType: "read_replica",
+		ID:           replica.ID,
+		Name:         replica.Name,
+		Region:       replica.Region,
+		Warnings:     []string{fmt.Sprintf("Read traffic sent to %s fails until clients are pointed elsewhere", replica.Name)},
+	}, nil
+}
+
+func (h *Handler) ListDatabaseBackups(clusterID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	backups, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DatabaseBackup, *godo.Response, error) {
+		return client.Databases.ListBackups(context.Background(), clusterID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_database_backups")
+	}
+
+	return h.HandleSuccess(listResult("backups", backups, meta), "list_database_backups")
+}
+
+// UpdateDatabaseMaintenanceWindow moves the weekly maintenance window. day is
+// a weekday name and hour is HH:MM in UTC.
+func (h *Handler) UpdateDatabaseMaintenanceWindow(clusterID, day, hour string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	day = strings.ToLower(day)
+	if !slices.Contains(maintenanceDays, day) {
+		return h.HandleError(fmt.Errorf("day %q must be a weekday name such as 'sunday'", day), "update_database_maintenance_window")
+	}
+	if !maintenanceHourPattern.MatchString(hour) {
+		return h.HandleError(fmt.Errorf("hour %q must be in 24-hour HH:MM format", hour), "update_database_maintenance_window")
+	}
+
+	_, err := client.Databases.UpdateMaintenance(context.Background(), clusterID, &godo.DatabaseUpdateMaintenanceRequest{Day: day, Hour: hour})
+	if err != nil {
+		return h.HandleError(err, "update_database_maintenance_window")
+	}
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "update_database_maintenance_window")
+	}
+
+	return h.HandleSuccess(cluster.MaintenanceWindow, "update_database_maintenance_window")
+}
+
+func (h *Handler) GetDatabaseFirewallRules(clusterID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	rules, _, err := client.Databases.GetFirewallRules(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "get_database_firewall_rules")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"rules": rules}, "get_database_firewall_rules")
+}
+
+// UpdateDatabaseFirewallRules replaces the cluster's trusted sources. An empty
+// list allows connections from anywhere.
+func (h *Handler) UpdateDatabaseFirewallRules(clusterID string, rules []*godo.DatabaseCreateFirewallRule) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateTrustedSources(rules); err != nil {
+		return h.HandleError(err, "update_database_firewall_rules")
+	}
+
+	updateRequest := &godo.DatabaseUpdateFirewallRulesRequest{Rules: []*godo.DatabaseFirewallRule{}}
+	for _, rule := range rules {
+		updateRequest.Rules = append(updateRequest.Rules, &godo.DatabaseFirewallRule{Type: rule.Type, Value: rule.Value})
+	}
+
+	_, err := client.Databases.UpdateFirewallRules(context.Background(), clusterID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_database_firewall_rules")
+	}
+
+	updated, _, err := client.Databases.GetFirewallRules(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "update_database_firewall_rules")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"rules": updated}, "update_database_firewall_rules")
+}
+
+func (h *Handler) GetDatabaseConfig(clusterID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "get_database_config")
+	}
+
+	config, err := getDatabaseConfig(client, cluster)
+	if err != nil {
+		return h.HandleError(err, "get_database_config")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"engine": cluster.EngineSlug,
+		"config": config,
+	}, "get_database_config")
+}
+
+// UpdateDatabaseConfig changes the given engine settings and leaves the rest
+// alone. Keys are checked against the engine's known settings before anything
+// is sent.
+func (h *Handler) UpdateDatabaseConfig(clusterID string, config map[string]interface{}) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if len(config) == 0 {
+		return h.HandleError(fmt.Errorf("config must contain at least one setting"), "update_database_config")
+	}
+
+	cluster, _, err := client.Databases.Get(context.Background(), clusterID)
+	if err != nil {
+		return h.HandleError(err, "update_database_config")
+	}
+
+	if err := updateDatabaseConfig(client, cluster, config); err != nil {
+		return h.HandleError(err, "update_database_config")
+	}
+
+	updated, err := getDatabaseConfig(client, cluster)
+	if err != nil {
+		return h.HandleError(err, "update_database_config")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"engine": cluster.EngineSlug,
+		"config": updated,
+	}, "update_database_config")
+}
+
+// getDatabaseConfig fetches the engine config with godo's typed call for the
+// engine. Engines godo has no type for are read as plain JSON.
+func getDatabaseConfig(client *godo.Client, cluster *godo.Database) (interface{}, error) {
+	ctx := context.Background()
+	var config interface{}
+	var err error
+	switch cluster.EngineSlug {
+	case "pg":
+		config, _, err = client.Databases.GetPostgreSQLConfig(ctx, cluster.ID)
+	case "mysql":
+		config, _, err = client.Databases.GetMySQLConfig(ctx, cluster.ID)
+	case "redis":
+		config, _, err = client.Databases.GetRedisConfig(ctx, cluster.ID)
+	case "mongodb":
+		config, _, err = client.Databases.GetMongoDBConfig(ctx, cluster.ID)
+	case "kafka":
+		config, _, err = client.Databases.GetKafkaConfig(ctx, cluster.ID)
+	case "opensearch":
+		config, _, err = client.Databases.GetOpensearchConfig(ctx, cluster.ID)
+	default:
+		var root struct {
+			Config map[string]interface{} `json:"config"`
+		}
+		err = databaseConfigRequest(client, http.MethodGet, cluster.ID, nil, &root)
+		config = root.Config
+	}
+	return config, err
+}
+
+// updateDatabaseConfig decodes config into the engine's typed config, which
+// rejects unknown keys, and sends it with the matching godo call.
+func updateDatabaseConfig(client *godo.Client, cluster *godo.Database, config map[string]interface{}) error {
+	ctx := context.Background()
+	var err error
+	switch cluster.EngineSlug {
+	case "pg":
+		typed := &godo.PostgreSQLConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdatePostgreSQLConfig(ctx, cluster.ID, typed)
+		}
+	case "mysql":
+		typed := &godo.MySQLConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdateMySQLConfig(ctx, cluster.ID, typed)
+		}
+	case "redis":
+		typed := &godo.RedisConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdateRedisConfig(ctx, cluster.ID, typed)
+		}
+	case "mongodb":
+		typed := &godo.MongoDBConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdateMongoDBConfig(ctx, cluster.ID, typed)
+		}
+	case "kafka":
+		typed := &godo.KafkaConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdateKafkaConfig(ctx, cluster.ID, typed)
+		}
+	case "opensearch":
+		typed := &godo.OpensearchConfig{}
+		if err = decodeDatabaseConfig(config, typed); err == nil {
+			_, err = client.Databases.UpdateOpensearchConfig(ctx, cluster.ID, typed)
+		}
+	default:
+		err = databaseConfigRequest(client, http.MethodPatch, cluster.ID, map[string]interface{}{"config": config}, nil)
+	}
+	return err
+}
+
+func decodeDatabaseConfig(config map[string]interface{}, typed interface{}) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(typed); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+// databaseConfigRequest calls the config endpoint directly for engines godo
+// has no typed config for.
+func databaseConfigRequest(client *godo.Client, method, clusterID string, body, v interface{}) error {
+	req, err := client.NewRequest(context.Background(), method, fmt.Sprintf("/v2/databases/%s/config", clusterID), body)
+	if err != nil {
+		return err
+	}
+	_, err = client.Do(context.Background(), req, v)
+	return err
+}
+
+// listPools returns the cluster's connection pools. Only PostgreSQL clusters
+// have them.
+func listPools(client *godo.Client, cluster *godo.Database) ([]godo.DatabasePool, error) {
+	if cluster.EngineSlug != "pg" {
+		return nil, nil
+	}
+	pools, _, err := client.Databases.ListPools(context.Background(), cluster.ID, nil)
+	return pools, err
+}
+
+func validatePoolSettings(mode string, size int) error {
+	if !slices.Contains(databasePoolModes, mode) {
+		return fmt.Errorf("mode %q must be one of %s", mode, strings.Join(databasePoolModes, ", "))
+	}
+	if size < 1 {
+		return fmt.Errorf("size must be at least 1, got %d", size)
+	}
+	return nil
+}
+
+func validateTrustedSources(rules []*godo.DatabaseCreateFirewallRule) error {
+	for _, rule := range rules {
+		if !slices.Contains(databaseTrustedSources, rule.Type) {
+			return fmt.Errorf("unsupported trusted source type %q (expected one of %s)", rule.Type, strings.Join(databaseTrustedSources, ", "))
+		}
+		if rule.Value == "" {
+			return fmt.Errorf("a value is required for the %s trusted source", rule.Type)
+		}
+	}
+	return nil
+}
+
+func maskDatabase(db *godo.Database) {
+	maskConnection(db.Connection)
+	maskConnection(db.PrivateConnection)
+	maskConnection(db.StandbyConnection)
+	maskConnection(db.StandbyPrivateConnection)
+	maskConnection(db.UIConnection)
+	for i := range db.Users {
+		maskDatabaseUser(&db.Users[i])
+	}
+}
+
+func maskDatabaseUser(user *godo.DatabaseUser) {
+	user.Password = maskIfSet(user.Password)
+	user.AccessCert = maskIfSet(user.AccessCert)
+	user.AccessKey = maskIfSet(user.AccessKey)
+}
+
+func maskDatabasePool(pool *godo.DatabasePool) {
+	maskConnection(pool.Connection)
+	maskConnection(pool.PrivateConnection)
+	maskConnection(pool.StandbyConnection)
+	maskConnection(pool.StandbyPrivateConnection)
+}
+
+func maskDatabaseReplica(replica *godo.DatabaseReplica) {
+	maskConnection(replica.Connection)
+	maskConnection(replica.PrivateConnection)
+}
+
+// maskConnection hides the password, including the copy embedded in the
+// connection URI.
+func maskConnection(connection *godo.DatabaseConnection) {
+	if connection == nil {
+		return
+	}
+	connection.URI = maskConnectionURI(connection.URI, connection.Password)
+	connection.Password = maskIfSet(connection.Password)
+}
+
+// maskConnectionURI masks the password in the userinfo of uri. It fails
+// closed: a URI that cannot be parsed, or that still contains password after
+// masking, is replaced as a whole.
+func maskConnectionURI(uri, password string) string {
+	if uri == "" {
+		return ""
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return maskedCredential
+	}
+
+	masked := uri
+	if parsed.User != nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			// Replace the userinfo in place so the mask is not percent-encoded
+			userinfo := parsed.User.String() + "@"
+			maskedUserinfo := url.User(parsed.User.Username()).String() + ":" + maskedCredential + "@"
+			if strings.Contains(uri, userinfo) {
+				masked = strings.Replace(uri, userinfo, maskedUserinfo, 1)
+			} else {
+				parsed.User = url.UserPassword(parsed.User.Username(), maskedCredential)
+				masked = parsed.String()
+			}
+		}
+	}
+
+	if password != "" && (strings.Contains(masked, password) || strings.Contains(masked, url.QueryEscape(password))) {
+		return maskedCredential
+	}
+	return masked
+}
+
+func maskIfSet(value string) string {
+	if value == "" {
+		return ""
+	}
+	return maskedCredential
+}
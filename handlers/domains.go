@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// domainRecordTypes are the record types the DNS tools manage. SOA records
+// exist on every domain but are maintained by DigitalOcean.
+var domainRecordTypes = []string{"A", "AAAA", "CAA", "CNAME", "MX", "NS", "SRV", "TXT"}
+
+var caaTags = []string{"issue", "issuewild", "iodef"}
+
+// DomainRecordUpdate holds the record fields to change. Empty strings and nil
+// pointers keep the current value.
+type DomainRecordUpdate struct {
+	Name     string
+	Data     string
+	TTL      *int
+	Priority *int
+	Port     *int
+	Weight   *int
+	Flags    *int
+	Tag      string
+}
+
+func (h *Handler) ListDomains(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	domains, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Domain, *godo.Response, error) {
+		return client.Domains.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_domains")
+	}
+
+	// Zone files can be long, so they are only returned by get_domain
+	simplifiedDomains := make([]map[string]interface{}, len(domains))
+	for i, domain := range domains {
+		simplifiedDomains[i] = map[string]interface{}{
+			"name": domain.Name,
+			"ttl":  domain.TTL,
+		}
+	}
+
+	return h.HandleSuccess(listResult("domains", simplifiedDomains, meta), "list_domains")
+}
+
+func (h *Handler) GetDomain(name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	domain, _, err := client.Domains.Get(context.Background(), name)
+	if err != nil {
+		return h.HandleError(err, "get_domain")
+	}
+
+	return h.HandleSuccess(domain, "get_domain")
+}
+
+// CreateDomain adds a domain to DigitalOcean DNS. With an IP address an A
+// record for the apex is created as well.
+func (h *Handler) CreateDomain(name, ipAddress, projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := h.checkProject(projectID); err != nil {
+		return h.HandleError(err, "create_domain")
+	}
+
+	createRequest := &godo.DomainCreateRequest{
+		Name:      name,
+		IPAddress: ipAddress,
+	}
+
+	domain, _, err := client.Domains.Create(context.Background(), createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_domain")
+	}
+	if err := h.assignToProject(projectID, domain); err != nil {
+		return h.HandleError(err, "create_domain")
+	}
+
+	return h.HandleSuccess(domain, "create_domain")
+}
+
+func (h *Handler) DeleteDomain(name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Domains.Delete(context.Background(), name)
+	if err != nil {
+		return h.HandleError(err, "delete_domain")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Domain %s deleted successfully", name),
+	}, "delete_domain")
+}
+
+// ListDomainRecords lists the records of a domain, optionally only those with
+// the given type and name. The name may be relative ("www", "@") or fully
+// qualified ("www.example.com").
+func (h *Handler) ListDomainRecords(domain, name, recordType string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	recordType = strings.ToUpper(recordType)
+	if recordType != "" && recordType != "SOA" && !slices.Contains(domainRecordTypes, recordType) {
+		return h.HandleError(fmt.Errorf("unsupported record type %q (expected one of %s)", recordType, strings.Join(domainRecordTypes, ", ")), "list_domain_records")
+	}
+	fqdn := qualifyRecordName(name, domain)
+
+	records, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.DomainRecord, *godo.Response, error) {
+		ctx := context.Background()
+		switch {
+		case recordType != "" && fqdn != "":
+			return client.Domains.RecordsByTypeAndName(ctx, domain, recordType, fqdn, opt)
+		case recordType != "":
+			return client.Domains.RecordsByType(ctx, domain, recordType, opt)
+		case fqdn != "":
+			return client.Domains.RecordsByName(ctx, domain, fqdn, opt)
+		}
+		return client.Domains.Records(ctx, domain, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_domain_records")
+	}
+
+	return h.HandleSuccess(listResult("domain_records", records, meta), "list_domain_records")
+}
+
+func (h *Handler) GetDomainRecord(domain string, recordID int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	record, _, err := client.Domains.Record(context.Background(), domain, recordID)
+	if err != nil {
+		return h.HandleError(err, "get_domain_record")
+	}
+
+	return h.HandleSuccess(record, "get_domain_record")
+}
+
+func (h *Handler) CreateDomainRecord(domain string, record godo.DomainRecordEditRequest) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	record.Type = strings.ToUpper(record.Type)
+	if err := validateDomainRecord(record); err != nil {
+		return h.HandleError(err, "create_domain_record")
+	}
+
+	created, _, err := client.Domains.CreateRecord(context.Background(), domain, &record)
+	if err != nil {
+		return h.HandleError(err, "create_domain_record")
+	}
+
+	return h.HandleSuccess(created, "create_domain_record")
+}
+
+// UpdateDomainRecord changes the given fields of a record. The record type
+// cannot be changed; delete the record and create a new one instead.
+func (h *Handler) UpdateDomainRecord(domain string, recordID int, update DomainRecordUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	// The API replaces the whole record, so start from its current values
+	record, _, err := client.Domains.Record(context.Background(), domain, recordID)
+	if err != nil {
+		return h.HandleError(err, "update_domain_record")
+	}
+
+	editRequest := godo.DomainRecordEditRequest{
+		Type:     record.Type,
+		Name:     record.Name,
+		Data:     record.Data,
+		Priority: record.Priority,
+		Port:     record.Port,
+		TTL:      record.TTL,
+		Weight:   record.Weight,
+		Flags:    record.Flags,
+		Tag:      record.Tag,
+	}
+	if update.Name != "" {
+		editRequest.Name = update.Name
+	}
+	if update.Data != "" {
+		editRequest.Data = update.Data
+	}
+	if update.Tag != "" {
+		editRequest.Tag = update.Tag
+	}
+	if update.TTL != nil {
+		editRequest.TTL = *update.TTL
+	}
+	if update.Priority != nil {
+		editRequest.Priority = *update.Priority
+	}
+	if update.Port != nil {
+		editRequest.Port = *update.Port
+	}
+	if update.Weight != nil {
+		editRequest.Weight = *update.Weight
+	}
+	if update.Flags != nil {
+		editRequest.Flags = *update.Flags
+	}
+	if err := validateDomainRecord(editRequest); err != nil {
+		return h.HandleError(err, "update_domain_record")
+	}
+
+	updated, _, err := client.Domains.EditRecord(context.Background(), domain, recordID, &editRequest)
+	if err != nil {
+		return h.HandleError(err, "update_domain_record")
+	}
+
+	return h.HandleSuccess(updated, "update_domain_record")
+}
+
+func (h *Handler) DeleteDomainRecord(domain string, recordID int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Domains.DeleteRecord(context.Background(), domain, recordID)
+	if err != nil {
+		return h.HandleError(err, "delete_domain_record")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Record %d deleted from domain %s successfully", recordID, domain),
+	}, "delete_domain_record")
+}
+
+func (h *Handler) PreviewDeleteDomain(name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	domain, _, err := client.Domains.Get(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+	records, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.DomainRecord, *godo.Response, error) {
+		return client.Domains.Records(context.Background(), name, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "domain",
+		ID:           domain.Name,
+		Name:         domain.Name,
+	}
+	for _, record := range records {
+		if record.Type == "SOA" || record.Type == "NS" {
+			continue
+		}
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":        "domain_record",
+			"id":          record.ID,
+			"record_type": record.Type,
+			"name":        qualifyRecordName(record.Name, name),
+			"data":        record.Data,
+		})
+	}
+	if len(preview.AttachedResources) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d record(s) will be deleted and stop resolving", len(preview.AttachedResources)))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) PreviewDeleteDomainRecord(domain string, recordID int) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	record, _, err := client.Domains.Record(context.Background(), domain, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	fqdn := qualifyRecordName(record.Name, domain)
+	preview := &DeletionPreview{
+		ResourceType: "domain_record",
+		ID:           strconv.Itoa(record.ID),
+		Name:         fqdn,
+		Warnings: []string{
+			fmt.Sprintf("%s lookups for %s will no longer return %s", record.Type, fqdn, record.Data),
+		},
+	}
+	if record.Type == "NS" && fqdn == domain {
+		preview.Warnings = append(preview.Warnings, "This is a name server record for the domain apex; removing it can break resolution of the whole domain")
+	}
+
+	return preview, nil
+}
+
+// validateDomainRecord catches mistakes the API reports with less helpful
+// messages.
+func validateDomainRecord(record godo.DomainRecordEditRequest) error {
+	if !slices.Contains(domainRecordTypes, record.Type) {
+		return fmt.Errorf("unsupported record type %q (expected one of %s)", record.Type, strings.Join(domainRecordTypes, ", "))
+	}
+	if record.Data == "" {
+		return fmt.Errorf("data is required")
+	}
+	if record.Type == "SRV" && record.Port == 0 {
+		return fmt.Errorf("SRV records require a port")
+	}
+	if record.Type == "CAA" && !slices.Contains(caaTags, record.Tag) {
+		return fmt.Errorf("CAA records require a tag of %s", strings.Join(caaTags, ", "))
+	}
+	return nil
+}
+
+// qualifyRecordName turns a record name relative to domain into the fully
+// qualified name the API filters on. "@" stands for the domain itself.
+func qualifyRecordName(name, domain string) string {
+	name = strings.TrimSuffix(name, ".")
+	switch {
+	case name == "":
+		return ""
+	case name == "@" || name == domain:
+		return domain
+	case strings.HasSuffix(name, "."+domain):
+		return name
+	}
+	return name + "." + domain
+}
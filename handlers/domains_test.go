@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+// seedDomain adds example.com with a handful of records and returns their IDs
+// in order: apex A, www A, www AAAA, mail MX, apex TXT.
+func seedDomain(fake *fakedo.Server) []int {
+	return fake.AddDomain("example.com",
+		godo.DomainRecord{Type: "A", Name: "@", Data: "203.0.113.10"},
+		godo.DomainRecord{Type: "A", Name: "www", Data: "203.0.113.10"},
+		godo.DomainRecord{Type: "AAAA", Name: "www", Data: "2001:db8::10"},
+		godo.DomainRecord{Type: "MX", Name: "@", Data: "mail.example.com.", Priority: 10},
+		godo.DomainRecord{Type: "TXT", Name: "@", Data: "v=spf1 -all"},
+	)
+}
+
+func TestDomains(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var domain godo.Domain
+	resp, err := h.CreateDomain("example.org", "198.51.100.7", "")
+	decodeResponse(t, resp, err, &domain)
+	if domain.Name != "example.org" {
+		t.Errorf("created domain = %+v", domain)
+	}
+
+	resp, err = h.CreateDomain("example.org", "", "")
+	expectError(t, resp, err, "create_domain", "422", "already exists")
+
+	resp, err = h.GetDomain("example.org")
+	decodeResponse(t, resp, err, &domain)
+	if !strings.Contains(domain.ZoneFile, "@\t1800\tIN\tA\t198.51.100.7") || !strings.Contains(domain.ZoneFile, "ns1.digitalocean.com") {
+		t.Errorf("zone file = %q", domain.ZoneFile)
+	}
+
+	seedDomain(fake)
+	var listed []map[string]interface{}
+	resp, err = h.ListDomains(0, 0)
+	decodeList(t, resp, err, "domains", &listed)
+	if len(listed) != 2 || len(listed[0]) != 2 {
+		t.Errorf("listed domains = %v", listed)
+	}
+
+	resp, err = h.DeleteDomain("example.org")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if _, ok := fake.Domains.Get("example.org"); ok {
+		t.Errorf("domain was not deleted")
+	}
+	if records := fake.DomainRecords.Filter(func(r fakedo.DomainRecord) bool { return r.Domain == "example.org" }); len(records) != 0 {
+		t.Errorf("%d records of the deleted domain remain", len(records))
+	}
+
+	resp, err = h.GetDomain("example.org")
+	expectError(t, resp, err, "get_domain", "404")
+}
+
+func TestListDomainRecords(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordName string
+		recordType string
+		want       []string
+		wantErr    string
+	}{
+		{name: "all", want: []string{"A @", "A www", "AAAA www", "MX @", "TXT @"}},
+		{name: "by type", recordType: "a", want: []string{"A @", "A www"}},
+		{name: "by relative name", recordName: "www", want: []string{"A www", "AAAA www"}},
+		{name: "by qualified name", recordName: "www.example.com", want: []string{"A www", "AAAA www"}},
+		{name: "apex by type", recordName: "@", recordType: "MX", want: []string{"MX @"}},
+		{name: "no match", recordName: "api", want: []string{}},
+		{name: "unsupported type", recordType: "PTR", wantErr: `unsupported record type "PTR"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedDomain(fake)
+
+			var records []godo.DomainRecord
+			resp, err := h.ListDomainRecords("example.com", tt.recordName, tt.recordType, 0, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "list_domain_records", tt.wantErr)
+				return
+			}
+			decodeList(t, resp, err, "domain_records", &records)
+			got := make([]string, len(records))
+			for i, record := range records {
+				got[i] = record.Type + " " + record.Name
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("records = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateDomainRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  godo.DomainRecordEditRequest
+		wantErr string
+	}{
+		{name: "A", record: godo.DomainRecordEditRequest{Type: "A", Name: "api", Data: "203.0.113.20"}},
+		{name: "lowercase type", record: godo.DomainRecordEditRequest{Type: "cname", Name: "docs", Data: "example.github.io."}},
+		{name: "SRV", record: godo.DomainRecordEditRequest{Type: "SRV", Name: "_sip._tcp", Data: "sip.example.com.", Priority: 10, Weight: 5, Port: 5060}},
+		{name: "CAA", record: godo.DomainRecordEditRequest{Type: "CAA", Name: "@", Data: "letsencrypt.org.", Tag: "issue"}},
+		{name: "NS", record: godo.DomainRecordEditRequest{Type: "NS", Name: "sub", Data: "ns1.example.net."}},
+		{name: "unsupported type", record: godo.DomainRecordEditRequest{Type: "SOA", Name: "@", Data: "1800"}, wantErr: "unsupported record type"},
+		{name: "no data", record: godo.DomainRecordEditRequest{Type: "TXT", Name: "@"}, wantErr: "data is required"},
+		{name: "SRV without port", record: godo.DomainRecordEditRequest{Type: "SRV", Name: "_sip._tcp", Data: "sip.example.com."}, wantErr: "SRV records require a port"},
+		{name: "CAA with bad tag", record: godo.DomainRecordEditRequest{Type: "CAA", Name: "@", Data: "letsencrypt.org.", Tag: "issuer"}, wantErr: "CAA records require a tag"},
+		{name: "no name", record: godo.DomainRecordEditRequest{Type: "A", Data: "203.0.113.20"}, wantErr: "422"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedDomain(fake)
+
+			resp, err := h.CreateDomainRecord("example.com", tt.record)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_domain_record", tt.wantErr)
+				return
+			}
+			var record godo.DomainRecord
+			decodeResponse(t, resp, err, &record)
+			stored, ok := fake.DomainRecords.Get(record.ID)
+			if !ok || stored.Domain != "example.com" || stored.Type != strings.ToUpper(tt.record.Type) || stored.TTL != 1800 {
+				t.Errorf("stored record = %+v", stored)
+			}
+		})
+	}
+
+	h, _ := newTestHandler(t)
+	resp, err := h.CreateDomainRecord("missing.com", godo.DomainRecordEditRequest{Type: "A", Name: "@", Data: "203.0.113.20"})
+	expectError(t, resp, err, "create_domain_record", "404")
+}
+
+func TestUpdateDomainRecord(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDomain(fake)
+	ttl, priority := 300, 20
+
+	var record godo.DomainRecord
+	resp, err := h.UpdateDomainRecord("example.com", ids[3], DomainRecordUpdate{TTL: &ttl, Priority: &priority})
+	decodeResponse(t, resp, err, &record)
+	if record.TTL != 300 || record.Priority != 20 || record.Data != "mail.example.com." || record.Name != "@" {
+		t.Errorf("updated record = %+v", record)
+	}
+
+	resp, err = h.UpdateDomainRecord("example.com", ids[1], DomainRecordUpdate{Data: "203.0.113.11"})
+	decodeResponse(t, resp, err, &record)
+	if stored, _ := fake.DomainRecords.Get(ids[1]); stored.Data != "203.0.113.11" || stored.Name != "www" || stored.TTL != 1800 {
+		t.Errorf("stored record = %+v", stored)
+	}
+
+	resp, err = h.UpdateDomainRecord("example.com", ids[1]+100, DomainRecordUpdate{Data: "203.0.113.11"})
+	expectError(t, resp, err, "update_domain_record", "404")
+
+	fake.FailNext("PUT", "/v2/domains/example.com/records/"+strconv.Itoa(ids[0]), http.StatusServiceUnavailable, "dns service degraded")
+	resp, err = h.UpdateDomainRecord("example.com", ids[0], DomainRecordUpdate{Data: "203.0.113.12"})
+	expectError(t, resp, err, "update_domain_record", "503", "dns service degraded")
+}
+
+func TestGetAndDeleteDomainRecord(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDomain(fake)
+
+	var record godo.DomainRecord
+	resp, err := h.GetDomainRecord("example.com", ids[2])
+	decodeResponse(t, resp, err, &record)
+	if record.Type != "AAAA" || record.Data != "2001:db8::10" {
+		t.Errorf("record = %+v", record)
+	}
+
+	resp, err = h.GetDomainRecord("example.org", ids[2])
+	expectError(t, resp, err, "get_domain_record", "404")
+
+	resp, err = h.DeleteDomainRecord("example.com", ids[2])
+	decodeResponse(t, resp, err, &map[string]string{})
+	if _, ok := fake.DomainRecords.Get(ids[2]); ok {
+		t.Errorf("record %d was not deleted", ids[2])
+	}
+
+	resp, err = h.DeleteDomainRecord("example.com", ids[2])
+	expectError(t, resp, err, "delete_domain_record", "404")
+}
+
+func TestPreviewDeleteDomain(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDomain(fake)
+	nsIDs := fake.AddDomain("example.net", godo.DomainRecord{Type: "NS", Name: "@", Data: "ns1.digitalocean.com"})
+
+	preview, err := h.PreviewDeleteDomain("example.com")
+	if err != nil {
+		t.Fatalf("PreviewDeleteDomain: %v", err)
+	}
+	if preview.ResourceType != "domain" || len(preview.AttachedResources) != 5 || len(preview.Warnings) != 1 {
+		t.Errorf("domain preview = %+v", preview)
+	}
+	if preview.AttachedResources[1]["name"] != "www.example.com" {
+		t.Errorf("attached record = %v", preview.AttachedResources[1])
+	}
+
+	preview, err = h.PreviewDeleteDomainRecord("example.com", ids[3])
+	if err != nil {
+		t.Fatalf("PreviewDeleteDomainRecord: %v", err)
+	}
+	if preview.Name != "example.com" || len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "MX lookups") {
+		t.Errorf("record preview = %+v", preview)
+	}
+
+	preview, err = h.PreviewDeleteDomainRecord("example.net", nsIDs[0])
+	if err != nil {
+		t.Fatalf("PreviewDeleteDomainRecord: %v", err)
+	}
+	if len(preview.Warnings) != 2 {
+		t.Errorf("apex NS preview warnings = %v", preview.Warnings)
+	}
+
+	if _, err := h.PreviewDeleteDomain("missing.com"); err == nil {
+		t.Errorf("expected an error for a missing domain")
+	}
+}
+
+func TestQualifyRecordName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "", want: ""},
+		{name: "@", want: "example.com"},
+		{name: "example.com", want: "example.com"},
+		{name: "www", want: "www.example.com"},
+		{name: "www.example.com", want: "www.example.com"},
+		{name: "www.example.com.", want: "www.example.com"},
+		{name: "_sip._tcp", want: "_sip._tcp.example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := qualifyRecordName(tt.name, "example.com"); got != tt.want {
+			t.Errorf("qualifyRecordName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// dropletActionFunc is the shape shared by the godo DropletActions methods
+// that take nothing but the droplet ID.
+type dropletActionFunc func(ctx context.Context, dropletID int) (*godo.Action, *godo.Response, error)
+
+// runDropletAction starts an action on a droplet and returns it, so the
+// caller can track it by ID or, with a non-zero wait, see how it finished.
+func (h *Handler) runDropletAction(operation string, dropletID int, wait time.Duration, action dropletActionFunc) (*mcp_golang.ToolResponse, error) {
+	started, _, err := action(context.Background(), dropletID)
+	if err != nil {
+		return h.HandleError(err, operation)
+	}
+
+	return h.actionResult(started, wait, operation)
+}
+
+func (h *Handler) PowerOnDroplet(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("power_on_droplet", dropletID, wait, client.DropletActions.PowerOn)
+}
+
+func (h *Handler) PowerOffDroplet(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("power_off_droplet", dropletID, wait, client.DropletActions.PowerOff)
+}
+
+func (h *Handler) ShutdownDroplet(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("shutdown_droplet", dropletID, wait, client.DropletActions.Shutdown)
+}
+
+func (h *Handler) PowerCycleDroplet(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("power_cycle_droplet", dropletID, wait, client.DropletActions.PowerCycle)
+}
+
+func (h *Handler) RebootDroplet(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("reboot_droplet", dropletID, wait, client.DropletActions.Reboot)
+}
+
+func (h *Handler) ResetDropletPassword(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("reset_droplet_password", dropletID, wait, client.DropletActions.PasswordReset)
+}
+
+func (h *Handler) EnableDropletBackups(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("enable_droplet_backups", dropletID, wait, client.DropletActions.EnableBackups)
+}
+
+func (h *Handler) DisableDropletBackups(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("disable_droplet_backups", dropletID, wait, client.DropletActions.DisableBackups)
+}
+
+func (h *Handler) EnableDropletIPv6(dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletAction("enable_droplet_ipv6", dropletID, wait, client.DropletActions.EnableIPv6)
+}
+
+// RebuildDroplet reinstalls a droplet from image, which is either a slug
+// such as "ubuntu-24-04-x64" or a numeric image ID.
+func (h *Handler) RebuildDroplet(dropletID int, image string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if image == "" {
+		return h.HandleError(fmt.Errorf("image is required"), "rebuild_droplet")
+	}
+	return h.runDropletAction("rebuild_droplet", dropletID, wait, func(ctx context.Context, id int) (*godo.Action, *godo.Response, error) {
+		if imageID, err := strconv.Atoi(image); err == nil {
+			return client.DropletActions.RebuildByImageID(ctx, id, imageID)
+		}
+		return client.DropletActions.RebuildByImageSlug(ctx, id, image)
+	})
+}
+
+func (h *Handler) RenameDroplet(dropletID int, name string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	return h.runDropletAction("rename_droplet", dropletID, wait, func(ctx context.Context, id int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.Rename(ctx, id, name)
+	})
+}
+
+func (h *Handler) ChangeDropletKernel(dropletID, kernelID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	return h.runDropletAction("change_droplet_kernel", dropletID, wait, func(ctx context.Context, id int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.ChangeKernel(ctx, id, kernelID)
+	})
+}
+
+func (h *Handler) ListDropletKernels(dropletID, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	kernels, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Kernel, *godo.Response, error) {
+		return client.Droplets.Kernels(context.Background(), dropletID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_droplet_kernels")
+	}
+
+	return h.HandleSuccess(listResult("kernels", kernels, meta), "list_droplet_kernels")
+}
+
+// PreviewRebuildDroplet describes what RebuildDroplet would erase.
+func (h *Handler) PreviewRebuildDroplet(dropletID int, image string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	droplet, _, err := client.Droplets.Get(context.Background(), dropletID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "droplet_disk",
+		ID:           strconv.Itoa(droplet.ID),
+		Name:         droplet.Name,
+		Warnings: []string{
+			fmt.Sprintf("All data on the droplet's disk is erased and replaced with image %s", image),
+			"The droplet keeps its ID, IP addresses and attached volumes",
+		},
+	}
+	if droplet.Region != nil {
+		preview.Region = droplet.Region.Slug
+	}
+	if droplet.Image != nil {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "current_image",
+			"id":   droplet.Image.ID,
+			"name": droplet.Image.Name,
+			"slug": droplet.Image.Slug,
+		})
+	}
+
+	return preview, nil
+}
+
+// PreviewDisableDropletBackups lists the backups that are removed when
+// backups are disabled. The estimated cost is the backup charge the droplet
+// stops paying.
+func (h *Handler) PreviewDisableDropletBackups(dropletID int) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	droplet, _, err := client.Droplets.Get(context.Background(), dropletID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "droplet_backups",
+		ID:           strconv.Itoa(droplet.ID),
+		Name:         droplet.Name,
+	}
+	if droplet.Region != nil {
+		preview.Region = droplet.Region.Slug
+	}
+	if droplet.Size != nil {
+		preview.EstimatedMonthlyCost = droplet.Size.PriceMonthly * backupPriceRatio
+	}
+	for _, backupID := range droplet.BackupIDs {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "backup",
+			"id":   backupID,
+		})
+	}
+	if len(droplet.BackupIDs) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d existing backup(s) will be deleted; snapshot the droplet first to keep a copy", len(droplet.BackupIDs)))
+	}
+
+	return preview, nil
+}
+
+// dropletTagActionFunc is the shape shared by the godo DropletActions
+// methods that act on every droplet with a tag.
+type dropletTagActionFunc func(ctx context.Context, tag string) ([]godo.Action, *godo.Response, error)
+
+// runDropletTagAction starts an action on every droplet carrying tag and
+// returns one action per droplet.
+func (h *Handler) runDropletTagAction(operation, tag string, wait time.Duration, action dropletTagActionFunc) (*mcp_golang.ToolResponse, error) {
+	if tag == "" {
+		return h.HandleError(fmt.Errorf("tag is required"), operation)
+	}
+
+	started, _, err := action(context.Background(), tag)
+	if err != nil {
+		return h.HandleError(err, operation)
+	}
+
+	return h.actionsResult(started, wait, operation)
+}
+
+func (h *Handler) PowerOnDropletsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("power_on_droplets_by_tag", tag, wait, client.DropletActions.PowerOnByTag)
+}
+
+func (h *Handler) PowerOffDropletsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("power_off_droplets_by_tag", tag, wait, client.DropletActions.PowerOffByTag)
+}
+
+func (h *Handler) ShutdownDropletsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("shutdown_droplets_by_tag", tag, wait, client.DropletActions.ShutdownByTag)
+}
+
+func (h *Handler) PowerCycleDropletsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("power_cycle_droplets_by_tag", tag, wait, client.DropletActions.PowerCycleByTag)
+}
+
+func (h *Handler) EnableDropletBackupsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("enable_droplet_backups_by_tag", tag, wait, client.DropletActions.EnableBackupsByTag)
+}
+
+func (h *Handler) DisableDropletBackupsByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("disable_droplet_backups_by_tag", tag, wait, client.DropletActions.DisableBackupsByTag)
+}
+
+func (h *Handler) EnableDropletIPv6ByTag(tag string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+	return h.runDropletTagAction("enable_droplet_ipv6_by_tag", tag, wait, client.DropletActions.EnableIPv6ByTag)
+}
+
+func (h *Handler) SnapshotDropletsByTag(tag, snapshotName string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if snapshotName == "" {
+		return h.HandleError(fmt.Errorf("snapshot_name is required"), "snapshot_droplets_by_tag")
+	}
+	return h.runDropletTagAction("snapshot_droplets_by_tag", tag, wait, func(ctx context.Context, tag string) ([]godo.Action, *godo.Response, error) {
+		return client.DropletActions.SnapshotByTag(ctx, tag, snapshotName)
+	})
+}
+
+func (h *Handler) DeleteDropletsByTag(tag string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if tag == "" {
+		return h.HandleError(fmt.Errorf("tag is required"), "delete_droplets_by_tag")
+	}
+	_, err := client.Droplets.DeleteByTag(context.Background(), tag)
+	if err != nil {
+		return h.HandleError(err, "delete_droplets_by_tag")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Droplets tagged %s deleted successfully", tag),
+	}, "delete_droplets_by_tag")
+}
+
+func (h *Handler) PreviewPowerOffDropletsByTag(tag string) (*DeletionPreview, error) {
+	preview, _, err := h.previewDropletsByTag(tag, "droplet_power", nil, "Every matched droplet loses power immediately, as if unplugged; unsaved data may be lost")
+	return preview, err
+}
+
+func (h *Handler) PreviewShutdownDropletsByTag(tag string) (*DeletionPreview, error) {
+	preview, _, err := h.previewDropletsByTag(tag, "droplet_power", nil, "Every matched droplet is shut down and stays off until powered on")
+	return preview, err
+}
+
+func (h *Handler) PreviewPowerCycleDropletsByTag(tag string) (*DeletionPreview, error) {
+	preview, _, err := h.previewDropletsByTag(tag, "droplet_power", nil, "Every matched droplet is hard-restarted and briefly unavailable")
+	return preview, err
+}
+
+// PreviewDisableDropletBackupsByTag estimates the backup charges the matched
+// droplets stop paying.
+func (h *Handler) PreviewDisableDropletBackupsByTag(tag string) (*DeletionPreview, error) {
+	preview, droplets, err := h.previewDropletsByTag(tag, "droplet_backups", func(droplet godo.Droplet) float64 {
+		if droplet.Size == nil || !slices.Contains(droplet.Features, "backups") {
+			return 0
+		}
+		return droplet.Size.PriceMonthly * backupPriceRatio
+	}, "Existing backups of every matched droplet are deleted; snapshot the droplets first to keep a copy")
+	if err != nil {
+		return nil, err
+	}
+
+	backups := 0
+	for _, droplet := range droplets {
+		backups += len(droplet.BackupIDs)
+	}
+	if backups > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d backup(s) in total will be deleted", backups))
+	}
+	return preview, nil
+}
+
+func (h *Handler) PreviewDeleteDropletsByTag(tag string) (*DeletionPreview, error) {
+	preview, droplets, err := h.previewDropletsByTag(tag, "droplet", func(droplet godo.Droplet) float64 {
+		if droplet.Size == nil {
+			return 0
+		}
+		return droplet.Size.PriceMonthly
+	}, "Every matched droplet and its backups are deleted; attached volumes are detached but not deleted")
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := 0
+	for _, droplet := range droplets {
+		volumes += len(droplet.VolumeIDs)
+	}
+	if volumes > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d volume(s) will be detached", volumes))
+	}
+	return preview, nil
+}
+
+// previewDropletsByTag lists the droplets a by-tag call would act on and
+// returns them for callers that add their own warnings. cost, if set, gives
+// each droplet's share of the estimated monthly cost.
+func (h *Handler) previewDropletsByTag(tag, resourceType string, cost func(godo.Droplet) float64, warning string) (*DeletionPreview, []godo.Droplet, error) {
+	client := h.doClient.GetClient()
+
+	if tag == "" {
+		return nil, nil, fmt.Errorf("tag is required")
+	}
+	droplets, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Droplet, *godo.Response, error) {
+		return client.Droplets.ListByTag(context.Background(), tag, opt)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int, 0, len(droplets))
+	for _, droplet := range droplets {
+		ids = append(ids, droplet.ID)
+	}
+	slices.Sort(ids)
+
+	preview := &DeletionPreview{
+		ResourceType: resourceType,
+		ID:           tag,
+		Name:         fmt.Sprintf("droplets tagged %s", tag),
+		scope:        fmt.Sprintf("droplets tagged %s: %v", tag, ids),
+	}
+	if len(droplets) == 0 {
+		preview.Warnings = []string{fmt.Sprintf("No droplets are tagged %s; the call will have no effect", tag)}
+		return preview, droplets, nil
+	}
+
+	for _, droplet := range droplets {
+		resource := map[string]interface{}{
+			"type":    "droplet",
+			"id":      droplet.ID,
+			"name":    droplet.Name,
+			"status":  droplet.Status,
+			"backups": len(droplet.BackupIDs),
+		}
+		if droplet.Region != nil {
+			resource["region"] = droplet.Region.Slug
+		}
+		if len(droplet.VolumeIDs) > 0 {
+			resource["volume_ids"] = droplet.VolumeIDs
+		}
+		preview.AttachedResources = append(preview.AttachedResources, resource)
+		if cost != nil {
+			preview.EstimatedMonthlyCost += cost(droplet)
+		}
+	}
+	preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d droplet(s) match tag %s", len(droplets), tag), warning)
+
+	return preview, droplets, nil
+}
@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func TestDropletActions(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		status    string
+		run       func(h *Handler, id int) (*mcp_golang.ToolResponse, error)
+		check     func(d godo.Droplet) bool
+		wantType  string
+		wantErr   string
+	}{
+		{
+			name:      "power off",
+			operation: "power_off_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.PowerOffDroplet(id, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "off" },
+			wantType:  "power_off",
+		},
+		{
+			name:      "shutdown",
+			operation: "shutdown_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.ShutdownDroplet(id, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "off" },
+			wantType:  "shutdown",
+		},
+		{
+			name:      "power on",
+			operation: "power_on_droplet",
+			status:    "off",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.PowerOnDroplet(id, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "active" },
+			wantType:  "power_on",
+		},
+		{
+			name:      "power on an active droplet",
+			operation: "power_on_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.PowerOnDroplet(id, 0) },
+			wantErr:   "already powered on",
+		},
+		{
+			name:      "power cycle",
+			operation: "power_cycle_droplet",
+			status:    "off",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.PowerCycleDroplet(id, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "active" },
+			wantType:  "power_cycle",
+		},
+		{
+			name:      "reboot",
+			operation: "reboot_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.RebootDroplet(id, 0) },
+			wantType:  "reboot",
+		},
+		{
+			name:      "password reset",
+			operation: "reset_droplet_password",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.ResetDropletPassword(id, 0) },
+			wantType:  "password_reset",
+		},
+		{
+			name:      "enable backups",
+			operation: "enable_droplet_backups",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.EnableDropletBackups(id, 0) },
+			check:     func(d godo.Droplet) bool { return slices.Contains(d.Features, "backups") },
+			wantType:  "enable_backups",
+		},
+		{
+			name:      "disable backups",
+			operation: "disable_droplet_backups",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.DisableDropletBackups(id, 0) },
+			check:     func(d godo.Droplet) bool { return !slices.Contains(d.Features, "backups") },
+			wantType:  "disable_backups",
+		},
+		{
+			name:      "enable IPv6",
+			operation: "enable_droplet_ipv6",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.EnableDropletIPv6(id, 0) },
+			check:     func(d godo.Droplet) bool { return slices.Contains(d.Features, "ipv6") },
+			wantType:  "enable_ipv6",
+		},
+		{
+			name:      "rename",
+			operation: "rename_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.RenameDroplet(id, "api-1", 0) },
+			check:     func(d godo.Droplet) bool { return d.Name == "api-1" },
+			wantType:  "rename",
+		},
+		{
+			name:      "change kernel",
+			operation: "change_droplet_kernel",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.ChangeDropletKernel(id, 9102, 0) },
+			check:     func(d godo.Droplet) bool { return d.Kernel != nil && d.Kernel.ID == 9102 },
+			wantType:  "change_kernel",
+		},
+		{
+			name:      "unknown kernel",
+			operation: "change_droplet_kernel",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.ChangeDropletKernel(id, 1, 0) },
+			wantErr:   "invalid kernel: 1",
+		},
+		{
+			name:      "missing droplet",
+			operation: "reboot_droplet",
+			run:       func(h *Handler, id int) (*mcp_golang.ToolResponse, error) { return h.RebootDroplet(id+1, 0) },
+			wantErr:   "404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := seedDroplets(fake, 1)[0]
+			if tt.status != "" {
+				fake.Droplets.Update(id, func(d *godo.Droplet) { d.Status = tt.status })
+			}
+
+			resp, err := tt.run(h, id)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, tt.operation, tt.wantErr)
+				return
+			}
+
+			var result godo.Action
+			decodeResponse(t, resp, err, &result)
+			action, ok := fake.Actions.Get(result.ID)
+			if !ok || action.Type != tt.wantType || action.ResourceID != id {
+				t.Errorf("action %d = %+v, want a %s action on droplet %d", result.ID, action, tt.wantType, id)
+			}
+			if result.Status != action.Status {
+				t.Errorf("status = %q, want %q", result.Status, action.Status)
+			}
+
+			stored, _ := fake.Droplets.Get(id)
+			if tt.check != nil && !tt.check(stored) {
+				t.Errorf("droplet after %s = %+v", tt.name, stored)
+			}
+		})
+	}
+}
+
+func TestRebuildDroplet(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   func(id int) string
+		wantErr string
+	}{
+		{name: "by slug", image: func(int) string { return "ubuntu-24-04-x64" }},
+		{name: "by ID", image: func(id int) string { return strconv.Itoa(id) }},
+		{name: "unknown image", image: func(int) string { return "plan9" }, wantErr: "invalid image: plan9"},
+		{name: "no image", image: func(int) string { return "" }, wantErr: "image is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := seedDroplets(fake, 1)[0]
+			imageID := fake.NextID()
+			fake.Images.Put(imageID, godo.Image{ID: imageID, Name: "Ubuntu 24.04", Slug: "ubuntu-24-04-x64", Public: true})
+
+			resp, err := h.RebuildDroplet(dropletID, tt.image(imageID), 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "rebuild_droplet", tt.wantErr)
+				return
+			}
+
+			var action godo.Action
+			decodeResponse(t, resp, err, &action)
+			if action.Type != "rebuild" {
+				t.Errorf("action = %+v", action)
+			}
+			stored, _ := fake.Droplets.Get(dropletID)
+			if stored.Image == nil || stored.Image.ID != imageID {
+				t.Errorf("image after rebuild = %+v", stored.Image)
+			}
+		})
+	}
+}
+
+func TestListDropletKernels(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedDroplets(fake, 1)[0]
+
+	var kernels []godo.Kernel
+	resp, err := h.ListDropletKernels(id, 1, 2)
+	meta := decodeList(t, resp, err, "kernels", &kernels)
+	if len(kernels) != 2 || meta.Total != 3 {
+		t.Errorf("kernels = %+v, meta = %+v", kernels, meta)
+	}
+
+	resp, err = h.ListDropletKernels(id+1, 0, 0)
+	expectError(t, resp, err, "list_droplet_kernels", "404")
+}
+
+func TestPreviewDropletActions(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := fake.NextID()
+	fake.Droplets.Put(id, godo.Droplet{
+		ID:        id,
+		Name:      "db",
+		Region:    &godo.Region{Slug: "ams3"},
+		Size:      &godo.Size{Slug: "s-2vcpu-4gb", PriceMonthly: 24},
+		Image:     &godo.Image{ID: 7, Slug: "ubuntu-22-04-x64"},
+		BackupIDs: []int{1, 2},
+		Features:  []string{"backups"},
+	})
+
+	preview, err := h.PreviewRebuildDroplet(id, "debian-12-x64")
+	if err != nil {
+		t.Fatalf("PreviewRebuildDroplet: %v", err)
+	}
+	if preview.ResourceType != "droplet_disk" || preview.Region != "ams3" || len(preview.AttachedResources) != 1 || len(preview.Warnings) != 2 {
+		t.Errorf("rebuild preview = %+v", preview)
+	}
+
+	preview, err = h.PreviewDisableDropletBackups(id)
+	if err != nil {
+		t.Fatalf("PreviewDisableDropletBackups: %v", err)
+	}
+	if math.Abs(preview.EstimatedMonthlyCost-4.8) > 0.001 || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 1 {
+		t.Errorf("disable backups preview = %+v", preview)
+	}
+
+	if _, err := h.PreviewRebuildDroplet(id+1, "debian-12-x64"); err == nil {
+		t.Errorf("expected an error for a missing droplet")
+	}
+}
+
+func TestDropletActionsByTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		run       func(h *Handler, tag string) (*mcp_golang.ToolResponse, error)
+		check     func(d godo.Droplet) bool
+		wantType  string
+		wantErr   string
+	}{
+		{
+			name:      "power off",
+			operation: "power_off_droplets_by_tag",
+			run:       func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) { return h.PowerOffDropletsByTag(tag, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "off" },
+			wantType:  "power_off",
+		},
+		{
+			name:      "shutdown",
+			operation: "shutdown_droplets_by_tag",
+			run:       func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) { return h.ShutdownDropletsByTag(tag, 0) },
+			check:     func(d godo.Droplet) bool { return d.Status == "off" },
+			wantType:  "shutdown",
+		},
+		{
+			name:      "power cycle",
+			operation: "power_cycle_droplets_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.PowerCycleDropletsByTag(tag, 0)
+			},
+			check:    func(d godo.Droplet) bool { return d.Status == "active" },
+			wantType: "power_cycle",
+		},
+		{
+			name:      "snapshot",
+			operation: "snapshot_droplets_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.SnapshotDropletsByTag(tag, "nightly", 0)
+			},
+			check:    func(d godo.Droplet) bool { return len(d.SnapshotIDs) == 1 },
+			wantType: "snapshot",
+		},
+		{
+			name:      "enable backups",
+			operation: "enable_droplet_backups_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.EnableDropletBackupsByTag(tag, 0)
+			},
+			check:    func(d godo.Droplet) bool { return slices.Contains(d.Features, "backups") },
+			wantType: "enable_backups",
+		},
+		{
+			name:      "disable backups",
+			operation: "disable_droplet_backups_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.DisableDropletBackupsByTag(tag, 0)
+			},
+			check:    func(d godo.Droplet) bool { return !slices.Contains(d.Features, "backups") },
+			wantType: "disable_backups",
+		},
+		{
+			name:      "enable IPv6",
+			operation: "enable_droplet_ipv6_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.EnableDropletIPv6ByTag(tag, 0)
+			},
+			check:    func(d godo.Droplet) bool { return slices.Contains(d.Features, "ipv6") },
+			wantType: "enable_ipv6",
+		},
+		{
+			name:      "snapshot without a name",
+			operation: "snapshot_droplets_by_tag",
+			run: func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) {
+				return h.SnapshotDropletsByTag(tag, "", 0)
+			},
+			wantErr: "snapshot_name is required",
+		},
+		{
+			name:      "no tag",
+			operation: "power_on_droplets_by_tag",
+			run:       func(h *Handler, tag string) (*mcp_golang.ToolResponse, error) { return h.PowerOnDropletsByTag("", 0) },
+			wantErr:   "tag is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			ids := seedDroplets(fake, 3)
+			tagDroplets(fake, "web", ids[0], ids[1])
+
+			resp, err := tt.run(h, "web")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, tt.operation, tt.wantErr)
+				return
+			}
+
+			var result struct {
+				Actions []godo.Action `json:"actions"`
+			}
+			decodeResponse(t, resp, err, &result)
+			if len(result.Actions) != 2 {
+				t.Fatalf("started %d actions, want 2", len(result.Actions))
+			}
+			for i, action := range result.Actions {
+				if action.Type != tt.wantType || action.ResourceID != ids[i] {
+					t.Errorf("action %d = %+v, want a %s action on droplet %d", i, action, tt.wantType, ids[i])
+				}
+				stored, _ := fake.Droplets.Get(ids[i])
+				if !tt.check(stored) {
+					t.Errorf("tagged droplet after %s = %+v", tt.name, stored)
+				}
+			}
+			if untagged, _ := fake.Droplets.Get(ids[2]); untagged.Status != "active" || len(untagged.Features) != 0 || len(untagged.SnapshotIDs) != 0 {
+				t.Errorf("untagged droplet was changed: %+v", untagged)
+			}
+		})
+	}
+}
+
+func TestDropletActionsByTagWait(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 2)
+	tagDroplets(fake, "web", ids...)
+	fake.Droplets.Update(ids[1], func(d *godo.Droplet) { d.Status = "off" })
+	fake.ActionPolls = 2
+
+	var result struct {
+		Actions []godo.Action `json:"actions"`
+	}
+	resp, err := h.PowerOffDropletsByTag("web", time.Second)
+	decodeResponse(t, resp, err, &result)
+	// The droplet that was already off is skipped
+	if len(result.Actions) != 1 || result.Actions[0].ResourceID != ids[0] || result.Actions[0].Status != godo.ActionCompleted {
+		t.Errorf("actions = %+v", result.Actions)
+	}
+
+	resp, err = h.PowerOnDropletsByTag("missing", 0)
+	decodeResponse(t, resp, err, &result)
+	if len(result.Actions) != 0 {
+		t.Errorf("actions for an unused tag = %+v", result.Actions)
+	}
+}
+
+func TestDeleteDropletsByTag(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 3)
+	tagDroplets(fake, "staging", ids[0], ids[2])
+
+	resp, err := h.DeleteDropletsByTag("staging")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.Droplets.Len() != 1 {
+		t.Errorf("%d droplets left, want 1", fake.Droplets.Len())
+	}
+	if _, ok := fake.Droplets.Get(ids[1]); !ok {
+		t.Errorf("untagged droplet %d was deleted", ids[1])
+	}
+
+	resp, err = h.DeleteDropletsByTag("")
+	expectError(t, resp, err, "delete_droplets_by_tag", "tag is required")
+}
+
+func TestPreviewDropletActionsByTag(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 3)
+	tagDroplets(fake, "web", ids[0], ids[1])
+	fake.Droplets.Update(ids[0], func(d *godo.Droplet) {
+		d.Features = []string{"backups"}
+		d.BackupIDs = []int{1, 2, 3}
+		d.VolumeIDs = []string{"vol-1"}
+	})
+
+	preview, err := h.PreviewDeleteDropletsByTag("web")
+	if err != nil {
+		t.Fatalf("PreviewDeleteDropletsByTag: %v", err)
+	}
+	if preview.ID != "web" || len(preview.AttachedResources) != 2 || preview.EstimatedMonthlyCost != 12 {
+		t.Errorf("delete preview = %+v", preview)
+	}
+	if len(preview.Warnings) != 3 || !strings.Contains(preview.Warnings[2], "1 volume(s)") {
+		t.Errorf("delete preview warnings = %v", preview.Warnings)
+	}
+
+	preview, err = h.PreviewDisableDropletBackupsByTag("web")
+	if err != nil {
+		t.Fatalf("PreviewDisableDropletBackupsByTag: %v", err)
+	}
+	if math.Abs(preview.EstimatedMonthlyCost-1.2) > 0.001 || !strings.Contains(strings.Join(preview.Warnings, " "), "3 backup(s)") {
+		t.Errorf("disable backups preview = %+v", preview)
+	}
+
+	for name, run := range map[string]func(string) (*DeletionPreview, error){
+		"power off":   h.PreviewPowerOffDropletsByTag,
+		"shutdown":    h.PreviewShutdownDropletsByTag,
+		"power cycle": h.PreviewPowerCycleDropletsByTag,
+	} {
+		preview, err := run("web")
+		if err != nil {
+			t.Fatalf("%s preview: %v", name, err)
+		}
+		if preview.ResourceType != "droplet_power" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 2 {
+			t.Errorf("%s preview = %+v", name, preview)
+		}
+	}
+
+	preview, err = h.PreviewPowerOffDropletsByTag("unused")
+	if err != nil {
+		t.Fatalf("PreviewPowerOffDropletsByTag: %v", err)
+	}
+	if len(preview.AttachedResources) != 0 || !strings.Contains(preview.Warnings[0], "no effect") {
+		t.Errorf("preview for an unused tag = %+v", preview)
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
@@ -11,21 +13,8 @@ import (
 func (h *Handler) ListDroplets(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	// Set default values if not provided
-	if page <= 0 {
-		page = 1
-	}
-	if perPage <= 0 {
-		perPage = 25
-	}
-	// Limit perPage to DigitalOcean's maximum of 200
-	if perPage > 200 {
-		perPage = 200
-	}
-	
-	droplets, response, err := client.Droplets.List(context.Background(), &godo.ListOptions{
-		Page:    page,
-		PerPage: perPage,
+	droplets, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Droplet, *godo.Response, error) {
+		return client.Droplets.List(context.Background(), opt)
 	})
 	if err != nil {
 		return h.HandleError(err, "list_droplets")
@@ -41,19 +30,7 @@ func (h *Handler) ListDroplets(page, perPage int) (*mcp_golang.ToolResponse, err
 		}
 	}
 
-	// Include pagination metadata in response
-	result := map[string]interface{}{
-		"droplets": simplifiedDroplets,
-		"meta": map[string]interface{}{
-			"total":    response.Meta.Total,
-			"page":     page,
-			"per_page": perPage,
-			"pages":    (response.Meta.Total + perPage - 1) / perPage,
-		},
-		"links": response.Links,
-	}
-
-	return h.HandleSuccess(result, "list_droplets")
+	return h.HandleSuccess(listResult("droplets", simplifiedDroplets, meta), "list_droplets")
 }
 
 func (h *Handler) GetDroplet(dropletID int) (*mcp_golang.ToolResponse, error) {
@@ -67,26 +44,153 @@ func (h *Handler) GetDroplet(dropletID int) (*mcp_golang.ToolResponse, error) {
 	return h.HandleSuccess(droplet, "get_droplet")
 }
 
-func (h *Handler) CreateDroplet(name, region, size, image string) (*mcp_golang.ToolResponse, error) {
+// maxBatchCreate and maxUserDataBytes are the API limits on droplet creation.
+const (
+	maxBatchCreate   = 10
+	maxUserDataBytes = 64 * 1024
+)
+
+// CreateDropletOptions mirrors godo.DropletCreateRequest with the loosely
+// typed references an MCP client sends: Image and SSHKeys entries may be
+// numeric IDs or slugs/fingerprints, and Volumes holds volume IDs.
+type CreateDropletOptions struct {
+	Name       string
+	Names      []string
+	Region     string
+	Size       string
+	Image      string
+	SSHKeys    []string
+	Tags       []string
+	VPCUUID    string
+	UserData   string
+	Monitoring bool
+	Backups    bool
+	IPv6       bool
+	Volumes    []string
+	// ProjectID assigns the new droplets to a project instead of the
+	// default one.
+	ProjectID string
+}
+
+// CreateDroplet creates one droplet, or one per entry of opts.Names in a
+// single request. With a non-zero wait it waits for the create actions and
+// returns the droplets as they are once running.
+func (h *Handler) CreateDroplet(opts CreateDropletOptions, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
+
+	if err := opts.validate(); err != nil {
+		return h.HandleError(err, "create_droplet")
+	}
+	if err := h.validateDropletPlacement(opts.Region, opts.Size, opts.Image); err != nil {
+		return h.HandleError(err, "create_droplet")
+	}
+	if err := h.checkProject(opts.ProjectID); err != nil {
+		return h.HandleError(err, "create_droplet")
+	}
+
+	var sshKeys []godo.DropletCreateSSHKey
+	for _, key := range opts.SSHKeys {
+		if id, err := strconv.Atoi(key); err == nil {
+			sshKeys = append(sshKeys, godo.DropletCreateSSHKey{ID: id})
+		} else {
+			sshKeys = append(sshKeys, godo.DropletCreateSSHKey{Fingerprint: key})
+		}
+	}
+	var volumes []godo.DropletCreateVolume
+	for _, volumeID := range opts.Volumes {
+		volumes = append(volumes, godo.DropletCreateVolume{ID: volumeID})
+	}
+	image := godo.DropletCreateImage{Slug: opts.Image}
+	if id, err := strconv.Atoi(opts.Image); err == nil {
+		image = godo.DropletCreateImage{ID: id}
+	}
+
+	if len(opts.Names) > 0 {
+		createRequest := &godo.DropletMultiCreateRequest{
+			Names:      opts.Names,
+			Region:     opts.Region,
+			Size:       opts.Size,
+			Image:      image,
+			SSHKeys:    sshKeys,
+			Backups:    opts.Backups,
+			IPv6:       opts.IPv6,
+			Monitoring: opts.Monitoring,
+			UserData:   opts.UserData,
+			Tags:       opts.Tags,
+			VPCUUID:    opts.VPCUUID,
+		}
+
+		droplets, response, err := client.Droplets.CreateMultiple(context.Background(), createRequest)
+		if err != nil {
+			return h.HandleError(err, "create_droplet")
+		}
+		created := make([]godo.ResourceWithURN, len(droplets))
+		for i, droplet := range droplets {
+			created[i] = droplet
+		}
+		if err := h.assignToProject(opts.ProjectID, created...); err != nil {
+			return h.HandleError(err, "create_droplet")
+		}
+		if wait > 0 {
+			droplets, err = h.waitForCreate(droplets, response, wait)
+			if err != nil {
+				return h.HandleError(err, "create_droplet")
+			}
+		}
+
+		return h.HandleSuccess(map[string]interface{}{"droplets": droplets}, "create_droplet")
+	}
+
 	createRequest := &godo.DropletCreateRequest{
-		Name:   name,
-		Region: region,
-		Size:   size,
-		Image: godo.DropletCreateImage{
-			Slug: image,
-		},
+		Name:       opts.Name,
+		Region:     opts.Region,
+		Size:       opts.Size,
+		Image:      image,
+		SSHKeys:    sshKeys,
+		Backups:    opts.Backups,
+		IPv6:       opts.IPv6,
+		Monitoring: opts.Monitoring,
+		UserData:   opts.UserData,
+		Volumes:    volumes,
+		Tags:       opts.Tags,
+		VPCUUID:    opts.VPCUUID,
 	}
-	
-	droplet, _, err := client.Droplets.Create(context.Background(), createRequest)
+
+	droplet, response, err := client.Droplets.Create(context.Background(), createRequest)
 	if err != nil {
 		return h.HandleError(err, "create_droplet")
 	}
+	if err := h.assignToProject(opts.ProjectID, droplet); err != nil {
+		return h.HandleError(err, "create_droplet")
+	}
+	if wait > 0 {
+		droplets, err := h.waitForCreate([]godo.Droplet{*droplet}, response, wait)
+		if err != nil {
+			return h.HandleError(err, "create_droplet")
+		}
+		droplet = &droplets[0]
+	}
 
 	return h.HandleSuccess(droplet, "create_droplet")
 }
 
+func (opts CreateDropletOptions) validate() error {
+	switch {
+	case opts.Name == "" && len(opts.Names) == 0:
+		return fmt.Errorf("name or names is required")
+	case opts.Name != "" && len(opts.Names) > 0:
+		return fmt.Errorf("set either name or names, not both")
+	case len(opts.Names) > maxBatchCreate:
+		return fmt.Errorf("at most %d droplets can be created in one call, got %d names", maxBatchCreate, len(opts.Names))
+	case len(opts.Names) > 0 && len(opts.Volumes) > 0:
+		// A volume can only be attached to one droplet.
+		return fmt.Errorf("volumes cannot be attached when creating several droplets")
+	case len(opts.UserData) > maxUserDataBytes:
+		return fmt.Errorf("user_data is %d bytes, the limit is %d", len(opts.UserData), maxUserDataBytes)
+	}
+	return nil
+}
+
 func (h *Handler) DeleteDroplet(dropletID int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
@@ -101,16 +205,49 @@ func (h *Handler) DeleteDroplet(dropletID int) (*mcp_golang.ToolResponse, error)
 	}, "delete_droplet")
 }
 
-func (h *Handler) ResizeDroplet(dropletID int, size string, disk bool) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ResizeDroplet(dropletID int, size string, disk bool, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	_, _, err := client.DropletActions.Resize(context.Background(), dropletID, size, disk)
+	action, _, err := client.DropletActions.Resize(context.Background(), dropletID, size, disk)
 	if err != nil {
 		return h.HandleError(err, "resize_droplet")
 	}
 
-	return h.HandleSuccess(map[string]string{
-		"status":  "success",
-		"message": fmt.Sprintf("Droplet %d resize initiated", dropletID),
-	}, "resize_droplet")
-}
\ No newline at end of file
+	return h.actionResult(action, wait, "resize_droplet")
+}
+
+func (h *Handler) PreviewDeleteDroplet(dropletID int) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	droplet, _, err := client.Droplets.Get(context.Background(), dropletID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "droplet",
+		ID:           fmt.Sprintf("%d", droplet.ID),
+		Name:         droplet.Name,
+	}
+	if droplet.Region != nil {
+		preview.Region = droplet.Region.Slug
+	}
+	if droplet.Size != nil {
+		preview.EstimatedMonthlyCost = droplet.Size.PriceMonthly
+	}
+
+	for _, volumeID := range droplet.VolumeIDs {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "volume",
+			"id":   volumeID,
+		})
+	}
+	if len(droplet.VolumeIDs) > 0 {
+		preview.Warnings = append(preview.Warnings, "Attached volumes are detached but not deleted")
+	}
+	if len(droplet.BackupIDs) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d backup(s) of this droplet will be deleted", len(droplet.BackupIDs)))
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func seedDroplets(fake *fakedo.Server, n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = fake.NextID()
+		fake.Droplets.Put(ids[i], godo.Droplet{
+			ID:     ids[i],
+			Name:   "web-" + strconv.Itoa(i+1),
+			Status: "active",
+			Region: &godo.Region{Slug: "nyc3"},
+			Size:   &godo.Size{Slug: "s-1vcpu-1gb", PriceMonthly: 6},
+		})
+	}
+	return ids
+}
+
+// seedImages stores the distribution images the create tests boot from and a
+// custom image with ID 4321.
+func seedImages(fake *fakedo.Server) {
+	for _, image := range []godo.Image{
+		{ID: fake.NextID(), Name: "Ubuntu 22.04", Slug: "ubuntu-22-04-x64", Distribution: "Ubuntu", Type: "base", Public: true},
+		{ID: fake.NextID(), Name: "Ubuntu 24.04", Slug: "ubuntu-24-04-x64", Distribution: "Ubuntu", Type: "base", Public: true},
+		{ID: 4321, Name: "golden", Type: "custom", Regions: []string{"nyc3"}},
+	} {
+		fake.Images.Put(image.ID, image)
+	}
+}
+
+func TestListDroplets(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed      int
+		page      int
+		perPage   int
+		wantCount int
+		wantMeta  ListMeta
+	}{
+		{
+			name:      "all pages by default",
+			seed:      3,
+			wantCount: 3,
+			wantMeta:  ListMeta{Total: 3, Count: 3, PerPage: 200, Pages: 1, AllPages: true},
+		},
+		{
+			name:      "all pages with small per_page",
+			seed:      5,
+			perPage:   2,
+			wantCount: 5,
+			wantMeta:  ListMeta{Total: 5, Count: 5, PerPage: 2, Pages: 3, AllPages: true},
+		},
+		{
+			name:      "second page",
+			seed:      5,
+			page:      2,
+			perPage:   2,
+			wantCount: 2,
+			wantMeta:  ListMeta{Total: 5, Count: 2, Page: 2, PerPage: 2, Pages: 3},
+		},
+		{
+			name:      "last partial page",
+			seed:      5,
+			page:      3,
+			perPage:   2,
+			wantCount: 1,
+			wantMeta:  ListMeta{Total: 5, Count: 1, Page: 3, PerPage: 2, Pages: 3},
+		},
+		{
+			name:      "page with default per_page",
+			seed:      30,
+			page:      1,
+			wantCount: 25,
+			wantMeta:  ListMeta{Total: 30, Count: 25, Page: 1, PerPage: 25, Pages: 2},
+		},
+		{
+			name:      "per_page capped at 200",
+			seed:      1,
+			page:      1,
+			perPage:   500,
+			wantCount: 1,
+			wantMeta:  ListMeta{Total: 1, Count: 1, Page: 1, PerPage: 200, Pages: 1},
+		},
+		{
+			name:     "empty account",
+			wantMeta: ListMeta{PerPage: 200, AllPages: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedDroplets(fake, tt.seed)
+
+			var droplets []map[string]interface{}
+			resp, err := h.ListDroplets(tt.page, tt.perPage)
+			meta := decodeList(t, resp, err, "droplets", &droplets)
+
+			if len(droplets) != tt.wantCount {
+				t.Errorf("got %d droplets, want %d", len(droplets), tt.wantCount)
+			}
+			for _, d := range droplets {
+				if len(d) != 3 || d["id"] == nil || d["name"] == nil || d["status"] == nil {
+					t.Errorf("droplet should only carry id, name and status, got %v", d)
+				}
+			}
+			if meta != tt.wantMeta {
+				t.Errorf("meta = %+v, want %+v", meta, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestGetDroplet(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 1)
+
+	tests := []struct {
+		name    string
+		id      int
+		wantErr string
+	}{
+		{name: "found", id: ids[0]},
+		{name: "not found", id: 1, wantErr: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.GetDroplet(tt.id)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "get_droplet", tt.wantErr)
+				return
+			}
+			var droplet godo.Droplet
+			decodeResponse(t, resp, err, &droplet)
+			if droplet.ID != tt.id || droplet.Name != "web-1" {
+				t.Errorf("got droplet %d %q", droplet.ID, droplet.Name)
+			}
+		})
+	}
+}
+
+func TestCreateDroplet(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    CreateDropletOptions
+		check   func(t *testing.T, fake *fakedo.Server, droplet godo.Droplet)
+		wantErr string
+	}{
+		{
+			name: "created",
+			opts: CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64"},
+			check: func(t *testing.T, fake *fakedo.Server, droplet godo.Droplet) {
+				if droplet.Image.Slug != "ubuntu-22-04-x64" || droplet.SizeSlug != "s-1vcpu-1gb" || regionSlugOf(droplet.Region) != "nyc3" {
+					t.Errorf("stored droplet has image %q size %q region %q", droplet.Image.Slug, droplet.SizeSlug, regionSlugOf(droplet.Region))
+				}
+			},
+		},
+		{
+			name: "all options",
+			opts: CreateDropletOptions{
+				Name:       "web",
+				Region:     "nyc3",
+				Size:       "s-1vcpu-1gb",
+				Image:      "4321",
+				SSHKeys:    []string{"512189", "3b:16:bf:e4:8b:00:8b:b8:59:8c:a9:d3:f0:19:45:fa"},
+				Tags:       []string{"web", "prod"},
+				VPCUUID:    "vpc-1",
+				UserData:   "#cloud-config\npackages: [nginx]\n",
+				Monitoring: true,
+				Backups:    true,
+				IPv6:       true,
+			},
+			check: func(t *testing.T, fake *fakedo.Server, droplet godo.Droplet) {
+				if droplet.Image.ID != 4321 || droplet.VPCUUID != "vpc-1" || len(droplet.Tags) != 2 {
+					t.Errorf("stored droplet = %+v", droplet)
+				}
+				for _, feature := range []string{"backups", "ipv6", "monitoring"} {
+					if !slices.Contains(droplet.Features, feature) {
+						t.Errorf("feature %s not enabled in %v", feature, droplet.Features)
+					}
+				}
+				setup, _ := fake.DropletSetups.Get(droplet.ID)
+				if !slices.Equal(setup.SSHKeys, []string{"512189", "3b:16:bf:e4:8b:00:8b:b8:59:8c:a9:d3:f0:19:45:fa"}) || !strings.HasPrefix(setup.UserData, "#cloud-config") {
+					t.Errorf("setup = %+v", setup)
+				}
+			},
+		},
+		{
+			name:    "invalid size",
+			opts:    CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-64vcpu-1tb", Image: "ubuntu-22-04-x64"},
+			wantErr: "invalid size",
+		},
+		{
+			name:    "no name",
+			opts:    CreateDropletOptions{Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64"},
+			wantErr: "name or names is required",
+		},
+		{
+			name:    "name and names",
+			opts:    CreateDropletOptions{Name: "web", Names: []string{"web-1"}, Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64"},
+			wantErr: "set either name or names, not both",
+		},
+		{
+			name:    "too many names",
+			opts:    CreateDropletOptions{Names: make([]string, 11), Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64"},
+			wantErr: "at most 10 droplets",
+		},
+		{
+			name:    "volumes with names",
+			opts:    CreateDropletOptions{Names: []string{"a", "b"}, Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", Volumes: []string{"vol-1"}},
+			wantErr: "volumes cannot be attached when creating several droplets",
+		},
+		{
+			name:    "user data too large",
+			opts:    CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", UserData: strings.Repeat("x", 64*1024+1)},
+			wantErr: "user_data is 65537 bytes",
+		},
+		{
+			name:    "VPC in another region",
+			opts:    CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", VPCUUID: "vpc-2"},
+			wantErr: "vpc vpc-2 is in sfo3, not nyc3",
+		},
+		{
+			name:    "unknown VPC",
+			opts:    CreateDropletOptions{Name: "web", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", VPCUUID: "vpc-9"},
+			wantErr: "vpc vpc-9 does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedImages(fake)
+			fake.VPCs.Put("vpc-1", godo.VPC{ID: "vpc-1", Name: "prod", RegionSlug: "nyc3", IPRange: "10.10.0.0/20"})
+			fake.VPCs.Put("vpc-2", godo.VPC{ID: "vpc-2", Name: "staging", RegionSlug: "sfo3", IPRange: "10.20.0.0/20"})
+
+			resp, err := h.CreateDroplet(tt.opts, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_droplet", tt.wantErr)
+				if fake.Droplets.Len() != 0 {
+					t.Errorf("no droplet should have been created")
+				}
+				return
+			}
+
+			var droplet godo.Droplet
+			decodeResponse(t, resp, err, &droplet)
+			stored, ok := fake.Droplets.Get(droplet.ID)
+			if !ok {
+				t.Fatalf("droplet %d was not stored", droplet.ID)
+			}
+			tt.check(t, fake, stored)
+		})
+	}
+}
+
+func TestCreateDropletWithVolumes(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedImages(fake)
+	local := seedVolume(fake, "data", "nyc3", 100)
+	remote := seedVolume(fake, "far", "ams3", 100)
+
+	var droplet godo.Droplet
+	resp, err := h.CreateDroplet(CreateDropletOptions{Name: "db", Region: "nyc3", Size: "s-2vcpu-4gb", Image: "ubuntu-22-04-x64", Volumes: []string{local}}, 0)
+	decodeResponse(t, resp, err, &droplet)
+	if !slices.Equal(droplet.VolumeIDs, []string{local}) {
+		t.Errorf("volume IDs = %v", droplet.VolumeIDs)
+	}
+	if volume, _ := fake.Volumes.Get(local); !slices.Equal(volume.DropletIDs, []int{droplet.ID}) {
+		t.Errorf("volume %s is attached to %v", local, volume.DropletIDs)
+	}
+
+	resp, err = h.CreateDroplet(CreateDropletOptions{Name: "db-2", Region: "nyc3", Size: "s-2vcpu-4gb", Image: "ubuntu-22-04-x64", Volumes: []string{remote}}, 0)
+	expectError(t, resp, err, "create_droplet", "does not exist in region nyc3")
+}
+
+func TestCreateDropletBatch(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedImages(fake)
+	fake.ActionPolls = 2
+
+	var result struct {
+		Droplets []godo.Droplet `json:"droplets"`
+	}
+	resp, err := h.CreateDroplet(CreateDropletOptions{Names: []string{"web-1", "web-2", "web-3"}, Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", Tags: []string{"web"}}, time.Second)
+	decodeResponse(t, resp, err, &result)
+	if len(result.Droplets) != 3 || fake.Droplets.Len() != 3 {
+		t.Fatalf("created %d droplets, stored %d, want 3", len(result.Droplets), fake.Droplets.Len())
+	}
+	for i, droplet := range result.Droplets {
+		if droplet.Name != fmt.Sprintf("web-%d", i+1) || droplet.Status != "active" || !slices.Contains(droplet.Tags, "web") {
+			t.Errorf("droplet %d = %s/%s with tags %v", i, droplet.Name, droplet.Status, droplet.Tags)
+		}
+	}
+}
+
+func TestDeleteDroplet(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 1)
+
+	var result map[string]string
+	resp, err := h.DeleteDroplet(ids[0])
+	decodeResponse(t, resp, err, &result)
+	if result["status"] != "success" {
+		t.Errorf("status = %q", result["status"])
+	}
+	if _, ok := fake.Droplets.Get(ids[0]); ok {
+		t.Errorf("droplet still exists after delete")
+	}
+
+	resp, err = h.DeleteDroplet(ids[0])
+	expectError(t, resp, err, "delete_droplet", "404")
+}
+
+func TestResizeDroplet(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		fail    int
+		wantErr string
+	}{
+		{name: "resized", size: "s-2vcpu-4gb"},
+		{name: "unknown size", size: "huge", wantErr: "invalid size"},
+		{name: "rate limited", size: "s-2vcpu-4gb", fail: http.StatusTooManyRequests, wantErr: "API Rate limit exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := seedDroplets(fake, 1)[0]
+			if tt.fail != 0 {
+				fake.FailNext("POST", "/v2/droplets/"+strconv.Itoa(id)+"/actions", tt.fail, tt.wantErr)
+			}
+
+			resp, err := h.ResizeDroplet(id, tt.size, false, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "resize_droplet", tt.wantErr)
+				return
+			}
+
+			var action godo.Action
+			decodeResponse(t, resp, err, &action)
+			if action.Type != "resize" || action.ResourceID != id {
+				t.Errorf("action = %+v", action)
+			}
+			stored, _ := fake.Droplets.Get(id)
+			if stored.SizeSlug != tt.size {
+				t.Errorf("size = %q, want %q", stored.SizeSlug, tt.size)
+			}
+		})
+	}
+}
+
+func TestPreviewDeleteDroplet(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := fake.NextID()
+	fake.Droplets.Put(id, godo.Droplet{
+		ID:        id,
+		Name:      "db",
+		Region:    &godo.Region{Slug: "ams3"},
+		Size:      &godo.Size{Slug: "s-2vcpu-4gb", PriceMonthly: 24},
+		VolumeIDs: []string{"vol-1"},
+		BackupIDs: []int{1, 2},
+	})
+
+	preview, err := h.PreviewDeleteDroplet(id)
+	if err != nil {
+		t.Fatalf("PreviewDeleteDroplet: %v", err)
+	}
+	if preview.Name != "db" || preview.Region != "ams3" || preview.EstimatedMonthlyCost != 24 {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(preview.AttachedResources) != 1 || preview.AttachedResources[0]["id"] != "vol-1" {
+		t.Errorf("attached resources = %v", preview.AttachedResources)
+	}
+	if len(preview.Warnings) != 2 {
+		t.Errorf("warnings = %v, want volume and backup warnings", preview.Warnings)
+	}
+
+	if _, err := h.PreviewDeleteDroplet(1); err == nil {
+		t.Errorf("expected an error previewing a missing droplet")
+	}
+}
+
+func regionSlugOf(region *godo.Region) string {
+	if region == nil {
+		return ""
+	}
+	return region.Slug
+}
@@ -8,10 +8,12 @@ import (
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListFirewalls() (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListFirewalls(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	firewalls, _, err := client.Firewalls.List(context.Background(), &godo.ListOptions{})
+	firewalls, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Firewall, *godo.Response, error) {
+		return client.Firewalls.List(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_firewalls")
 	}
@@ -25,11 +27,7 @@ func (h *Handler) ListFirewalls() (*mcp_golang.ToolResponse, error) {
 		}
 	}
 
-	result := map[string]interface{}{
-		"firewalls": simplifiedFirewalls,
-	}
-
-	return h.HandleSuccess(result, "list_firewalls")
+	return h.HandleSuccess(listResult("firewalls", simplifiedFirewalls, meta), "list_firewalls")
 }
 
 func (h *Handler) GetFirewall(firewallID string) (*mcp_golang.ToolResponse, error) {
@@ -185,4 +183,36 @@ func (h *Handler) RemoveRulesFromFirewall(firewallID string, inboundRules []godo
 		"status":  "success",
 		"message": fmt.Sprintf("Rules removed from firewall %s successfully", firewallID),
 	}, "remove_rules_from_firewall")
-}
\ No newline at end of file
+}
+
+func (h *Handler) PreviewDeleteFirewall(firewallID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	firewall, _, err := client.Firewalls.Get(context.Background(), firewallID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "firewall",
+		ID:           firewall.ID,
+		Name:         firewall.Name,
+	}
+	for _, dropletID := range firewall.DropletIDs {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "droplet",
+			"id":   dropletID,
+		})
+	}
+	for _, tag := range firewall.Tags {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "tag",
+			"id":   tag,
+		})
+	}
+	if len(preview.AttachedResources) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d inbound and %d outbound rule(s) will stop protecting the attached resources", len(firewall.InboundRules), len(firewall.OutboundRules)))
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+var (
+	sshRule   = godo.InboundRule{Protocol: "tcp", PortRange: "22", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}}
+	httpsRule = godo.InboundRule{Protocol: "tcp", PortRange: "443", Sources: &godo.Sources{Addresses: []string{"0.0.0.0/0"}}}
+	egressAll = godo.OutboundRule{Protocol: "tcp", PortRange: "all", Destinations: &godo.Destinations{Addresses: []string{"0.0.0.0/0"}}}
+)
+
+func TestCreateAndListFirewalls(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var created godo.Firewall
+	resp, err := h.CreateFirewall("web", []godo.InboundRule{sshRule}, []godo.OutboundRule{egressAll}, nil, []string{"web"})
+	decodeResponse(t, resp, err, &created)
+	if created.ID == "" || len(created.InboundRules) != 1 || created.Tags[0] != "web" {
+		t.Errorf("created firewall = %+v", created)
+	}
+
+	var listed []map[string]interface{}
+	resp, err = h.ListFirewalls(0, 0)
+	decodeList(t, resp, err, "firewalls", &listed)
+	if len(listed) != 1 || listed[0]["id"] != created.ID || len(listed[0]) != 3 {
+		t.Errorf("listed firewalls = %v", listed)
+	}
+
+	resp, err = h.CreateFirewall("", nil, nil, nil, nil)
+	expectError(t, resp, err, "create_firewall", "422", "name is required")
+
+	fake.FailNext("GET", "/v2/firewalls", http.StatusServiceUnavailable, "firewall service degraded")
+	resp, err = h.ListFirewalls(0, 0)
+	expectError(t, resp, err, "list_firewalls", "503", "firewall service degraded")
+}
+
+func TestFirewallMembership(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		run   func(h *Handler, id string, dropletID int) error
+		check func(fw godo.Firewall, dropletID int) bool
+	}{
+		{
+			name: "add droplets",
+			op:   "add_droplets_to_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.AddDropletsToFirewall(id, []int{dropletID})
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool { return len(fw.DropletIDs) == 2 },
+		},
+		{
+			name: "remove droplets",
+			op:   "remove_droplets_from_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.RemoveDropletsFromFirewall(id, []int{1})
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool { return len(fw.DropletIDs) == 0 },
+		},
+		{
+			name: "add tags",
+			op:   "add_tags_to_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.AddTagsToFirewall(id, []string{"db"})
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool { return len(fw.Tags) == 2 },
+		},
+		{
+			name: "remove tags",
+			op:   "remove_tags_from_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.RemoveTagsFromFirewall(id, []string{"web"})
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool { return len(fw.Tags) == 0 },
+		},
+		{
+			name: "add rules",
+			op:   "add_rules_to_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.AddRulesToFirewall(id, []godo.InboundRule{httpsRule}, nil)
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool { return len(fw.InboundRules) == 2 },
+		},
+		{
+			name: "remove rules",
+			op:   "remove_rules_from_firewall",
+			run: func(h *Handler, id string, dropletID int) error {
+				_, err := h.RemoveRulesFromFirewall(id, []godo.InboundRule{sshRule}, []godo.OutboundRule{egressAll})
+				return err
+			},
+			check: func(fw godo.Firewall, dropletID int) bool {
+				return len(fw.InboundRules) == 0 && len(fw.OutboundRules) == 0
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := seedDroplets(fake, 1)[0]
+			fake.Firewalls.Put("fw-1", godo.Firewall{
+				ID:            "fw-1",
+				Name:          "web",
+				InboundRules:  []godo.InboundRule{sshRule},
+				OutboundRules: []godo.OutboundRule{egressAll},
+				DropletIDs:    []int{1},
+				Tags:          []string{"web"},
+			})
+
+			if err := tt.run(h, "fw-1", dropletID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			firewall, _ := fake.Firewalls.Get("fw-1")
+			if !tt.check(firewall, dropletID) {
+				t.Errorf("firewall after %s = %+v", tt.op, firewall)
+			}
+
+			err := tt.run(h, "fw-missing", dropletID)
+			expectError(t, nil, err, tt.op, "404")
+		})
+	}
+}
+
+func TestUpdateAndDeleteFirewall(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.Firewalls.Put("fw-1", godo.Firewall{ID: "fw-1", Name: "web"})
+
+	var updated godo.Firewall
+	resp, err := h.UpdateFirewall("fw-1", "web-v2", []godo.InboundRule{httpsRule}, nil)
+	decodeResponse(t, resp, err, &updated)
+	if updated.Name != "web-v2" || len(updated.InboundRules) != 1 {
+		t.Errorf("updated firewall = %+v", updated)
+	}
+
+	var fetched godo.Firewall
+	resp, err = h.GetFirewall("fw-1")
+	decodeResponse(t, resp, err, &fetched)
+	if fetched.Name != "web-v2" {
+		t.Errorf("fetched name = %q", fetched.Name)
+	}
+
+	var result map[string]string
+	resp, err = h.DeleteFirewall("fw-1")
+	decodeResponse(t, resp, err, &result)
+	if fake.Firewalls.Len() != 0 {
+		t.Errorf("firewall still exists after delete")
+	}
+
+	resp, err = h.GetFirewall("fw-1")
+	expectError(t, resp, err, "get_firewall", "404")
+}
+
+func TestPreviewDeleteFirewall(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.Firewalls.Put("fw-1", godo.Firewall{
+		ID:           "fw-1",
+		Name:         "web",
+		InboundRules: []godo.InboundRule{sshRule, httpsRule},
+		DropletIDs:   []int{7},
+		Tags:         []string{"web"},
+	})
+	fake.Firewalls.Put("fw-2", godo.Firewall{ID: "fw-2", Name: "unused"})
+
+	preview, err := h.PreviewDeleteFirewall("fw-1")
+	if err != nil {
+		t.Fatalf("PreviewDeleteFirewall: %v", err)
+	}
+	if len(preview.AttachedResources) != 2 || len(preview.Warnings) != 1 {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+
+	preview, err = h.PreviewDeleteFirewall("fw-2")
+	if err != nil {
+		t.Fatalf("PreviewDeleteFirewall: %v", err)
+	}
+	if len(preview.Warnings) != 0 {
+		t.Errorf("an unattached firewall should not warn, got %v", preview.Warnings)
+	}
+}
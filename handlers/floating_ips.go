@@ -2,91 +2,120 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListFloatingIPs() (*mcp_golang.ToolResponse, error) {
+// The floating IP tools are kept for existing clients. DigitalOcean renamed
+// floating IPs to reserved IPs, so they call the reserved IP API, keep their
+// old response shape and add a deprecation notice naming the replacement.
+
+func (h *Handler) ListFloatingIPs(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	floatingIPs, _, err := client.FloatingIPs.List(context.Background(), &godo.ListOptions{})
+
+	reservedIPs, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.ReservedIP, *godo.Response, error) {
+		return client.ReservedIPs.List(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_floating_ips")
 	}
 
-	return h.HandleSuccess(floatingIPs, "list_floating_ips")
+	resp, err := h.HandleSuccess(listResult("floating_ips", reservedIPs, meta), "list_floating_ips")
+	return deprecated(resp, err, "list_reserved_ips")
 }
 
 func (h *Handler) GetFloatingIP(ip string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	floatingIP, _, err := client.FloatingIPs.Get(context.Background(), ip)
+
+	reservedIP, _, err := client.ReservedIPs.Get(context.Background(), ip)
 	if err != nil {
 		return h.HandleError(err, "get_floating_ip")
 	}
 
-	return h.HandleSuccess(floatingIP, "get_floating_ip")
+	resp, err := h.HandleSuccess(reservedIP, "get_floating_ip")
+	return deprecated(resp, err, "get_reserved_ip")
 }
 
-func (h *Handler) CreateFloatingIP(region string, dropletID int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) CreateFloatingIP(region string, dropletID int, projectID string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	var createRequest *godo.FloatingIPCreateRequest
-	
+
+	// A droplet decides the region; otherwise the IP is reserved in region
+	createRequest := &godo.ReservedIPCreateRequest{ProjectID: projectID}
 	if dropletID > 0 {
-		// Assign to specific droplet
-		createRequest = &godo.FloatingIPCreateRequest{
-			DropletID: dropletID,
-		}
+		createRequest.DropletID = dropletID
 	} else {
-		// Create reserved floating IP for region
-		createRequest = &godo.FloatingIPCreateRequest{
-			Region: region,
-		}
+		createRequest.Region = region
 	}
-	
-	floatingIP, _, err := client.FloatingIPs.Create(context.Background(), createRequest)
+
+	reservedIP, _, err := client.ReservedIPs.Create(context.Background(), createRequest)
 	if err != nil {
 		return h.HandleError(err, "create_floating_ip")
 	}
 
-	return h.HandleSuccess(floatingIP, "create_floating_ip")
+	resp, err := h.HandleSuccess(reservedIP, "create_floating_ip")
+	return deprecated(resp, err, "create_reserved_ip")
 }
 
 func (h *Handler) DeleteFloatingIP(ip string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	_, err := client.FloatingIPs.Delete(context.Background(), ip)
+
+	_, err := client.ReservedIPs.Delete(context.Background(), ip)
 	if err != nil {
 		return h.HandleError(err, "delete_floating_ip")
 	}
 
-	return h.HandleSuccess(map[string]string{
+	resp, err := h.HandleSuccess(map[string]string{
 		"status":  "success",
 		"message": fmt.Sprintf("Floating IP %s deleted successfully", ip),
 	}, "delete_floating_ip")
+	return deprecated(resp, err, "delete_reserved_ip")
 }
 
-func (h *Handler) AssignFloatingIP(ip string, dropletID int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) AssignFloatingIP(ip string, dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	action, _, err := client.FloatingIPActions.Assign(context.Background(), ip, dropletID)
+
+	action, _, err := client.ReservedIPActions.Assign(context.Background(), ip, dropletID)
 	if err != nil {
 		return h.HandleError(err, "assign_floating_ip")
 	}
 
-	return h.HandleSuccess(action, "assign_floating_ip")
+	resp, err := h.actionResult(action, wait, "assign_floating_ip")
+	return deprecated(resp, err, "assign_reserved_ip")
 }
 
-func (h *Handler) UnassignFloatingIP(ip string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) UnassignFloatingIP(ip string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
-	
-	action, _, err := client.FloatingIPActions.Unassign(context.Background(), ip)
+
+	action, _, err := client.ReservedIPActions.Unassign(context.Background(), ip)
 	if err != nil {
 		return h.HandleError(err, "unassign_floating_ip")
 	}
 
-	return h.HandleSuccess(action, "unassign_floating_ip")
-}
\ No newline at end of file
+	resp, err := h.actionResult(action, wait, "unassign_floating_ip")
+	return deprecated(resp, err, "unassign_reserved_ip")
+}
+
+// deprecated adds a "deprecation" field naming the replacement tool to a
+// successful JSON object response.
+func deprecated(resp *mcp_golang.ToolResponse, err error, replacement string) (*mcp_golang.ToolResponse, error) {
+	if err != nil || resp == nil || len(resp.Content) != 1 || resp.Content[0].TextContent == nil {
+		return resp, err
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(resp.Content[0].TextContent.Text), &body); err != nil {
+		return resp, nil
+	}
+	notice, _ := json.Marshal(fmt.Sprintf("The floating_ip tools are deprecated because DigitalOcean renamed floating IPs to reserved IPs; use %s instead", replacement))
+	body["deprecation"] = notice
+
+	jsonData, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return resp, nil
+	}
+	return mcp_golang.NewToolResponse(mcp_golang.NewTextContent(string(jsonData))), nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestCreateFloatingIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		region      string
+		withDroplet bool
+		wantRegion  string
+		wantErr     string
+	}{
+		{name: "reserved in region", region: "sfo3", wantRegion: "sfo3"},
+		{name: "assigned to droplet", withDroplet: true, wantRegion: "nyc3"},
+		{name: "neither region nor droplet", wantErr: "region or droplet_id is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := 0
+			if tt.withDroplet {
+				dropletID = seedDroplets(fake, 1)[0]
+			}
+
+			resp, err := h.CreateFloatingIP(tt.region, dropletID, "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_floating_ip", "422", tt.wantErr)
+				return
+			}
+
+			var floatingIP godo.FloatingIP
+			decodeResponse(t, resp, err, &floatingIP)
+			if regionSlugOf(floatingIP.Region) != tt.wantRegion {
+				t.Errorf("region = %q, want %q", regionSlugOf(floatingIP.Region), tt.wantRegion)
+			}
+			if tt.withDroplet && (floatingIP.Droplet == nil || floatingIP.Droplet.ID != dropletID) {
+				t.Errorf("floating IP is not assigned to droplet %d", dropletID)
+			}
+		})
+	}
+}
+
+func TestFloatingIPLifecycle(t *testing.T) {
+	h, fake := newTestHandler(t)
+	dropletID := seedDroplets(fake, 1)[0]
+
+	var created godo.FloatingIP
+	resp, err := h.CreateFloatingIP("nyc3", 0, "")
+	decodeResponse(t, resp, err, &created)
+
+	var listed []godo.FloatingIP
+	resp, err = h.ListFloatingIPs(0, 0)
+	decodeList(t, resp, err, "floating_ips", &listed)
+	if len(listed) != 1 || listed[0].IP != created.IP {
+		t.Fatalf("list = %+v", listed)
+	}
+
+	resp, err = h.UnassignFloatingIP(created.IP, 0)
+	expectError(t, resp, err, "unassign_floating_ip", "not assigned")
+
+	var action godo.Action
+	resp, err = h.AssignFloatingIP(created.IP, dropletID, 0)
+	decodeResponse(t, resp, err, &action)
+	if action.Type != "assign" {
+		t.Errorf("action type = %q", action.Type)
+	}
+
+	var fetched godo.FloatingIP
+	resp, err = h.GetFloatingIP(created.IP)
+	decodeResponse(t, resp, err, &fetched)
+	if fetched.Droplet == nil || fetched.Droplet.ID != dropletID {
+		t.Errorf("floating IP droplet = %+v", fetched.Droplet)
+	}
+
+	resp, err = h.UnassignFloatingIP(created.IP, 0)
+	decodeResponse(t, resp, err, &action)
+
+	var result map[string]string
+	resp, err = h.DeleteFloatingIP(created.IP)
+	decodeResponse(t, resp, err, &result)
+	if result["status"] != "success" {
+		t.Errorf("status = %q", result["status"])
+	}
+
+	resp, err = h.GetFloatingIP(created.IP)
+	expectError(t, resp, err, "get_floating_ip", "404")
+}
+
+func TestAssignFloatingIPToMissingDroplet(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.ReservedIPs.Put("203.0.113.9", godo.ReservedIP{IP: "203.0.113.9", Region: &godo.Region{Slug: "nyc3"}})
+
+	resp, err := h.AssignFloatingIP("203.0.113.9", 1, 0)
+	expectError(t, resp, err, "assign_floating_ip", "422", "Droplet 1 not found")
+}
+
+func TestFloatingIPDeprecationNotice(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var created struct {
+		godo.ReservedIP
+		Deprecation string `json:"deprecation"`
+	}
+	resp, err := h.CreateFloatingIP("nyc3", 0, "")
+	decodeResponse(t, resp, err, &created)
+	if created.IP == "" || !strings.Contains(created.Deprecation, "use create_reserved_ip instead") {
+		t.Errorf("created = %+v", created)
+	}
+
+	// The alias and the replacement see the same address
+	var reservedIP ReservedIP
+	resp, err = h.GetReservedIP(created.IP)
+	decodeResponse(t, resp, err, &reservedIP)
+	if reservedIP.Version != "ipv4" {
+		t.Errorf("reserved IP = %+v", reservedIP)
+	}
+
+	var listed struct {
+		Deprecation string `json:"deprecation"`
+	}
+	resp, err = h.ListFloatingIPs(0, 0)
+	decodeList(t, resp, err, "floating_ips", &[]godo.ReservedIP{})
+	decodeResponse(t, resp, err, &listed)
+	if !strings.Contains(listed.Deprecation, "list_reserved_ips") {
+		t.Errorf("list deprecation = %q", listed.Deprecation)
+	}
+
+	resp, err = h.GetFloatingIP("203.0.113.250")
+	expectError(t, resp, err, "get_floating_ip", "404")
+}
@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// functionsRegions are the regions Functions namespaces can be created in.
+var functionsRegions = []string{"ams3", "blr1", "fra1", "lon1", "nyc1", "sfo3", "sgp1", "syd1", "tor1"}
+
+// scheduledTrigger is the only trigger type Functions offers.
+const scheduledTrigger = "SCHEDULED"
+
+// FunctionsTriggerOptions describes a new scheduled trigger.
+type FunctionsTriggerOptions struct {
+	Name     string
+	Function string
+	Cron     string
+	Body     map[string]interface{}
+	Enabled  *bool
+}
+
+// FunctionsTriggerUpdate holds the fields to change on a trigger. A non-nil
+// Body replaces the current one.
+type FunctionsTriggerUpdate struct {
+	Enabled *bool
+	Cron    *string
+	Body    map[string]interface{}
+}
+
+// ScheduledTrigger is one entry of the schedule summary.
+type ScheduledTrigger struct {
+	Namespace      string     `json:"namespace"`
+	NamespaceLabel string     `json:"namespace_label"`
+	Trigger        string     `json:"trigger"`
+	Function       string     `json:"function"`
+	Cron           string     `json:"cron"`
+	Enabled        bool       `json:"enabled"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	NextRunIn      string     `json:"next_run_in,omitempty"`
+	Note           string     `json:"note,omitempty"`
+}
+
+// ListFunctionsNamespaces lists namespaces without their keys; use
+// get_functions_namespace with show_credentials for a key.
+func (h *Handler) ListFunctionsNamespaces() (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	namespaces, _, err := client.Functions.ListNamespaces(context.Background())
+	if err != nil {
+		return h.HandleError(err, "list_functions_namespaces")
+	}
+	if namespaces == nil {
+		namespaces = []godo.FunctionsNamespace{}
+	}
+	for i := range namespaces {
+		namespaces[i].Key = maskIfSet(namespaces[i].Key)
+	}
+
+	return h.HandleSuccess(filteredListResult("namespaces", namespaces), "list_functions_namespaces")
+}
+
+func (h *Handler) GetFunctionsNamespace(namespaceRef string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "get_functions_namespace")
+	}
+
+	if !showCredentials {
+		namespace.Key = maskIfSet(namespace.Key)
+	}
+
+	return h.HandleSuccess(namespace, "get_functions_namespace")
+}
+
+func (h *Handler) CreateFunctionsNamespace(label, region string, showCredentials bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if label == "" {
+		return h.HandleError(fmt.Errorf("label is required"), "create_functions_namespace")
+	}
+	if !slices.Contains(functionsRegions, region) {
+		return h.HandleError(fmt.Errorf("invalid region %q for Functions; try: %s", region, strings.Join(suggest(region, functionsRegions), ", ")), "create_functions_namespace")
+	}
+
+	namespace, _, err := client.Functions.CreateNamespace(context.Background(), &godo.FunctionsNamespaceCreateRequest{
+		Label:  label,
+		Region: region,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_functions_namespace")
+	}
+
+	if !showCredentials {
+		namespace.Key = maskIfSet(namespace.Key)
+	}
+
+	return h.HandleSuccess(namespace, "create_functions_namespace")
+}
+
+func (h *Handler) DeleteFunctionsNamespace(namespaceRef string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "delete_functions_namespace")
+	}
+
+	_, err = client.Functions.DeleteNamespace(context.Background(), namespace.Namespace)
+	if err != nil {
+		return h.HandleError(err, "delete_functions_namespace")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Functions namespace %s (%s) deleted successfully", namespace.Label, namespace.Namespace),
+	}, "delete_functions_namespace")
+}
+
+// PreviewDeleteFunctionsNamespace lists the triggers deleted along with the
+// namespace.
+func (h *Handler) PreviewDeleteFunctionsNamespace(namespaceRef string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return nil, err
+	}
+	triggers, _, err := client.Functions.ListTriggers(context.Background(), namespace.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "functions_namespace",
+		ID:           namespace.Namespace,
+		Name:         namespace.Label,
+		Region:       namespace.Region,
+		Warnings:     []string{"Every function deployed to the namespace is deleted and its API host stops answering"},
+	}
+	for _, trigger := range triggers {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":     "functions_trigger",
+			"name":     trigger.Name,
+			"function": trigger.Function,
+		})
+	}
+	if len(triggers) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d trigger(s) are deleted and their scheduled runs stop", len(triggers)))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListFunctionsTriggers(namespaceRef string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "list_functions_triggers")
+	}
+
+	triggers, _, err := client.Functions.ListTriggers(context.Background(), namespace.Namespace)
+	if err != nil {
+		return h.HandleError(err, "list_functions_triggers")
+	}
+	if triggers == nil {
+		triggers = []godo.FunctionsTrigger{}
+	}
+
+	return h.HandleSuccess(filteredListResult("triggers", triggers), "list_functions_triggers")
+}
+
+func (h *Handler) GetFunctionsTrigger(namespaceRef, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "get_functions_trigger")
+	}
+
+	trigger, _, err := client.Functions.GetTrigger(context.Background(), namespace.Namespace, name)
+	if err != nil {
+		return h.HandleError(err, "get_functions_trigger")
+	}
+
+	return h.HandleSuccess(trigger, "get_functions_trigger")
+}
+
+// CreateFunctionsTrigger runs a function on a cron schedule, in UTC, with
+// body as its parameters.
+func (h *Handler) CreateFunctionsTrigger(namespaceRef string, opts FunctionsTriggerOptions) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	switch {
+	case opts.Name == "":
+		return h.HandleError(fmt.Errorf("name is required"), "create_functions_trigger")
+	case opts.Function == "":
+		return h.HandleError(fmt.Errorf("function is required, e.g. 'package/function'"), "create_functions_trigger")
+	}
+	if err := validateTriggerCron(opts.Cron); err != nil {
+		return h.HandleError(err, "create_functions_trigger")
+	}
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "create_functions_trigger")
+	}
+	enabled := true
+	if opts.Enabled != nil {
+		enabled = *opts.Enabled
+	}
+
+	trigger, _, err := client.Functions.CreateTrigger(context.Background(), namespace.Namespace, &godo.FunctionsTriggerCreateRequest{
+		Name:      opts.Name,
+		Type:      scheduledTrigger,
+		Function:  opts.Function,
+		IsEnabled: enabled,
+		ScheduledDetails: &godo.TriggerScheduledDetails{
+			Cron: opts.Cron,
+			Body: opts.Body,
+		},
+	})
+	if err != nil {
+		return h.HandleError(err, "create_functions_trigger")
+	}
+
+	return h.HandleSuccess(trigger, "create_functions_trigger")
+}
+
+// UpdateFunctionsTrigger enables or disables a trigger or changes its
+// schedule. The API sets the cron and body together, so an omitted one keeps
+// its current value.
+func (h *Handler) UpdateFunctionsTrigger(namespaceRef, name string, update FunctionsTriggerUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if update.Enabled == nil && update.Cron == nil && update.Body == nil {
+		return h.HandleError(fmt.Errorf("nothing to update: set enabled, cron or body"), "update_functions_trigger")
+	}
+	if update.Cron != nil {
+		if err := validateTriggerCron(*update.Cron); err != nil {
+			return h.HandleError(err, "update_functions_trigger")
+		}
+	}
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "update_functions_trigger")
+	}
+
+	updateRequest := &godo.FunctionsTriggerUpdateRequest{IsEnabled: update.Enabled}
+	if update.Cron != nil || update.Body != nil {
+		current, _, err := client.Functions.GetTrigger(context.Background(), namespace.Namespace, name)
+		if err != nil {
+			return h.HandleError(err, "update_functions_trigger")
+		}
+		details := &godo.TriggerScheduledDetails{}
+		if current.ScheduledDetails != nil {
+			*details = *current.ScheduledDetails
+		}
+		if update.Cron != nil {
+			details.Cron = *update.Cron
+		}
+		if update.Body != nil {
+			details.Body = update.Body
+		}
+		updateRequest.ScheduledDetails = details
+	}
+
+	trigger, _, err := client.Functions.UpdateTrigger(context.Background(), namespace.Namespace, name, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_functions_trigger")
+	}
+
+	return h.HandleSuccess(trigger, "update_functions_trigger")
+}
+
+func (h *Handler) DeleteFunctionsTrigger(namespaceRef, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return h.HandleError(err, "delete_functions_trigger")
+	}
+
+	_, err = client.Functions.DeleteTrigger(context.Background(), namespace.Namespace, name)
+	if err != nil {
+		return h.HandleError(err, "delete_functions_trigger")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Functions trigger %s deleted successfully", name),
+	}, "delete_functions_trigger")
+}
+
+// PreviewDeleteFunctionsTrigger names the function that stops running on the
+// trigger's schedule.
+func (h *Handler) PreviewDeleteFunctionsTrigger(namespaceRef, name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	namespace, err := h.resolveNamespace(namespaceRef)
+	if err != nil {
+		return nil, err
+	}
+	trigger, _, err := client.Functions.GetTrigger(context.Background(), namespace.Namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "functions_trigger",
+		ID:           trigger.Name,
+		Name:         trigger.Name,
+		Region:       namespace.Region,
+		AttachedResources: []map[string]interface{}{
+			{"type": "function", "name": trigger.Function, "namespace": namespace.Label},
+		},
+	}
+	if trigger.IsEnabled && trigger.ScheduledDetails != nil {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s stops running on %q; the function itself is kept", trigger.Function, trigger.ScheduledDetails.Cron))
+	}
+
+	return preview, nil
+}
+
+// GetFunctionsSchedule lists the triggers of one namespace, or of every
+// namespace when namespaceRef is empty, with when each will next fire. The
+// API's next run time is used when it has one; otherwise it is worked out
+// from the cron expression.
+func (h *Handler) GetFunctionsSchedule(namespaceRef string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	var namespaces []godo.FunctionsNamespace
+	if namespaceRef != "" {
+		namespace, err := h.resolveNamespace(namespaceRef)
+		if err != nil {
+			return h.HandleError(err, "get_functions_schedule")
+		}
+		namespaces = []godo.FunctionsNamespace{*namespace}
+	} else {
+		var err error
+		if namespaces, _, err = client.Functions.ListNamespaces(context.Background()); err != nil {
+			return h.HandleError(err, "get_functions_schedule")
+		}
+	}
+
+	now := time.Now().UTC()
+	schedule := []ScheduledTrigger{}
+	for _, namespace := range namespaces {
+		triggers, _, err := client.Functions.ListTriggers(context.Background(), namespace.Namespace)
+		if err != nil {
+			return h.HandleError(err, "get_functions_schedule")
+		}
+		for _, trigger := range triggers {
+			schedule = append(schedule, scheduledTriggerAt(namespace, trigger, now))
+		}
+	}
+	sortSchedule(schedule)
+
+	scheduled := 0
+	for _, entry := range schedule {
+		if entry.NextRunAt != nil {
+			scheduled++
+		}
+	}
+	return h.HandleSuccess(map[string]interface{}{
+		"now":       now.Truncate(time.Second),
+		"timezone":  "UTC",
+		"total":     len(schedule),
+		"scheduled": scheduled,
+		"triggers":  schedule,
+	}, "get_functions_schedule")
+}
+
+// resolveNamespace finds a namespace by ID or label.
+func (h *Handler) resolveNamespace(ref string) (*godo.FunctionsNamespace, error) {
+	client := h.doClient.GetClient()
+
+	if ref == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	namespaces, _, err := client.Functions.ListNamespaces(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*godo.FunctionsNamespace
+	labels := make([]string, 0, len(namespaces))
+	for i := range namespaces {
+		if namespaces[i].Namespace == ref {
+			return &namespaces[i], nil
+		}
+		if namespaces[i].Label == ref {
+			matches = append(matches, &namespaces[i])
+		}
+		labels = append(labels, namespaces[i].Label)
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("namespace label %q is ambiguous; use its ID", ref)
+	case len(labels) == 0:
+		return nil, fmt.Errorf("namespace %q not found; the account has no Functions namespaces", ref)
+	default:
+		return nil, fmt.Errorf("namespace %q not found; try: %s", ref, strings.Join(suggest(ref, labels), ", "))
+	}
+}
+
+// scheduledTriggerAt describes when trigger next fires after now.
+func scheduledTriggerAt(namespace godo.FunctionsNamespace, trigger godo.FunctionsTrigger, now time.Time) ScheduledTrigger {
+	entry := ScheduledTrigger{
+		Namespace:      namespace.Namespace,
+		NamespaceLabel: namespace.Label,
+		Trigger:        trigger.Name,
+		Function:       trigger.Function,
+		Enabled:        trigger.IsEnabled,
+	}
+	if trigger.ScheduledDetails != nil {
+		entry.Cron = trigger.ScheduledDetails.Cron
+	}
+	if runs := trigger.ScheduledRuns; runs != nil && !runs.LastRunAt.IsZero() {
+		last := runs.LastRunAt.UTC()
+		entry.LastRunAt = &last
+	}
+	if !trigger.IsEnabled {
+		entry.Note = "disabled; enable it with update_functions_trigger"
+		return entry
+	}
+
+	var next time.Time
+	if runs := trigger.ScheduledRuns; runs != nil && runs.NextRunAt.After(now) {
+		next = runs.NextRunAt.UTC()
+	} else {
+		schedule, err := parseCron(entry.Cron)
+		if err != nil {
+			entry.Note = err.Error()
+			return entry
+		}
+		var ok bool
+		if next, ok = schedule.next(now); !ok {
+			entry.Note = "the cron expression never matches a date"
+			return entry
+		}
+	}
+	entry.NextRunAt = &next
+	entry.NextRunIn = next.Sub(now).Round(time.Second).String()
+	return entry
+}
+
+// sortSchedule puts the triggers that fire soonest first, followed by those
+// that will not fire, by namespace and name.
+func sortSchedule(schedule []ScheduledTrigger) {
+	slices.SortStableFunc(schedule, func(a, b ScheduledTrigger) int {
+		switch {
+		case a.NextRunAt != nil && b.NextRunAt != nil:
+			if c := a.NextRunAt.Compare(*b.NextRunAt); c != 0 {
+				return c
+			}
+		case a.NextRunAt != nil:
+			return -1
+		case b.NextRunAt != nil:
+			return 1
+		}
+		if c := strings.Compare(a.NamespaceLabel, b.NamespaceLabel); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Trigger, b.Trigger)
+	})
+}
+
+// validateTriggerCron checks that cron parses and fires at some point.
+func validateTriggerCron(cron string) error {
+	if cron == "" {
+		return fmt.Errorf("cron is required, e.g. '0 9 * * mon-fri' for 09:00 UTC on weekdays")
+	}
+	schedule, err := parseCron(cron)
+	if err != nil {
+		return err
+	}
+	if _, ok := schedule.next(time.Now()); !ok {
+		return fmt.Errorf("cron %q never fires", cron)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// createNamespace creates a namespace through the handler and returns it.
+func createNamespace(t *testing.T, h *Handler, label, region string) godo.FunctionsNamespace {
+	t.Helper()
+	var namespace godo.FunctionsNamespace
+	resp, err := h.CreateFunctionsNamespace(label, region, false)
+	decodeResponse(t, resp, err, &namespace)
+	return namespace
+}
+
+func TestFunctionsNamespaceCRUD(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var created godo.FunctionsNamespace
+	resp, err := h.CreateFunctionsNamespace("jobs", "nyc1", true)
+	decodeResponse(t, resp, err, &created)
+	if created.Label != "jobs" || created.Region != "nyc1" || created.Key == maskedCredential || created.ApiHost == "" {
+		t.Errorf("namespace = %+v", created)
+	}
+	masked := createNamespace(t, h, "web", "ams3")
+	if masked.Key != maskedCredential {
+		t.Errorf("key = %q, want it masked", masked.Key)
+	}
+
+	var namespaces []godo.FunctionsNamespace
+	resp, err = h.ListFunctionsNamespaces()
+	meta := decodeList(t, resp, err, "namespaces", &namespaces)
+	if meta.Total != 2 {
+		t.Errorf("total = %d, want 2", meta.Total)
+	}
+	for _, namespace := range namespaces {
+		if namespace.Key != maskedCredential {
+			t.Errorf("listed key = %q, want it masked", namespace.Key)
+		}
+	}
+
+	tests := []struct {
+		name            string
+		ref             string
+		showCredentials bool
+		wantKey         string
+		wantErr         string
+	}{
+		{name: "by id", ref: created.Namespace, wantKey: maskedCredential},
+		{name: "by label with key", ref: "jobs", showCredentials: true, wantKey: created.Key},
+		{name: "label typo", ref: "job", wantErr: `namespace "job" not found; try: jobs`},
+		{name: "no namespace", wantErr: "namespace is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.GetFunctionsNamespace(tt.ref, tt.showCredentials)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "get_functions_namespace", tt.wantErr)
+				return
+			}
+			var namespace godo.FunctionsNamespace
+			decodeResponse(t, resp, err, &namespace)
+			if namespace.Namespace != created.Namespace || namespace.Key != tt.wantKey {
+				t.Errorf("namespace = %+v", namespace)
+			}
+		})
+	}
+
+	resp, err = h.DeleteFunctionsNamespace("web")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.FunctionsNamespaces.Len() != 1 {
+		t.Errorf("namespaces = %d after delete, want 1", fake.FunctionsNamespaces.Len())
+	}
+}
+
+func TestCreateFunctionsNamespaceValidation(t *testing.T) {
+	h, fake := newTestHandler(t)
+	createNamespace(t, h, "jobs", "nyc1")
+
+	tests := []struct {
+		name    string
+		label   string
+		region  string
+		wantErr []string
+	}{
+		{name: "no label", region: "nyc1", wantErr: []string{"label is required"}},
+		{name: "droplet region", label: "web", region: "nyc3", wantErr: []string{`invalid region "nyc3" for Functions`, "try: nyc1"}},
+		{name: "taken label", label: "jobs", region: "fra1", wantErr: []string{"422", "already exists"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.CreateFunctionsNamespace(tt.label, tt.region, false)
+			expectError(t, resp, err, "create_functions_namespace", tt.wantErr...)
+		})
+	}
+	if fake.FunctionsNamespaces.Len() != 1 {
+		t.Errorf("namespaces = %d, want only the first", fake.FunctionsNamespaces.Len())
+	}
+}
+
+func TestCreateFunctionsTrigger(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name        string
+		opts        FunctionsTriggerOptions
+		wantEnabled bool
+		wantErr     []string
+	}{
+		{name: "enabled by default", opts: FunctionsTriggerOptions{Name: "nightly", Function: "reports/build", Cron: "0 2 * * *", Body: map[string]interface{}{"full": true}}, wantEnabled: true},
+		{name: "disabled", opts: FunctionsTriggerOptions{Name: "weekly", Function: "reports/build", Cron: "0 9 * * mon", Enabled: &disabled}},
+		{name: "no name", opts: FunctionsTriggerOptions{Function: "reports/build", Cron: "0 2 * * *"}, wantErr: []string{"name is required"}},
+		{name: "no function", opts: FunctionsTriggerOptions{Name: "nightly", Cron: "0 2 * * *"}, wantErr: []string{"function is required"}},
+		{name: "no cron", opts: FunctionsTriggerOptions{Name: "nightly", Function: "reports/build"}, wantErr: []string{"cron is required"}},
+		{name: "bad cron", opts: FunctionsTriggerOptions{Name: "nightly", Function: "reports/build", Cron: "0 25 * * *"}, wantErr: []string{`invalid cron "0 25 * * *": hour 25 is out of range 0-23`}},
+		{name: "never fires", opts: FunctionsTriggerOptions{Name: "nightly", Function: "reports/build", Cron: "0 0 31 feb *"}, wantErr: []string{"never fires"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			namespace := createNamespace(t, h, "jobs", "nyc1")
+
+			resp, err := h.CreateFunctionsTrigger("jobs", tt.opts)
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_functions_trigger", tt.wantErr...)
+				if fake.FunctionsTriggers.Len() != 0 {
+					t.Error("trigger was created")
+				}
+				return
+			}
+
+			var trigger godo.FunctionsTrigger
+			decodeResponse(t, resp, err, &trigger)
+			if trigger.Namespace != namespace.Namespace || trigger.Type != "SCHEDULED" || trigger.IsEnabled != tt.wantEnabled || trigger.ScheduledDetails.Cron != tt.opts.Cron {
+				t.Errorf("trigger = %+v", trigger)
+			}
+		})
+	}
+
+	h, _ := newTestHandler(t)
+	resp, err := h.CreateFunctionsTrigger("jobs", FunctionsTriggerOptions{Name: "nightly", Function: "reports/build", Cron: "0 2 * * *"})
+	expectError(t, resp, err, "create_functions_trigger", "no Functions namespaces")
+}
+
+func TestUpdateFunctionsTrigger(t *testing.T) {
+	h, fake := newTestHandler(t)
+	namespace := createNamespace(t, h, "jobs", "nyc1")
+	resp, err := h.CreateFunctionsTrigger("jobs", FunctionsTriggerOptions{Name: "nightly", Function: "reports/build", Cron: "0 2 * * *", Body: map[string]interface{}{"full": true}})
+	decodeResponse(t, resp, err, &godo.FunctionsTrigger{})
+
+	disabled := false
+	cron := "30 3 * * *"
+	badCron := "30 3 * *"
+	steps := []struct {
+		name    string
+		update  FunctionsTriggerUpdate
+		wantErr string
+		check   func(trigger godo.FunctionsTrigger) bool
+	}{
+		{name: "nothing", wantErr: "nothing to update"},
+		{name: "bad cron", update: FunctionsTriggerUpdate{Cron: &badCron}, wantErr: "expected 5 fields"},
+		{
+			name:   "cron keeps the body",
+			update: FunctionsTriggerUpdate{Cron: &cron},
+			check: func(trigger godo.FunctionsTrigger) bool {
+				return trigger.ScheduledDetails.Cron == cron && trigger.ScheduledDetails.Body["full"] == true && trigger.IsEnabled
+			},
+		},
+		{
+			name:   "body keeps the cron",
+			update: FunctionsTriggerUpdate{Body: map[string]interface{}{"full": false}},
+			check: func(trigger godo.FunctionsTrigger) bool {
+				return trigger.ScheduledDetails.Cron == cron && trigger.ScheduledDetails.Body["full"] == false
+			},
+		},
+		{
+			name:   "disable",
+			update: FunctionsTriggerUpdate{Enabled: &disabled},
+			check: func(trigger godo.FunctionsTrigger) bool {
+				return !trigger.IsEnabled && trigger.ScheduledDetails.Cron == cron
+			},
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			resp, err := h.UpdateFunctionsTrigger(namespace.Namespace, "nightly", step.update)
+			if step.wantErr != "" {
+				expectError(t, resp, err, "update_functions_trigger", step.wantErr)
+				return
+			}
+			var trigger godo.FunctionsTrigger
+			decodeResponse(t, resp, err, &trigger)
+			if !step.check(trigger) {
+				t.Errorf("trigger = %+v", trigger)
+			}
+		})
+	}
+
+	if stored, _ := fake.FunctionsTriggers.Get(namespace.Namespace + "/nightly"); stored.IsEnabled {
+		t.Error("trigger is still enabled")
+	}
+	resp, err = h.UpdateFunctionsTrigger("jobs", "hourly", FunctionsTriggerUpdate{Cron: &cron})
+	expectError(t, resp, err, "update_functions_trigger", "404")
+}
+
+func TestFunctionsTriggerListAndDelete(t *testing.T) {
+	h, fake := newTestHandler(t)
+	namespace := createNamespace(t, h, "jobs", "nyc1")
+	for _, name := range []string{"nightly", "hourly"} {
+		resp, err := h.CreateFunctionsTrigger("jobs", FunctionsTriggerOptions{Name: name, Function: "reports/" + name, Cron: "0 * * * *"})
+		decodeResponse(t, resp, err, &godo.FunctionsTrigger{})
+	}
+
+	var triggers []godo.FunctionsTrigger
+	resp, err := h.ListFunctionsTriggers("jobs")
+	meta := decodeList(t, resp, err, "triggers", &triggers)
+	if meta.Total != 2 {
+		t.Errorf("triggers = %+v", triggers)
+	}
+
+	var trigger godo.FunctionsTrigger
+	resp, err = h.GetFunctionsTrigger(namespace.Namespace, "hourly")
+	decodeResponse(t, resp, err, &trigger)
+	if trigger.Function != "reports/hourly" {
+		t.Errorf("trigger = %+v", trigger)
+	}
+
+	preview, err := h.PreviewDeleteFunctionsTrigger("jobs", "hourly")
+	if err != nil {
+		t.Fatalf("PreviewDeleteFunctionsTrigger: %v", err)
+	}
+	if preview.ResourceType != "functions_trigger" || preview.AttachedResources[0]["name"] != "reports/hourly" || len(preview.Warnings) != 1 {
+		t.Errorf("preview = %+v", preview)
+	}
+	resp, err = h.DeleteFunctionsTrigger("jobs", "hourly")
+	decodeResponse(t, resp, err, &map[string]string{})
+	resp, err = h.GetFunctionsTrigger("jobs", "hourly")
+	expectError(t, resp, err, "get_functions_trigger", "404")
+
+	preview, err = h.PreviewDeleteFunctionsNamespace("jobs")
+	if err != nil {
+		t.Fatalf("PreviewDeleteFunctionsNamespace: %v", err)
+	}
+	if preview.Region != "nyc1" || len(preview.AttachedResources) != 1 || len(preview.Warnings) != 2 {
+		t.Errorf("preview = %+v", preview)
+	}
+	resp, err = h.DeleteFunctionsNamespace("jobs")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.FunctionsTriggers.Len() != 0 {
+		t.Error("the namespace's triggers were not deleted")
+	}
+}
+
+func TestGetFunctionsSchedule(t *testing.T) {
+	h, fake := newTestHandler(t)
+	jobs := createNamespace(t, h, "jobs", "nyc1")
+	createNamespace(t, h, "web", "ams3")
+
+	disabled := false
+	for _, tt := range []struct {
+		namespace string
+		opts      FunctionsTriggerOptions
+	}{
+		{namespace: "jobs", opts: FunctionsTriggerOptions{Name: "yearly", Function: "reports/archive", Cron: "0 0 1 1 *"}},
+		{namespace: "jobs", opts: FunctionsTriggerOptions{Name: "paused", Function: "reports/build", Cron: "* * * * *", Enabled: &disabled}},
+		{namespace: "web", opts: FunctionsTriggerOptions{Name: "minutely", Function: "cache/warm", Cron: "* * * * *"}},
+		{namespace: "web", opts: FunctionsTriggerOptions{Name: "api-planned", Function: "cache/purge", Cron: "0 0 1 1 *"}},
+	} {
+		resp, err := h.CreateFunctionsTrigger(tt.namespace, tt.opts)
+		decodeResponse(t, resp, err, &godo.FunctionsTrigger{})
+	}
+	// The API's own next run time wins over the cron expression
+	webNamespace, _ := h.resolveNamespace("web")
+	apiNext := time.Now().UTC().Add(5 * time.Minute).Truncate(time.Second)
+	lastRun := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	fake.FunctionsTriggers.Update(webNamespace.Namespace+"/api-planned", func(trigger *godo.FunctionsTrigger) {
+		trigger.ScheduledRuns = &godo.TriggerScheduledRuns{LastRunAt: lastRun, NextRunAt: apiNext}
+	})
+
+	var schedule struct {
+		Total     int                `json:"total"`
+		Scheduled int                `json:"scheduled"`
+		Timezone  string             `json:"timezone"`
+		Triggers  []ScheduledTrigger `json:"triggers"`
+	}
+	resp, err := h.GetFunctionsSchedule("")
+	decodeResponse(t, resp, err, &schedule)
+	if schedule.Total != 4 || schedule.Scheduled != 3 || schedule.Timezone != "UTC" {
+		t.Errorf("schedule = %+v", schedule)
+	}
+	var order []string
+	for _, entry := range schedule.Triggers {
+		order = append(order, entry.Trigger)
+	}
+	if len(order) != 4 || order[0] != "minutely" || order[1] != "api-planned" || order[2] != "yearly" || order[3] != "paused" {
+		t.Fatalf("order = %v, want soonest first and disabled last", order)
+	}
+
+	planned := schedule.Triggers[1]
+	if planned.NextRunAt == nil || !planned.NextRunAt.Equal(apiNext) || planned.LastRunAt == nil || !planned.LastRunAt.Equal(lastRun) || planned.NextRunIn == "" {
+		t.Errorf("api-planned = %+v", planned)
+	}
+	yearly := schedule.Triggers[2]
+	if yearly.NextRunAt == nil || yearly.NextRunAt.Month() != time.January || yearly.NextRunAt.Day() != 1 || yearly.NamespaceLabel != "jobs" {
+		t.Errorf("yearly = %+v", yearly)
+	}
+	paused := schedule.Triggers[3]
+	if paused.NextRunAt != nil || paused.Enabled || paused.Note == "" {
+		t.Errorf("paused = %+v", paused)
+	}
+
+	resp, err = h.GetFunctionsSchedule(jobs.Namespace)
+	decodeResponse(t, resp, err, &schedule)
+	if schedule.Total != 2 || schedule.Triggers[0].Trigger != "yearly" {
+		t.Errorf("jobs schedule = %+v", schedule)
+	}
+	resp, err = h.GetFunctionsSchedule("jbos")
+	expectError(t, resp, err, "get_functions_schedule", "try: jobs")
+}
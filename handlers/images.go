@@ -4,36 +4,37 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListImages(imageType string, isPublic bool) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListImages(imageType string, isPublic bool, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	listOptions := &godo.ListOptions{}
-	
-	var images []godo.Image
-	var err error
-	
+	var list func(context.Context, *godo.ListOptions) ([]godo.Image, *godo.Response, error)
 	switch imageType {
 	case "distribution":
-		images, _, err = client.Images.ListDistribution(context.Background(), listOptions)
+		list = client.Images.ListDistribution
 	case "application":
-		images, _, err = client.Images.ListApplication(context.Background(), listOptions)
+		list = client.Images.ListApplication
 	case "user":
-		images, _, err = client.Images.ListUser(context.Background(), listOptions)
+		list = client.Images.ListUser
 	default:
 		// List all images
-		images, _, err = client.Images.List(context.Background(), listOptions)
+		list = client.Images.List
 	}
 	
+	images, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+		return list(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_images")
 	}
 
-	return h.HandleSuccess(images, "list_images")
+	return h.HandleSuccess(listResult("images", images, meta), "list_images")
 }
 
 func (h *Handler) GetImage(imageID string) (*mcp_golang.ToolResponse, error) {
@@ -95,7 +96,7 @@ func (h *Handler) DeleteImage(imageID string) (*mcp_golang.ToolResponse, error)
 	}, "delete_image")
 }
 
-func (h *Handler) TransferImage(imageID, regionSlug string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) TransferImage(imageID, regionSlug string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	id, err := strconv.Atoi(imageID)
@@ -113,10 +114,10 @@ func (h *Handler) TransferImage(imageID, regionSlug string) (*mcp_golang.ToolRes
 		return h.HandleError(err, "transfer_image")
 	}
 
-	return h.HandleSuccess(action, "transfer_image")
+	return h.actionResult(action, wait, "transfer_image")
 }
 
-func (h *Handler) ConvertImageToSnapshot(imageID string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ConvertImageToSnapshot(imageID string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	id, err := strconv.Atoi(imageID)
@@ -129,5 +130,34 @@ func (h *Handler) ConvertImageToSnapshot(imageID string) (*mcp_golang.ToolRespon
 		return h.HandleError(err, "convert_image_to_snapshot")
 	}
 
-	return h.HandleSuccess(action, "convert_image_to_snapshot")
-}
\ No newline at end of file
+	return h.actionResult(action, wait, "convert_image_to_snapshot")
+}
+
+func (h *Handler) PreviewDeleteImage(imageID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	id, err := strconv.Atoi(imageID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image ID: %s", imageID)
+	}
+
+	image, _, err := client.Images.GetByID(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "image",
+		ID:                   imageID,
+		Name:                 image.Name,
+		EstimatedMonthlyCost: image.SizeGigaBytes * snapshotPricePerGB * float64(len(image.Regions)),
+	}
+	if len(image.Regions) > 0 {
+		preview.Region = strings.Join(image.Regions, ",")
+	}
+	if image.Public {
+		preview.Warnings = append(preview.Warnings, "This is a public image and cannot be deleted")
+	}
+
+	return preview, nil
+}
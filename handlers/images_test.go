@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func TestListImages(t *testing.T) {
+	h, fake := newTestHandler(t)
+	for _, image := range []godo.Image{
+		{Name: "Ubuntu 22.04", Slug: "ubuntu-22-04-x64", Type: "base", Public: true},
+		{Name: "Docker", Slug: "docker-20-04", Type: "application", Public: true},
+		{Name: "my-golden-image", Type: "custom"},
+	} {
+		image.ID = fake.NextID()
+		fake.Images.Put(image.ID, image)
+	}
+
+	tests := []struct {
+		imageType string
+		wantNames []string
+	}{
+		{imageType: "", wantNames: []string{"Ubuntu 22.04", "Docker", "my-golden-image"}},
+		{imageType: "distribution", wantNames: []string{"Ubuntu 22.04"}},
+		{imageType: "application", wantNames: []string{"Docker"}},
+		{imageType: "user", wantNames: []string{"my-golden-image"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("type="+tt.imageType, func(t *testing.T) {
+			var images []godo.Image
+			resp, err := h.ListImages(tt.imageType, false, 0, 0)
+			decodeList(t, resp, err, "images", &images)
+
+			if len(images) != len(tt.wantNames) {
+				t.Fatalf("got %d images, want %d", len(images), len(tt.wantNames))
+			}
+			for i, image := range images {
+				if image.Name != tt.wantNames[i] {
+					t.Errorf("image %d = %q, want %q", i, image.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetImage(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := fake.NextID()
+	fake.Images.Put(id, godo.Image{ID: id, Name: "Ubuntu 22.04", Slug: "ubuntu-22-04-x64", Public: true})
+
+	tests := []struct {
+		name    string
+		imageID string
+		wantErr bool
+	}{
+		{name: "by id", imageID: strconv.Itoa(id)},
+		{name: "by slug", imageID: "ubuntu-22-04-x64"},
+		{name: "unknown slug", imageID: "plan9", wantErr: true},
+		{name: "unknown id", imageID: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.GetImage(tt.imageID)
+			if tt.wantErr {
+				expectError(t, resp, err, "get_image", "404")
+				return
+			}
+			var image godo.Image
+			decodeResponse(t, resp, err, &image)
+			if image.ID != id {
+				t.Errorf("got image %d, want %d", image.ID, id)
+			}
+		})
+	}
+}
+
+func TestImageMutations(t *testing.T) {
+	update := func(h *Handler, id string) (*mcp_golang.ToolResponse, error) { return h.UpdateImage(id, "renamed") }
+	remove := func(h *Handler, id string) (*mcp_golang.ToolResponse, error) { return h.DeleteImage(id) }
+	transfer := func(h *Handler, id string) (*mcp_golang.ToolResponse, error) { return h.TransferImage(id, "ams3", 0) }
+	convert := func(h *Handler, id string) (*mcp_golang.ToolResponse, error) { return h.ConvertImageToSnapshot(id, 0) }
+
+	tests := []struct {
+		name    string
+		op      string
+		run     func(h *Handler, id string) (*mcp_golang.ToolResponse, error)
+		imageID string
+		check   func(image godo.Image, exists bool) bool
+		wantErr string
+	}{
+		{name: "update", op: "update_image", run: update, check: func(image godo.Image, _ bool) bool { return image.Name == "renamed" }},
+		{name: "update with slug", op: "update_image", run: update, imageID: "ubuntu", wantErr: "invalid image ID: ubuntu"},
+		{name: "delete", op: "delete_image", run: remove, check: func(_ godo.Image, exists bool) bool { return !exists }},
+		{name: "delete missing", op: "delete_image", run: remove, imageID: "1", wantErr: "404"},
+		{name: "transfer", op: "transfer_image", run: transfer, check: func(image godo.Image, _ bool) bool { return containsSlug(image.Regions, "ams3") }},
+		{name: "transfer with slug", op: "transfer_image", run: transfer, imageID: "ubuntu", wantErr: "invalid image ID"},
+		{name: "convert", op: "convert_image_to_snapshot", run: convert, check: func(image godo.Image, _ bool) bool { return image.Type == "snapshot" }},
+		{name: "convert missing", op: "convert_image_to_snapshot", run: convert, imageID: "1", wantErr: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := fake.NextID()
+			fake.Images.Put(id, godo.Image{ID: id, Name: "golden", Type: "backup", Regions: []string{"nyc3"}})
+			imageID := tt.imageID
+			if imageID == "" {
+				imageID = strconv.Itoa(id)
+			}
+
+			resp, err := tt.run(h, imageID)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, tt.op, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			image, exists := fake.Images.Get(id)
+			if !tt.check(image, exists) {
+				t.Errorf("%s did not update the image: %+v (exists=%v)", tt.op, image, exists)
+			}
+		})
+	}
+}
+
+func TestPreviewDeleteImage(t *testing.T) {
+	h, fake := newTestHandler(t)
+	private := fake.NextID()
+	fake.Images.Put(private, godo.Image{ID: private, Name: "golden", SizeGigaBytes: 10, Regions: []string{"nyc3", "ams3"}})
+	public := fake.NextID()
+	fake.Images.Put(public, godo.Image{ID: public, Name: "Ubuntu", Public: true})
+
+	preview, err := h.PreviewDeleteImage(strconv.Itoa(private))
+	if err != nil {
+		t.Fatalf("PreviewDeleteImage: %v", err)
+	}
+	if preview.EstimatedMonthlyCost != 10*snapshotPricePerGB*2 || len(preview.Warnings) != 0 {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+
+	preview, err = h.PreviewDeleteImage(strconv.Itoa(public))
+	if err != nil {
+		t.Fatalf("PreviewDeleteImage: %v", err)
+	}
+	if len(preview.Warnings) != 1 {
+		t.Errorf("expected a public image warning, got %v", preview.Warnings)
+	}
+
+	if _, err := h.PreviewDeleteImage("ubuntu"); err == nil {
+		t.Errorf("expected an error for a non-numeric image ID")
+	}
+}
+
+func containsSlug(slugs []string, slug string) bool {
+	for _, s := range slugs {
+		if s == slug {
+			return true
+		}
+	}
+	return false
+}
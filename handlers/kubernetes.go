@@ -3,20 +3,24 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListK8SClusters() (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListK8SClusters(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	clusters, _, err := client.Kubernetes.List(context.Background(), &godo.ListOptions{})
+	clusters, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.KubernetesCluster, *godo.Response, error) {
+		return client.Kubernetes.List(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_k8s_clusters")
 	}
 
-	return h.HandleSuccess(clusters, "list_k8s_clusters")
+	return h.HandleSuccess(listResult("clusters", clusters, meta), "list_k8s_clusters")
 }
 
 func (h *Handler) GetK8SCluster(clusterID string) (*mcp_golang.ToolResponse, error) {
@@ -30,13 +34,21 @@ func (h *Handler) GetK8SCluster(clusterID string) (*mcp_golang.ToolResponse, err
 	return h.HandleSuccess(cluster, "get_k8s_cluster")
 }
 
-func (h *Handler) CreateK8SCluster(name, region, version, nodePoolSize string, nodeCount int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) CreateK8SCluster(name, region, version, nodePoolSize string, nodeCount int, vpcUUID, projectID string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
+	if err := h.validateK8SPlacement(region, version, nodePoolSize); err != nil {
+		return h.HandleError(err, "create_k8s_cluster")
+	}
+	if err := h.checkProject(projectID); err != nil {
+		return h.HandleError(err, "create_k8s_cluster")
+	}
+
 	createRequest := &godo.KubernetesClusterCreateRequest{
 		Name:        name,
 		RegionSlug:  region,
 		VersionSlug: version,
+		VPCUUID:     vpcUUID,
 		NodePools: []*godo.KubernetesNodePoolCreateRequest{
 			{
 				Name:  fmt.Sprintf("%s-pool", name),
@@ -50,6 +62,9 @@ func (h *Handler) CreateK8SCluster(name, region, version, nodePoolSize string, n
 	if err != nil {
 		return h.HandleError(err, "create_k8s_cluster")
 	}
+	if err := h.assignToProject(projectID, cluster); err != nil {
+		return h.HandleError(err, "create_k8s_cluster")
+	}
 
 	return h.HandleSuccess(cluster, "create_k8s_cluster")
 }
@@ -81,15 +96,17 @@ func (h *Handler) GetK8SClusterKubeconfig(clusterID string) (*mcp_golang.ToolRes
 	}, "get_k8s_cluster_kubeconfig")
 }
 
-func (h *Handler) ListK8SNodePools(clusterID string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListK8SNodePools(clusterID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	nodePools, _, err := client.Kubernetes.ListNodePools(context.Background(), clusterID, &godo.ListOptions{})
+	nodePools, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.KubernetesNodePool, *godo.Response, error) {
+		return client.Kubernetes.ListNodePools(context.Background(), clusterID, opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_k8s_node_pools")
 	}
 
-	return h.HandleSuccess(nodePools, "list_k8s_node_pools")
+	return h.HandleSuccess(listResult("node_pools", nodePools, meta), "list_k8s_node_pools")
 }
 
 func (h *Handler) GetK8SNodePool(clusterID, poolID string) (*mcp_golang.ToolResponse, error) {
@@ -101,4 +118,415 @@ func (h *Handler) GetK8SNodePool(clusterID, poolID string) (*mcp_golang.ToolResp
 	}
 
 	return h.HandleSuccess(nodePool, "get_k8s_node_pool")
-}
\ No newline at end of file
+}
+
+func (h *Handler) PreviewDeleteK8SCluster(clusterID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Kubernetes.Get(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := h.sizePrices()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "kubernetes_cluster",
+		ID:           cluster.ID,
+		Name:         cluster.Name,
+		Region:       cluster.RegionSlug,
+	}
+
+	for _, pool := range cluster.NodePools {
+		preview.EstimatedMonthlyCost += prices[pool.Size] * float64(pool.Count)
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":  "node_pool",
+			"id":    pool.ID,
+			"name":  pool.Name,
+			"size":  pool.Size,
+			"count": pool.Count,
+		})
+	}
+
+	associated, _, err := client.Kubernetes.ListAssociatedResourcesForDeletion(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	groups := []struct {
+		resourceType string
+		resources    []*godo.AssociatedResource
+	}{
+		{"volume", associated.Volumes},
+		{"volume_snapshot", associated.VolumeSnapshots},
+		{"load_balancer", associated.LoadBalancers},
+	}
+	associatedCount := 0
+	for _, group := range groups {
+		for _, resource := range group.resources {
+			associatedCount++
+			preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+				"type": group.resourceType,
+				"id":   resource.ID,
+				"name": resource.Name,
+			})
+		}
+	}
+	if associatedCount > 0 {
+		preview.Warnings = append(preview.Warnings, "Volumes, volume snapshots and load balancers created by the cluster are not deleted with it")
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) CreateK8SNodePool(clusterID, name, size string, count int, autoScale bool, minNodes, maxNodes int, tags []string, labels map[string]string, taints []string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	parsedTaints, err := parseTaints(taints)
+	if err != nil {
+		return h.HandleError(err, "create_k8s_node_pool")
+	}
+	if autoScale && minNodes > maxNodes {
+		return h.HandleError(fmt.Errorf("min_nodes (%d) cannot be greater than max_nodes (%d)", minNodes, maxNodes), "create_k8s_node_pool")
+	}
+
+	createRequest := &godo.KubernetesNodePoolCreateRequest{
+		Name:      name,
+		Size:      size,
+		Count:     count,
+		Tags:      tags,
+		Labels:    labels,
+		Taints:    parsedTaints,
+		AutoScale: autoScale,
+		MinNodes:  minNodes,
+		MaxNodes:  maxNodes,
+	}
+
+	nodePool, _, err := client.Kubernetes.CreateNodePool(context.Background(), clusterID, createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_k8s_node_pool")
+	}
+
+	return h.HandleSuccess(nodePool, "create_k8s_node_pool")
+}
+
+// UpdateK8SNodePool changes only the fields that are set. An empty, non-nil
+// taints slice removes every taint from the pool.
+func (h *Handler) UpdateK8SNodePool(clusterID, poolID, name string, count *int, autoScale *bool, minNodes, maxNodes *int, labels map[string]string, taints *[]string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	// The API replaces the whole pool, so start from its current settings
+	pool, _, err := client.Kubernetes.GetNodePool(context.Background(), clusterID, poolID)
+	if err != nil {
+		return h.HandleError(err, "update_k8s_node_pool")
+	}
+
+	updateRequest := &godo.KubernetesNodePoolUpdateRequest{
+		Name:      pool.Name,
+		Count:     &pool.Count,
+		Tags:      pool.Tags,
+		Labels:    pool.Labels,
+		Taints:    &pool.Taints,
+		AutoScale: &pool.AutoScale,
+		MinNodes:  &pool.MinNodes,
+		MaxNodes:  &pool.MaxNodes,
+	}
+	if name != "" {
+		updateRequest.Name = name
+	}
+	if count != nil {
+		updateRequest.Count = count
+	}
+	if autoScale != nil {
+		updateRequest.AutoScale = autoScale
+	}
+	if minNodes != nil {
+		updateRequest.MinNodes = minNodes
+	}
+	if maxNodes != nil {
+		updateRequest.MaxNodes = maxNodes
+	}
+	// Check the range the pool ends up with, so a new min_nodes is compared
+	// with the current max_nodes and the other way round. A pool that never
+	// autoscaled keeps 0 for both, so a lone bound is accepted there.
+	if (minNodes != nil || maxNodes != nil) && (*updateRequest.AutoScale || *updateRequest.MaxNodes > 0) && *updateRequest.MinNodes > *updateRequest.MaxNodes {
+		return h.HandleError(fmt.Errorf("min_nodes (%d%s) cannot be greater than max_nodes (%d%s)", *updateRequest.MinNodes, currentValue(minNodes), *updateRequest.MaxNodes, currentValue(maxNodes)), "update_k8s_node_pool")
+	}
+	if labels != nil {
+		updateRequest.Labels = labels
+	}
+	if taints != nil {
+		parsedTaints, err := parseTaints(*taints)
+		if err != nil {
+			return h.HandleError(err, "update_k8s_node_pool")
+		}
+		updateRequest.Taints = &parsedTaints
+	}
+
+	nodePool, _, err := client.Kubernetes.UpdateNodePool(context.Background(), clusterID, poolID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_k8s_node_pool")
+	}
+
+	return h.HandleSuccess(nodePool, "update_k8s_node_pool")
+}
+
+func (h *Handler) DeleteK8SNodePool(clusterID, poolID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Kubernetes.DeleteNodePool(context.Background(), clusterID, poolID)
+	if err != nil {
+		return h.HandleError(err, "delete_k8s_node_pool")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Node pool %s deleted from cluster %s", poolID, clusterID),
+	}, "delete_k8s_node_pool")
+}
+
+// currentValue marks a bound in an error message as the pool's current one
+// when the caller did not supply it.
+func currentValue(supplied *int) string {
+	if supplied == nil {
+		return ", the pool's current value"
+	}
+	return ""
+}
+
+// RecycleK8SNodePoolNodes replaces the given nodes, or every node in the pool
+// when nodeIDs is empty, with fresh ones.
+func (h *Handler) RecycleK8SNodePoolNodes(clusterID, poolID string, nodeIDs []string, skipDrain bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if len(nodeIDs) == 0 {
+		pool, _, err := client.Kubernetes.GetNodePool(context.Background(), clusterID, poolID)
+		if err != nil {
+			return h.HandleError(err, "recycle_k8s_node_pool_nodes")
+		}
+		for _, node := range pool.Nodes {
+			nodeIDs = append(nodeIDs, node.ID)
+		}
+	}
+
+	deleteRequest := &godo.KubernetesNodeDeleteRequest{
+		Replace:   true,
+		SkipDrain: skipDrain,
+	}
+	for i, nodeID := range nodeIDs {
+		_, err := client.Kubernetes.DeleteNode(context.Background(), clusterID, poolID, nodeID, deleteRequest)
+		if err != nil {
+			return h.HandleError(fmt.Errorf("node %s (%d of %d recycled before the failure): %w", nodeID, i, len(nodeIDs), err), "recycle_k8s_node_pool_nodes")
+		}
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"status":  "success",
+		"message": fmt.Sprintf("%d node(s) in pool %s are being replaced", len(nodeIDs), poolID),
+		"nodes":   nodeIDs,
+	}, "recycle_k8s_node_pool_nodes")
+}
+
+// PreviewRecycleK8SNodePoolNodes lists the nodes a recycle call would replace.
+// Without nodeIDs the whole pool is looked up again when the call runs, so the
+// preview is scoped to the nodes it listed.
+func (h *Handler) PreviewRecycleK8SNodePoolNodes(clusterID, poolID string, nodeIDs []string, skipDrain bool) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	pool, _, err := client.Kubernetes.GetNodePool(context.Background(), clusterID, poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*godo.KubernetesNode, len(pool.Nodes))
+	for _, node := range pool.Nodes {
+		nodes[node.ID] = node
+	}
+	targets := nodeIDs
+	if len(targets) == 0 {
+		for _, node := range pool.Nodes {
+			targets = append(targets, node.ID)
+		}
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "kubernetes_node_pool_nodes",
+		ID:           pool.ID,
+		Name:         pool.Name,
+	}
+	for _, nodeID := range targets {
+		node, ok := nodes[nodeID]
+		if !ok {
+			return nil, fmt.Errorf("node %s not found in node pool %s", nodeID, poolID)
+		}
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":       "kubernetes_node",
+			"id":         node.ID,
+			"name":       node.Name,
+			"droplet_id": node.DropletID,
+		})
+	}
+	if len(nodeIDs) == 0 {
+		sorted := slices.Clone(targets)
+		slices.Sort(sorted)
+		preview.scope = fmt.Sprintf("nodes of pool %s: %v", pool.ID, sorted)
+	}
+
+	preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d of %d node(s) in pool %s are deleted and replaced with fresh ones; their local data is lost", len(targets), len(pool.Nodes), pool.Name))
+	if skipDrain {
+		preview.Warnings = append(preview.Warnings, "skip_drain is set: pods are killed without being rescheduled first")
+	}
+	if len(targets) == len(pool.Nodes) && len(targets) > 0 {
+		preview.Warnings = append(preview.Warnings, "Every node in the pool is replaced; workloads without replicas in other pools are unavailable while the nodes are recycled")
+	}
+
+	return preview, nil
+}
+
+// DeleteK8SNode removes a node without replacing it, shrinking the pool by one.
+func (h *Handler) DeleteK8SNode(clusterID, poolID, nodeID string, skipDrain bool) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Kubernetes.DeleteNode(context.Background(), clusterID, poolID, nodeID, &godo.KubernetesNodeDeleteRequest{
+		SkipDrain: skipDrain,
+	})
+	if err != nil {
+		return h.HandleError(err, "delete_k8s_node")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Node %s deleted from node pool %s", nodeID, poolID),
+	}, "delete_k8s_node")
+}
+
+func (h *Handler) PreviewDeleteK8SNodePool(clusterID, poolID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	cluster, _, err := client.Kubernetes.Get(context.Background(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var pool *godo.KubernetesNodePool
+	for _, p := range cluster.NodePools {
+		if p.ID == poolID {
+			pool = p
+		}
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("node pool %s not found in cluster %s", poolID, clusterID)
+	}
+
+	prices, err := h.sizePrices()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "kubernetes_node_pool",
+		ID:                   pool.ID,
+		Name:                 pool.Name,
+		Region:               cluster.RegionSlug,
+		EstimatedMonthlyCost: prices[pool.Size] * float64(pool.Count),
+	}
+	for _, node := range pool.Nodes {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":       "node",
+			"id":         node.ID,
+			"name":       node.Name,
+			"droplet_id": node.DropletID,
+		})
+	}
+	if len(pool.Nodes) > 0 {
+		preview.Warnings = append(preview.Warnings, "Pods running on these nodes will be evicted")
+	}
+	if len(cluster.NodePools) == 1 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("This is the only node pool in cluster %s; workloads will have nowhere to run", cluster.Name))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) PreviewDeleteK8SNode(clusterID, poolID, nodeID string, skipDrain bool) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	pool, _, err := client.Kubernetes.GetNodePool(context.Background(), clusterID, poolID)
+	if err != nil {
+		return nil, err
+	}
+	var node *godo.KubernetesNode
+	for _, n := range pool.Nodes {
+		if n.ID == nodeID {
+			node = n
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %s not found in node pool %s", nodeID, poolID)
+	}
+
+	prices, err := h.sizePrices()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "kubernetes_node",
+		ID:                   node.ID,
+		Name:                 node.Name,
+		EstimatedMonthlyCost: prices[pool.Size],
+		AttachedResources: []map[string]interface{}{
+			{
+				"type": "droplet",
+				"id":   node.DropletID,
+			},
+		},
+		Warnings: []string{fmt.Sprintf("Node pool %s shrinks from %d to %d node(s)", pool.Name, pool.Count, pool.Count-1)},
+	}
+	if skipDrain {
+		preview.Warnings = append(preview.Warnings, "skip_drain is set: pods are killed without being rescheduled first")
+	}
+	if pool.AutoScale {
+		preview.Warnings = append(preview.Warnings, "The pool autoscales and may add a node back")
+	}
+
+	return preview, nil
+}
+
+// sizePrices maps droplet size slugs to their monthly price.
+func (h *Handler) sizePrices() (map[string]float64, error) {
+	client := h.doClient.GetClient()
+
+	sizes, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+		return client.Sizes.List(context.Background(), opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]float64, len(sizes))
+	for _, size := range sizes {
+		prices[size.Slug] = size.PriceMonthly
+	}
+	return prices, nil
+}
+
+// parseTaints parses taints written as key=value:Effect or key:Effect.
+func parseTaints(taints []string) ([]godo.Taint, error) {
+	parsed := []godo.Taint{}
+	for _, taint := range taints {
+		keyValue, effect, ok := strings.Cut(taint, ":")
+		if !ok || keyValue == "" || effect == "" {
+			return nil, fmt.Errorf("invalid taint %q: expected key=value:Effect", taint)
+		}
+		switch effect {
+		case "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return nil, fmt.Errorf("invalid taint effect %q: expected NoSchedule, PreferNoSchedule or NoExecute", effect)
+		}
+		key, value, _ := strings.Cut(keyValue, "=")
+		parsed = append(parsed, godo.Taint{Key: key, Value: value, Effect: effect})
+	}
+	return parsed, nil
+}
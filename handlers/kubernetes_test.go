@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestCreateK8SCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		count   int
+		wantErr string
+	}{
+		{name: "created", size: "s-2vcpu-4gb", count: 3},
+		{name: "invalid node size", size: "s-999vcpu", count: 1, wantErr: "invalid node pool size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			resp, err := h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", tt.size, tt.count, "", "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_k8s_cluster", tt.wantErr)
+				return
+			}
+
+			var cluster godo.KubernetesCluster
+			decodeResponse(t, resp, err, &cluster)
+			if len(cluster.NodePools) != 1 {
+				t.Fatalf("expected one node pool, got %d", len(cluster.NodePools))
+			}
+			pool := cluster.NodePools[0]
+			if pool.Name != "prod-pool" || pool.Size != tt.size || pool.Count != tt.count || len(pool.Nodes) != tt.count {
+				t.Errorf("node pool = %+v", pool)
+			}
+			if _, ok := fake.Clusters.Get(cluster.ID); !ok {
+				t.Errorf("cluster %s was not stored", cluster.ID)
+			}
+		})
+	}
+}
+
+func TestK8SClusterReads(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	var cluster godo.KubernetesCluster
+	resp, err := h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 2, "", "")
+	decodeResponse(t, resp, err, &cluster)
+	poolID := cluster.NodePools[0].ID
+
+	t.Run("list clusters", func(t *testing.T) {
+		var clusters []godo.KubernetesCluster
+		resp, err := h.ListK8SClusters(0, 0)
+		decodeList(t, resp, err, "clusters", &clusters)
+		if len(clusters) != 1 || clusters[0].ID != cluster.ID {
+			t.Errorf("clusters = %+v", clusters)
+		}
+	})
+
+	t.Run("get cluster", func(t *testing.T) {
+		var fetched godo.KubernetesCluster
+		resp, err := h.GetK8SCluster(cluster.ID)
+		decodeResponse(t, resp, err, &fetched)
+		if fetched.Name != "prod" {
+			t.Errorf("name = %q", fetched.Name)
+		}
+	})
+
+	t.Run("kubeconfig", func(t *testing.T) {
+		var result map[string]string
+		resp, err := h.GetK8SClusterKubeconfig(cluster.ID)
+		decodeResponse(t, resp, err, &result)
+		if !strings.Contains(result["kubeconfig"], "current-context: do-prod") {
+			t.Errorf("kubeconfig = %q", result["kubeconfig"])
+		}
+	})
+
+	t.Run("list node pools", func(t *testing.T) {
+		var pools []godo.KubernetesNodePool
+		resp, err := h.ListK8SNodePools(cluster.ID, 0, 0)
+		decodeList(t, resp, err, "node_pools", &pools)
+		if len(pools) != 1 || pools[0].ID != poolID {
+			t.Errorf("pools = %+v", pools)
+		}
+	})
+
+	t.Run("get node pool", func(t *testing.T) {
+		var pool godo.KubernetesNodePool
+		resp, err := h.GetK8SNodePool(cluster.ID, poolID)
+		decodeResponse(t, resp, err, &pool)
+		if pool.Count != 2 {
+			t.Errorf("count = %d", pool.Count)
+		}
+
+		resp, err = h.GetK8SNodePool(cluster.ID, "pool-missing")
+		expectError(t, resp, err, "get_k8s_node_pool", "404")
+	})
+
+	t.Run("missing cluster", func(t *testing.T) {
+		resp, err := h.GetK8SCluster("k8s-missing")
+		expectError(t, resp, err, "get_k8s_cluster", "404")
+		resp, err = h.GetK8SClusterKubeconfig("k8s-missing")
+		expectError(t, resp, err, "get_k8s_cluster_kubeconfig", "404")
+		resp, err = h.ListK8SNodePools("k8s-missing", 0, 0)
+		expectError(t, resp, err, "list_k8s_node_pools", "404")
+	})
+}
+
+func TestDeleteK8SCluster(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var cluster godo.KubernetesCluster
+	resp, err := h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 1, "", "")
+	decodeResponse(t, resp, err, &cluster)
+
+	var result map[string]string
+	resp, err = h.DeleteK8SCluster(cluster.ID)
+	decodeResponse(t, resp, err, &result)
+	if fake.Clusters.Len() != 0 {
+		t.Errorf("cluster still exists after delete")
+	}
+
+	resp, err = h.DeleteK8SCluster(cluster.ID)
+	expectError(t, resp, err, "delete_k8s_cluster", "404")
+}
+
+func TestPreviewDeleteK8SCluster(t *testing.T) {
+	tests := []struct {
+		name         string
+		associated   godo.KubernetesAssociatedResources
+		wantAttached int
+		wantWarnings int
+	}{
+		{
+			name:         "node pools only",
+			wantAttached: 1,
+		},
+		{
+			name: "with associated resources",
+			associated: godo.KubernetesAssociatedResources{
+				Volumes:       []*godo.AssociatedResource{{ID: "vol-1", Name: "pvc-data"}},
+				LoadBalancers: []*godo.AssociatedResource{{ID: "lb-1", Name: "ingress"}},
+			},
+			wantAttached: 3,
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			var cluster godo.KubernetesCluster
+			resp, err := h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 3, "", "")
+			decodeResponse(t, resp, err, &cluster)
+			fake.ClusterResources.Put(cluster.ID, tt.associated)
+
+			preview, err := h.PreviewDeleteK8SCluster(cluster.ID)
+			if err != nil {
+				t.Fatalf("PreviewDeleteK8SCluster: %v", err)
+			}
+			if preview.EstimatedMonthlyCost != 3*24 {
+				t.Errorf("cost = %v, want %v", preview.EstimatedMonthlyCost, 3*24)
+			}
+			if len(preview.AttachedResources) != tt.wantAttached || len(preview.Warnings) != tt.wantWarnings {
+				t.Errorf("unexpected preview %+v", preview)
+			}
+		})
+	}
+}
+
+// newTestCluster creates a cluster with one three-node pool and returns the
+// cluster and pool IDs.
+func newTestCluster(t *testing.T, h *Handler) (string, string) {
+	t.Helper()
+
+	var cluster godo.KubernetesCluster
+	resp, err := h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 3, "", "")
+	decodeResponse(t, resp, err, &cluster)
+	return cluster.ID, cluster.NodePools[0].ID
+}
+
+func TestCreateK8SNodePool(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoScale  bool
+		min, max   int
+		taints     []string
+		wantTaints []godo.Taint
+		wantErr    string
+	}{
+		{
+			name:       "with labels and taints",
+			taints:     []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"},
+			wantTaints: []godo.Taint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}, {Key: "spot", Effect: "PreferNoSchedule"}},
+		},
+		{name: "autoscaling", autoScale: true, min: 1, max: 5},
+		{name: "inverted autoscale range", autoScale: true, min: 5, max: 1, wantErr: "min_nodes (5) cannot be greater than max_nodes (1)"},
+		{name: "taint without effect", taints: []string{"dedicated=gpu"}, wantErr: "expected key=value:Effect"},
+		{name: "unknown taint effect", taints: []string{"dedicated=gpu:Sometimes"}, wantErr: "invalid taint effect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			clusterID, _ := newTestCluster(t, h)
+
+			resp, err := h.CreateK8SNodePool(clusterID, "workers", "s-4vcpu-8gb", 2, tt.autoScale, tt.min, tt.max, []string{"team-a"}, map[string]string{"tier": "batch"}, tt.taints)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_k8s_node_pool", tt.wantErr)
+				return
+			}
+
+			var pool godo.KubernetesNodePool
+			decodeResponse(t, resp, err, &pool)
+			if pool.Count != 2 || len(pool.Nodes) != 2 || pool.Labels["tier"] != "batch" || pool.AutoScale != tt.autoScale || pool.MaxNodes != tt.max {
+				t.Errorf("created pool = %+v", pool)
+			}
+			if len(pool.Taints) != len(tt.wantTaints) {
+				t.Fatalf("taints = %+v, want %+v", pool.Taints, tt.wantTaints)
+			}
+			for i := range tt.wantTaints {
+				if pool.Taints[i] != tt.wantTaints[i] {
+					t.Errorf("taint %d = %+v, want %+v", i, pool.Taints[i], tt.wantTaints[i])
+				}
+			}
+
+			cluster, _ := fake.Clusters.Get(clusterID)
+			if len(cluster.NodePools) != 2 {
+				t.Errorf("cluster has %d node pools, want 2", len(cluster.NodePools))
+			}
+		})
+	}
+}
+
+func TestUpdateK8SNodePool(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	noTaints := []string{}
+	gpuTaint := []string{"dedicated=gpu:NoExecute"}
+
+	tests := []struct {
+		name      string
+		count     *int
+		autoScale *bool
+		min, max  *int
+		labels    map[string]string
+		taints    *[]string
+		check     func(pool godo.KubernetesNodePool) bool
+		wantErr   string
+	}{
+		{
+			name:  "scale up",
+			count: intPtr(5),
+			check: func(pool godo.KubernetesNodePool) bool {
+				return pool.Count == 5 && len(pool.Nodes) == 5 && pool.Labels["tier"] == "web"
+			},
+		},
+		{
+			name:      "enable autoscaling",
+			autoScale: boolPtr(true),
+			min:       intPtr(2),
+			max:       intPtr(6),
+			check: func(pool godo.KubernetesNodePool) bool {
+				return pool.AutoScale && pool.MinNodes == 2 && pool.MaxNodes == 6 && pool.Count == 3
+			},
+		},
+		{
+			name:   "replace labels and taints",
+			labels: map[string]string{"tier": "gpu"},
+			taints: &gpuTaint,
+			check: func(pool godo.KubernetesNodePool) bool {
+				return pool.Labels["tier"] == "gpu" && len(pool.Taints) == 1 && pool.Taints[0].Effect == "NoExecute"
+			},
+		},
+		{
+			name:   "clear taints",
+			taints: &noTaints,
+			check: func(pool godo.KubernetesNodePool) bool {
+				return len(pool.Taints) == 0 && pool.Labels["tier"] == "web"
+			},
+		},
+		{
+			name:    "inverted range",
+			min:     intPtr(4),
+			max:     intPtr(2),
+			wantErr: "min_nodes (4) cannot be greater than max_nodes (2)",
+		},
+		{
+			name:    "min above the current max",
+			min:     intPtr(7),
+			wantErr: "min_nodes (7) cannot be greater than max_nodes (6, the pool's current value)",
+		},
+		{
+			name:    "max below the current min",
+			max:     intPtr(1),
+			wantErr: "min_nodes (2, the pool's current value) cannot be greater than max_nodes (1)",
+		},
+		{
+			name: "min within the current range",
+			min:  intPtr(4),
+			check: func(pool godo.KubernetesNodePool) bool {
+				return pool.AutoScale && pool.MinNodes == 4 && pool.MaxNodes == 6
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestHandler(t)
+			clusterID, _ := newTestCluster(t, h)
+
+			var created godo.KubernetesNodePool
+			resp, err := h.CreateK8SNodePool(clusterID, "web", "s-2vcpu-4gb", 3, false, 0, 0, nil, map[string]string{"tier": "web"}, []string{"spot:NoSchedule"})
+			decodeResponse(t, resp, err, &created)
+			if tt.autoScale == nil && (tt.min == nil) != (tt.max == nil) {
+				// Bounds given one at a time are checked against an autoscaling pool
+				resp, err = h.UpdateK8SNodePool(clusterID, created.ID, "", nil, boolPtr(true), intPtr(2), intPtr(6), nil, nil)
+				decodeResponse(t, resp, err, &created)
+			}
+
+			resp, err = h.UpdateK8SNodePool(clusterID, created.ID, "", tt.count, tt.autoScale, tt.min, tt.max, tt.labels, tt.taints)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "update_k8s_node_pool", tt.wantErr)
+				return
+			}
+
+			var pool godo.KubernetesNodePool
+			decodeResponse(t, resp, err, &pool)
+			if !tt.check(pool) {
+				t.Errorf("updated pool = %+v", pool)
+			}
+			if pool.Name != "web" {
+				t.Errorf("name changed to %q although it was not set", pool.Name)
+			}
+		})
+	}
+
+	t.Run("missing pool", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		clusterID, _ := newTestCluster(t, h)
+
+		resp, err := h.UpdateK8SNodePool(clusterID, "pool-missing", "renamed", nil, nil, nil, nil, nil, nil)
+		expectError(t, resp, err, "update_k8s_node_pool", "404")
+	})
+}
+
+func TestDeleteK8SNodePool(t *testing.T) {
+	h, fake := newTestHandler(t)
+	clusterID, poolID := newTestCluster(t, h)
+
+	var result map[string]string
+	resp, err := h.DeleteK8SNodePool(clusterID, poolID)
+	decodeResponse(t, resp, err, &result)
+	cluster, _ := fake.Clusters.Get(clusterID)
+	if len(cluster.NodePools) != 0 {
+		t.Errorf("node pool still exists after delete")
+	}
+
+	resp, err = h.DeleteK8SNodePool(clusterID, poolID)
+	expectError(t, resp, err, "delete_k8s_node_pool", "404")
+}
+
+func TestRecycleK8SNodePoolNodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodes     func(pool godo.KubernetesNodePool) []string
+		wantNodes int
+		wantErr   string
+	}{
+		{
+			name:      "whole pool",
+			nodes:     func(godo.KubernetesNodePool) []string { return nil },
+			wantNodes: 3,
+		},
+		{
+			name:      "selected nodes",
+			nodes:     func(pool godo.KubernetesNodePool) []string { return []string{pool.Nodes[1].ID} },
+			wantNodes: 1,
+		},
+		{
+			name:    "unknown node",
+			nodes:   func(godo.KubernetesNodePool) []string { return []string{"node-missing"} },
+			wantErr: "node node-missing (0 of 1 recycled before the failure)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			clusterID, poolID := newTestCluster(t, h)
+			cluster, _ := fake.Clusters.Get(clusterID)
+			before := *cluster.NodePools[0]
+
+			resp, err := h.RecycleK8SNodePoolNodes(clusterID, poolID, tt.nodes(before), false)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "recycle_k8s_node_pool_nodes", tt.wantErr)
+				return
+			}
+
+			var result struct {
+				Nodes []string `json:"nodes"`
+			}
+			decodeResponse(t, resp, err, &result)
+			if len(result.Nodes) != tt.wantNodes {
+				t.Errorf("recycled %v, want %d nodes", result.Nodes, tt.wantNodes)
+			}
+
+			cluster, _ = fake.Clusters.Get(clusterID)
+			after := cluster.NodePools[0]
+			if after.Count != 3 || len(after.Nodes) != 3 {
+				t.Errorf("recycling must keep the pool size, got count %d with %d nodes", after.Count, len(after.Nodes))
+			}
+			for _, recycled := range result.Nodes {
+				for _, node := range after.Nodes {
+					if node.ID == recycled {
+						t.Errorf("node %s was not replaced", recycled)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPreviewRecycleK8SNodePoolNodes(t *testing.T) {
+	h, fake := newTestHandler(t)
+	clusterID, poolID := newTestCluster(t, h)
+	cluster, _ := fake.Clusters.Get(clusterID)
+	nodes := cluster.NodePools[0].Nodes
+
+	tests := []struct {
+		name         string
+		nodeIDs      []string
+		skipDrain    bool
+		wantNodes    int
+		wantScope    bool
+		wantWarnings int
+		wantErr      string
+	}{
+		{name: "whole pool", wantNodes: 3, wantScope: true, wantWarnings: 2},
+		{name: "selected node without drain", nodeIDs: []string{nodes[1].ID}, skipDrain: true, wantNodes: 1, wantWarnings: 2},
+		{name: "unknown node", nodeIDs: []string{"node-missing"}, wantErr: "node node-missing not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview, err := h.PreviewRecycleK8SNodePoolNodes(clusterID, poolID, tt.nodeIDs, tt.skipDrain)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PreviewRecycleK8SNodePoolNodes: %v", err)
+			}
+			if len(preview.AttachedResources) != tt.wantNodes || len(preview.Warnings) != tt.wantWarnings || (preview.ConfirmationScope() != "") != tt.wantScope {
+				t.Errorf("preview = %+v", preview)
+			}
+		})
+	}
+}
+
+func TestDeleteK8SNode(t *testing.T) {
+	h, fake := newTestHandler(t)
+	clusterID, poolID := newTestCluster(t, h)
+	cluster, _ := fake.Clusters.Get(clusterID)
+	nodeID := cluster.NodePools[0].Nodes[0].ID
+
+	var result map[string]string
+	resp, err := h.DeleteK8SNode(clusterID, poolID, nodeID, true)
+	decodeResponse(t, resp, err, &result)
+
+	cluster, _ = fake.Clusters.Get(clusterID)
+	if pool := cluster.NodePools[0]; pool.Count != 2 || len(pool.Nodes) != 2 {
+		t.Errorf("pool should shrink to 2 nodes, got count %d with %d nodes", pool.Count, len(pool.Nodes))
+	}
+
+	resp, err = h.DeleteK8SNode(clusterID, poolID, nodeID, false)
+	expectError(t, resp, err, "delete_k8s_node", "404")
+}
+
+func TestPreviewDeleteK8SNodePoolAndNode(t *testing.T) {
+	h, fake := newTestHandler(t)
+	clusterID, poolID := newTestCluster(t, h)
+	cluster, _ := fake.Clusters.Get(clusterID)
+	nodeID := cluster.NodePools[0].Nodes[0].ID
+
+	preview, err := h.PreviewDeleteK8SNodePool(clusterID, poolID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteK8SNodePool: %v", err)
+	}
+	if preview.EstimatedMonthlyCost != 3*24 || len(preview.AttachedResources) != 3 || len(preview.Warnings) != 2 {
+		t.Errorf("unexpected pool preview %+v", preview)
+	}
+	if _, err := h.PreviewDeleteK8SNodePool(clusterID, "pool-missing"); err == nil {
+		t.Errorf("expected an error for a missing pool")
+	}
+
+	preview, err = h.PreviewDeleteK8SNode(clusterID, poolID, nodeID, true)
+	if err != nil {
+		t.Fatalf("PreviewDeleteK8SNode: %v", err)
+	}
+	if preview.EstimatedMonthlyCost != 24 || len(preview.Warnings) != 2 {
+		t.Errorf("unexpected node preview %+v", preview)
+	}
+	if _, err := h.PreviewDeleteK8SNode(clusterID, poolID, "node-missing", false); err == nil {
+		t.Errorf("expected an error for a missing node")
+	}
+}
@@ -8,15 +8,17 @@ import (
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListLoadBalancers() (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListLoadBalancers(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	loadBalancers, _, err := client.LoadBalancers.List(context.Background(), &godo.ListOptions{})
+	loadBalancers, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.LoadBalancer, *godo.Response, error) {
+		return client.LoadBalancers.List(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_load_balancers")
 	}
 
-	return h.HandleSuccess(loadBalancers, "list_load_balancers")
+	return h.HandleSuccess(listResult("load_balancers", loadBalancers, meta), "list_load_balancers")
 }
 
 func (h *Handler) GetLoadBalancer(lbID string) (*mcp_golang.ToolResponse, error) {
@@ -30,8 +32,13 @@ func (h *Handler) GetLoadBalancer(lbID string) (*mcp_golang.ToolResponse, error)
 	return h.HandleSuccess(loadBalancer, "get_load_balancer")
 }
 
-func (h *Handler) CreateLoadBalancer(name, algorithm, region string, forwardingRules []godo.ForwardingRule, dropletIDs []int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) CreateLoadBalancer(name, algorithm, region string, forwardingRules []godo.ForwardingRule, dropletIDs []int, vpcUUID, projectID string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
+
+	forwardingRules, err := h.resolveRuleCertificates(forwardingRules)
+	if err != nil {
+		return h.HandleError(err, "create_load_balancer")
+	}
 	
 	createRequest := &godo.LoadBalancerRequest{
 		Name:            name,
@@ -39,6 +46,8 @@ func (h *Handler) CreateLoadBalancer(name, algorithm, region string, forwardingR
 		Region:          region,
 		ForwardingRules: forwardingRules,
 		DropletIDs:      dropletIDs,
+		VPCUUID:         vpcUUID,
+		ProjectID:       projectID,
 		RedirectHttpToHttps: false,
 		EnableProxyProtocol: false,
 	}
@@ -53,6 +62,11 @@ func (h *Handler) CreateLoadBalancer(name, algorithm, region string, forwardingR
 
 func (h *Handler) UpdateLoadBalancer(lbID, name, algorithm, region string, forwardingRules []godo.ForwardingRule, dropletIDs []int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
+
+	forwardingRules, err := h.resolveRuleCertificates(forwardingRules)
+	if err != nil {
+		return h.HandleError(err, "update_load_balancer")
+	}
 	
 	updateRequest := &godo.LoadBalancerRequest{
 		Name:            name,
@@ -114,8 +128,13 @@ func (h *Handler) RemoveDropletsFromLoadBalancer(lbID string, dropletIDs []int)
 
 func (h *Handler) AddForwardingRulesToLoadBalancer(lbID string, forwardingRules []godo.ForwardingRule) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
+
+	forwardingRules, err := h.resolveRuleCertificates(forwardingRules)
+	if err != nil {
+		return h.HandleError(err, "add_forwarding_rules_to_load_balancer")
+	}
 	
-	_, err := client.LoadBalancers.AddForwardingRules(context.Background(), lbID, forwardingRules...)
+	_, err = client.LoadBalancers.AddForwardingRules(context.Background(), lbID, forwardingRules...)
 	if err != nil {
 		return h.HandleError(err, "add_forwarding_rules_to_load_balancer")
 	}
@@ -138,4 +157,46 @@ func (h *Handler) RemoveForwardingRulesFromLoadBalancer(lbID string, forwardingR
 		"status":  "success",
 		"message": fmt.Sprintf("Forwarding rules removed from load balancer %s successfully", lbID),
 	}, "remove_forwarding_rules_from_load_balancer")
-}
\ No newline at end of file
+}
+
+func (h *Handler) PreviewDeleteLoadBalancer(lbID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	loadBalancer, _, err := client.LoadBalancers.Get(context.Background(), lbID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := loadBalancer.SizeUnit
+	if nodes == 0 {
+		nodes = 1
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "load_balancer",
+		ID:                   loadBalancer.ID,
+		Name:                 loadBalancer.Name,
+		EstimatedMonthlyCost: float64(nodes) * loadBalancerNodePrice,
+	}
+	if loadBalancer.Region != nil {
+		preview.Region = loadBalancer.Region.Slug
+	}
+
+	for _, dropletID := range loadBalancer.DropletIDs {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "droplet",
+			"id":   dropletID,
+		})
+	}
+	if loadBalancer.Tag != "" {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "tag",
+			"id":   loadBalancer.Tag,
+		})
+	}
+	if loadBalancer.IP != "" {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The public IP %s will be released", loadBalancer.IP))
+	}
+
+	return preview, nil
+}
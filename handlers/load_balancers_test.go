@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+var httpRule = godo.ForwardingRule{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 8080}
+
+func TestCreateLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []godo.ForwardingRule
+		wantErr string
+	}{
+		{name: "created", rules: []godo.ForwardingRule{httpRule}},
+		{name: "no forwarding rules", wantErr: "forwarding_rules are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletIDs := seedDroplets(fake, 2)
+
+			resp, err := h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", tt.rules, dropletIDs, "", "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_load_balancer", "422", tt.wantErr)
+				return
+			}
+
+			var lb godo.LoadBalancer
+			decodeResponse(t, resp, err, &lb)
+			if lb.Name != "web-lb" || len(lb.DropletIDs) != 2 || regionSlugOf(lb.Region) != "nyc3" {
+				t.Errorf("created load balancer = %+v", lb)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerLifecycle(t *testing.T) {
+	h, fake := newTestHandler(t)
+	dropletIDs := seedDroplets(fake, 2)
+
+	var lb godo.LoadBalancer
+	resp, err := h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpRule}, dropletIDs[:1], "", "")
+	decodeResponse(t, resp, err, &lb)
+
+	steps := []struct {
+		name  string
+		run   func() error
+		check func(lb godo.LoadBalancer) bool
+	}{
+		{
+			name: "add droplets",
+			run: func() error {
+				_, err := h.AddDropletsToLoadBalancer(lb.ID, dropletIDs[1:])
+				return err
+			},
+			check: func(lb godo.LoadBalancer) bool { return len(lb.DropletIDs) == 2 },
+		},
+		{
+			name: "remove droplets",
+			run: func() error {
+				_, err := h.RemoveDropletsFromLoadBalancer(lb.ID, dropletIDs[:1])
+				return err
+			},
+			check: func(lb godo.LoadBalancer) bool { return len(lb.DropletIDs) == 1 && lb.DropletIDs[0] == dropletIDs[1] },
+		},
+		{
+			name: "add forwarding rules",
+			run: func() error {
+				_, err := h.AddForwardingRulesToLoadBalancer(lb.ID, []godo.ForwardingRule{{EntryProtocol: "https", EntryPort: 443, TargetProtocol: "http", TargetPort: 8080}})
+				return err
+			},
+			check: func(lb godo.LoadBalancer) bool { return len(lb.ForwardingRules) == 2 },
+		},
+		{
+			name: "remove forwarding rules",
+			run: func() error {
+				_, err := h.RemoveForwardingRulesFromLoadBalancer(lb.ID, []godo.ForwardingRule{httpRule})
+				return err
+			},
+			check: func(lb godo.LoadBalancer) bool {
+				return len(lb.ForwardingRules) == 1 && lb.ForwardingRules[0].EntryPort == 443
+			},
+		},
+		{
+			name: "update",
+			run: func() error {
+				_, err := h.UpdateLoadBalancer(lb.ID, "web-lb-v2", "least_connections", "nyc3", []godo.ForwardingRule{httpRule}, dropletIDs)
+				return err
+			},
+			check: func(lb godo.LoadBalancer) bool {
+				return lb.Name == "web-lb-v2" && lb.Algorithm == "least_connections" && len(lb.DropletIDs) == 2
+			},
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		stored, _ := fake.LoadBalancers.Get(lb.ID)
+		if !step.check(stored) {
+			t.Fatalf("%s: load balancer = %+v", step.name, stored)
+		}
+	}
+
+	var listed []godo.LoadBalancer
+	resp, err = h.ListLoadBalancers(0, 0)
+	decodeList(t, resp, err, "load_balancers", &listed)
+	if len(listed) != 1 {
+		t.Errorf("got %d load balancers, want 1", len(listed))
+	}
+
+	var result map[string]string
+	resp, err = h.DeleteLoadBalancer(lb.ID)
+	decodeResponse(t, resp, err, &result)
+
+	resp, err = h.GetLoadBalancer(lb.ID)
+	expectError(t, resp, err, "get_load_balancer", "404")
+	resp, err = h.AddDropletsToLoadBalancer(lb.ID, dropletIDs)
+	expectError(t, resp, err, "add_droplets_to_load_balancer", "404")
+}
+
+func TestPreviewDeleteLoadBalancer(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.LoadBalancers.Put("lb-1", godo.LoadBalancer{
+		ID:         "lb-1",
+		Name:       "web-lb",
+		IP:         "198.51.100.7",
+		SizeUnit:   3,
+		Region:     &godo.Region{Slug: "nyc3"},
+		DropletIDs: []int{1, 2},
+		Tag:        "web",
+	})
+
+	preview, err := h.PreviewDeleteLoadBalancer("lb-1")
+	if err != nil {
+		t.Fatalf("PreviewDeleteLoadBalancer: %v", err)
+	}
+	if preview.EstimatedMonthlyCost != 3*loadBalancerNodePrice || preview.Region != "nyc3" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(preview.AttachedResources) != 3 || len(preview.Warnings) != 1 {
+		t.Errorf("expected two droplets, a tag and an IP warning, got %+v", preview)
+	}
+}
+
+func TestLoadBalancerCertificateByName(t *testing.T) {
+	h, fake := newTestHandler(t)
+	certificate := fake.AddCertificate("web-cert", "example.com")
+	httpsRule := godo.ForwardingRule{EntryProtocol: "https", EntryPort: 443, TargetProtocol: "http", TargetPort: 8080}
+
+	tests := []struct {
+		name        string
+		certificate string
+		wantErr     string
+	}{
+		{name: "by name", certificate: "web-cert"},
+		{name: "by id", certificate: certificate.ID},
+		{name: "typo", certificate: "web-crt", wantErr: `forwarding rule 2: certificate "web-crt" not found; try: web-cert`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := httpsRule
+			rule.CertificateID = tt.certificate
+			resp, err := h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpRule, rule}, nil, "", "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_load_balancer", tt.wantErr)
+				return
+			}
+
+			var lb godo.LoadBalancer
+			decodeResponse(t, resp, err, &lb)
+			if len(lb.ForwardingRules) != 2 || lb.ForwardingRules[1].CertificateID != certificate.ID {
+				t.Errorf("forwarding rules = %+v, want certificate %s", lb.ForwardingRules, certificate.ID)
+			}
+		})
+	}
+
+	var lb godo.LoadBalancer
+	resp, err := h.CreateLoadBalancer("api-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpRule}, nil, "", "")
+	decodeResponse(t, resp, err, &lb)
+	rule := httpsRule
+	rule.CertificateID = "web-cert"
+	resp, err = h.AddForwardingRulesToLoadBalancer(lb.ID, []godo.ForwardingRule{rule})
+	decodeResponse(t, resp, err, &map[string]string{})
+	lb, _ = fake.LoadBalancers.Get(lb.ID)
+	if len(lb.ForwardingRules) != 2 || lb.ForwardingRules[1].CertificateID != certificate.ID {
+		t.Errorf("forwarding rules = %+v, want certificate %s", lb.ForwardingRules, certificate.ID)
+	}
+}
@@ -0,0 +1,676 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/metrics"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// dropletMetricUnits maps the metrics get_droplet_metrics accepts to the unit
+// of their values.
+var dropletMetricUnits = map[string]string{
+	"cpu":       "percent",
+	"memory":    "percent",
+	"disk":      "percent",
+	"load_1":    "load average",
+	"load_5":    "load average",
+	"load_15":   "load average",
+	"bandwidth": "Mbps",
+}
+
+var dropletMetricNames = []string{"cpu", "memory", "disk", "load_1", "load_5", "load_15", "bandwidth"}
+
+// alertPolicyTypes are the metrics an alert policy can watch.
+var alertPolicyTypes = []string{
+	godo.DropletCPUUtilizationPercent,
+	godo.DropletMemoryUtilizationPercent,
+	godo.DropletDiskUtilizationPercent,
+	godo.DropletPublicOutboundBandwidthRate,
+	godo.DropletPublicInboundBandwidthRate,
+	godo.DropletPrivateOutboundBandwidthRate,
+	godo.DropletPrivateInboundBandwidthRate,
+	godo.DropletDiskReadRate,
+	godo.DropletDiskWriteRate,
+	godo.DropletOneMinuteLoadAverage,
+	godo.DropletFiveMinuteLoadAverage,
+	godo.DropletFifteenMinuteLoadAverage,
+	godo.LoadBalancerCPUUtilizationPercent,
+	godo.LoadBalancerConnectionUtilizationPercent,
+	godo.LoadBalancerDropletHealth,
+	godo.LoadBalancerTLSUtilizationPercent,
+	godo.LoadBalancerIncreaseInHTTPErrorRatePercentage5xx,
+	godo.LoadBalancerIncreaseInHTTPErrorRatePercentage4xx,
+	godo.LoadBalancerIncreaseInHTTPErrorRateCount5xx,
+	godo.LoadBalancerIncreaseInHTTPErrorRateCount4xx,
+	godo.LoadBalancerHighHttpResponseTime,
+	godo.LoadBalancerHighHttpResponseTime50P,
+	godo.LoadBalancerHighHttpResponseTime95P,
+	godo.LoadBalancerHighHttpResponseTime99P,
+	godo.DbaasFifteenMinuteLoadAverage,
+	godo.DbaasMemoryUtilizationPercent,
+	godo.DbaasDiskUtilizationPercent,
+	godo.DbaasCPUUtilizationPercent,
+}
+
+var alertPolicyWindows = []string{"5m", "10m", "30m", "1h"}
+
+// MetricsQuery selects the window of a metrics request. Window and Step are
+// durations such as "30m", "6h" or "7d"; End is RFC 3339 and defaults to now.
+type MetricsQuery struct {
+	Window string
+	Step   string
+	End    string
+	// Interface and Direction select the bandwidth series: "public" or
+	// "private", "inbound" or "outbound".
+	Interface string
+	Direction string
+	// Summary replaces each series' points with min/avg/max/p95.
+	Summary bool
+}
+
+// MetricPoint is one sample of a metric series.
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricSummary condenses a series to a few statistics.
+type MetricSummary struct {
+	Min     float64 `json:"min"`
+	Avg     float64 `json:"avg"`
+	Max     float64 `json:"max"`
+	P95     float64 `json:"p95"`
+	Last    float64 `json:"last"`
+	Samples int     `json:"samples"`
+}
+
+// MetricSeries is a metric series with its labels (e.g. the device and
+// mount point of a disk).
+type MetricSeries struct {
+	Labels  map[string]string `json:"labels,omitempty"`
+	Points  []MetricPoint     `json:"points,omitempty"`
+	Summary *MetricSummary    `json:"summary,omitempty"`
+}
+
+// AlertPolicyOptions describes a new alert policy.
+type AlertPolicyOptions struct {
+	Type        string
+	Description string
+	Compare     string
+	Value       float32
+	Window      string
+	Entities    []string
+	Tags        []string
+	Emails      []string
+	Slack       []godo.SlackDetails
+	// Enabled defaults to true.
+	Enabled *bool
+}
+
+// AlertPolicyUpdate holds the alert policy fields to change. Nil fields keep
+// the current value; an empty, non-nil slice clears it.
+type AlertPolicyUpdate struct {
+	Type        *string
+	Description *string
+	Compare     *string
+	Value       *float32
+	Window      *string
+	Entities    []string
+	Tags        []string
+	Emails      []string
+	Slack       []godo.SlackDetails
+	Enabled     *bool
+}
+
+// GetDropletMetrics returns a droplet metric over a time window. cpu, memory
+// and disk are utilisation percentages computed from the raw series the API
+// returns. With a step the points are averaged into buckets of that length;
+// with summary each series is reduced to min/avg/max/p95, so the two cannot
+// be combined.
+func (h *Handler) GetDropletMetrics(dropletID int, metric string, query MetricsQuery) (*mcp_golang.ToolResponse, error) {
+	unit, ok := dropletMetricUnits[metric]
+	if !ok {
+		return h.HandleError(fmt.Errorf("invalid metric %q: expected one of %s", metric, strings.Join(dropletMetricNames, ", ")), "get_droplet_metrics")
+	}
+	if query.Summary && query.Step != "" {
+		return h.HandleError(fmt.Errorf("step has no effect with summary, which reduces the whole window; omit one of them"), "get_droplet_metrics")
+	}
+	start, end, step, err := parseMetricsWindow(query)
+	if err != nil {
+		return h.HandleError(err, "get_droplet_metrics")
+	}
+
+	request := &godo.DropletMetricsRequest{HostID: strconv.Itoa(dropletID), Start: start, End: end}
+	series, err := h.dropletMetricSeries(metric, request, query)
+	if err != nil {
+		return h.HandleError(err, "get_droplet_metrics")
+	}
+
+	for i := range series {
+		if query.Summary {
+			series[i].Summary = summarizePoints(series[i].Points)
+			series[i].Points = nil
+		} else if step > 0 {
+			series[i].Points = resamplePoints(series[i].Points, start, step)
+		}
+	}
+
+	result := map[string]interface{}{
+		"droplet_id": dropletID,
+		"metric":     metric,
+		"unit":       unit,
+		"start":      start,
+		"end":        end,
+		"series":     series,
+	}
+	if step > 0 {
+		result["step"] = step.String()
+	}
+	return h.HandleSuccess(result, "get_droplet_metrics")
+}
+
+func (h *Handler) ListAlertPolicies(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	policies, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.AlertPolicy, *godo.Response, error) {
+		return client.Monitoring.ListAlertPolicies(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_alert_policies")
+	}
+
+	return h.HandleSuccess(listResult("policies", policies, meta), "list_alert_policies")
+}
+
+func (h *Handler) GetAlertPolicy(policyID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	policy, _, err := client.Monitoring.GetAlertPolicy(context.Background(), policyID)
+	if err != nil {
+		return h.HandleError(err, "get_alert_policy")
+	}
+
+	return h.HandleSuccess(policy, "get_alert_policy")
+}
+
+func (h *Handler) CreateAlertPolicy(opts AlertPolicyOptions) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	alerts := godo.Alerts{Email: opts.Emails, Slack: opts.Slack}
+	if err := validateAlertPolicy(opts.Type, opts.Compare, opts.Window, alerts); err != nil {
+		return h.HandleError(err, "create_alert_policy")
+	}
+	enabled := true
+	if opts.Enabled != nil {
+		enabled = *opts.Enabled
+	}
+
+	policy, _, err := client.Monitoring.CreateAlertPolicy(context.Background(), &godo.AlertPolicyCreateRequest{
+		Type:        opts.Type,
+		Description: opts.Description,
+		Compare:     godo.AlertPolicyComp(opts.Compare),
+		Value:       opts.Value,
+		Window:      opts.Window,
+		Entities:    nonNil(opts.Entities),
+		Tags:        nonNil(opts.Tags),
+		Alerts:      alerts,
+		Enabled:     &enabled,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_alert_policy")
+	}
+
+	return h.HandleSuccess(policy, "create_alert_policy")
+}
+
+// UpdateAlertPolicy changes the given fields of a policy. The API replaces
+// the whole policy, so the current one is read first and merged.
+func (h *Handler) UpdateAlertPolicy(policyID string, update AlertPolicyUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	current, _, err := client.Monitoring.GetAlertPolicy(context.Background(), policyID)
+	if err != nil {
+		return h.HandleError(err, "update_alert_policy")
+	}
+
+	updateRequest := &godo.AlertPolicyUpdateRequest{
+		Type:        current.Type,
+		Description: current.Description,
+		Compare:     current.Compare,
+		Value:       current.Value,
+		Window:      current.Window,
+		Entities:    current.Entities,
+		Tags:        current.Tags,
+		Alerts:      current.Alerts,
+		Enabled:     &current.Enabled,
+	}
+	if update.Type != nil {
+		updateRequest.Type = *update.Type
+	}
+	if update.Description != nil {
+		updateRequest.Description = *update.Description
+	}
+	if update.Compare != nil {
+		updateRequest.Compare = godo.AlertPolicyComp(*update.Compare)
+	}
+	if update.Value != nil {
+		updateRequest.Value = *update.Value
+	}
+	if update.Window != nil {
+		updateRequest.Window = *update.Window
+	}
+	if update.Entities != nil {
+		updateRequest.Entities = update.Entities
+	}
+	if update.Tags != nil {
+		updateRequest.Tags = update.Tags
+	}
+	if update.Emails != nil {
+		updateRequest.Alerts.Email = update.Emails
+	}
+	if update.Slack != nil {
+		updateRequest.Alerts.Slack = update.Slack
+	}
+	if update.Enabled != nil {
+		updateRequest.Enabled = update.Enabled
+	}
+	updateRequest.Entities = nonNil(updateRequest.Entities)
+	updateRequest.Tags = nonNil(updateRequest.Tags)
+	if err := validateAlertPolicy(updateRequest.Type, string(updateRequest.Compare), updateRequest.Window, updateRequest.Alerts); err != nil {
+		return h.HandleError(err, "update_alert_policy")
+	}
+
+	policy, _, err := client.Monitoring.UpdateAlertPolicy(context.Background(), policyID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_alert_policy")
+	}
+
+	return h.HandleSuccess(policy, "update_alert_policy")
+}
+
+func (h *Handler) DeleteAlertPolicy(policyID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Monitoring.DeleteAlertPolicy(context.Background(), policyID)
+	if err != nil {
+		return h.HandleError(err, "delete_alert_policy")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Alert policy %s deleted successfully", policyID),
+	}, "delete_alert_policy")
+}
+
+// PreviewDeleteAlertPolicy lists the resources and tags the policy watches.
+func (h *Handler) PreviewDeleteAlertPolicy(policyID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	policy, _, err := client.Monitoring.GetAlertPolicy(context.Background(), policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "alert_policy",
+		ID:           policy.UUID,
+		Name:         policy.Description,
+	}
+	entityType := alertEntityType(policy.Type)
+	for _, entity := range policy.Entities {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": entityType,
+			"id":   entity,
+		})
+	}
+	for _, tag := range policy.Tags {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "tag",
+			"name": tag,
+		})
+	}
+	if policy.Enabled {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("No more %s alerts are sent for the watched resources", policy.Type))
+	}
+
+	return preview, nil
+}
+
+// dropletMetricSeries fetches the series behind metric and converts them to
+// the unit in dropletMetricUnits.
+func (h *Handler) dropletMetricSeries(metric string, request *godo.DropletMetricsRequest, query MetricsQuery) ([]MetricSeries, error) {
+	client := h.doClient.GetClient()
+	ctx := context.Background()
+
+	fetch := func(get func(context.Context, *godo.DropletMetricsRequest) (*godo.MetricsResponse, *godo.Response, error)) ([]metrics.SampleStream, error) {
+		response, _, err := get(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return response.Data.Result, nil
+	}
+
+	switch metric {
+	case "cpu":
+		streams, err := fetch(client.Monitoring.GetDropletCPU)
+		if err != nil {
+			return nil, err
+		}
+		return cpuUtilization(streams), nil
+	case "memory":
+		total, err := fetch(client.Monitoring.GetDropletTotalMemory)
+		if err != nil {
+			return nil, err
+		}
+		available, err := fetch(client.Monitoring.GetDropletAvailableMemory)
+		if err != nil {
+			return nil, err
+		}
+		return usedPercent(total, available), nil
+	case "disk":
+		size, err := fetch(client.Monitoring.GetDropletFilesystemSize)
+		if err != nil {
+			return nil, err
+		}
+		free, err := fetch(client.Monitoring.GetDropletFilesystemFree)
+		if err != nil {
+			return nil, err
+		}
+		return usedPercent(size, free), nil
+	case "load_1", "load_5", "load_15":
+		get := map[string]func(context.Context, *godo.DropletMetricsRequest) (*godo.MetricsResponse, *godo.Response, error){
+			"load_1":  client.Monitoring.GetDropletLoad1,
+			"load_5":  client.Monitoring.GetDropletLoad5,
+			"load_15": client.Monitoring.GetDropletLoad15,
+		}[metric]
+		streams, err := fetch(get)
+		if err != nil {
+			return nil, err
+		}
+		return convertStreams(streams), nil
+	}
+
+	iface, direction := query.Interface, query.Direction
+	if iface == "" {
+		iface = "public"
+	}
+	if direction == "" {
+		direction = "outbound"
+	}
+	if iface != "public" && iface != "private" {
+		return nil, fmt.Errorf("invalid interface %q: expected public or private", iface)
+	}
+	if direction != "inbound" && direction != "outbound" {
+		return nil, fmt.Errorf("invalid direction %q: expected inbound or outbound", direction)
+	}
+	response, _, err := client.Monitoring.GetDropletBandwidth(ctx, &godo.DropletBandwidthMetricsRequest{
+		DropletMetricsRequest: *request,
+		Interface:             iface,
+		Direction:             direction,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertStreams(response.Data.Result), nil
+}
+
+// parseMetricsWindow resolves a query to a start, end and optional step.
+func parseMetricsWindow(query MetricsQuery) (time.Time, time.Time, time.Duration, error) {
+	end := time.Now().UTC()
+	if query.End != "" {
+		parsed, err := time.Parse(time.RFC3339, query.End)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid end %q: expected RFC 3339, e.g. 2024-05-01T12:00:00Z", query.End)
+		}
+		end = parsed.UTC()
+	}
+
+	window := time.Hour
+	if query.Window != "" {
+		parsed, err := parseMetricsDuration(query.Window)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid window: %w", err)
+		}
+		window = parsed
+	}
+
+	var step time.Duration
+	if query.Step != "" {
+		parsed, err := parseMetricsDuration(query.Step)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid step: %w", err)
+		}
+		if parsed > window {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("step %s is longer than the window %s", query.Step, window)
+		}
+		step = parsed
+	}
+
+	return end.Add(-window), end, step, nil
+}
+
+// parseMetricsDuration parses a positive Go duration, also accepting whole
+// days such as "7d".
+func parseMetricsDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration such as 30m, 6h or 7d", value)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration such as 30m, 6h or 7d", value)
+		}
+		duration = parsed
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%q must be positive", value)
+	}
+	return duration, nil
+}
+
+// cpuUtilization turns the per-mode CPU time counters the API returns into a
+// single busy percentage between consecutive samples.
+func cpuUtilization(streams []metrics.SampleStream) []MetricSeries {
+	type counters struct{ total, idle float64 }
+	byTime := map[metrics.Time]*counters{}
+	for _, stream := range streams {
+		idle := stream.Metric["mode"] == "idle"
+		for _, sample := range stream.Values {
+			c, ok := byTime[sample.Timestamp]
+			if !ok {
+				c = &counters{}
+				byTime[sample.Timestamp] = c
+			}
+			c.total += float64(sample.Value)
+			if idle {
+				c.idle += float64(sample.Value)
+			}
+		}
+	}
+	times := make([]metrics.Time, 0, len(byTime))
+	for t := range byTime {
+		times = append(times, t)
+	}
+	slices.Sort(times)
+
+	points := []MetricPoint{}
+	for i := 1; i < len(times); i++ {
+		previous, current := byTime[times[i-1]], byTime[times[i]]
+		total := current.total - previous.total
+		if total <= 0 {
+			continue
+		}
+		busy := 100 * (total - (current.idle - previous.idle)) / total
+		points = append(points, MetricPoint{Time: times[i].Time().UTC(), Value: roundMetric(busy)})
+	}
+	if len(streams) == 0 {
+		return []MetricSeries{}
+	}
+	return []MetricSeries{{Points: points}}
+}
+
+// usedPercent pairs each total series with the free series carrying the same
+// labels and returns the percentage in use.
+func usedPercent(totals, frees []metrics.SampleStream) []MetricSeries {
+	series := []MetricSeries{}
+	for _, total := range totals {
+		labels := seriesLabels(total.Metric)
+		i := slices.IndexFunc(frees, func(s metrics.SampleStream) bool {
+			return fmt.Sprint(seriesLabels(s.Metric)) == fmt.Sprint(labels)
+		})
+		if i < 0 {
+			continue
+		}
+		free := map[metrics.Time]float64{}
+		for _, sample := range frees[i].Values {
+			free[sample.Timestamp] = float64(sample.Value)
+		}
+		points := []MetricPoint{}
+		for _, sample := range total.Values {
+			available, ok := free[sample.Timestamp]
+			if !ok || sample.Value <= 0 {
+				continue
+			}
+			used := 100 * (float64(sample.Value) - available) / float64(sample.Value)
+			points = append(points, MetricPoint{Time: sample.Timestamp.Time().UTC(), Value: roundMetric(used)})
+		}
+		series = append(series, MetricSeries{Labels: labels, Points: points})
+	}
+	return series
+}
+
+func convertStreams(streams []metrics.SampleStream) []MetricSeries {
+	series := []MetricSeries{}
+	for _, stream := range streams {
+		points := []MetricPoint{}
+		for _, sample := range stream.Values {
+			points = append(points, MetricPoint{Time: sample.Timestamp.Time().UTC(), Value: roundMetric(float64(sample.Value))})
+		}
+		series = append(series, MetricSeries{Labels: seriesLabels(stream.Metric), Points: points})
+	}
+	return series
+}
+
+// seriesLabels returns a series' labels without the metric name, which the
+// caller already knows.
+func seriesLabels(metric metrics.Metric) map[string]string {
+	labels := map[string]string{}
+	for name, value := range metric {
+		if name != metrics.MetricNameLabel {
+			labels[string(name)] = string(value)
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// resamplePoints averages points into step-long buckets starting at start.
+// Empty buckets are left out.
+func resamplePoints(points []MetricPoint, start time.Time, step time.Duration) []MetricPoint {
+	type bucket struct {
+		sum   float64
+		count int
+	}
+	buckets := map[int64]*bucket{}
+	var order []int64
+	for _, point := range points {
+		index := int64(point.Time.Sub(start) / step)
+		b, ok := buckets[index]
+		if !ok {
+			b = &bucket{}
+			buckets[index] = b
+			order = append(order, index)
+		}
+		b.sum += point.Value
+		b.count++
+	}
+	slices.Sort(order)
+
+	resampled := []MetricPoint{}
+	for _, index := range order {
+		b := buckets[index]
+		resampled = append(resampled, MetricPoint{
+			Time:  start.Add(time.Duration(index) * step),
+			Value: roundMetric(b.sum / float64(b.count)),
+		})
+	}
+	return resampled
+}
+
+// summarizePoints returns min, average, max, 95th percentile (nearest rank)
+// and the latest value of points.
+func summarizePoints(points []MetricPoint) *MetricSummary {
+	if len(points) == 0 {
+		return &MetricSummary{}
+	}
+	values := make([]float64, len(points))
+	sum := 0.0
+	for i, point := range points {
+		values[i] = point.Value
+		sum += point.Value
+	}
+	sort.Float64s(values)
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+
+	return &MetricSummary{
+		Min:     values[0],
+		Avg:     roundMetric(sum / float64(len(values))),
+		Max:     values[len(values)-1],
+		P95:     values[rank],
+		Last:    points[len(points)-1].Value,
+		Samples: len(points),
+	}
+}
+
+func roundMetric(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+func validateAlertPolicy(policyType, compare, window string, alerts godo.Alerts) error {
+	switch {
+	case !slices.Contains(alertPolicyTypes, policyType):
+		return fmt.Errorf("invalid alert type %q; try: %s", policyType, strings.Join(suggest(policyType, alertPolicyTypes), ", "))
+	case compare != string(godo.GreaterThan) && compare != string(godo.LessThan):
+		return fmt.Errorf("invalid compare %q: expected GreaterThan or LessThan", compare)
+	case !slices.Contains(alertPolicyWindows, window):
+		return fmt.Errorf("invalid window %q: expected one of %s", window, strings.Join(alertPolicyWindows, ", "))
+	case len(alerts.Email) == 0 && len(alerts.Slack) == 0:
+		return fmt.Errorf("at least one email or Slack destination is required")
+	}
+	return nil
+}
+
+// alertEntityType names the kind of resource an alert policy's entities are.
+func alertEntityType(policyType string) string {
+	switch {
+	case strings.HasPrefix(policyType, "v1/insights/lbaas/"):
+		return "load_balancer"
+	case strings.HasPrefix(policyType, "v1/dbaas/"):
+		return "database"
+	}
+	return "droplet"
+}
+
+// nonNil returns an empty slice for nil so lists are sent as [] rather than
+// null.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
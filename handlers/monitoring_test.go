@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/metrics"
+)
+
+// metricsEnd is the end of the window the metrics tests query.
+var metricsEnd = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// sampleStream returns a series with one value per minute starting at first.
+func sampleStream(labels metrics.Metric, first time.Time, values ...float64) metrics.SampleStream {
+	stream := metrics.SampleStream{Metric: labels}
+	for i, value := range values {
+		stream.Values = append(stream.Values, metrics.SamplePair{
+			Timestamp: metrics.TimeFromUnix(first.Add(time.Duration(i) * time.Minute).Unix()),
+			Value:     metrics.SampleValue(value),
+		})
+	}
+	return stream
+}
+
+type metricsResult struct {
+	Metric string         `json:"metric"`
+	Unit   string         `json:"unit"`
+	Series []MetricSeries `json:"series"`
+}
+
+func TestGetDropletMetrics(t *testing.T) {
+	first := metricsEnd.Add(-9 * time.Minute)
+	tests := []struct {
+		name       string
+		metric     string
+		query      MetricsQuery
+		wantUnit   string
+		wantLabels map[string]string
+		wantValues []float64
+		wantTimes  []time.Time
+	}{
+		{
+			name:       "cpu",
+			metric:     "cpu",
+			wantUnit:   "percent",
+			wantValues: []float64{50, 66.667},
+		},
+		{
+			name:       "memory",
+			metric:     "memory",
+			wantUnit:   "percent",
+			wantValues: []float64{50, 75, 25, 90},
+		},
+		{
+			name:       "memory window excludes older samples",
+			metric:     "memory",
+			query:      MetricsQuery{Window: "8m"},
+			wantUnit:   "percent",
+			wantValues: []float64{75, 25, 90},
+		},
+		{
+			name:       "memory by step",
+			metric:     "memory",
+			query:      MetricsQuery{Window: "10m", Step: "2m"},
+			wantUnit:   "percent",
+			wantValues: []float64{50, 50, 90},
+			wantTimes:  []time.Time{metricsEnd.Add(-10 * time.Minute), metricsEnd.Add(-8 * time.Minute), metricsEnd.Add(-6 * time.Minute)},
+		},
+		{
+			name:       "disk",
+			metric:     "disk",
+			wantUnit:   "percent",
+			wantLabels: map[string]string{"device": "/dev/vda1", "mountpoint": "/"},
+			wantValues: []float64{20, 40},
+		},
+		{
+			name:       "load",
+			metric:     "load_5",
+			wantUnit:   "load average",
+			wantValues: []float64{0.5, 1.25},
+		},
+		{
+			name:       "bandwidth defaults to public outbound",
+			metric:     "bandwidth",
+			wantUnit:   "Mbps",
+			wantLabels: map[string]string{"interface": "public", "direction": "outbound"},
+			wantValues: []float64{1.5},
+		},
+		{
+			name:       "private inbound bandwidth",
+			metric:     "bandwidth",
+			query:      MetricsQuery{Interface: "private", Direction: "inbound"},
+			wantUnit:   "Mbps",
+			wantLabels: map[string]string{"interface": "private", "direction": "inbound"},
+			wantValues: []float64{0.25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := seedDroplets(fake, 1)[0]
+			seedDropletMetrics(fake, id, first)
+
+			tt.query.End = metricsEnd.Format(time.RFC3339)
+			var result metricsResult
+			resp, err := h.GetDropletMetrics(id, tt.metric, tt.query)
+			decodeResponse(t, resp, err, &result)
+			if result.Metric != tt.metric || result.Unit != tt.wantUnit || len(result.Series) != 1 {
+				t.Fatalf("result = %+v", result)
+			}
+			series := result.Series[0]
+			if len(series.Labels) != len(tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", series.Labels, tt.wantLabels)
+			}
+			for name, value := range tt.wantLabels {
+				if series.Labels[name] != value {
+					t.Errorf("labels = %v, want %v", series.Labels, tt.wantLabels)
+				}
+			}
+			if len(series.Points) != len(tt.wantValues) {
+				t.Fatalf("points = %+v, want values %v", series.Points, tt.wantValues)
+			}
+			for i, point := range series.Points {
+				if point.Value != tt.wantValues[i] {
+					t.Errorf("point %d = %v, want %v", i, point.Value, tt.wantValues[i])
+				}
+				if tt.wantTimes != nil && !point.Time.Equal(tt.wantTimes[i]) {
+					t.Errorf("point %d at %s, want %s", i, point.Time, tt.wantTimes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetDropletMetricsSummary(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedDroplets(fake, 1)[0]
+	seedDropletMetrics(fake, id, metricsEnd.Add(-9*time.Minute))
+
+	var result metricsResult
+	resp, err := h.GetDropletMetrics(id, "memory", MetricsQuery{End: metricsEnd.Format(time.RFC3339), Summary: true})
+	decodeResponse(t, resp, err, &result)
+	if len(result.Series) != 1 || result.Series[0].Points != nil || result.Series[0].Summary == nil {
+		t.Fatalf("series = %+v", result.Series)
+	}
+	want := MetricSummary{Min: 25, Avg: 60, Max: 90, P95: 90, Last: 90, Samples: 4}
+	if got := *result.Series[0].Summary; got != want {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+
+	if got := *summarizePoints(nil); got != (MetricSummary{}) {
+		t.Errorf("empty summary = %+v", got)
+	}
+}
+
+func TestGetDropletMetricsErrors(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedDroplets(fake, 1)[0]
+
+	tests := []struct {
+		name    string
+		id      int
+		metric  string
+		query   MetricsQuery
+		wantErr []string
+	}{
+		{name: "metric", id: id, metric: "iops", wantErr: []string{`invalid metric "iops"`, "cpu, memory, disk"}},
+		{name: "window", id: id, metric: "cpu", query: MetricsQuery{Window: "an hour"}, wantErr: []string{"invalid window", "30m, 6h or 7d"}},
+		{name: "negative window", id: id, metric: "cpu", query: MetricsQuery{Window: "-1h"}, wantErr: []string{"must be positive"}},
+		{name: "step longer than window", id: id, metric: "cpu", query: MetricsQuery{Window: "1h", Step: "2h"}, wantErr: []string{"step 2h is longer than the window 1h0m0s"}},
+		{name: "step with summary", id: id, metric: "cpu", query: MetricsQuery{Step: "5m", Summary: true}, wantErr: []string{"step has no effect with summary"}},
+		{name: "end", id: id, metric: "cpu", query: MetricsQuery{End: "yesterday"}, wantErr: []string{`invalid end "yesterday"`, "RFC 3339"}},
+		{name: "interface", id: id, metric: "bandwidth", query: MetricsQuery{Interface: "eth0"}, wantErr: []string{`invalid interface "eth0"`}},
+		{name: "direction", id: id, metric: "bandwidth", query: MetricsQuery{Direction: "up"}, wantErr: []string{`invalid direction "up"`}},
+		{name: "missing droplet", id: 9999, metric: "cpu", wantErr: []string{"404"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.GetDropletMetrics(tt.id, tt.metric, tt.query)
+			expectError(t, resp, err, "get_droplet_metrics", tt.wantErr...)
+		})
+	}
+
+	fake.FailNext(http.MethodGet, "/v2/monitoring/metrics/droplet/memory_available", http.StatusServiceUnavailable, "metrics unavailable")
+	resp, err := h.GetDropletMetrics(id, "memory", MetricsQuery{})
+	expectError(t, resp, err, "get_droplet_metrics", "503", "metrics unavailable")
+}
+
+func TestAlertPolicies(t *testing.T) {
+	h, fake := newTestHandler(t)
+	disabled := false
+
+	var policy godo.AlertPolicy
+	resp, err := h.CreateAlertPolicy(AlertPolicyOptions{
+		Type:        godo.DropletCPUUtilizationPercent,
+		Description: "CPU above 80%",
+		Compare:     "GreaterThan",
+		Value:       80,
+		Window:      "5m",
+		Entities:    []string{"1001"},
+		Emails:      []string{"ops@example.com"},
+	})
+	decodeResponse(t, resp, err, &policy)
+	if policy.UUID == "" || !policy.Enabled || policy.Value != 80 || len(policy.Entities) != 1 || policy.Tags == nil {
+		t.Errorf("created policy = %+v", policy)
+	}
+
+	var policies []godo.AlertPolicy
+	resp, err = h.ListAlertPolicies(0, 0)
+	decodeList(t, resp, err, "policies", &policies)
+	if len(policies) != 1 || policies[0].UUID != policy.UUID {
+		t.Errorf("policies = %+v", policies)
+	}
+
+	value, window := float32(90), "10m"
+	resp, err = h.UpdateAlertPolicy(policy.UUID, AlertPolicyUpdate{Value: &value, Window: &window, Entities: []string{}, Tags: []string{"web"}, Enabled: &disabled})
+	decodeResponse(t, resp, err, &policy)
+	if policy.Value != 90 || policy.Window != "10m" || len(policy.Entities) != 0 || policy.Tags[0] != "web" || policy.Enabled {
+		t.Errorf("updated policy = %+v", policy)
+	}
+	if policy.Description != "CPU above 80%" || policy.Alerts.Email[0] != "ops@example.com" || policy.Compare != godo.GreaterThan {
+		t.Errorf("update did not keep the other fields: %+v", policy)
+	}
+
+	resp, err = h.GetAlertPolicy(policy.UUID)
+	decodeResponse(t, resp, err, &policy)
+	if policy.Value != 90 {
+		t.Errorf("stored policy = %+v", policy)
+	}
+
+	badWindow := "15m"
+	resp, err = h.UpdateAlertPolicy(policy.UUID, AlertPolicyUpdate{Window: &badWindow})
+	expectError(t, resp, err, "update_alert_policy", `invalid window "15m"`)
+	resp, err = h.UpdateAlertPolicy("alert-missing", AlertPolicyUpdate{Value: &value})
+	expectError(t, resp, err, "update_alert_policy", "404")
+
+	preview, err := h.PreviewDeleteAlertPolicy(policy.UUID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteAlertPolicy: %v", err)
+	}
+	if preview.ResourceType != "alert_policy" || preview.Name != "CPU above 80%" || len(preview.AttachedResources) != 1 || preview.AttachedResources[0]["name"] != "web" || len(preview.Warnings) != 0 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	resp, err = h.DeleteAlertPolicy(policy.UUID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.AlertPolicies.Len() != 0 {
+		t.Errorf("policy was not deleted")
+	}
+}
+
+func TestCreateAlertPolicyValidation(t *testing.T) {
+	valid := AlertPolicyOptions{
+		Type:        godo.DropletMemoryUtilizationPercent,
+		Description: "memory",
+		Compare:     "GreaterThan",
+		Value:       90,
+		Window:      "5m",
+		Emails:      []string{"ops@example.com"},
+	}
+	tests := []struct {
+		name    string
+		modify  func(*AlertPolicyOptions)
+		wantErr []string
+	}{
+		{name: "type typo", modify: func(o *AlertPolicyOptions) { o.Type = "v1/insights/droplet/cpus" }, wantErr: []string{`invalid alert type "v1/insights/droplet/cpus"`, "try: v1/insights/droplet/cpu"}},
+		{name: "compare", modify: func(o *AlertPolicyOptions) { o.Compare = ">" }, wantErr: []string{`invalid compare ">"`}},
+		{name: "window", modify: func(o *AlertPolicyOptions) { o.Window = "1m" }, wantErr: []string{`invalid window "1m"`, "5m, 10m, 30m, 1h"}},
+		{name: "no destination", modify: func(o *AlertPolicyOptions) { o.Emails = nil }, wantErr: []string{"at least one email or Slack destination"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			opts := valid
+			tt.modify(&opts)
+
+			resp, err := h.CreateAlertPolicy(opts)
+			expectError(t, resp, err, "create_alert_policy", tt.wantErr...)
+			if fake.AlertPolicies.Len() != 0 {
+				t.Errorf("no policy should have been created")
+			}
+		})
+	}
+
+	h, _ := newTestHandler(t)
+	opts := valid
+	opts.Emails = nil
+	opts.Slack = []godo.SlackDetails{{URL: "https://hooks.slack.com/services/T0/B0/x", Channel: "#ops"}}
+	var policy godo.AlertPolicy
+	resp, err := h.CreateAlertPolicy(opts)
+	decodeResponse(t, resp, err, &policy)
+	if len(policy.Alerts.Slack) != 1 || !strings.HasPrefix(policy.UUID, "alert-") {
+		t.Errorf("policy = %+v", policy)
+	}
+}
+
+// seedDropletMetrics stores one minute resolution series for a droplet, the
+// first sample at first.
+func seedDropletMetrics(fake *fakedo.Server, dropletID int, first time.Time) {
+	fake.SetDropletMetrics(dropletID, "cpu",
+		sampleStream(metrics.Metric{"mode": "idle"}, first, 0, 30, 60),
+		sampleStream(metrics.Metric{"mode": "user"}, first, 0, 30, 90),
+	)
+	total := sampleStream(metrics.Metric{"__name__": "memory_total"}, first, 1000, 1000, 1000, 1000)
+	// A sample from before the queried window, which the API leaves out
+	older := metrics.SamplePair{Timestamp: metrics.TimeFromUnix(first.Add(-time.Hour).Unix()), Value: 1000}
+	total.Values = append([]metrics.SamplePair{older}, total.Values...)
+	fake.SetDropletMetrics(dropletID, "memory_total", total)
+	fake.SetDropletMetrics(dropletID, "memory_available",
+		sampleStream(metrics.Metric{"__name__": "memory_available"}, first, 500, 250, 750, 100),
+	)
+	disk := func(name string) metrics.Metric {
+		return metrics.Metric{"__name__": metrics.LabelValue(name), "device": "/dev/vda1", "mountpoint": "/"}
+	}
+	fake.SetDropletMetrics(dropletID, "filesystem_size", sampleStream(disk("filesystem_size"), first, 100, 100))
+	fake.SetDropletMetrics(dropletID, "filesystem_free", sampleStream(disk("filesystem_free"), first, 80, 60))
+	fake.SetDropletMetrics(dropletID, "load_5", sampleStream(nil, first, 0.5, 1.25))
+	fake.SetDropletMetrics(dropletID, "bandwidth",
+		sampleStream(metrics.Metric{"interface": "public", "direction": "outbound"}, first, 1.5),
+		sampleStream(metrics.Metric{"interface": "private", "direction": "inbound"}, first, 0.25),
+	)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+const (
+	defaultPerPage = 25
+	maxPerPage     = 200
+	// maxPages stops a runaway loop if the API keeps returning a next link.
+	maxPages = 1000
+)
+
+// ListMeta is returned alongside every list result. In all-pages mode Page is
+// omitted and Count equals Total.
+type ListMeta struct {
+	Total    int  `json:"total"`
+	Count    int  `json:"count"`
+	Page     int  `json:"page,omitempty"`
+	PerPage  int  `json:"per_page"`
+	Pages    int  `json:"pages"`
+	AllPages bool `json:"all_pages"`
+}
+
+// listFunc fetches a single page from a godo list endpoint.
+type listFunc[T any] func(opt *godo.ListOptions) ([]T, *godo.Response, error)
+
+// paginate runs list in one of two modes. With page > 0 it fetches exactly
+// that page; otherwise it follows response.Links until the last page and
+// returns every item. perPage defaults to 25 for a single page and to the API
+// maximum of 200 when fetching everything.
+func paginate[T any](page, perPage int, list listFunc[T]) ([]T, *ListMeta, error) {
+	allPages := page <= 0
+	if perPage <= 0 {
+		perPage = defaultPerPage
+		if allPages {
+			perPage = maxPerPage
+		}
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	if !allPages {
+		items, response, err := list(&godo.ListOptions{Page: page, PerPage: perPage})
+		if err != nil {
+			return nil, nil, err
+		}
+		meta := &ListMeta{
+			Total:   total(response),
+			Count:   len(items),
+			Page:    page,
+			PerPage: perPage,
+		}
+		if meta.Total < 0 {
+			// Endpoints without meta only tell us the total on the last page.
+			meta.Total = 0
+			if response == nil || response.Links == nil || response.Links.IsLastPage() {
+				meta.Total = (page-1)*perPage + len(items)
+			}
+		}
+		meta.Pages = (meta.Total + perPage - 1) / perPage
+		return items, meta, nil
+	}
+
+	opt := &godo.ListOptions{Page: 1, PerPage: perPage}
+	all := []T{}
+	for {
+		items, response, err := list(opt)
+		if err != nil {
+			return nil, nil, err
+		}
+		all = append(all, items...)
+
+		if response == nil || response.Links == nil || response.Links.IsLastPage() {
+			break
+		}
+		current, err := response.Links.CurrentPage()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read pagination links: %w", err)
+		}
+		if current < opt.Page || opt.Page >= maxPages {
+			return nil, nil, fmt.Errorf("pagination did not advance past page %d", opt.Page)
+		}
+		opt = &godo.ListOptions{Page: current + 1, PerPage: perPage}
+	}
+
+	return all, &ListMeta{
+		Total:    len(all),
+		Count:    len(all),
+		PerPage:  perPage,
+		Pages:    (len(all) + perPage - 1) / perPage,
+		AllPages: true,
+	}, nil
+}
+
+// total returns the item count reported by the API, or -1 when the response
+// carries no meta.
+func total(response *godo.Response) int {
+	if response == nil || response.Meta == nil {
+		return -1
+	}
+	return response.Meta.Total
+}
+
+// listResult builds the response body shared by all list tools.
+func listResult[T any](key string, items []T, meta *ListMeta) map[string]interface{} {
+	return map[string]interface{}{
+		key:    items,
+		"meta": meta,
+	}
+}
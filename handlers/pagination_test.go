@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+// fakeList serves items in pages and records which pages were requested.
+// withMeta controls whether responses report the total, as most but not all
+// endpoints do.
+type fakeList struct {
+	items     []int
+	withMeta  bool
+	failPage  int
+	requested []int
+}
+
+func (f *fakeList) list(opt *godo.ListOptions) ([]int, *godo.Response, error) {
+	f.requested = append(f.requested, opt.Page)
+	if opt.Page == f.failPage {
+		return nil, nil, errors.New("boom")
+	}
+
+	start := (opt.Page - 1) * opt.PerPage
+	end := start + opt.PerPage
+	if start > len(f.items) {
+		start = len(f.items)
+	}
+	if end > len(f.items) {
+		end = len(f.items)
+	}
+
+	pages := &godo.Pages{}
+	if opt.Page > 1 {
+		pages.Prev = fmt.Sprintf("https://api.example.com/v2/items?page=%d", opt.Page-1)
+	}
+	if end < len(f.items) {
+		pages.Next = fmt.Sprintf("https://api.example.com/v2/items?page=%d", opt.Page+1)
+	}
+	response := &godo.Response{Links: &godo.Links{Pages: pages}}
+	if f.withMeta {
+		response.Meta = &godo.Meta{Total: len(f.items)}
+	}
+	return f.items[start:end], response, nil
+}
+
+func newFakeList(n int, withMeta bool) *fakeList {
+	f := &fakeList{withMeta: withMeta}
+	for i := 0; i < n; i++ {
+		f.items = append(f.items, i)
+	}
+	return f
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name          string
+		items         int
+		withMeta      bool
+		page          int
+		perPage       int
+		wantCount     int
+		wantRequested []int
+		wantMeta      ListMeta
+	}{
+		{
+			name:          "all pages follows links",
+			items:         450,
+			withMeta:      true,
+			wantCount:     450,
+			wantRequested: []int{1, 2, 3},
+			wantMeta:      ListMeta{Total: 450, Count: 450, PerPage: 200, Pages: 3, AllPages: true},
+		},
+		{
+			name:          "all pages without meta",
+			items:         5,
+			perPage:       2,
+			wantCount:     5,
+			wantRequested: []int{1, 2, 3},
+			wantMeta:      ListMeta{Total: 5, Count: 5, PerPage: 2, Pages: 3, AllPages: true},
+		},
+		{
+			name:          "single page",
+			items:         60,
+			withMeta:      true,
+			page:          2,
+			wantCount:     25,
+			wantRequested: []int{2},
+			wantMeta:      ListMeta{Total: 60, Count: 25, Page: 2, PerPage: 25, Pages: 3},
+		},
+		{
+			name:          "last page without meta",
+			items:         60,
+			page:          3,
+			wantCount:     10,
+			wantRequested: []int{3},
+			wantMeta:      ListMeta{Total: 60, Count: 10, Page: 3, PerPage: 25, Pages: 3},
+		},
+		{
+			name:          "middle page without meta",
+			items:         60,
+			page:          1,
+			wantCount:     25,
+			wantRequested: []int{1},
+			wantMeta:      ListMeta{Count: 25, Page: 1, PerPage: 25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeList(tt.items, tt.withMeta)
+
+			items, meta, err := paginate(tt.page, tt.perPage, f.list)
+			if err != nil {
+				t.Fatalf("paginate: %v", err)
+			}
+			if len(items) != tt.wantCount {
+				t.Errorf("got %d items, want %d", len(items), tt.wantCount)
+			}
+			if fmt.Sprint(f.requested) != fmt.Sprint(tt.wantRequested) {
+				t.Errorf("requested pages %v, want %v", f.requested, tt.wantRequested)
+			}
+			if *meta != tt.wantMeta {
+				t.Errorf("meta = %+v, want %+v", *meta, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestPaginateErrorOnLaterPage(t *testing.T) {
+	f := newFakeList(450, true)
+	f.failPage = 2
+
+	items, meta, err := paginate(0, 0, f.list)
+	if err == nil || items != nil || meta != nil {
+		t.Fatalf("expected a failure on page 2, got %d items, meta %+v, err %v", len(items), meta, err)
+	}
+}
+
+func TestPaginateStopsWhenLinksDoNotAdvance(t *testing.T) {
+	calls := 0
+	stuck := func(opt *godo.ListOptions) ([]int, *godo.Response, error) {
+		calls++
+		// Always claims to be page 1 with more to come.
+		return []int{1}, &godo.Response{Links: &godo.Links{Pages: &godo.Pages{Next: "https://api.example.com/v2/items?page=2"}}}, nil
+	}
+
+	if _, _, err := paginate(0, 0, stuck); err == nil {
+		t.Fatalf("expected an error for links that never advance")
+	}
+	if calls != 2 {
+		t.Errorf("made %d calls, want 2", calls)
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// projectEnvironments are the environments a project can be marked with.
+var projectEnvironments = []string{"Development", "Staging", "Production"}
+
+// ProjectUpdate holds the project fields to change. Nil pointers keep the
+// current value.
+type ProjectUpdate struct {
+	Name        *string
+	Description *string
+	Purpose     *string
+	Environment *string
+}
+
+func (h *Handler) ListProjects(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	projects, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Project, *godo.Response, error) {
+		return client.Projects.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_projects")
+	}
+
+	return h.HandleSuccess(listResult("projects", projects, meta), "list_projects")
+}
+
+// GetProject returns a project; "default" names the account's default
+// project.
+func (h *Handler) GetProject(projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	project, _, err := client.Projects.Get(context.Background(), projectID)
+	if err != nil {
+		return h.HandleError(err, "get_project")
+	}
+
+	return h.HandleSuccess(project, "get_project")
+}
+
+func (h *Handler) CreateProject(name, description, purpose, environment string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateProjectEnvironment(environment); err != nil {
+		return h.HandleError(err, "create_project")
+	}
+
+	project, _, err := client.Projects.Create(context.Background(), &godo.CreateProjectRequest{
+		Name:        name,
+		Description: description,
+		Purpose:     purpose,
+		Environment: environment,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_project")
+	}
+
+	return h.HandleSuccess(project, "create_project")
+}
+
+func (h *Handler) UpdateProject(projectID string, update ProjectUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	// The request is a PATCH: fields left nil are sent as null and kept
+	updateRequest := &godo.UpdateProjectRequest{}
+	if update.Name != nil {
+		if *update.Name == "" {
+			return h.HandleError(fmt.Errorf("name cannot be empty"), "update_project")
+		}
+		updateRequest.Name = *update.Name
+	}
+	if update.Description != nil {
+		updateRequest.Description = *update.Description
+	}
+	if update.Purpose != nil {
+		updateRequest.Purpose = *update.Purpose
+	}
+	if update.Environment != nil {
+		if err := validateProjectEnvironment(*update.Environment); err != nil {
+			return h.HandleError(err, "update_project")
+		}
+		updateRequest.Environment = *update.Environment
+	}
+	if update == (ProjectUpdate{}) {
+		return h.HandleError(fmt.Errorf("nothing to update: set name, description, purpose or environment"), "update_project")
+	}
+
+	project, _, err := client.Projects.Update(context.Background(), projectID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_project")
+	}
+
+	return h.HandleSuccess(project, "update_project")
+}
+
+// SetDefaultProject makes a project the account's default. Resources created
+// without a project land in the default project.
+func (h *Handler) SetDefaultProject(projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	project, _, err := client.Projects.Update(context.Background(), projectID, &godo.UpdateProjectRequest{IsDefault: true})
+	if err != nil {
+		return h.HandleError(err, "set_default_project")
+	}
+
+	return h.HandleSuccess(project, "set_default_project")
+}
+
+func (h *Handler) DeleteProject(projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Projects.Delete(context.Background(), projectID)
+	if err != nil {
+		return h.HandleError(err, "delete_project")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Project %s deleted successfully", projectID),
+	}, "delete_project")
+}
+
+// PreviewDeleteProject lists the project's resources. The API refuses to
+// delete the default project or a project that still has resources, so the
+// warnings say what has to happen first.
+func (h *Handler) PreviewDeleteProject(projectID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	project, _, err := client.Projects.Get(context.Background(), projectID)
+	if err != nil {
+		return nil, err
+	}
+	resources, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.ProjectResource, *godo.Response, error) {
+		return client.Projects.ListResources(context.Background(), project.ID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "project",
+		ID:           project.ID,
+		Name:         project.Name,
+	}
+	for _, resource := range resources {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"urn": resource.URN,
+		})
+	}
+	if project.IsDefault {
+		preview.Warnings = append(preview.Warnings, "This is the default project; make another project the default before deleting it")
+	}
+	if len(resources) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The project has %d resource(s); assign them to another project before deleting it", len(resources)))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListProjectResources(projectID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	resources, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.ProjectResource, *godo.Response, error) {
+		return client.Projects.ListResources(context.Background(), projectID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_project_resources")
+	}
+
+	return h.HandleSuccess(listResult("resources", resources, meta), "list_project_resources")
+}
+
+// AssignProjectResources moves resources, given by URN (e.g.
+// "do:droplet:123"), into a project. A resource belongs to one project, so
+// assigning it removes it from its current one.
+func (h *Handler) AssignProjectResources(projectID string, urns []string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if len(urns) == 0 {
+		return h.HandleError(fmt.Errorf("at least one resource URN is required"), "assign_project_resources")
+	}
+	resources := make([]interface{}, len(urns))
+	for i, urn := range urns {
+		if err := validateURN(urn); err != nil {
+			return h.HandleError(err, "assign_project_resources")
+		}
+		resources[i] = urn
+	}
+
+	assigned, _, err := client.Projects.AssignResources(context.Background(), projectID, resources...)
+	if err != nil {
+		return h.HandleError(err, "assign_project_resources")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"resources": assigned}, "assign_project_resources")
+}
+
+// checkProject verifies that a project exists before a resource is created
+// in it, so a typo does not leave a resource outside any intended project.
+func (h *Handler) checkProject(projectID string) error {
+	if projectID == "" {
+		return nil
+	}
+	client := h.doClient.GetClient()
+
+	if _, _, err := client.Projects.Get(context.Background(), projectID); err != nil {
+		return fmt.Errorf("project %s: %w", projectID, err)
+	}
+	return nil
+}
+
+// assignToProject assigns a newly created resource to projectID. The
+// resource already exists when this fails, so the error says so.
+func (h *Handler) assignToProject(projectID string, resources ...godo.ResourceWithURN) error {
+	if projectID == "" {
+		return nil
+	}
+	client := h.doClient.GetClient()
+
+	urns := make([]interface{}, len(resources))
+	names := make([]string, len(resources))
+	for i, resource := range resources {
+		urns[i] = resource
+		names[i] = resource.URN()
+	}
+	if _, _, err := client.Projects.AssignResources(context.Background(), projectID, urns...); err != nil {
+		return fmt.Errorf("created %s but could not assign it to project %s (retry with assign_project_resources): %w", strings.Join(names, ", "), projectID, err)
+	}
+	return nil
+}
+
+func validateProjectEnvironment(environment string) error {
+	if environment != "" && !slices.Contains(projectEnvironments, environment) {
+		return fmt.Errorf("invalid environment %q: expected one of %s", environment, strings.Join(projectEnvironments, ", "))
+	}
+	return nil
+}
+
+// validateURN checks that urn has the form do:<type>:<id>.
+func validateURN(urn string) error {
+	parts := strings.SplitN(urn, ":", 3)
+	if len(parts) != 3 || parts[0] != "do" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("invalid resource URN %q: expected do:<type>:<id>, e.g. do:droplet:123 or do:volume:<uuid>", urn)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestProjectCRUD(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var project godo.Project
+	resp, err := h.CreateProject("web", "storefront", "Web Application", "Staging")
+	decodeResponse(t, resp, err, &project)
+	if project.Name != "web" || project.Purpose != "Web Application" || project.Environment != "Staging" || project.IsDefault {
+		t.Errorf("created project = %+v", project)
+	}
+
+	resp, err = h.CreateProject("web", "", "Web Application", "Testing")
+	expectError(t, resp, err, "create_project", `invalid environment "Testing"`, "Development, Staging, Production")
+	resp, err = h.CreateProject("web", "", "", "")
+	expectError(t, resp, err, "create_project", "422", "name and purpose are required")
+
+	var projects []godo.Project
+	resp, err = h.ListProjects(0, 0)
+	meta := decodeList(t, resp, err, "projects", &projects)
+	if meta.Total != 2 || projects[0].Name != "Default" || projects[1].ID != project.ID {
+		t.Errorf("projects = %+v", projects)
+	}
+
+	resp, err = h.GetProject("default")
+	decodeResponse(t, resp, err, &project)
+	if project.Name != "Default" || !project.IsDefault {
+		t.Errorf("default project = %+v", project)
+	}
+
+	name, description := "shop", ""
+	id := projects[1].ID
+	resp, err = h.UpdateProject(id, ProjectUpdate{Name: &name, Description: &description})
+	decodeResponse(t, resp, err, &project)
+	if project.Name != "shop" || project.Description != "" || project.Purpose != "Web Application" || project.Environment != "Staging" {
+		t.Errorf("updated project = %+v", project)
+	}
+
+	empty, environment := "", "QA"
+	tests := []struct {
+		name    string
+		id      string
+		update  ProjectUpdate
+		wantErr string
+	}{
+		{name: "nothing", id: id, wantErr: "nothing to update"},
+		{name: "empty name", id: id, update: ProjectUpdate{Name: &empty}, wantErr: "name cannot be empty"},
+		{name: "environment", id: id, update: ProjectUpdate{Environment: &environment}, wantErr: `invalid environment "QA"`},
+		{name: "missing", id: "project-missing", update: ProjectUpdate{Name: &name}, wantErr: "404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.UpdateProject(tt.id, tt.update)
+			expectError(t, resp, err, "update_project", tt.wantErr)
+		})
+	}
+
+	resp, err = h.DeleteProject(id)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if _, ok := fake.Projects.Get(id); ok {
+		t.Errorf("project %s was not deleted", id)
+	}
+}
+
+func TestSetDefaultProject(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := fake.AddProject("web").ID
+
+	var project godo.Project
+	resp, err := h.SetDefaultProject(id)
+	decodeResponse(t, resp, err, &project)
+	if !project.IsDefault {
+		t.Errorf("project = %+v, want default", project)
+	}
+	defaults := fake.Projects.Filter(func(p godo.Project) bool { return p.IsDefault })
+	if len(defaults) != 1 || defaults[0].ID != id {
+		t.Errorf("default projects = %+v", defaults)
+	}
+
+	resp, err = h.GetProject("default")
+	decodeResponse(t, resp, err, &project)
+	if project.ID != id {
+		t.Errorf("default project = %s, want %s", project.ID, id)
+	}
+
+	resp, err = h.SetDefaultProject("project-missing")
+	expectError(t, resp, err, "set_default_project", "404")
+}
+
+func TestPreviewDeleteProject(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := fake.AddProject("web").ID
+	fake.AssignToProject(id, "do:droplet:1", "do:volume:vol-1")
+
+	preview, err := h.PreviewDeleteProject(id)
+	if err != nil {
+		t.Fatalf("PreviewDeleteProject: %v", err)
+	}
+	if preview.ResourceType != "project" || preview.Name != "web" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 1 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	resp, err := h.DeleteProject(id)
+	expectError(t, resp, err, "delete_project", "412", "move them to another project first")
+
+	preview, err = h.PreviewDeleteProject("default")
+	if err != nil {
+		t.Fatalf("PreviewDeleteProject: %v", err)
+	}
+	if preview.ID != "project-default" || len(preview.Warnings) != 1 {
+		t.Errorf("default project preview = %+v", preview)
+	}
+	resp, err = h.DeleteProject("project-default")
+	expectError(t, resp, err, "delete_project", "412", "cannot delete the default project")
+}
+
+func TestAssignProjectResources(t *testing.T) {
+	h, fake := newTestHandler(t)
+	web := fake.AddProject("web").ID
+	ops := fake.AddProject("ops").ID
+	fake.AssignToProject(web, "do:droplet:1")
+
+	var assigned struct {
+		Resources []godo.ProjectResource `json:"resources"`
+	}
+	resp, err := h.AssignProjectResources(ops, []string{"do:droplet:1", "do:domain:example.com"})
+	decodeResponse(t, resp, err, &assigned)
+	if len(assigned.Resources) != 2 || assigned.Resources[0].Links.Self != "https://api.digitalocean.com/v2/droplets/1" {
+		t.Errorf("assigned = %+v", assigned.Resources)
+	}
+
+	var resources []godo.ProjectResource
+	resp, err = h.ListProjectResources(web, 0, 0)
+	decodeList(t, resp, err, "resources", &resources)
+	if len(resources) != 0 {
+		t.Errorf("web resources = %+v, want the droplet moved out", resources)
+	}
+	resp, err = h.ListProjectResources(ops, 0, 0)
+	decodeList(t, resp, err, "resources", &resources)
+	if urns := projectURNs(resources); !slices.Equal(urns, []string{"do:droplet:1", "do:domain:example.com"}) {
+		t.Errorf("ops resources = %v", urns)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		urns    []string
+		wantErr []string
+	}{
+		{name: "no resources", id: ops, wantErr: []string{"at least one resource URN"}},
+		{name: "malformed", id: ops, urns: []string{"droplet:1"}, wantErr: []string{`invalid resource URN "droplet:1"`, "do:<type>:<id>"}},
+		{name: "unknown type", id: ops, urns: []string{"do:server:1"}, wantErr: []string{"422", "invalid urn: do:server:1"}},
+		{name: "missing project", id: "project-missing", urns: []string{"do:droplet:1"}, wantErr: []string{"404"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.AssignProjectResources(tt.id, tt.urns)
+			expectError(t, resp, err, "assign_project_resources", tt.wantErr...)
+		})
+	}
+}
+
+func TestCreateInProject(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedImages(fake)
+	id := fake.AddProject("web").ID
+
+	var droplet godo.Droplet
+	resp, err := h.CreateDroplet(CreateDropletOptions{Name: "web-1", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", ProjectID: id}, 0)
+	decodeResponse(t, resp, err, &droplet)
+
+	var created struct {
+		Droplets []godo.Droplet `json:"droplets"`
+	}
+	resp, err = h.CreateDroplet(CreateDropletOptions{Names: []string{"web-2", "web-3"}, Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", ProjectID: id}, 0)
+	decodeResponse(t, resp, err, &created)
+
+	var volume godo.Volume
+	resp, err = h.CreateVolume("data", "nyc3", 10, "", id)
+	decodeResponse(t, resp, err, &volume)
+
+	var loadBalancer godo.LoadBalancer
+	rule := godo.ForwardingRule{EntryProtocol: "http", EntryPort: 80, TargetProtocol: "http", TargetPort: 80}
+	resp, err = h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{rule}, nil, "", id)
+	decodeResponse(t, resp, err, &loadBalancer)
+
+	var reservedIP godo.ReservedIP
+	resp, err = h.CreateFloatingIP("nyc3", 0, id)
+	decodeResponse(t, resp, err, &reservedIP)
+
+	var domain godo.Domain
+	resp, err = h.CreateDomain("example.org", "", id)
+	decodeResponse(t, resp, err, &domain)
+
+	var resources []godo.ProjectResource
+	resp, err = h.ListProjectResources(id, 0, 0)
+	decodeList(t, resp, err, "resources", &resources)
+	want := []string{droplet.URN(), created.Droplets[0].URN(), created.Droplets[1].URN(), volume.URN(), loadBalancer.URN(), reservedIP.URN(), domain.URN()}
+	if urns := projectURNs(resources); !slices.Equal(urns, want) {
+		t.Errorf("project resources = %v, want %v", urns, want)
+	}
+}
+
+func TestCreateInProjectErrors(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedImages(fake)
+
+	resp, err := h.CreateDroplet(CreateDropletOptions{Name: "web-1", Region: "nyc3", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64", ProjectID: "project-missing"}, 0)
+	expectError(t, resp, err, "create_droplet", "project project-missing", "404")
+	resp, err = h.CreateVolume("data", "nyc3", 10, "", "project-missing")
+	expectError(t, resp, err, "create_volume", "project project-missing", "404")
+	resp, err = h.CreateFloatingIP("nyc3", 0, "project-missing")
+	expectError(t, resp, err, "create_floating_ip", "422", "project project-missing not found")
+	if fake.Droplets.Len() != 0 || fake.Volumes.Len() != 0 || fake.ReservedIPs.Len() != 0 {
+		t.Errorf("nothing should have been created")
+	}
+
+	id := fake.AddProject("web").ID
+	fake.FailNext(http.MethodPost, "/v2/projects/"+id+"/resources", http.StatusInternalServerError, "assignment failed")
+	resp, err = h.CreateVolume("data", "nyc3", 10, "", id)
+	expectError(t, resp, err, "create_volume", "created do:volume:", "could not assign it to project "+id, "assign_project_resources", "assignment failed")
+	if fake.Volumes.Len() != 1 {
+		t.Errorf("volumes = %d, want the created volume kept", fake.Volumes.Len())
+	}
+}
+
+func projectURNs(resources []godo.ProjectResource) []string {
+	urns := []string{}
+	for _, resource := range resources {
+		urns = append(urns, resource.URN)
+	}
+	return urns
+}
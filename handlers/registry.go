@@ -2,12 +2,21 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
+const (
+	defaultDockerCredentialsExpiry = 3600
+	// noDockerCredentialsExpiry must be passed explicitly to get credentials
+	// that never expire.
+	noDockerCredentialsExpiry = -1
+)
+
 func (h *Handler) ListRegistries() (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
@@ -26,25 +35,33 @@ func (h *Handler) GetRegistry(registryName string) (*mcp_golang.ToolResponse, er
 	if err != nil {
 		return h.HandleError(err, "get_registry")
 	}
+	// An account has at most one registry, so the name only has to match it.
+	if registryName != "" && registry.Name != registryName {
+		return h.HandleError(fmt.Errorf("registry %s not found", registryName), "get_registry")
+	}
 
 	return h.HandleSuccess(registry, "get_registry")
 }
 
-func (h *Handler) ListRepositories(registryName string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListRepositories(registryName string, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	repositories, _, err := client.Registry.ListRepositories(context.Background(), registryName, &godo.ListOptions{})
+	repositories, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.Repository, *godo.Response, error) {
+		return client.Registry.ListRepositories(context.Background(), registryName, opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_repositories")
 	}
 
-	return h.HandleSuccess(repositories, "list_repositories")
+	return h.HandleSuccess(listResult("repositories", repositories, meta), "list_repositories")
 }
 
 func (h *Handler) GetRepository(registryName, repositoryName string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	repositories, _, err := client.Registry.ListRepositories(context.Background(), registryName, &godo.ListOptions{})
+	repositories, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]*godo.Repository, *godo.Response, error) {
+		return client.Registry.ListRepositories(context.Background(), registryName, opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "get_repository")
 	}
@@ -58,13 +75,249 @@ func (h *Handler) GetRepository(registryName, repositoryName string) (*mcp_golan
 	return h.HandleError(fmt.Errorf("repository %s not found", repositoryName), "get_repository")
 }
 
-func (h *Handler) ListRepositoryTags(registryName, repositoryName string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListRepositoryTags(registryName, repositoryName string, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	tags, _, err := client.Registry.ListRepositoryTags(context.Background(), registryName, repositoryName, &godo.ListOptions{})
+	tags, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.RepositoryTag, *godo.Response, error) {
+		return client.Registry.ListRepositoryTags(context.Background(), registryName, repositoryName, opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_repository_tags")
 	}
 
-	return h.HandleSuccess(tags, "list_repository_tags")
-}
\ No newline at end of file
+	return h.HandleSuccess(listResult("tags", tags, meta), "list_repository_tags")
+}
+
+func (h *Handler) ListRepositoryManifests(registryName, repositoryName string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	manifests, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.RepositoryManifest, *godo.Response, error) {
+		return client.Registry.ListRepositoryManifests(context.Background(), registryName, repositoryName, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_repository_manifests")
+	}
+
+	return h.HandleSuccess(listResult("manifests", manifests, meta), "list_repository_manifests")
+}
+
+func (h *Handler) DeleteRepositoryTag(registryName, repositoryName, tag string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Registry.DeleteTag(context.Background(), registryName, repositoryName, tag)
+	if err != nil {
+		return h.HandleError(err, "delete_repository_tag")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Tag %s:%s deleted; run garbage collection to reclaim its storage", repositoryName, tag),
+	}, "delete_repository_tag")
+}
+
+func (h *Handler) DeleteRepositoryManifest(registryName, repositoryName, digest string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Registry.DeleteManifest(context.Background(), registryName, repositoryName, digest)
+	if err != nil {
+		return h.HandleError(err, "delete_repository_manifest")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Manifest %s@%s deleted; run garbage collection to reclaim its storage", repositoryName, digest),
+	}, "delete_repository_manifest")
+}
+
+// PreviewDeleteRepositoryTag describes the tag that DeleteRepositoryTag would
+// remove.
+func (h *Handler) PreviewDeleteRepositoryTag(registryName, repositoryName, tag string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	tags, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]*godo.RepositoryTag, *godo.Response, error) {
+		return client.Registry.ListRepositoryTags(context.Background(), registryName, repositoryName, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var found *godo.RepositoryTag
+	for _, t := range tags {
+		if t.Tag == tag {
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("tag %s not found in repository %s", tag, repositoryName)
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "registry_tag",
+		ID:           found.ManifestDigest,
+		Name:         fmt.Sprintf("%s:%s", repositoryName, tag),
+		AttachedResources: []map[string]interface{}{{
+			"type":                  "manifest",
+			"digest":                found.ManifestDigest,
+			"compressed_size_bytes": found.CompressedSizeBytes,
+		}},
+		Warnings: []string{"The manifest stays in the registry, reachable by digest, until garbage collection removes it"},
+	}
+	for _, t := range tags {
+		if t.ManifestDigest == found.ManifestDigest && t.Tag != tag {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Tag %s points to the same manifest and is kept", t.Tag))
+		}
+	}
+
+	return preview, nil
+}
+
+// PreviewDeleteRepositoryManifest describes the manifest that
+// DeleteRepositoryManifest would remove, including every tag pointing at it.
+func (h *Handler) PreviewDeleteRepositoryManifest(registryName, repositoryName, digest string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	manifests, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]*godo.RepositoryManifest, *godo.Response, error) {
+		return client.Registry.ListRepositoryManifests(context.Background(), registryName, repositoryName, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var manifest *godo.RepositoryManifest
+	for _, m := range manifests {
+		if m.Digest == digest {
+			manifest = m
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("manifest %s not found in repository %s", digest, repositoryName)
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "registry_manifest",
+		ID:           manifest.Digest,
+		Name:         fmt.Sprintf("%s@%s", repositoryName, manifest.Digest),
+	}
+	for _, tag := range manifest.Tags {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "tag",
+			"name": tag,
+		})
+	}
+	if len(manifest.Tags) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d tag(s) pointing at this manifest will be deleted with it", len(manifest.Tags)))
+	}
+	preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d bytes are reclaimed only after garbage collection runs", manifest.CompressedSizeBytes))
+
+	return preview, nil
+}
+
+// garbageCollectionTypes are the values accepted by
+// StartRegistryGarbageCollection.
+var garbageCollectionTypes = map[string]godo.GarbageCollectionType{
+	"untagged_manifests_only":                   godo.GCTypeUntaggedManifestsOnly,
+	"unreferenced_blobs_only":                   godo.GCTypeUnreferencedBlobsOnly,
+	"untagged_manifests_and_unreferenced_blobs": godo.GCTypeUntaggedManifestsAndUnreferencedBlobs,
+}
+
+func (h *Handler) StartRegistryGarbageCollection(registryName, gcType string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	var requests []*godo.StartGarbageCollectionRequest
+	if gcType != "" {
+		value, ok := garbageCollectionTypes[gcType]
+		if !ok {
+			return h.HandleError(fmt.Errorf("invalid garbage collection type %q: use untagged_manifests_only, unreferenced_blobs_only or untagged_manifests_and_unreferenced_blobs", gcType), "start_registry_garbage_collection")
+		}
+		requests = append(requests, &godo.StartGarbageCollectionRequest{Type: value})
+	}
+
+	gc, _, err := client.Registry.StartGarbageCollection(context.Background(), registryName, requests...)
+	if err != nil {
+		return h.HandleError(err, "start_registry_garbage_collection")
+	}
+
+	return h.HandleSuccess(gc, "start_registry_garbage_collection")
+}
+
+func (h *Handler) GetRegistryGarbageCollection(registryName string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	gc, _, err := client.Registry.GetGarbageCollection(context.Background(), registryName)
+	if err != nil {
+		return h.HandleError(err, "get_registry_garbage_collection")
+	}
+
+	return h.HandleSuccess(gc, "get_registry_garbage_collection")
+}
+
+func (h *Handler) ListRegistryGarbageCollections(registryName string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	gcs, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.GarbageCollection, *godo.Response, error) {
+		return client.Registry.ListGarbageCollections(context.Background(), registryName, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_registry_garbage_collections")
+	}
+
+	return h.HandleSuccess(listResult("garbage_collections", gcs, meta), "list_registry_garbage_collections")
+}
+
+// CancelRegistryGarbageCollection cancels the garbage collection identified by
+// gcUUID, or the active one when gcUUID is empty.
+func (h *Handler) CancelRegistryGarbageCollection(registryName, gcUUID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if gcUUID == "" {
+		active, _, err := client.Registry.GetGarbageCollection(context.Background(), registryName)
+		if err != nil {
+			return h.HandleError(fmt.Errorf("no active garbage collection to cancel: %v", err), "cancel_registry_garbage_collection")
+		}
+		gcUUID = active.UUID
+	}
+
+	gc, _, err := client.Registry.UpdateGarbageCollection(context.Background(), registryName, gcUUID, &godo.UpdateGarbageCollectionRequest{Cancel: true})
+	if err != nil {
+		return h.HandleError(err, "cancel_registry_garbage_collection")
+	}
+
+	return h.HandleSuccess(gc, "cancel_registry_garbage_collection")
+}
+
+// GetRegistryDockerCredentials returns a docker config.json for the registry.
+// Credentials expire after an hour unless expirySeconds is set; -1 requests
+// credentials that never expire.
+func (h *Handler) GetRegistryDockerCredentials(readWrite bool, expirySeconds int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	switch {
+	case expirySeconds == 0:
+		expirySeconds = defaultDockerCredentialsExpiry
+	case expirySeconds < 0 && expirySeconds != noDockerCredentialsExpiry:
+		return h.HandleError(fmt.Errorf("expiry_seconds must be positive, or -1 for credentials that never expire"), "get_registry_docker_credentials")
+	}
+
+	request := &godo.RegistryDockerCredentialsRequest{ReadWrite: readWrite}
+	if expirySeconds != noDockerCredentialsExpiry {
+		request.ExpirySeconds = &expirySeconds
+	}
+
+	credentials, _, err := client.Registry.DockerCredentials(context.Background(), request)
+	if err != nil {
+		return h.HandleError(err, "get_registry_docker_credentials")
+	}
+	if !json.Valid(credentials.DockerConfigJSON) {
+		return h.HandleError(fmt.Errorf("registry returned an invalid docker config"), "get_registry_docker_credentials")
+	}
+
+	result := map[string]interface{}{
+		"read_write":         readWrite,
+		"expires_at":         "never",
+		"docker_config_json": json.RawMessage(credentials.DockerConfigJSON),
+	}
+	if request.ExpirySeconds != nil {
+		result["expiry_seconds"] = expirySeconds
+		result["expires_at"] = time.Now().UTC().Add(time.Duration(expirySeconds) * time.Second).Truncate(time.Second)
+	}
+
+	return h.HandleSuccess(result, "get_registry_docker_credentials")
+}
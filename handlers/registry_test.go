@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"encoding/base64"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestRegistry(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	resp, err := h.ListRegistries()
+	expectError(t, resp, err, "list_registries", "404")
+
+	fake.Registry = &godo.Registry{Name: "acme", Region: "nyc3"}
+	for _, tag := range []godo.RepositoryTag{
+		{RegistryName: "acme", Repository: "api", Tag: "v1"},
+		{RegistryName: "acme", Repository: "api", Tag: "v2"},
+		{RegistryName: "acme", Repository: "web", Tag: "latest"},
+	} {
+		fake.AddRepositoryTag(tag)
+	}
+
+	t.Run("list registries", func(t *testing.T) {
+		var registry godo.Registry
+		resp, err := h.ListRegistries()
+		decodeResponse(t, resp, err, &registry)
+		if registry.Name != "acme" {
+			t.Errorf("name = %q", registry.Name)
+		}
+	})
+
+	t.Run("get registry", func(t *testing.T) {
+		var registry godo.Registry
+		resp, err := h.GetRegistry("acme")
+		decodeResponse(t, resp, err, &registry)
+		if registry.Region != "nyc3" {
+			t.Errorf("region = %q", registry.Region)
+		}
+	})
+
+	t.Run("get registry with another name", func(t *testing.T) {
+		resp, err := h.GetRegistry("globex")
+		expectError(t, resp, err, "get_registry", "registry globex not found")
+	})
+
+	t.Run("list repositories", func(t *testing.T) {
+		var repositories []godo.Repository
+		resp, err := h.ListRepositories("acme", 0, 0)
+		decodeList(t, resp, err, "repositories", &repositories)
+		if len(repositories) != 2 || repositories[0].TagCount != 2 {
+			t.Errorf("repositories = %+v", repositories)
+		}
+
+		resp, err = h.ListRepositories("other", 0, 0)
+		expectError(t, resp, err, "list_repositories", "404")
+	})
+
+	tests := []struct {
+		name       string
+		repository string
+		wantErr    string
+	}{
+		{name: "existing repository", repository: "web"},
+		{name: "missing repository", repository: "worker", wantErr: "repository worker not found"},
+	}
+	for _, tt := range tests {
+		t.Run("get repository/"+tt.name, func(t *testing.T) {
+			resp, err := h.GetRepository("acme", tt.repository)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "get_repository", tt.wantErr)
+				return
+			}
+			var repository godo.Repository
+			decodeResponse(t, resp, err, &repository)
+			if repository.Name != tt.repository {
+				t.Errorf("name = %q", repository.Name)
+			}
+		})
+	}
+
+	t.Run("list repository tags", func(t *testing.T) {
+		var tags []godo.RepositoryTag
+		resp, err := h.ListRepositoryTags("acme", "api", 0, 0)
+		decodeList(t, resp, err, "tags", &tags)
+		if len(tags) != 2 || tags[0].Tag != "v1" || tags[1].Tag != "v2" {
+			t.Errorf("tags = %+v", tags)
+		}
+	})
+}
+
+// seedRegistry creates the "acme" registry with an "api" repository holding
+// two manifests: sha256:aaa tagged v1 and stable, and sha256:bbb tagged v2.
+func seedRegistry(fake *fakedo.Server) {
+	fake.Registry = &godo.Registry{Name: "acme", Region: "nyc3"}
+	for _, tag := range []godo.RepositoryTag{
+		{RegistryName: "acme", Repository: "team/api", Tag: "v1", ManifestDigest: "sha256:aaa", CompressedSizeBytes: 1000},
+		{RegistryName: "acme", Repository: "team/api", Tag: "stable", ManifestDigest: "sha256:aaa", CompressedSizeBytes: 1000},
+		{RegistryName: "acme", Repository: "team/api", Tag: "v2", ManifestDigest: "sha256:bbb", CompressedSizeBytes: 2000},
+	} {
+		fake.AddRepositoryTag(tag)
+	}
+}
+
+func TestRepositoryManifestsAndDeletion(t *testing.T) {
+	t.Run("list manifests", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		seedRegistry(fake)
+
+		var manifests []godo.RepositoryManifest
+		resp, err := h.ListRepositoryManifests("acme", "team/api", 1, 1)
+		meta := decodeList(t, resp, err, "manifests", &manifests)
+		if len(manifests) != 1 || manifests[0].Digest != "sha256:aaa" || len(manifests[0].Tags) != 2 {
+			t.Errorf("manifests = %+v", manifests)
+		}
+		if meta.Total != 2 || meta.Pages != 2 {
+			t.Errorf("meta = %+v", meta)
+		}
+	})
+
+	t.Run("delete tag keeps the manifest", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		seedRegistry(fake)
+
+		preview, err := h.PreviewDeleteRepositoryTag("acme", "team/api", "v1")
+		if err != nil {
+			t.Fatalf("PreviewDeleteRepositoryTag: %v", err)
+		}
+		if preview.ID != "sha256:aaa" || preview.Name != "team/api:v1" || len(preview.Warnings) != 2 {
+			t.Errorf("preview = %+v", preview)
+		}
+
+		var result map[string]string
+		resp, err := h.DeleteRepositoryTag("acme", "team/api", "v1")
+		decodeResponse(t, resp, err, &result)
+
+		manifest, ok := fake.Manifests.Get("team/api@sha256:aaa")
+		if !ok || len(manifest.Tags) != 1 || manifest.Tags[0] != "stable" {
+			t.Errorf("manifest after tag deletion = %+v", manifest)
+		}
+
+		resp, err = h.DeleteRepositoryTag("acme", "team/api", "v1")
+		expectError(t, resp, err, "delete_repository_tag", "404")
+		if _, err := h.PreviewDeleteRepositoryTag("acme", "team/api", "v1"); err == nil {
+			t.Errorf("expected an error previewing a deleted tag")
+		}
+	})
+
+	t.Run("delete manifest removes its tags", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		seedRegistry(fake)
+
+		preview, err := h.PreviewDeleteRepositoryManifest("acme", "team/api", "sha256:aaa")
+		if err != nil {
+			t.Fatalf("PreviewDeleteRepositoryManifest: %v", err)
+		}
+		if len(preview.AttachedResources) != 2 || len(preview.Warnings) != 2 {
+			t.Errorf("preview = %+v", preview)
+		}
+
+		var result map[string]string
+		resp, err := h.DeleteRepositoryManifest("acme", "team/api", "sha256:aaa")
+		decodeResponse(t, resp, err, &result)
+
+		var tags []godo.RepositoryTag
+		resp, err = h.ListRepositoryTags("acme", "team/api", 0, 0)
+		decodeList(t, resp, err, "tags", &tags)
+		if len(tags) != 1 || tags[0].Tag != "v2" {
+			t.Errorf("tags after manifest deletion = %+v", tags)
+		}
+
+		resp, err = h.DeleteRepositoryManifest("acme", "team/api", "sha256:aaa")
+		expectError(t, resp, err, "delete_repository_manifest", "404")
+	})
+}
+
+func TestRegistryGarbageCollection(t *testing.T) {
+	tests := []struct {
+		name     string
+		gcType   string
+		wantType godo.GarbageCollectionType
+		wantErr  string
+	}{
+		{name: "default type", wantType: godo.GCTypeUnreferencedBlobsOnly},
+		{name: "untagged manifests", gcType: "untagged_manifests_and_unreferenced_blobs", wantType: godo.GCTypeUntaggedManifestsAndUnreferencedBlobs},
+		{name: "unknown type", gcType: "everything", wantErr: `invalid garbage collection type "everything"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedRegistry(fake)
+
+			resp, err := h.StartRegistryGarbageCollection("acme", tt.gcType)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "start_registry_garbage_collection", tt.wantErr)
+				return
+			}
+			var started godo.GarbageCollection
+			decodeResponse(t, resp, err, &started)
+			if started.Type != tt.wantType || started.Status != "requested" {
+				t.Errorf("started = %+v", started)
+			}
+		})
+	}
+
+	t.Run("lifecycle", func(t *testing.T) {
+		h, fake := newTestHandler(t)
+		seedRegistry(fake)
+
+		resp, err := h.GetRegistryGarbageCollection("acme")
+		expectError(t, resp, err, "get_registry_garbage_collection", "404")
+		resp, err = h.CancelRegistryGarbageCollection("acme", "")
+		expectError(t, resp, err, "cancel_registry_garbage_collection", "no active garbage collection to cancel")
+
+		var started godo.GarbageCollection
+		resp, err = h.StartRegistryGarbageCollection("acme", "")
+		decodeResponse(t, resp, err, &started)
+
+		resp, err = h.StartRegistryGarbageCollection("acme", "")
+		expectError(t, resp, err, "start_registry_garbage_collection", "409")
+
+		var active godo.GarbageCollection
+		resp, err = h.GetRegistryGarbageCollection("acme")
+		decodeResponse(t, resp, err, &active)
+		if active.UUID != started.UUID {
+			t.Errorf("active = %s, want %s", active.UUID, started.UUID)
+		}
+
+		var cancelled godo.GarbageCollection
+		resp, err = h.CancelRegistryGarbageCollection("acme", "")
+		decodeResponse(t, resp, err, &cancelled)
+		if cancelled.UUID != started.UUID || cancelled.Status != "cancelled" {
+			t.Errorf("cancelled = %+v", cancelled)
+		}
+
+		resp, err = h.CancelRegistryGarbageCollection("acme", started.UUID)
+		expectError(t, resp, err, "cancel_registry_garbage_collection", "already cancelled")
+
+		var gcs []godo.GarbageCollection
+		resp, err = h.ListRegistryGarbageCollections("acme", 0, 0)
+		decodeList(t, resp, err, "garbage_collections", &gcs)
+		if len(gcs) != 1 || gcs[0].Status != "cancelled" {
+			t.Errorf("garbage collections = %+v", gcs)
+		}
+	})
+}
+
+func TestGetRegistryDockerCredentials(t *testing.T) {
+	tests := []struct {
+		name          string
+		readWrite     bool
+		expiry        int
+		wantAuth      string
+		wantExpiresAt bool
+		wantErr       string
+	}{
+		{name: "expires in an hour by default", wantAuth: "read:expiry=3600", wantExpiresAt: true},
+		{name: "read write with expiry", readWrite: true, expiry: 600, wantAuth: "read-write:expiry=600", wantExpiresAt: true},
+		{name: "never expires when asked", expiry: -1, wantAuth: "read:expiry="},
+		{name: "negative expiry", expiry: -60, wantErr: "expiry_seconds must be positive, or -1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			seedRegistry(fake)
+
+			resp, err := h.GetRegistryDockerCredentials(tt.readWrite, tt.expiry)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "get_registry_docker_credentials", tt.wantErr)
+				return
+			}
+
+			var result struct {
+				ReadWrite bool   `json:"read_write"`
+				ExpiresAt string `json:"expires_at"`
+				Config    struct {
+					Auths map[string]struct {
+						Auth string `json:"auth"`
+					} `json:"auths"`
+				} `json:"docker_config_json"`
+			}
+			decodeResponse(t, resp, err, &result)
+
+			auth, err := base64.StdEncoding.DecodeString(result.Config.Auths["registry.digitalocean.com"].Auth)
+			if err != nil {
+				t.Fatalf("decoding auth: %v", err)
+			}
+			if string(auth) != tt.wantAuth || result.ReadWrite != tt.readWrite {
+				t.Errorf("auth = %q, read_write = %v", auth, result.ReadWrite)
+			}
+			if tt.wantExpiresAt == (result.ExpiresAt == "never") {
+				t.Errorf("expires_at = %q", result.ExpiresAt)
+			}
+		})
+	}
+}
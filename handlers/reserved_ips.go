@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+const (
+	ipv4 = "ipv4"
+	ipv6 = "ipv6"
+)
+
+var ipVersions = []string{ipv4, ipv6}
+
+// ReservedIP is an IPv4 or IPv6 reserved IP. The API describes the two
+// differently; this gives both one shape so they can be listed together.
+type ReservedIP struct {
+	IP         string             `json:"ip"`
+	Version    string             `json:"ip_version"`
+	Region     string             `json:"region"`
+	Droplet    *ReservedIPDroplet `json:"droplet"`
+	ProjectID  string             `json:"project_id,omitempty"`
+	Locked     bool               `json:"locked,omitempty"`
+	ReservedAt *time.Time         `json:"reserved_at,omitempty"`
+}
+
+// ReservedIPDroplet is the droplet a reserved IP points at.
+type ReservedIPDroplet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListReservedIPs lists reserved IPs of one version, or of both when version
+// is empty. Both versions come from separate endpoints, so they can only be
+// listed together in all-pages mode.
+func (h *Handler) ListReservedIPs(version string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateIPVersion(version); err != nil {
+		return h.HandleError(err, "list_reserved_ips")
+	}
+	if version == "" && page > 0 {
+		return h.HandleError(fmt.Errorf("set ip_version to ipv4 or ipv6 to fetch a single page"), "list_reserved_ips")
+	}
+
+	var v4, v6 []ReservedIP
+	var meta *ListMeta
+	if version != ipv6 {
+		reservedIPs, listMeta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.ReservedIP, *godo.Response, error) {
+			return client.ReservedIPs.List(context.Background(), opt)
+		})
+		if err != nil {
+			return h.HandleError(err, "list_reserved_ips")
+		}
+		v4, meta = reservedIPsFromV4(reservedIPs), listMeta
+	}
+	if version != ipv4 {
+		reservedIPs, listMeta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.ReservedIPV6, *godo.Response, error) {
+			return client.ReservedIPV6s.List(context.Background(), opt)
+		})
+		if err != nil {
+			return h.HandleError(err, "list_reserved_ips")
+		}
+		v6, meta = reservedIPsFromV6(reservedIPs), listMeta
+	}
+
+	if version == "" {
+		return h.HandleSuccess(filteredListResult("reserved_ips", append(v4, v6...)), "list_reserved_ips")
+	}
+	return h.HandleSuccess(listResult("reserved_ips", append(v4, v6...), meta), "list_reserved_ips")
+}
+
+func (h *Handler) GetReservedIP(ip string) (*mcp_golang.ToolResponse, error) {
+	reservedIP, err := h.getReservedIP(ip)
+	if err != nil {
+		return h.HandleError(err, "get_reserved_ip")
+	}
+	return h.HandleSuccess(reservedIP, "get_reserved_ip")
+}
+
+// CreateReservedIP reserves an IPv4 address in a region or directly on a
+// droplet, or an IPv6 address in a region. IPv6 addresses are assigned
+// separately with AssignReservedIP.
+func (h *Handler) CreateReservedIP(version, region string, dropletID int, projectID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateIPVersion(version); err != nil {
+		return h.HandleError(err, "create_reserved_ip")
+	}
+
+	if version == ipv6 {
+		if dropletID > 0 {
+			return h.HandleError(fmt.Errorf("IPv6 reserved IPs are created in a region; create one with region, then assign it with assign_reserved_ip"), "create_reserved_ip")
+		}
+		if region == "" {
+			return h.HandleError(fmt.Errorf("region is required for IPv6 reserved IPs"), "create_reserved_ip")
+		}
+		if err := h.checkProject(projectID); err != nil {
+			return h.HandleError(err, "create_reserved_ip")
+		}
+		reservedIP, _, err := client.ReservedIPV6s.Create(context.Background(), &godo.ReservedIPV6CreateRequest{Region: region})
+		if err != nil {
+			return h.HandleError(err, "create_reserved_ip")
+		}
+		if err := h.assignToProject(projectID, reservedIP); err != nil {
+			return h.HandleError(err, "create_reserved_ip")
+		}
+		return h.HandleSuccess(reservedIPFromV6(*reservedIP), "create_reserved_ip")
+	}
+
+	reservedIP, _, err := client.ReservedIPs.Create(context.Background(), &godo.ReservedIPCreateRequest{
+		Region:    region,
+		DropletID: dropletID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_reserved_ip")
+	}
+	return h.HandleSuccess(reservedIPFromV4(*reservedIP), "create_reserved_ip")
+}
+
+func (h *Handler) DeleteReservedIP(ip string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	version, err := ipVersionOf(ip)
+	if err != nil {
+		return h.HandleError(err, "delete_reserved_ip")
+	}
+
+	if version == ipv6 {
+		_, err = client.ReservedIPV6s.Delete(context.Background(), ip)
+	} else {
+		_, err = client.ReservedIPs.Delete(context.Background(), ip)
+	}
+	if err != nil {
+		return h.HandleError(err, "delete_reserved_ip")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Reserved IP %s deleted successfully", ip),
+	}, "delete_reserved_ip")
+}
+
+// PreviewDeleteReservedIP shows the address being released and what still
+// points at it.
+func (h *Handler) PreviewDeleteReservedIP(ip string) (*DeletionPreview, error) {
+	reservedIP, err := h.getReservedIP(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "reserved_ip",
+		ID:           reservedIP.IP,
+		Name:         fmt.Sprintf("%s reserved IP %s", reservedIP.Version, reservedIP.IP),
+		Region:       reservedIP.Region,
+	}
+	if reservedIP.Droplet != nil {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "droplet",
+			"id":   reservedIP.Droplet.ID,
+			"name": reservedIP.Droplet.Name,
+		})
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The address is assigned to droplet %s (%d), which loses it as a public endpoint", reservedIP.Droplet.Name, reservedIP.Droplet.ID))
+	}
+	if reservedIP.ProjectID != "" {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "project",
+			"id":   reservedIP.ProjectID,
+		})
+	}
+	if reservedIP.Locked {
+		preview.Warnings = append(preview.Warnings, "The address is locked by an action in progress and cannot be released until it completes")
+	}
+	preview.Warnings = append(preview.Warnings, "A released address goes back to the pool and cannot be reserved again")
+
+	return preview, nil
+}
+
+// AssignReservedIP points a reserved IP at a droplet in the same region,
+// moving it off any droplet it was assigned to.
+func (h *Handler) AssignReservedIP(ip string, dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	version, err := ipVersionOf(ip)
+	if err != nil {
+		return h.HandleError(err, "assign_reserved_ip")
+	}
+
+	var action *godo.Action
+	if version == ipv6 {
+		action, _, err = client.ReservedIPV6Actions.Assign(context.Background(), ip, dropletID)
+	} else {
+		action, _, err = client.ReservedIPActions.Assign(context.Background(), ip, dropletID)
+	}
+	if err != nil {
+		return h.HandleError(err, "assign_reserved_ip")
+	}
+
+	return h.actionResult(action, wait, "assign_reserved_ip")
+}
+
+func (h *Handler) UnassignReservedIP(ip string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	version, err := ipVersionOf(ip)
+	if err != nil {
+		return h.HandleError(err, "unassign_reserved_ip")
+	}
+
+	var action *godo.Action
+	if version == ipv6 {
+		action, _, err = client.ReservedIPV6Actions.Unassign(context.Background(), ip)
+	} else {
+		action, _, err = client.ReservedIPActions.Unassign(context.Background(), ip)
+	}
+	if err != nil {
+		return h.HandleError(err, "unassign_reserved_ip")
+	}
+
+	return h.actionResult(action, wait, "unassign_reserved_ip")
+}
+
+// ipVersionOf tells which endpoints serve ip.
+func ipVersionOf(ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	switch {
+	case parsed == nil:
+		return "", fmt.Errorf("invalid IP address %q", ip)
+	case parsed.To4() != nil:
+		return ipv4, nil
+	default:
+		return ipv6, nil
+	}
+}
+
+// getReservedIP looks an IP up with the endpoint for its version.
+func (h *Handler) getReservedIP(ip string) (ReservedIP, error) {
+	client := h.doClient.GetClient()
+
+	version, err := ipVersionOf(ip)
+	if err != nil {
+		return ReservedIP{}, err
+	}
+
+	if version == ipv6 {
+		reservedIP, _, err := client.ReservedIPV6s.Get(context.Background(), ip)
+		if err != nil {
+			return ReservedIP{}, err
+		}
+		return reservedIPFromV6(*reservedIP), nil
+	}
+
+	reservedIP, _, err := client.ReservedIPs.Get(context.Background(), ip)
+	if err != nil {
+		return ReservedIP{}, err
+	}
+	return reservedIPFromV4(*reservedIP), nil
+}
+
+func validateIPVersion(version string) error {
+	if version != "" && !slices.Contains(ipVersions, version) {
+		return fmt.Errorf("invalid ip_version %q; try: %s", version, strings.Join(suggest(version, ipVersions), ", "))
+	}
+	return nil
+}
+
+func reservedIPFromV4(reservedIP godo.ReservedIP) ReservedIP {
+	result := ReservedIP{
+		IP:        reservedIP.IP,
+		Version:   ipv4,
+		ProjectID: reservedIP.ProjectID,
+		Locked:    reservedIP.Locked,
+	}
+	if reservedIP.Region != nil {
+		result.Region = reservedIP.Region.Slug
+	}
+	if reservedIP.Droplet != nil {
+		result.Droplet = &ReservedIPDroplet{ID: reservedIP.Droplet.ID, Name: reservedIP.Droplet.Name}
+	}
+	return result
+}
+
+func reservedIPFromV6(reservedIP godo.ReservedIPV6) ReservedIP {
+	result := ReservedIP{
+		IP:      reservedIP.IP,
+		Version: ipv6,
+		Region:  reservedIP.RegionSlug,
+	}
+	if !reservedIP.ReservedAt.IsZero() {
+		result.ReservedAt = &reservedIP.ReservedAt
+	}
+	if reservedIP.Droplet != nil {
+		result.Droplet = &ReservedIPDroplet{ID: reservedIP.Droplet.ID, Name: reservedIP.Droplet.Name}
+	}
+	return result
+}
+
+func reservedIPsFromV4(reservedIPs []godo.ReservedIP) []ReservedIP {
+	result := make([]ReservedIP, len(reservedIPs))
+	for i, reservedIP := range reservedIPs {
+		result[i] = reservedIPFromV4(reservedIP)
+	}
+	return result
+}
+
+func reservedIPsFromV6(reservedIPs []godo.ReservedIPV6) []ReservedIP {
+	result := make([]ReservedIP, len(reservedIPs))
+	for i, reservedIP := range reservedIPs {
+		result[i] = reservedIPFromV6(reservedIP)
+	}
+	return result
+}
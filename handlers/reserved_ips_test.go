@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestCreateReservedIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		region      string
+		withDroplet bool
+		wantVersion string
+		wantRegion  string
+		wantErr     []string
+	}{
+		{name: "ipv4 in region", region: "sfo3", wantVersion: "ipv4", wantRegion: "sfo3"},
+		{name: "ipv4 on droplet", withDroplet: true, wantVersion: "ipv4", wantRegion: "nyc3"},
+		{name: "ipv6 in region", version: "ipv6", region: "nyc3", wantVersion: "ipv6", wantRegion: "nyc3"},
+		{name: "ipv4 without region", wantErr: []string{"422", "region or droplet_id is required"}},
+		{name: "ipv6 without region", version: "ipv6", wantErr: []string{"region is required for IPv6"}},
+		{name: "ipv6 on droplet", version: "ipv6", region: "nyc3", withDroplet: true, wantErr: []string{"then assign it with assign_reserved_ip"}},
+		{name: "typo", version: "ipv5", region: "nyc3", wantErr: []string{`invalid ip_version "ipv5"; try: ipv4, ipv6`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := 0
+			if tt.withDroplet {
+				dropletID = seedDroplets(fake, 1)[0]
+			}
+
+			resp, err := h.CreateReservedIP(tt.version, tt.region, dropletID, "")
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_reserved_ip", tt.wantErr...)
+				if fake.ReservedIPs.Len()+fake.ReservedIPV6s.Len() != 0 {
+					t.Error("an IP was reserved")
+				}
+				return
+			}
+
+			var reservedIP ReservedIP
+			decodeResponse(t, resp, err, &reservedIP)
+			if reservedIP.Version != tt.wantVersion || reservedIP.Region != tt.wantRegion || reservedIP.IP == "" {
+				t.Errorf("reserved IP = %+v", reservedIP)
+			}
+			if tt.withDroplet && (reservedIP.Droplet == nil || reservedIP.Droplet.ID != dropletID) {
+				t.Errorf("droplet = %+v, want %d", reservedIP.Droplet, dropletID)
+			}
+		})
+	}
+}
+
+func TestCreateReservedIPv6InProject(t *testing.T) {
+	h, fake := newTestHandler(t)
+	project := fake.AddProject("web")
+
+	var reservedIP ReservedIP
+	resp, err := h.CreateReservedIP("ipv6", "nyc3", 0, project.ID)
+	decodeResponse(t, resp, err, &reservedIP)
+
+	urn := godo.ReservedIPV6{IP: reservedIP.IP}.URN()
+	if resource, ok := fake.ProjectResources.Get(urn); !ok || resource.ProjectID != project.ID {
+		t.Errorf("%s was not assigned to project %s", urn, project.ID)
+	}
+
+	resp, err = h.CreateReservedIP("ipv6", "nyc3", 0, "project-missing")
+	expectError(t, resp, err, "create_reserved_ip", "project project-missing", "404")
+	if fake.ReservedIPV6s.Len() != 1 {
+		t.Errorf("reserved IPv6 addresses = %d, want the failed create to reserve none", fake.ReservedIPV6s.Len())
+	}
+}
+
+func TestReservedIPLifecycle(t *testing.T) {
+	for _, version := range []string{"ipv4", "ipv6"} {
+		t.Run(version, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := seedDroplets(fake, 1)[0]
+			elsewhere := fake.NextID()
+			fake.Droplets.Put(elsewhere, godo.Droplet{ID: elsewhere, Name: "far", Region: &godo.Region{Slug: "ams3"}})
+
+			var created ReservedIP
+			resp, err := h.CreateReservedIP(version, "nyc3", 0, "")
+			decodeResponse(t, resp, err, &created)
+
+			resp, err = h.UnassignReservedIP(created.IP, 0)
+			expectError(t, resp, err, "unassign_reserved_ip", "not assigned")
+			resp, err = h.AssignReservedIP(created.IP, elsewhere, 0)
+			expectError(t, resp, err, "assign_reserved_ip", "422", "is in ams3 but the reserved IP is in nyc3")
+
+			var action godo.Action
+			resp, err = h.AssignReservedIP(created.IP, dropletID, 0)
+			decodeResponse(t, resp, err, &action)
+			if action.Type != "assign" {
+				t.Errorf("action type = %q", action.Type)
+			}
+
+			var fetched ReservedIP
+			resp, err = h.GetReservedIP(created.IP)
+			decodeResponse(t, resp, err, &fetched)
+			if fetched.Version != version || fetched.Droplet == nil || fetched.Droplet.ID != dropletID {
+				t.Errorf("reserved IP = %+v", fetched)
+			}
+
+			resp, err = h.UnassignReservedIP(created.IP, 0)
+			decodeResponse(t, resp, err, &action)
+			if action.Type != "unassign" {
+				t.Errorf("action type = %q", action.Type)
+			}
+
+			resp, err = h.DeleteReservedIP(created.IP)
+			decodeResponse(t, resp, err, &map[string]string{})
+			resp, err = h.GetReservedIP(created.IP)
+			expectError(t, resp, err, "get_reserved_ip", "404")
+		})
+	}
+}
+
+func TestPreviewDeleteReservedIP(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.ReservedIPs.Put("203.0.113.10", godo.ReservedIP{
+		IP:        "203.0.113.10",
+		Region:    &godo.Region{Slug: "nyc3"},
+		Droplet:   &godo.Droplet{ID: 7, Name: "web"},
+		ProjectID: "project-1",
+	})
+	fake.ReservedIPV6s.Put("2001:db8::1", godo.ReservedIPV6{IP: "2001:db8::1", RegionSlug: "ams3"})
+
+	tests := []struct {
+		name         string
+		ip           string
+		wantRegion   string
+		wantAttached []string
+		wantWarnings int
+		wantErr      string
+	}{
+		{name: "assigned ipv4 in a project", ip: "203.0.113.10", wantRegion: "nyc3", wantAttached: []string{"droplet", "project"}, wantWarnings: 2},
+		{name: "unassigned ipv6", ip: "2001:db8::1", wantRegion: "ams3", wantWarnings: 1},
+		{name: "unknown address", ip: "203.0.113.99", wantErr: "404"},
+		{name: "not an address", ip: "web", wantErr: `invalid IP address "web"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview, err := h.PreviewDeleteReservedIP(tt.ip)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PreviewDeleteReservedIP: %v", err)
+			}
+			var attached []string
+			for _, resource := range preview.AttachedResources {
+				attached = append(attached, resource["type"].(string))
+			}
+			if preview.ID != tt.ip || preview.Region != tt.wantRegion || !slices.Equal(attached, tt.wantAttached) || len(preview.Warnings) != tt.wantWarnings {
+				t.Errorf("preview = %+v", preview)
+			}
+		})
+	}
+}
+
+func TestListReservedIPs(t *testing.T) {
+	h, _ := newTestHandler(t)
+	for _, version := range []string{"ipv4", "ipv4", "ipv6"} {
+		resp, err := h.CreateReservedIP(version, "nyc3", 0, "")
+		decodeResponse(t, resp, err, &ReservedIP{})
+	}
+
+	tests := []struct {
+		name      string
+		version   string
+		page      int
+		wantCount int
+		wantErr   string
+	}{
+		{name: "both", wantCount: 3},
+		{name: "ipv4", version: "ipv4", wantCount: 2},
+		{name: "ipv6 page", version: "ipv6", page: 1, wantCount: 1},
+		{name: "both paged", page: 1, wantErr: "set ip_version to ipv4 or ipv6"},
+		{name: "typo", version: "v6", wantErr: `invalid ip_version "v6"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.ListReservedIPs(tt.version, tt.page, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "list_reserved_ips", tt.wantErr)
+				return
+			}
+			var reservedIPs []ReservedIP
+			meta := decodeList(t, resp, err, "reserved_ips", &reservedIPs)
+			if len(reservedIPs) != tt.wantCount || meta.Total != tt.wantCount {
+				t.Errorf("listed %d (total %d), want %d", len(reservedIPs), meta.Total, tt.wantCount)
+			}
+			for _, reservedIP := range reservedIPs {
+				if tt.version != "" && reservedIP.Version != tt.version {
+					t.Errorf("listed %+v, want only %s", reservedIP, tt.version)
+				}
+			}
+		})
+	}
+}
+
+func TestReservedIPInvalidAddress(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	resp, err := h.GetReservedIP("203.0.113")
+	expectError(t, resp, err, "get_reserved_ip", `invalid IP address "203.0.113"`)
+	resp, err = h.AssignReservedIP("not-an-ip", 1, 0)
+	expectError(t, resp, err, "assign_reserved_ip", "invalid IP address")
+}
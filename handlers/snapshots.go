@@ -3,55 +3,59 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListSnapshots(resourceType string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListSnapshots(resourceType string, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	listOptions := &godo.ListOptions{}
+	// Filter by resource type on the API side so totals stay accurate
+	list := client.Snapshots.List
+	switch resourceType {
+	case "droplet":
+		list = client.Snapshots.ListDroplet
+	case "volume":
+		list = client.Snapshots.ListVolume
+	}
 	
-	snapshots, _, err := client.Snapshots.List(context.Background(), listOptions)
+	snapshots, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Snapshot, *godo.Response, error) {
+		return list(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_snapshots")
 	}
 
-	// Filter by resource type if specified
-	if resourceType != "" {
-		var filteredSnapshots []godo.Snapshot
-		for _, snapshot := range snapshots {
-			if snapshot.ResourceType == resourceType {
-				filteredSnapshots = append(filteredSnapshots, snapshot)
-			}
-		}
-		return h.HandleSuccess(filteredSnapshots, "list_snapshots")
-	}
-
-	return h.HandleSuccess(snapshots, "list_snapshots")
+	return h.HandleSuccess(listResult("snapshots", snapshots, meta), "list_snapshots")
 }
 
-func (h *Handler) ListVolumeSnapshots() (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListVolumeSnapshots(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	snapshots, _, err := client.Snapshots.ListVolume(context.Background(), &godo.ListOptions{})
+	snapshots, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Snapshot, *godo.Response, error) {
+		return client.Snapshots.ListVolume(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_volume_snapshots")
 	}
 
-	return h.HandleSuccess(snapshots, "list_volume_snapshots")
+	return h.HandleSuccess(listResult("snapshots", snapshots, meta), "list_volume_snapshots")
 }
 
-func (h *Handler) ListDropletSnapshots() (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListDropletSnapshots(page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	snapshots, _, err := client.Snapshots.ListDroplet(context.Background(), &godo.ListOptions{})
+	snapshots, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Snapshot, *godo.Response, error) {
+		return client.Snapshots.ListDroplet(context.Background(), opt)
+	})
 	if err != nil {
 		return h.HandleError(err, "list_droplet_snapshots")
 	}
 
-	return h.HandleSuccess(snapshots, "list_droplet_snapshots")
+	return h.HandleSuccess(listResult("snapshots", snapshots, meta), "list_droplet_snapshots")
 }
 
 func (h *Handler) GetSnapshot(snapshotID string) (*mcp_golang.ToolResponse, error) {
@@ -79,7 +83,7 @@ func (h *Handler) DeleteSnapshot(snapshotID string) (*mcp_golang.ToolResponse, e
 	}, "delete_snapshot")
 }
 
-func (h *Handler) CreateDropletSnapshot(dropletID int, name string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) CreateDropletSnapshot(dropletID int, name string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	action, _, err := client.DropletActions.Snapshot(context.Background(), dropletID, name)
@@ -87,5 +91,32 @@ func (h *Handler) CreateDropletSnapshot(dropletID int, name string) (*mcp_golang
 		return h.HandleError(err, "create_droplet_snapshot")
 	}
 
-	return h.HandleSuccess(action, "create_droplet_snapshot")
-}
\ No newline at end of file
+	return h.actionResult(action, wait, "create_droplet_snapshot")
+}
+
+func (h *Handler) PreviewDeleteSnapshot(snapshotID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	snapshot, _, err := client.Snapshots.Get(context.Background(), snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "snapshot",
+		ID:                   snapshot.ID,
+		Name:                 snapshot.Name,
+		EstimatedMonthlyCost: snapshot.SizeGigaBytes * snapshotPricePerGB * float64(len(snapshot.Regions)),
+		AttachedResources: []map[string]interface{}{
+			{
+				"type": snapshot.ResourceType,
+				"id":   snapshot.ResourceID,
+			},
+		},
+	}
+	if len(snapshot.Regions) > 0 {
+		preview.Region = strings.Join(snapshot.Regions, ",")
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func seedSnapshot(fake *fakedo.Server, name, resourceType string, size float64, regions ...string) string {
+	id := fake.NextUUID("snap")
+	fake.Snapshots.Put(id, godo.Snapshot{
+		ID:            id,
+		Name:          name,
+		ResourceID:    "res-1",
+		ResourceType:  resourceType,
+		Regions:       regions,
+		SizeGigaBytes: size,
+	})
+	return id
+}
+
+func TestListSnapshots(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedSnapshot(fake, "droplet-a", "droplet", 10, "nyc3")
+	seedSnapshot(fake, "volume-a", "volume", 10, "nyc3")
+	seedSnapshot(fake, "droplet-b", "droplet", 10, "ams3")
+
+	tests := []struct {
+		name         string
+		resourceType string
+		wantNames    []string
+	}{
+		{
+			name:      "all",
+			wantNames: []string{"droplet-a", "volume-a", "droplet-b"},
+		},
+		{
+			name:         "filtered by resource type",
+			resourceType: "volume",
+			wantNames:    []string{"volume-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var snapshots []godo.Snapshot
+			resp, err := h.ListSnapshots(tt.resourceType, 0, 0)
+			decodeList(t, resp, err, "snapshots", &snapshots)
+			assertSnapshotNames(t, snapshots, tt.wantNames)
+		})
+	}
+
+	t.Run("volume snapshots", func(t *testing.T) {
+		var snapshots []godo.Snapshot
+		resp, err := h.ListVolumeSnapshots(0, 0)
+		decodeList(t, resp, err, "snapshots", &snapshots)
+		assertSnapshotNames(t, snapshots, []string{"volume-a"})
+	})
+
+	t.Run("droplet snapshots", func(t *testing.T) {
+		var snapshots []godo.Snapshot
+		resp, err := h.ListDropletSnapshots(0, 0)
+		decodeList(t, resp, err, "snapshots", &snapshots)
+		assertSnapshotNames(t, snapshots, []string{"droplet-a", "droplet-b"})
+	})
+}
+
+func assertSnapshotNames(t *testing.T, snapshots []godo.Snapshot, want []string) {
+	t.Helper()
+	if len(snapshots) != len(want) {
+		t.Fatalf("got %d snapshots, want %d", len(snapshots), len(want))
+	}
+	for i, snapshot := range snapshots {
+		if snapshot.Name != want[i] {
+			t.Errorf("snapshot %d = %q, want %q", i, snapshot.Name, want[i])
+		}
+	}
+}
+
+func TestGetAndDeleteSnapshot(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedSnapshot(fake, "nightly", "volume", 10, "nyc3")
+
+	var snapshot godo.Snapshot
+	resp, err := h.GetSnapshot(id)
+	decodeResponse(t, resp, err, &snapshot)
+	if snapshot.Name != "nightly" {
+		t.Errorf("name = %q", snapshot.Name)
+	}
+
+	var result map[string]string
+	resp, err = h.DeleteSnapshot(id)
+	decodeResponse(t, resp, err, &result)
+	if fake.Snapshots.Len() != 0 {
+		t.Errorf("snapshot still exists after delete")
+	}
+
+	resp, err = h.GetSnapshot(id)
+	expectError(t, resp, err, "get_snapshot", "404")
+	resp, err = h.DeleteSnapshot(id)
+	expectError(t, resp, err, "delete_snapshot", "404")
+}
+
+func TestCreateDropletSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    int
+		wantErr string
+	}{
+		{name: "created"},
+		{name: "droplet busy", fail: http.StatusUnprocessableEntity, wantErr: "Droplet already has a pending event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			id := seedDroplets(fake, 1)[0]
+			if tt.fail != 0 {
+				fake.FailNext("POST", "/v2/droplets/"+strconv.Itoa(id)+"/actions", tt.fail, tt.wantErr)
+			}
+
+			resp, err := h.CreateDropletSnapshot(id, "before-upgrade", 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_droplet_snapshot", "422", tt.wantErr)
+				return
+			}
+
+			var action godo.Action
+			decodeResponse(t, resp, err, &action)
+			if action.Type != "snapshot" || action.ResourceID != id {
+				t.Errorf("got action %+v", action)
+			}
+			snapshots := fake.Snapshots.List()
+			if len(snapshots) != 1 || snapshots[0].Name != "before-upgrade" {
+				t.Errorf("snapshots = %+v", snapshots)
+			}
+		})
+	}
+}
+
+func TestPreviewDeleteSnapshot(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedSnapshot(fake, "nightly", "droplet", 50, "nyc3", "ams3")
+
+	preview, err := h.PreviewDeleteSnapshot(id)
+	if err != nil {
+		t.Fatalf("PreviewDeleteSnapshot: %v", err)
+	}
+	if preview.Region != "nyc3,ams3" || preview.EstimatedMonthlyCost != 50*snapshotPricePerGB*2 {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(preview.AttachedResources) != 1 || preview.AttachedResources[0]["type"] != "droplet" {
+		t.Errorf("attached resources = %v", preview.AttachedResources)
+	}
+}
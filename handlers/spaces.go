@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"context"
+	"digitalocean-mcp-server/client"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+const (
+	defaultObjectReadBytes = 1 << 20
+	maxObjectReadBytes     = 10 << 20
+	defaultPresignExpiry   = time.Hour
+)
+
+var (
+	bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+	cannedACLs        = []string{"private", "public-read"}
+	corsMethods       = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+)
+
+// SpacesObject is an object's metadata and, for get_spaces_object, its
+// content. Text is returned as-is and anything else base64-encoded.
+type SpacesObject struct {
+	Bucket       string            `json:"bucket"`
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	ContentType  string            `json:"content_type,omitempty"`
+	ETag         string            `json:"etag"`
+	LastModified time.Time         `json:"last_modified"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Encoding     string            `json:"encoding,omitempty"`
+	Content      *string           `json:"content,omitempty"`
+	Truncated    bool              `json:"truncated,omitempty"`
+}
+
+// PutObjectOptions describes an upload. Encoding is "text" (the default) or
+// "base64"; ContentType defaults from the key's extension.
+type PutObjectOptions struct {
+	Content     string
+	Encoding    string
+	ContentType string
+	ACL         string
+}
+
+func (h *Handler) ListSpacesBuckets() (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "list_spaces_buckets")
+	}
+
+	buckets, err := spaces.ListBuckets(context.Background())
+	if err != nil {
+		return h.HandleError(err, "list_spaces_buckets")
+	}
+	if buckets == nil {
+		buckets = []client.Bucket{}
+	}
+
+	return h.HandleSuccess(filteredListResult("buckets", buckets), "list_spaces_buckets")
+}
+
+// CreateSpacesBucket creates a bucket in the configured region. Names are
+// global across Spaces, so a taken name fails with a conflict.
+func (h *Handler) CreateSpacesBucket(name, acl string) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "create_spaces_bucket")
+	}
+	if acl == "" {
+		acl = "private"
+	}
+	if err := validateBucketName(name); err != nil {
+		return h.HandleError(err, "create_spaces_bucket")
+	}
+	if !slices.Contains(cannedACLs, acl) {
+		return h.HandleError(fmt.Errorf("invalid acl %q: must be one of %s", acl, strings.Join(cannedACLs, ", ")), "create_spaces_bucket")
+	}
+
+	if err := spaces.CreateBucket(context.Background(), name, acl); err != nil {
+		return h.HandleError(err, "create_spaces_bucket")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"name":   name,
+		"region": spaces.Region(),
+		"acl":    acl,
+	}, "create_spaces_bucket")
+}
+
+func (h *Handler) DeleteSpacesBucket(name string) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "delete_spaces_bucket")
+	}
+
+	if err := spaces.DeleteBucket(context.Background(), name); err != nil {
+		var spacesErr *client.SpacesError
+		if errors.As(err, &spacesErr) && spacesErr.Code == "BucketNotEmpty" {
+			err = fmt.Errorf("bucket %s is not empty; delete its objects first: %w", name, err)
+		}
+		return h.HandleError(err, "delete_spaces_bucket")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Bucket %s deleted successfully", name),
+	}, "delete_spaces_bucket")
+}
+
+// PreviewDeleteSpacesBucket counts the bucket's objects, since Spaces only
+// deletes empty buckets.
+func (h *Handler) PreviewDeleteSpacesBucket(name string) (*DeletionPreview, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := spaces.ListObjects(context.Background(), name, client.ListObjectsOptions{MaxKeys: 1000})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "spaces_bucket",
+		ID:           name,
+		Name:         name,
+		Region:       spaces.Region(),
+	}
+	if count := len(page.Objects); count > 0 {
+		more := ""
+		if page.Truncated {
+			more = "+"
+		}
+		preview.Warnings = append(preview.Warnings,
+			fmt.Sprintf("Bucket holds %d%s objects; Spaces refuses to delete it until they are deleted", count, more))
+	}
+	return preview, nil
+}
+
+// ListSpacesObjects lists one page of objects under prefix. With a
+// delimiter (usually "/"), keys below the next delimiter are rolled up into
+// common_prefixes like directories. Pass next_cursor back as cursor for the
+// following page.
+func (h *Handler) ListSpacesObjects(bucket string, opts client.ListObjectsOptions) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "list_spaces_objects")
+	}
+	if opts.MaxKeys < 0 || opts.MaxKeys > 1000 {
+		return h.HandleError(fmt.Errorf("max_keys must be between 1 and 1000"), "list_spaces_objects")
+	}
+
+	result, err := spaces.ListObjects(context.Background(), bucket, opts)
+	if err != nil {
+		return h.HandleError(err, "list_spaces_objects")
+	}
+
+	return h.HandleSuccess(result, "list_spaces_objects")
+}
+
+func (h *Handler) HeadSpacesObject(bucket, key string) (*mcp_golang.ToolResponse, error) {
+	object, err := h.headSpacesObject(bucket, key)
+	if err != nil {
+		return h.HandleError(err, "head_spaces_object")
+	}
+
+	return h.HandleSuccess(object, "head_spaces_object")
+}
+
+// GetSpacesObject downloads at most maxBytes (default 1 MiB) of an object.
+// Text content is returned as-is; binary content is base64-encoded.
+func (h *Handler) GetSpacesObject(bucket, key string, maxBytes int64) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "get_spaces_object")
+	}
+	if maxBytes == 0 {
+		maxBytes = defaultObjectReadBytes
+	}
+	if maxBytes < 0 || maxBytes > maxObjectReadBytes {
+		return h.HandleError(fmt.Errorf("max_bytes must be between 1 and %d", maxObjectReadBytes), "get_spaces_object")
+	}
+
+	info, body, err := spaces.GetObject(context.Background(), bucket, key, maxBytes)
+	if err != nil {
+		return h.HandleError(objectError(bucket, key, err), "get_spaces_object")
+	}
+
+	object := spacesObject(bucket, info)
+	object.Truncated = int64(len(body)) < info.Size
+	content := base64.StdEncoding.EncodeToString(body)
+	object.Encoding = "base64"
+	if text, ok := textContent(info.ContentType, body, object.Truncated); ok {
+		content = text
+		object.Encoding = "text"
+	}
+	object.Content = &content
+
+	return h.HandleSuccess(object, "get_spaces_object")
+}
+
+// PutSpacesObject uploads an object, replacing any with the same key.
+func (h *Handler) PutSpacesObject(bucket, key string, opts PutObjectOptions) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "put_spaces_object")
+	}
+	if key == "" {
+		return h.HandleError(fmt.Errorf("key is required"), "put_spaces_object")
+	}
+	if opts.ACL == "" {
+		opts.ACL = "private"
+	}
+	if !slices.Contains(cannedACLs, opts.ACL) {
+		return h.HandleError(fmt.Errorf("invalid acl %q: must be one of %s", opts.ACL, strings.Join(cannedACLs, ", ")), "put_spaces_object")
+	}
+
+	var body []byte
+	switch opts.Encoding {
+	case "", "text":
+		body = []byte(opts.Content)
+	case "base64":
+		body, err = base64.StdEncoding.DecodeString(opts.Content)
+		if err != nil {
+			return h.HandleError(fmt.Errorf("content is not valid base64: %w", err), "put_spaces_object")
+		}
+	default:
+		return h.HandleError(fmt.Errorf("invalid encoding %q: must be text or base64", opts.Encoding), "put_spaces_object")
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+		if opts.Encoding != "base64" {
+			contentType = "text/plain; charset=utf-8"
+		}
+	}
+
+	etag, err := spaces.PutObject(context.Background(), bucket, key, body, contentType, opts.ACL)
+	if err != nil {
+		return h.HandleError(err, "put_spaces_object")
+	}
+
+	return h.HandleSuccess(SpacesObject{
+		Bucket:      bucket,
+		Key:         key,
+		Size:        int64(len(body)),
+		ContentType: contentType,
+		ETag:        etag,
+	}, "put_spaces_object")
+}
+
+// DeleteSpacesObject deletes an object. S3 reports success for keys that do
+// not exist, so the object is looked up first to catch typos.
+func (h *Handler) DeleteSpacesObject(bucket, key string) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "delete_spaces_object")
+	}
+	if _, err := h.headSpacesObject(bucket, key); err != nil {
+		return h.HandleError(err, "delete_spaces_object")
+	}
+
+	if err := spaces.DeleteObject(context.Background(), bucket, key); err != nil {
+		return h.HandleError(err, "delete_spaces_object")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Object %s deleted successfully from bucket %s", key, bucket),
+	}, "delete_spaces_object")
+}
+
+func (h *Handler) PreviewDeleteSpacesObject(bucket, key string) (*DeletionPreview, error) {
+	object, err := h.headSpacesObject(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletionPreview{
+		ResourceType: "spaces_object",
+		ID:           bucket + "/" + key,
+		Name:         key,
+		Region:       h.spaces.Region(),
+		Warnings: []string{
+			fmt.Sprintf("%d bytes of %s will be removed; Spaces does not keep deleted objects unless versioning is enabled", object.Size, object.ContentType),
+		},
+	}, nil
+}
+
+// PresignSpacesURL returns a URL that lets anyone holding it download (GET)
+// or upload (PUT) one object until it expires. A GET URL is only issued for
+// an object that exists.
+func (h *Handler) PresignSpacesURL(bucket, key, method string, expires time.Duration) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "presign_spaces_url")
+	}
+	method = strings.ToUpper(method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		return h.HandleError(fmt.Errorf("invalid method %q: must be GET or PUT", method), "presign_spaces_url")
+	}
+	if expires == 0 {
+		expires = defaultPresignExpiry
+	}
+	if method == http.MethodGet {
+		if _, err := h.headSpacesObject(bucket, key); err != nil {
+			return h.HandleError(err, "presign_spaces_url")
+		}
+	}
+
+	url, err := spaces.PresignURL(method, bucket, key, expires)
+	if err != nil {
+		return h.HandleError(err, "presign_spaces_url")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"url":        url,
+		"method":     method,
+		"expires_at": time.Now().UTC().Add(expires).Truncate(time.Second),
+	}, "presign_spaces_url")
+}
+
+func (h *Handler) GetSpacesBucketCORS(bucket string) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "get_spaces_bucket_cors")
+	}
+
+	rules, err := spaces.GetBucketCORS(context.Background(), bucket)
+	if err != nil {
+		return h.HandleError(err, "get_spaces_bucket_cors")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"bucket": bucket, "cors_rules": rules}, "get_spaces_bucket_cors")
+}
+
+// SetSpacesBucketCORS replaces a bucket's CORS rules. An empty list removes
+// the configuration.
+func (h *Handler) SetSpacesBucketCORS(bucket string, rules []client.CORSRule) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "set_spaces_bucket_cors")
+	}
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 {
+			return h.HandleError(fmt.Errorf("rule %d: allowed_origins and allowed_methods are required", i+1), "set_spaces_bucket_cors")
+		}
+		for j, method := range rule.AllowedMethods {
+			rule.AllowedMethods[j] = strings.ToUpper(method)
+			if !slices.Contains(corsMethods, rule.AllowedMethods[j]) {
+				return h.HandleError(fmt.Errorf("rule %d: invalid method %q: must be one of %s", i+1, method, strings.Join(corsMethods, ", ")), "set_spaces_bucket_cors")
+			}
+		}
+		if rule.MaxAgeSeconds < 0 {
+			return h.HandleError(fmt.Errorf("rule %d: max_age_seconds cannot be negative", i+1), "set_spaces_bucket_cors")
+		}
+	}
+
+	if err := spaces.PutBucketCORS(context.Background(), bucket, rules); err != nil {
+		return h.HandleError(err, "set_spaces_bucket_cors")
+	}
+
+	return h.GetSpacesBucketCORS(bucket)
+}
+
+func (h *Handler) GetSpacesBucketLifecycle(bucket string) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "get_spaces_bucket_lifecycle")
+	}
+
+	rules, err := spaces.GetBucketLifecycle(context.Background(), bucket)
+	if err != nil {
+		return h.HandleError(err, "get_spaces_bucket_lifecycle")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{"bucket": bucket, "lifecycle_rules": rules}, "get_spaces_bucket_lifecycle")
+}
+
+// SetSpacesBucketLifecycle replaces a bucket's lifecycle rules. Rules
+// without an ID are numbered; an empty list removes the configuration.
+func (h *Handler) SetSpacesBucketLifecycle(bucket string, rules []client.LifecycleRule) (*mcp_golang.ToolResponse, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return h.HandleError(err, "set_spaces_bucket_lifecycle")
+	}
+	ids := map[string]bool{}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.ExpirationDays < 0 || rule.AbortIncompleteMultipartUploadDays < 0 {
+			return h.HandleError(fmt.Errorf("rule %d: days cannot be negative", i+1), "set_spaces_bucket_lifecycle")
+		}
+		if rule.ExpirationDays == 0 && rule.AbortIncompleteMultipartUploadDays == 0 {
+			return h.HandleError(fmt.Errorf("rule %d: set expiration_days or abort_incomplete_multipart_upload_days", i+1), "set_spaces_bucket_lifecycle")
+		}
+		if rule.ID == "" {
+			rule.ID = fmt.Sprintf("rule-%d", i+1)
+		}
+		if ids[rule.ID] {
+			return h.HandleError(fmt.Errorf("duplicate rule id %q", rule.ID), "set_spaces_bucket_lifecycle")
+		}
+		ids[rule.ID] = true
+	}
+
+	if err := spaces.PutBucketLifecycle(context.Background(), bucket, rules); err != nil {
+		return h.HandleError(err, "set_spaces_bucket_lifecycle")
+	}
+
+	return h.GetSpacesBucketLifecycle(bucket)
+}
+
+// spacesClient returns the S3 client, or explains how to configure one.
+func (h *Handler) spacesClient() (*client.SpacesClient, error) {
+	if h.spaces == nil {
+		return nil, fmt.Errorf("Spaces is not configured: set SPACES_ACCESS_KEY_ID and SPACES_SECRET_ACCESS_KEY (create a key with create_spaces_key)")
+	}
+	return h.spaces, nil
+}
+
+func (h *Handler) headSpacesObject(bucket, key string) (*SpacesObject, error) {
+	spaces, err := h.spacesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := spaces.HeadObject(context.Background(), bucket, key)
+	if err != nil {
+		return nil, objectError(bucket, key, err)
+	}
+	object := spacesObject(bucket, info)
+	return &object, nil
+}
+
+func spacesObject(bucket string, info *client.ObjectInfo) SpacesObject {
+	return SpacesObject{
+		Bucket:       bucket,
+		Key:          info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+		Metadata:     info.Metadata,
+	}
+}
+
+// objectError names the object in a 404, which HEAD responses cannot
+// explain themselves.
+func objectError(bucket, key string, err error) error {
+	if client.IsSpacesNotFound(err) {
+		return fmt.Errorf("object %s not found in bucket %s: %w", key, bucket, err)
+	}
+	return err
+}
+
+func validateBucketName(name string) error {
+	if !bucketNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid bucket name %q: use 3-63 lowercase letters, numbers and hyphens, starting and ending with a letter or number", name)
+	}
+	return nil
+}
+
+// textContent returns body as text when its content type is textual and it
+// is valid UTF-8. A truncated body may end partway through a character,
+// which is dropped.
+func textContent(contentType string, body []byte, truncated bool) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	textual := strings.HasPrefix(mediaType, "text/")
+	for _, suffix := range []string{"json", "xml", "javascript", "yaml", "csv"} {
+		textual = textual || strings.HasSuffix(mediaType, suffix)
+	}
+	if !textual {
+		return "", false
+	}
+
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	}
+	return string(body), utf8.Valid(body)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+var spacesKeyPermissions = []string{
+	string(godo.SpacesKeyRead),
+	string(godo.SpacesKeyReadWrite),
+	string(godo.SpacesKeyFullAccess),
+}
+
+// SpacesKeyUpdate holds the fields to change on an access key. Grants
+// replace the current ones when non-nil.
+type SpacesKeyUpdate struct {
+	Name   *string
+	Grants []*godo.Grant
+}
+
+// ListSpacesKeys lists access keys and their grants. Secrets are only ever
+// returned when a key is created.
+func (h *Handler) ListSpacesKeys(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	keys, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.SpacesKey, *godo.Response, error) {
+		return client.SpacesKeys.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_spaces_keys")
+	}
+
+	return h.HandleSuccess(listResult("keys", keys, meta), "list_spaces_keys")
+}
+
+func (h *Handler) GetSpacesKey(accessKey string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	key, _, err := client.SpacesKeys.Get(context.Background(), accessKey)
+	if err != nil {
+		return h.HandleError(err, "get_spaces_key")
+	}
+
+	return h.HandleSuccess(key, "get_spaces_key")
+}
+
+// CreateSpacesKey creates an access key. The response is the only time the
+// secret is shown.
+func (h *Handler) CreateSpacesKey(name string, grants []*godo.Grant) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if name == "" {
+		return h.HandleError(fmt.Errorf("name is required"), "create_spaces_key")
+	}
+	if err := validateSpacesGrants(grants); err != nil {
+		return h.HandleError(err, "create_spaces_key")
+	}
+
+	key, _, err := client.SpacesKeys.Create(context.Background(), &godo.SpacesKeyCreateRequest{
+		Name:   name,
+		Grants: grants,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_spaces_key")
+	}
+
+	return h.HandleSuccess(map[string]interface{}{
+		"key":  key,
+		"note": "Store secret_key now; it cannot be retrieved again",
+	}, "create_spaces_key")
+}
+
+// UpdateSpacesKey renames a key or replaces its grants. The API replaces
+// both, so the current key is fetched and merged first.
+func (h *Handler) UpdateSpacesKey(accessKey string, update SpacesKeyUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if update.Name == nil && update.Grants == nil {
+		return h.HandleError(fmt.Errorf("nothing to update: set name or grants"), "update_spaces_key")
+	}
+	if update.Name != nil && *update.Name == "" {
+		return h.HandleError(fmt.Errorf("name cannot be empty"), "update_spaces_key")
+	}
+	if update.Grants != nil {
+		if err := validateSpacesGrants(update.Grants); err != nil {
+			return h.HandleError(err, "update_spaces_key")
+		}
+	}
+
+	current, _, err := client.SpacesKeys.Get(context.Background(), accessKey)
+	if err != nil {
+		return h.HandleError(err, "update_spaces_key")
+	}
+	request := &godo.SpacesKeyUpdateRequest{Name: current.Name, Grants: current.Grants}
+	if update.Name != nil {
+		request.Name = *update.Name
+	}
+	if update.Grants != nil {
+		request.Grants = update.Grants
+	}
+
+	key, _, err := client.SpacesKeys.Update(context.Background(), accessKey, request)
+	if err != nil {
+		return h.HandleError(err, "update_spaces_key")
+	}
+
+	return h.HandleSuccess(key, "update_spaces_key")
+}
+
+func (h *Handler) DeleteSpacesKey(accessKey string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.SpacesKeys.Delete(context.Background(), accessKey)
+	if err != nil {
+		return h.HandleError(err, "delete_spaces_key")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Spaces key %s deleted successfully", accessKey),
+	}, "delete_spaces_key")
+}
+
+// PreviewDeleteSpacesKey lists the key's grants and warns when it is the key
+// this server signs Spaces requests with.
+func (h *Handler) PreviewDeleteSpacesKey(accessKey string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	key, _, err := client.SpacesKeys.Get(context.Background(), accessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "spaces_key",
+		ID:           key.AccessKey,
+		Name:         key.Name,
+		Warnings:     []string{"Anything signing requests with this key loses access to Spaces immediately"},
+	}
+	for _, grant := range key.Grants {
+		bucket := grant.Bucket
+		if bucket == "" {
+			bucket = "all buckets"
+		}
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type":       "grant",
+			"bucket":     bucket,
+			"permission": grant.Permission,
+		})
+	}
+	if h.spaces != nil && h.spaces.AccessKey() == key.AccessKey {
+		preview.Warnings = append(preview.Warnings, "This is the key the server uses (SPACES_ACCESS_KEY_ID); the Spaces object tools will stop working")
+	}
+	return preview, nil
+}
+
+// validateSpacesGrants checks permissions and that fullaccess, which covers
+// every bucket, does not name one while read and readwrite do.
+func validateSpacesGrants(grants []*godo.Grant) error {
+	if len(grants) == 0 {
+		return fmt.Errorf("at least one grant is required, e.g. {bucket: 'assets', permission: 'read'} or {permission: 'fullaccess'}")
+	}
+	for i, grant := range grants {
+		permission := string(grant.Permission)
+		switch grant.Permission {
+		case godo.SpacesKeyRead, godo.SpacesKeyReadWrite:
+			if grant.Bucket == "" {
+				return fmt.Errorf("grant %d: %s grants need a bucket", i+1, permission)
+			}
+		case godo.SpacesKeyFullAccess:
+			if grant.Bucket != "" {
+				return fmt.Errorf("grant %d: fullaccess applies to all buckets; omit bucket", i+1)
+			}
+		default:
+			return fmt.Errorf("grant %d: invalid permission %q; try: %s", i+1, permission, strings.Join(suggest(permission, spacesKeyPermissions), ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/client"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestSpacesKeyCRUD(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var created struct {
+		Key  godo.SpacesKey `json:"key"`
+		Note string         `json:"note"`
+	}
+	resp, err := h.CreateSpacesKey("deploy", []*godo.Grant{{Bucket: "assets", Permission: godo.SpacesKeyReadWrite}})
+	decodeResponse(t, resp, err, &created)
+	if created.Key.AccessKey == "" || created.Key.SecretKey == "" || created.Note == "" {
+		t.Errorf("created = %+v", created)
+	}
+	accessKey := created.Key.AccessKey
+
+	var keys []godo.SpacesKey
+	resp, err = h.ListSpacesKeys(0, 0)
+	meta := decodeList(t, resp, err, "keys", &keys)
+	if meta.Total != 1 || keys[0].Name != "deploy" || keys[0].SecretKey != "" {
+		t.Errorf("keys = %+v", keys)
+	}
+
+	var key godo.SpacesKey
+	resp, err = h.GetSpacesKey(accessKey)
+	decodeResponse(t, resp, err, &key)
+	if key.SecretKey != "" || len(key.Grants) != 1 || key.Grants[0].Bucket != "assets" {
+		t.Errorf("key = %+v", key)
+	}
+
+	name := "deploy-ci"
+	resp, err = h.UpdateSpacesKey(accessKey, SpacesKeyUpdate{Name: &name})
+	decodeResponse(t, resp, err, &key)
+	if key.Name != "deploy-ci" || len(key.Grants) != 1 || key.Grants[0].Permission != godo.SpacesKeyReadWrite {
+		t.Errorf("renamed key = %+v, want the grants kept", key)
+	}
+	resp, err = h.UpdateSpacesKey(accessKey, SpacesKeyUpdate{Grants: []*godo.Grant{{Permission: godo.SpacesKeyFullAccess}}})
+	decodeResponse(t, resp, err, &key)
+	if key.Name != "deploy-ci" || len(key.Grants) != 1 || key.Grants[0].Permission != godo.SpacesKeyFullAccess {
+		t.Errorf("regranted key = %+v", key)
+	}
+
+	empty := ""
+	tests := []struct {
+		name      string
+		accessKey string
+		update    SpacesKeyUpdate
+		wantErr   string
+	}{
+		{name: "nothing", accessKey: accessKey, wantErr: "nothing to update"},
+		{name: "empty name", accessKey: accessKey, update: SpacesKeyUpdate{Name: &empty}, wantErr: "name cannot be empty"},
+		{name: "no grants", accessKey: accessKey, update: SpacesKeyUpdate{Grants: []*godo.Grant{}}, wantErr: "at least one grant is required"},
+		{name: "missing", accessKey: "DO00MISSING", update: SpacesKeyUpdate{Name: &name}, wantErr: "404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.UpdateSpacesKey(tt.accessKey, tt.update)
+			expectError(t, resp, err, "update_spaces_key", tt.wantErr)
+		})
+	}
+
+	resp, err = h.DeleteSpacesKey(accessKey)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.SpacesKeys.Len() != 0 {
+		t.Error("key was not deleted")
+	}
+	resp, err = h.DeleteSpacesKey(accessKey)
+	expectError(t, resp, err, "delete_spaces_key", "404")
+}
+
+func TestCreateSpacesKeyValidation(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	tests := []struct {
+		name    string
+		keyName string
+		grants  []*godo.Grant
+		wantErr string
+	}{
+		{name: "no name", grants: []*godo.Grant{{Permission: godo.SpacesKeyFullAccess}}, wantErr: "name is required"},
+		{name: "no grants", keyName: "ci", wantErr: "at least one grant is required"},
+		{name: "typo", keyName: "ci", grants: []*godo.Grant{{Bucket: "assets", Permission: "reed"}}, wantErr: `grant 1: invalid permission "reed"; try: read`},
+		{name: "read without bucket", keyName: "ci", grants: []*godo.Grant{{Permission: godo.SpacesKeyRead}}, wantErr: "grant 1: read grants need a bucket"},
+		{name: "fullaccess with bucket", keyName: "ci", grants: []*godo.Grant{{Bucket: "assets", Permission: godo.SpacesKeyFullAccess}}, wantErr: "fullaccess applies to all buckets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.CreateSpacesKey(tt.keyName, tt.grants)
+			expectError(t, resp, err, "create_spaces_key", tt.wantErr)
+		})
+	}
+	if fake.SpacesKeys.Len() != 0 {
+		t.Errorf("keys = %d, want none created", fake.SpacesKeys.Len())
+	}
+}
+
+func TestPreviewDeleteSpacesKey(t *testing.T) {
+	h, fake := newTestHandler(t)
+	key := fake.AddSpacesKey("deploy",
+		&godo.Grant{Bucket: "assets", Permission: godo.SpacesKeyRead},
+		&godo.Grant{Bucket: "logs", Permission: godo.SpacesKeyReadWrite})
+
+	preview, err := h.PreviewDeleteSpacesKey(key.AccessKey)
+	if err != nil {
+		t.Fatalf("PreviewDeleteSpacesKey: %v", err)
+	}
+	if preview.ResourceType != "spaces_key" || preview.Name != "deploy" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 1 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	// Deleting the key the server signs with breaks the object tools
+	spaces, _ := client.NewSpacesClient(fake.SpacesURL(), "", key.AccessKey, key.SecretKey)
+	h.SetSpacesClient(spaces)
+	preview, err = h.PreviewDeleteSpacesKey(key.AccessKey)
+	if err != nil {
+		t.Fatalf("PreviewDeleteSpacesKey: %v", err)
+	}
+	if len(preview.Warnings) != 2 {
+		t.Errorf("warnings = %v, want the server's key flagged", preview.Warnings)
+	}
+
+	if _, err := h.PreviewDeleteSpacesKey("DO00MISSING"); err == nil {
+		t.Error("PreviewDeleteSpacesKey succeeded for a missing key")
+	}
+}
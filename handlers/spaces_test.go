@@ -0,0 +1,467 @@
+package handlers
+
+import (
+	"bytes"
+	"digitalocean-mcp-server/client"
+	"digitalocean-mcp-server/internal/fakedo"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestSpacesBuckets(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var created map[string]string
+	resp, err := h.CreateSpacesBucket("assets", "")
+	decodeResponse(t, resp, err, &created)
+	if created["region"] != "nyc3" || created["acl"] != "private" {
+		t.Errorf("created = %v", created)
+	}
+
+	tests := []struct {
+		name    string
+		bucket  string
+		acl     string
+		wantErr []string
+	}{
+		{name: "invalid name", bucket: "Assets_2", wantErr: []string{`invalid bucket name "Assets_2"`}},
+		{name: "too short", bucket: "ab", wantErr: []string{`invalid bucket name "ab"`}},
+		{name: "invalid acl", bucket: "media", acl: "public-read-write", wantErr: []string{`invalid acl "public-read-write"`, "private, public-read"}},
+		{name: "taken", bucket: "assets", wantErr: []string{"409 BucketAlreadyExists"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.CreateSpacesBucket(tt.bucket, tt.acl)
+			expectError(t, resp, err, "create_spaces_bucket", tt.wantErr...)
+		})
+	}
+
+	fake.AddSpacesObject("assets", "logo.png", "image/png", []byte("png"))
+	var buckets []client.Bucket
+	resp, err = h.ListSpacesBuckets()
+	meta := decodeList(t, resp, err, "buckets", &buckets)
+	if meta.Total != 1 || buckets[0].Name != "assets" || buckets[0].CreationDate.IsZero() {
+		t.Errorf("buckets = %+v", buckets)
+	}
+
+	preview, err := h.PreviewDeleteSpacesBucket("assets")
+	if err != nil {
+		t.Fatalf("PreviewDeleteSpacesBucket: %v", err)
+	}
+	if preview.ResourceType != "spaces_bucket" || preview.Region != "nyc3" || len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "holds 1 objects") {
+		t.Errorf("preview = %+v", preview)
+	}
+	resp, err = h.DeleteSpacesBucket("assets")
+	expectError(t, resp, err, "delete_spaces_bucket", "bucket assets is not empty", "409 BucketNotEmpty")
+
+	fake.SpacesObjects.Delete("assets/logo.png")
+	preview, err = h.PreviewDeleteSpacesBucket("assets")
+	if err != nil || len(preview.Warnings) != 0 {
+		t.Errorf("empty bucket preview = %+v, %v", preview, err)
+	}
+	resp, err = h.DeleteSpacesBucket("assets")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.SpacesBuckets.Len() != 0 {
+		t.Error("bucket was not deleted")
+	}
+
+	resp, err = h.DeleteSpacesBucket("assets")
+	expectError(t, resp, err, "delete_spaces_bucket", "404 NoSuchBucket")
+}
+
+func TestSpacesObjects(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddSpacesObject("assets", "index.html", "text/html", []byte("<h1>hi</h1>"))
+
+	var object SpacesObject
+	resp, err := h.PutSpacesObject("assets", "config/app settings+prod.json", PutObjectOptions{Content: `{"debug":false}`})
+	decodeResponse(t, resp, err, &object)
+	if object.ContentType != "application/json" || object.Size != 15 || object.ETag == "" {
+		t.Errorf("put object = %+v", object)
+	}
+	stored, ok := fake.SpacesObjects.Get("assets/config/app settings+prod.json")
+	if !ok || stored.ACL != "private" || string(stored.Body) != `{"debug":false}` {
+		t.Errorf("stored object = %+v", stored)
+	}
+
+	binary := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00}
+	resp, err = h.PutSpacesObject("assets", "images/logo.png", PutObjectOptions{
+		Content:  base64.StdEncoding.EncodeToString(binary),
+		Encoding: "base64",
+		ACL:      "public-read",
+	})
+	decodeResponse(t, resp, err, &object)
+	if object.ContentType != "image/png" {
+		t.Errorf("content type = %s, want image/png", object.ContentType)
+	}
+	resp, err = h.PutSpacesObject("assets", "notes", PutObjectOptions{Content: "remember"})
+	decodeResponse(t, resp, err, &object)
+	if object.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("content type = %s, want text/plain", object.ContentType)
+	}
+
+	putErrors := []struct {
+		name    string
+		key     string
+		opts    PutObjectOptions
+		wantErr string
+	}{
+		{name: "no key", opts: PutObjectOptions{Content: "x"}, wantErr: "key is required"},
+		{name: "bad base64", key: "a", opts: PutObjectOptions{Content: "!!", Encoding: "base64"}, wantErr: "content is not valid base64"},
+		{name: "bad encoding", key: "a", opts: PutObjectOptions{Content: "x", Encoding: "hex"}, wantErr: `invalid encoding "hex"`},
+		{name: "bad acl", key: "a", opts: PutObjectOptions{Content: "x", ACL: "authenticated-read"}, wantErr: `invalid acl "authenticated-read"`},
+	}
+	for _, tt := range putErrors {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.PutSpacesObject("assets", tt.key, tt.opts)
+			expectError(t, resp, err, "put_spaces_object", tt.wantErr)
+		})
+	}
+
+	resp, err = h.HeadSpacesObject("assets", "config/app settings+prod.json")
+	decodeResponse(t, resp, err, &object)
+	if object.Size != 15 || object.ContentType != "application/json" || object.Content != nil || object.LastModified.IsZero() {
+		t.Errorf("head = %+v", object)
+	}
+	resp, err = h.HeadSpacesObject("assets", "missing.txt")
+	expectError(t, resp, err, "head_spaces_object", "object missing.txt not found in bucket assets", "404")
+
+	resp, err = h.GetSpacesObject("assets", "index.html", 0)
+	decodeResponse(t, resp, err, &object)
+	if object.Encoding != "text" || *object.Content != "<h1>hi</h1>" || object.Truncated {
+		t.Errorf("get text = %+v", object)
+	}
+	resp, err = h.GetSpacesObject("assets", "images/logo.png", 0)
+	decodeResponse(t, resp, err, &object)
+	if decoded, _ := base64.StdEncoding.DecodeString(*object.Content); object.Encoding != "base64" || !bytes.Equal(decoded, binary) {
+		t.Errorf("get binary = %+v", object)
+	}
+	resp, err = h.GetSpacesObject("assets", "index.html", 4)
+	decodeResponse(t, resp, err, &object)
+	if *object.Content != "<h1>" || object.Size != 11 || !object.Truncated {
+		t.Errorf("get truncated = %+v", object)
+	}
+
+	fake.AddSpacesObject("assets", "empty.txt", "text/plain", nil)
+	object = SpacesObject{}
+	resp, err = h.GetSpacesObject("assets", "empty.txt", 0)
+	decodeResponse(t, resp, err, &object)
+	if *object.Content != "" || object.Size != 0 || object.Truncated {
+		t.Errorf("get empty = %+v", object)
+	}
+	fake.AddSpacesObject("assets", "accent.txt", "text/plain", []byte("café"))
+	resp, err = h.GetSpacesObject("assets", "accent.txt", 4)
+	decodeResponse(t, resp, err, &object)
+	if object.Encoding != "text" || *object.Content != "caf" {
+		t.Errorf("get split character = %+v", object)
+	}
+
+	resp, err = h.GetSpacesObject("assets", "index.html", maxObjectReadBytes+1)
+	expectError(t, resp, err, "get_spaces_object", "max_bytes must be between 1 and")
+	resp, err = h.GetSpacesObject("assets", "missing.txt", 0)
+	expectError(t, resp, err, "get_spaces_object", "object missing.txt not found in bucket assets", "NoSuchKey")
+	resp, err = h.GetSpacesObject("media", "index.html", 0)
+	expectError(t, resp, err, "get_spaces_object", "NoSuchBucket")
+
+	preview, err := h.PreviewDeleteSpacesObject("assets", "index.html")
+	if err != nil {
+		t.Fatalf("PreviewDeleteSpacesObject: %v", err)
+	}
+	if preview.ID != "assets/index.html" || preview.ResourceType != "spaces_object" || !strings.Contains(preview.Warnings[0], "11 bytes of text/html") {
+		t.Errorf("preview = %+v", preview)
+	}
+	resp, err = h.DeleteSpacesObject("assets", "index.html")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if _, ok := fake.SpacesObjects.Get("assets/index.html"); ok {
+		t.Error("object was not deleted")
+	}
+	resp, err = h.DeleteSpacesObject("assets", "index.html")
+	expectError(t, resp, err, "delete_spaces_object", "object index.html not found in bucket assets")
+}
+
+func TestListSpacesObjects(t *testing.T) {
+	h, fake := newTestHandler(t)
+	for _, key := range []string{"a.txt", "docs/guide.md", "docs/intro.md", "img/1.png", "img/2.png", "img/raw/3.png", "z.txt"} {
+		fake.AddSpacesObject("assets", key, "text/plain", []byte(key))
+	}
+
+	tests := []struct {
+		name         string
+		opts         client.ListObjectsOptions
+		wantKeys     []string
+		wantPrefixes []string
+	}{
+		{name: "all", wantKeys: []string{"a.txt", "docs/guide.md", "docs/intro.md", "img/1.png", "img/2.png", "img/raw/3.png", "z.txt"}},
+		{name: "prefix", opts: client.ListObjectsOptions{Prefix: "img/"}, wantKeys: []string{"img/1.png", "img/2.png", "img/raw/3.png"}},
+		{name: "top level", opts: client.ListObjectsOptions{Delimiter: "/"}, wantKeys: []string{"a.txt", "z.txt"}, wantPrefixes: []string{"docs/", "img/"}},
+		{name: "directory", opts: client.ListObjectsOptions{Prefix: "img/", Delimiter: "/"}, wantKeys: []string{"img/1.png", "img/2.png"}, wantPrefixes: []string{"img/raw/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result client.ListObjectsResult
+			resp, err := h.ListSpacesObjects("assets", tt.opts)
+			decodeResponse(t, resp, err, &result)
+			if keys := objectKeys(result.Objects); strings.Join(keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if strings.Join(result.CommonPrefixes, ",") != strings.Join(tt.wantPrefixes, ",") {
+				t.Errorf("common prefixes = %v, want %v", result.CommonPrefixes, tt.wantPrefixes)
+			}
+			if result.Truncated {
+				t.Error("result is truncated")
+			}
+		})
+	}
+
+	// Page through the top level two entries at a time; a page can end on a
+	// common prefix
+	var pages [][]string
+	cursor := ""
+	for {
+		var result client.ListObjectsResult
+		resp, err := h.ListSpacesObjects("assets", client.ListObjectsOptions{Delimiter: "/", MaxKeys: 2, Cursor: cursor})
+		decodeResponse(t, resp, err, &result)
+		pages = append(pages, append(objectKeys(result.Objects), result.CommonPrefixes...))
+		if !result.Truncated {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if len(pages) != 2 || strings.Join(pages[0], ",") != "a.txt,docs/" || strings.Join(pages[1], ",") != "z.txt,img/" {
+		t.Errorf("pages = %v", pages)
+	}
+
+	resp, err := h.ListSpacesObjects("assets", client.ListObjectsOptions{MaxKeys: 1001})
+	expectError(t, resp, err, "list_spaces_objects", "max_keys must be between 1 and 1000")
+	resp, err = h.ListSpacesObjects("media", client.ListObjectsOptions{})
+	expectError(t, resp, err, "list_spaces_objects", "404 NoSuchBucket")
+}
+
+func TestPresignSpacesURL(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddSpacesObject("assets", "report 2024.csv", "text/csv", []byte("a,b\n1,2\n"))
+
+	var presigned struct {
+		URL       string    `json:"url"`
+		Method    string    `json:"method"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	resp, err := h.PresignSpacesURL("assets", "report 2024.csv", "", 0)
+	decodeResponse(t, resp, err, &presigned)
+	if presigned.Method != "GET" || time.Until(presigned.ExpiresAt) < 59*time.Minute {
+		t.Errorf("presigned = %+v", presigned)
+	}
+	response, err := http.Get(presigned.URL)
+	if err != nil {
+		t.Fatalf("GET presigned URL: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || string(body) != "a,b\n1,2\n" {
+		t.Errorf("presigned GET = %d %q", response.StatusCode, body)
+	}
+
+	// The signature covers the path, so the URL cannot be reused for another
+	// object
+	tampered := strings.Replace(presigned.URL, "report%202024.csv", "other.csv", 1)
+	response, err = http.Get(tampered)
+	if err != nil {
+		t.Fatalf("GET tampered URL: %v", err)
+	}
+	body, _ = io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusForbidden || !strings.Contains(string(body), "SignatureDoesNotMatch") {
+		t.Errorf("tampered GET = %d %s", response.StatusCode, body)
+	}
+
+	resp, err = h.PresignSpacesURL("assets", "uploads/new.txt", "put", 10*time.Minute)
+	decodeResponse(t, resp, err, &presigned)
+	request, _ := http.NewRequest(http.MethodPut, presigned.URL, strings.NewReader("uploaded"))
+	response, err = http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("PUT presigned URL: %v", err)
+	}
+	response.Body.Close()
+	if uploaded, ok := fake.SpacesObjects.Get("assets/uploads/new.txt"); response.StatusCode != http.StatusOK || !ok || string(uploaded.Body) != "uploaded" {
+		t.Errorf("presigned PUT = %d, stored %+v", response.StatusCode, uploaded)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		method  string
+		expires time.Duration
+		wantErr []string
+	}{
+		{name: "method", key: "report 2024.csv", method: "DELETE", wantErr: []string{`invalid method "DELETE"`}},
+		{name: "expiry", key: "report 2024.csv", expires: 8 * 24 * time.Hour, wantErr: []string{"expiry must be between 1 second and 168h0m0s"}},
+		{name: "missing object", key: "missing.csv", wantErr: []string{"object missing.csv not found in bucket assets"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.PresignSpacesURL("assets", tt.key, tt.method, tt.expires)
+			expectError(t, resp, err, "presign_spaces_url", tt.wantErr...)
+		})
+	}
+}
+
+func TestSpacesBucketCORS(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddSpacesObject("assets", "logo.png", "image/png", nil)
+
+	var config struct {
+		Rules []client.CORSRule `json:"cors_rules"`
+	}
+	resp, err := h.GetSpacesBucketCORS("assets")
+	decodeResponse(t, resp, err, &config)
+	if len(config.Rules) != 0 {
+		t.Errorf("initial rules = %+v", config.Rules)
+	}
+
+	rules := []client.CORSRule{{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"get", "HEAD"},
+		AllowedHeaders: []string{"*"},
+		MaxAgeSeconds:  3600,
+	}}
+	resp, err = h.SetSpacesBucketCORS("assets", rules)
+	decodeResponse(t, resp, err, &config)
+	if len(config.Rules) != 1 || strings.Join(config.Rules[0].AllowedMethods, ",") != "GET,HEAD" || config.Rules[0].MaxAgeSeconds != 3600 || config.Rules[0].AllowedHeaders[0] != "*" {
+		t.Errorf("rules = %+v", config.Rules)
+	}
+
+	tests := []struct {
+		name    string
+		rules   []client.CORSRule
+		wantErr string
+	}{
+		{name: "no origin", rules: []client.CORSRule{{AllowedMethods: []string{"GET"}}}, wantErr: "rule 1: allowed_origins and allowed_methods are required"},
+		{name: "bad method", rules: []client.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}}, wantErr: `rule 1: invalid method "PATCH"`},
+		{name: "negative max age", rules: []client.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, MaxAgeSeconds: -1}}, wantErr: "max_age_seconds cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.SetSpacesBucketCORS("assets", tt.rules)
+			expectError(t, resp, err, "set_spaces_bucket_cors", tt.wantErr)
+		})
+	}
+
+	resp, err = h.SetSpacesBucketCORS("assets", nil)
+	decodeResponse(t, resp, err, &config)
+	if bucket, _ := fake.SpacesBuckets.Get("assets"); len(config.Rules) != 0 || bucket.CORS != nil {
+		t.Errorf("rules after clearing = %+v", config.Rules)
+	}
+}
+
+func TestSpacesBucketLifecycle(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddSpacesObject("assets", "logo.png", "image/png", nil)
+
+	var config struct {
+		Rules []client.LifecycleRule `json:"lifecycle_rules"`
+	}
+	resp, err := h.GetSpacesBucketLifecycle("assets")
+	decodeResponse(t, resp, err, &config)
+	if len(config.Rules) != 0 {
+		t.Errorf("initial rules = %+v", config.Rules)
+	}
+
+	resp, err = h.SetSpacesBucketLifecycle("assets", []client.LifecycleRule{
+		{Prefix: "tmp/", Enabled: true, ExpirationDays: 7},
+		{ID: "uploads", Enabled: false, AbortIncompleteMultipartUploadDays: 2},
+	})
+	decodeResponse(t, resp, err, &config)
+	want := []client.LifecycleRule{
+		{ID: "rule-1", Prefix: "tmp/", Enabled: true, ExpirationDays: 7},
+		{ID: "uploads", Enabled: false, AbortIncompleteMultipartUploadDays: 2},
+	}
+	if len(config.Rules) != 2 || config.Rules[0] != want[0] || config.Rules[1] != want[1] {
+		t.Errorf("rules = %+v, want %+v", config.Rules, want)
+	}
+
+	tests := []struct {
+		name    string
+		rules   []client.LifecycleRule
+		wantErr string
+	}{
+		{name: "no action", rules: []client.LifecycleRule{{Prefix: "tmp/", Enabled: true}}, wantErr: "rule 1: set expiration_days or abort_incomplete_multipart_upload_days"},
+		{name: "negative", rules: []client.LifecycleRule{{ExpirationDays: -1}}, wantErr: "rule 1: days cannot be negative"},
+		{name: "duplicate id", rules: []client.LifecycleRule{{ID: "a", ExpirationDays: 1}, {ID: "a", ExpirationDays: 2}}, wantErr: `duplicate rule id "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.SetSpacesBucketLifecycle("assets", tt.rules)
+			expectError(t, resp, err, "set_spaces_bucket_lifecycle", tt.wantErr)
+		})
+	}
+
+	resp, err = h.SetSpacesBucketLifecycle("assets", []client.LifecycleRule{})
+	decodeResponse(t, resp, err, &config)
+	if len(config.Rules) != 0 {
+		t.Errorf("rules after clearing = %+v", config.Rules)
+	}
+	resp, err = h.GetSpacesBucketLifecycle("media")
+	expectError(t, resp, err, "get_spaces_bucket_lifecycle", "404 NoSuchBucket")
+}
+
+func TestSpacesCredentials(t *testing.T) {
+	h, fake := newTestHandler(t)
+	fake.AddSpacesObject("assets", "logo.png", "image/png", nil)
+
+	tests := []struct {
+		name      string
+		region    string
+		accessKey string
+		secretKey string
+		wantErr   string
+	}{
+		{name: "wrong secret", accessKey: fakedo.SpacesAccessKey, secretKey: "guess", wantErr: "403 SignatureDoesNotMatch"},
+		{name: "unknown key", accessKey: "DO00UNKNOWN", secretKey: "guess", wantErr: "403 InvalidAccessKeyId"},
+		{name: "wrong region", region: "ams3", accessKey: fakedo.SpacesAccessKey, secretKey: fakedo.SpacesSecretKey, wantErr: "the region 'ams3' is wrong"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spaces, err := client.NewSpacesClient(fake.SpacesURL(), tt.region, tt.accessKey, tt.secretKey)
+			if err != nil {
+				t.Fatalf("NewSpacesClient: %v", err)
+			}
+			h.SetSpacesClient(spaces)
+			resp, err := h.ListSpacesBuckets()
+			expectError(t, resp, err, "list_spaces_buckets", tt.wantErr)
+		})
+	}
+
+	// A key created through the API can sign requests
+	key := fake.AddSpacesKey("ci", &godo.Grant{Permission: godo.SpacesKeyFullAccess})
+	spaces, _ := client.NewSpacesClient(fake.SpacesURL(), "", key.AccessKey, key.SecretKey)
+	h.SetSpacesClient(spaces)
+	var buckets []client.Bucket
+	resp, err := h.ListSpacesBuckets()
+	decodeList(t, resp, err, "buckets", &buckets)
+
+	h.SetSpacesClient(nil)
+	resp, err = h.ListSpacesBuckets()
+	expectError(t, resp, err, "list_spaces_buckets", "Spaces is not configured", "SPACES_ACCESS_KEY_ID")
+	if _, err := h.PreviewDeleteSpacesObject("assets", "logo.png"); err == nil || !strings.Contains(err.Error(), "Spaces is not configured") {
+		t.Errorf("PreviewDeleteSpacesObject error = %v", err)
+	}
+
+	fake.FailNext(http.MethodGet, "/", http.StatusServiceUnavailable, "Please reduce your request rate.")
+	h.SetSpacesClient(spaces)
+	resp, err = h.ListSpacesBuckets()
+	expectError(t, resp, err, "list_spaces_buckets", "503 ServiceUnavailable: Please reduce your request rate.")
+}
+
+func objectKeys(objects []client.ObjectInfo) []string {
+	keys := []string{}
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	return keys
+}
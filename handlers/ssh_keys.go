@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// sshPublicKey is a parsed authorized_keys line.
+type sshPublicKey struct {
+	Type        string
+	Comment     string
+	Fingerprint string
+}
+
+func (h *Handler) ListSSHKeys(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	keys, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Key, *godo.Response, error) {
+		return client.Keys.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_ssh_keys")
+	}
+
+	return h.HandleSuccess(listResult("ssh_keys", keys, meta), "list_ssh_keys")
+}
+
+// GetSSHKey looks a key up by its numeric ID or its fingerprint.
+func (h *Handler) GetSSHKey(key string) (*mcp_golang.ToolResponse, error) {
+	sshKey, err := h.getSSHKey(key)
+	if err != nil {
+		return h.HandleError(err, "get_ssh_key")
+	}
+
+	return h.HandleSuccess(sshKey, "get_ssh_key")
+}
+
+// CreateSSHKey registers a public key. The key is parsed first so a
+// malformed or private key is reported before it reaches the API.
+func (h *Handler) CreateSSHKey(name, publicKey string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if _, err := parseSSHPublicKey(publicKey); err != nil {
+		return h.HandleError(err, "create_ssh_key")
+	}
+
+	sshKey, _, err := client.Keys.Create(context.Background(), &godo.KeyCreateRequest{
+		Name:      name,
+		PublicKey: strings.TrimSpace(publicKey),
+	})
+	if err != nil {
+		return h.HandleError(err, "create_ssh_key")
+	}
+
+	return h.HandleSuccess(sshKey, "create_ssh_key")
+}
+
+// UpdateSSHKey renames a key; the public key itself cannot be changed.
+func (h *Handler) UpdateSSHKey(key, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if name == "" {
+		return h.HandleError(fmt.Errorf("name cannot be empty"), "update_ssh_key")
+	}
+
+	updateRequest := &godo.KeyUpdateRequest{Name: name}
+	var sshKey *godo.Key
+	var err error
+	if id, convErr := strconv.Atoi(key); convErr == nil {
+		sshKey, _, err = client.Keys.UpdateByID(context.Background(), id, updateRequest)
+	} else {
+		sshKey, _, err = client.Keys.UpdateByFingerprint(context.Background(), key, updateRequest)
+	}
+	if err != nil {
+		return h.HandleError(err, "update_ssh_key")
+	}
+
+	return h.HandleSuccess(sshKey, "update_ssh_key")
+}
+
+func (h *Handler) DeleteSSHKey(key string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	var err error
+	if id, convErr := strconv.Atoi(key); convErr == nil {
+		_, err = client.Keys.DeleteByID(context.Background(), id)
+	} else {
+		_, err = client.Keys.DeleteByFingerprint(context.Background(), key)
+	}
+	if err != nil {
+		return h.HandleError(err, "delete_ssh_key")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("SSH key %s deleted successfully", key),
+	}, "delete_ssh_key")
+}
+
+// PreviewDeleteSSHKey names the key. Droplets keep keys that were installed
+// when they were created, so deleting only affects new droplets and rebuilds.
+func (h *Handler) PreviewDeleteSSHKey(key string) (*DeletionPreview, error) {
+	sshKey, err := h.getSSHKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletionPreview{
+		ResourceType: "ssh_key",
+		ID:           strconv.Itoa(sshKey.ID),
+		Name:         sshKey.Name,
+		Warnings: []string{
+			fmt.Sprintf("Key %s can no longer be added to new or rebuilt droplets; droplets that already have it keep it", sshKey.Fingerprint),
+		},
+	}, nil
+}
+
+// GetSSHKeyFingerprint computes the MD5 fingerprint of a public key and
+// reports whether the account has a key with that fingerprint.
+func (h *Handler) GetSSHKeyFingerprint(publicKey string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	parsed, err := parseSSHPublicKey(publicKey)
+	if err != nil {
+		return h.HandleError(err, "get_ssh_key_fingerprint")
+	}
+
+	result := map[string]interface{}{
+		"type":        parsed.Type,
+		"fingerprint": parsed.Fingerprint,
+		"registered":  false,
+	}
+	if parsed.Comment != "" {
+		result["comment"] = parsed.Comment
+	}
+	sshKey, _, err := client.Keys.GetByFingerprint(context.Background(), parsed.Fingerprint)
+	switch {
+	case err == nil:
+		result["registered"] = true
+		result["ssh_key"] = sshKey
+	case !isNotFound(err):
+		return h.HandleError(err, "get_ssh_key_fingerprint")
+	}
+
+	return h.HandleSuccess(result, "get_ssh_key_fingerprint")
+}
+
+func (h *Handler) getSSHKey(key string) (*godo.Key, error) {
+	client := h.doClient.GetClient()
+
+	if id, err := strconv.Atoi(key); err == nil {
+		sshKey, _, err := client.Keys.GetByID(context.Background(), id)
+		return sshKey, err
+	}
+	sshKey, _, err := client.Keys.GetByFingerprint(context.Background(), key)
+	return sshKey, err
+}
+
+// parseSSHPublicKey parses a public key in authorized_keys format
+// ("type base64-blob [comment]") and computes the colon-separated MD5
+// fingerprint DigitalOcean uses to identify it.
+func parseSSHPublicKey(publicKey string) (*sshPublicKey, error) {
+	publicKey = strings.TrimSpace(publicKey)
+	if publicKey == "" {
+		return nil, fmt.Errorf("public_key is required")
+	}
+	if strings.HasPrefix(publicKey, "-----BEGIN") {
+		return nil, fmt.Errorf("this looks like a private key; pass the contents of the .pub file instead")
+	}
+	if strings.ContainsAny(publicKey, "\r\n") {
+		return nil, fmt.Errorf("public_key must be a single line")
+	}
+
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("public_key must have the form \"type key [comment]\"")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("public_key data is not valid base64")
+	}
+	// The blob starts with the key type as a length-prefixed string
+	if len(blob) < 4 || int(binary.BigEndian.Uint32(blob)) > len(blob)-4 {
+		return nil, fmt.Errorf("public_key data is truncated")
+	}
+	if embedded := string(blob[4 : 4+binary.BigEndian.Uint32(blob)]); embedded != fields[0] {
+		return nil, fmt.Errorf("public_key type %q does not match its data (%q)", fields[0], embedded)
+	}
+
+	sum := md5.Sum(blob)
+	hexPairs := make([]string, len(sum))
+	for i, b := range sum {
+		hexPairs[i] = fmt.Sprintf("%02x", b)
+	}
+
+	return &sshPublicKey{
+		Type:        fields[0],
+		Comment:     strings.Join(fields[2:], " "),
+		Fingerprint: strings.Join(hexPairs, ":"),
+	}, nil
+}
+
+// isNotFound reports whether err is an API 404.
+func isNotFound(err error) bool {
+	var apiErr *godo.ErrorResponse
+	return errors.As(err, &apiErr) && apiErr.Response != nil && apiErr.Response.StatusCode == http.StatusNotFound
+}
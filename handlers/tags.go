@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// taggableResourceTypes are the resource types tag_resources and
+// untag_resources accept.
+var taggableResourceTypes = []godo.ResourceType{
+	godo.DropletResourceType,
+	godo.VolumeResourceType,
+	godo.ImageResourceType,
+	godo.DatabaseResourceType,
+	godo.LoadBalancerResourceType,
+}
+
+func (h *Handler) ListTags(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	tags, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Tag, *godo.Response, error) {
+		return client.Tags.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_tags")
+	}
+
+	return h.HandleSuccess(listResult("tags", tags, meta), "list_tags")
+}
+
+func (h *Handler) GetTag(name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	tag, _, err := client.Tags.Get(context.Background(), name)
+	if err != nil {
+		return h.HandleError(err, "get_tag")
+	}
+
+	return h.HandleSuccess(tag, "get_tag")
+}
+
+func (h *Handler) CreateTag(name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	tag, _, err := client.Tags.Create(context.Background(), &godo.TagCreateRequest{Name: name})
+	if err != nil {
+		return h.HandleError(err, "create_tag")
+	}
+
+	return h.HandleSuccess(tag, "create_tag")
+}
+
+func (h *Handler) DeleteTag(name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.Tags.Delete(context.Background(), name)
+	if err != nil {
+		return h.HandleError(err, "delete_tag")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Tag %s deleted successfully", name),
+	}, "delete_tag")
+}
+
+func (h *Handler) TagResources(name string, resources []godo.Resource) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateTagResources(resources); err != nil {
+		return h.HandleError(err, "tag_resources")
+	}
+
+	_, err := client.Tags.TagResources(context.Background(), name, &godo.TagResourcesRequest{Resources: resources})
+	if err != nil {
+		return h.HandleError(err, "tag_resources")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Tagged %d resource(s) with %s successfully", len(resources), name),
+	}, "tag_resources")
+}
+
+func (h *Handler) UntagResources(name string, resources []godo.Resource) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if err := validateTagResources(resources); err != nil {
+		return h.HandleError(err, "untag_resources")
+	}
+
+	_, err := client.Tags.UntagResources(context.Background(), name, &godo.UntagResourcesRequest{Resources: resources})
+	if err != nil {
+		return h.HandleError(err, "untag_resources")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Removed tag %s from %d resource(s) successfully", name, len(resources)),
+	}, "untag_resources")
+}
+
+// PreviewDeleteTag lists what carries the tag and what relies on it. Tagged
+// resources are kept, but firewalls and load balancers that select droplets
+// by the tag stop matching them.
+func (h *Handler) PreviewDeleteTag(name string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	tag, _, err := client.Tags.Get(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "tag",
+		ID:           tag.Name,
+		Name:         tag.Name,
+	}
+	if tag.Resources != nil {
+		counts := map[string]int{}
+		if tag.Resources.Droplets != nil {
+			counts["droplets"] = tag.Resources.Droplets.Count
+		}
+		if tag.Resources.Volumes != nil {
+			counts["volumes"] = tag.Resources.Volumes.Count
+		}
+		if tag.Resources.Images != nil {
+			counts["images"] = tag.Resources.Images.Count
+		}
+		if tag.Resources.Databases != nil {
+			counts["databases"] = tag.Resources.Databases.Count
+		}
+		for _, kind := range []string{"droplets", "volumes", "images", "databases"} {
+			if counts[kind] > 0 {
+				preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+					"type":  kind,
+					"count": counts[kind],
+				})
+			}
+		}
+		if tag.Resources.Count > 0 {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("The tag is removed from %d resource(s); the resources themselves are not deleted", tag.Resources.Count))
+		}
+	}
+
+	firewalls, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.Firewall, *godo.Response, error) {
+		return client.Firewalls.List(context.Background(), opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, firewall := range firewalls {
+		if slices.Contains(firewall.Tags, name) {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Firewall %s (%s) stops protecting droplets tagged %s", firewall.Name, firewall.ID, name))
+		}
+	}
+
+	lbs, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.LoadBalancer, *godo.Response, error) {
+		return client.LoadBalancers.List(context.Background(), opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range lbs {
+		if lb.Tag == name {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Load balancer %s (%s) stops sending traffic to droplets tagged %s", lb.Name, lb.ID, name))
+		}
+	}
+
+	return preview, nil
+}
+
+func validateTagResources(resources []godo.Resource) error {
+	if len(resources) == 0 {
+		return fmt.Errorf("at least one resource is required")
+	}
+	for _, resource := range resources {
+		if resource.ID == "" {
+			return fmt.Errorf("resource_id is required for every resource")
+		}
+		if !slices.Contains(taggableResourceTypes, resource.Type) {
+			names := make([]string, len(taggableResourceTypes))
+			for i, t := range taggableResourceTypes {
+				names[i] = string(t)
+			}
+			return fmt.Errorf("unsupported resource type %q for %s (expected one of %s)", resource.Type, resource.ID, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
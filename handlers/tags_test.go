@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+// tagDroplets creates tag and applies it to the given droplets.
+func tagDroplets(fake *fakedo.Server, tag string, ids ...int) {
+	fake.Tags.Put(tag, godo.Tag{Name: tag})
+	for _, id := range ids {
+		fake.Droplets.Update(id, func(d *godo.Droplet) { d.Tags = append(d.Tags, tag) })
+	}
+}
+
+func TestTags(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 3)
+
+	var tag godo.Tag
+	resp, err := h.CreateTag("env:prod")
+	decodeResponse(t, resp, err, &tag)
+	if tag.Name != "env:prod" {
+		t.Errorf("created tag = %+v", tag)
+	}
+
+	resp, err = h.CreateTag("not a tag")
+	expectError(t, resp, err, "create_tag", "422", "may only contain")
+
+	tagDroplets(fake, "env:prod", ids[0], ids[1])
+	resp, err = h.GetTag("env:prod")
+	decodeResponse(t, resp, err, &tag)
+	if tag.Resources == nil || tag.Resources.Count != 2 || tag.Resources.Droplets.Count != 2 {
+		t.Errorf("tag resources = %+v", tag.Resources)
+	}
+
+	fake.Tags.Put("env:dev", godo.Tag{Name: "env:dev"})
+	var tags []godo.Tag
+	resp, err = h.ListTags(0, 0)
+	decodeList(t, resp, err, "tags", &tags)
+	if len(tags) != 2 || tags[0].Resources.Count != 2 || tags[1].Resources.Count != 0 {
+		t.Errorf("listed tags = %+v", tags)
+	}
+
+	resp, err = h.DeleteTag("env:prod")
+	decodeResponse(t, resp, err, &map[string]string{})
+	if droplet, _ := fake.Droplets.Get(ids[0]); slices.Contains(droplet.Tags, "env:prod") {
+		t.Errorf("droplet still carries the deleted tag: %v", droplet.Tags)
+	}
+
+	resp, err = h.GetTag("env:prod")
+	expectError(t, resp, err, "get_tag", "404")
+}
+
+func TestTagAndUntagResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources func(dropletID int, volumeID string) []godo.Resource
+		wantErr   string
+	}{
+		{
+			name: "droplet and volume",
+			resources: func(dropletID int, volumeID string) []godo.Resource {
+				return []godo.Resource{
+					{ID: strconv.Itoa(dropletID), Type: godo.DropletResourceType},
+					{ID: volumeID, Type: godo.VolumeResourceType},
+				}
+			},
+		},
+		{
+			name:      "no resources",
+			resources: func(int, string) []godo.Resource { return nil },
+			wantErr:   "at least one resource is required",
+		},
+		{
+			name: "unsupported type",
+			resources: func(dropletID int, _ string) []godo.Resource {
+				return []godo.Resource{{ID: strconv.Itoa(dropletID), Type: "kubernetes_cluster"}}
+			},
+			wantErr: `unsupported resource type "kubernetes_cluster"`,
+		},
+		{
+			name: "missing ID",
+			resources: func(int, string) []godo.Resource {
+				return []godo.Resource{{Type: godo.DropletResourceType}}
+			},
+			wantErr: "resource_id is required",
+		},
+		{
+			name: "unknown droplet",
+			resources: func(dropletID int, _ string) []godo.Resource {
+				return []godo.Resource{{ID: strconv.Itoa(dropletID + 100), Type: godo.DropletResourceType}}
+			},
+			wantErr: "422",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := seedDroplets(fake, 1)[0]
+			volumeID := seedVolume(fake, "data", "nyc3", 10)
+			fake.Tags.Put("web", godo.Tag{Name: "web"})
+
+			resp, err := h.TagResources("web", tt.resources(dropletID, volumeID))
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "tag_resources", tt.wantErr)
+				return
+			}
+			decodeResponse(t, resp, err, &map[string]string{})
+			droplet, _ := fake.Droplets.Get(dropletID)
+			volume, _ := fake.Volumes.Get(volumeID)
+			if !slices.Contains(droplet.Tags, "web") || !slices.Contains(volume.Tags, "web") {
+				t.Fatalf("after tagging droplet tags = %v, volume tags = %v", droplet.Tags, volume.Tags)
+			}
+
+			resp, err = h.UntagResources("web", tt.resources(dropletID, volumeID)[:1])
+			decodeResponse(t, resp, err, &map[string]string{})
+			droplet, _ = fake.Droplets.Get(dropletID)
+			volume, _ = fake.Volumes.Get(volumeID)
+			if slices.Contains(droplet.Tags, "web") || !slices.Contains(volume.Tags, "web") {
+				t.Errorf("after untagging droplet tags = %v, volume tags = %v", droplet.Tags, volume.Tags)
+			}
+		})
+	}
+
+	h, _ := newTestHandler(t)
+	resp, err := h.TagResources("missing", []godo.Resource{{ID: "1", Type: godo.DropletResourceType}})
+	expectError(t, resp, err, "tag_resources", "404")
+}
+
+func TestPreviewDeleteTag(t *testing.T) {
+	h, fake := newTestHandler(t)
+	ids := seedDroplets(fake, 2)
+	tagDroplets(fake, "web", ids...)
+	fake.Firewalls.Put("fw-1", godo.Firewall{ID: "fw-1", Name: "web-fw", Tags: []string{"web"}})
+	fake.LoadBalancers.Put("lb-1", godo.LoadBalancer{ID: "lb-1", Name: "web-lb", Tag: "web"})
+
+	preview, err := h.PreviewDeleteTag("web")
+	if err != nil {
+		t.Fatalf("PreviewDeleteTag: %v", err)
+	}
+	if preview.ResourceType != "tag" || len(preview.AttachedResources) != 1 || preview.AttachedResources[0]["count"] != 2 {
+		t.Errorf("preview = %+v", preview)
+	}
+	warnings := strings.Join(preview.Warnings, "\n")
+	for _, want := range []string{"removed from 2 resource(s)", "Firewall web-fw", "Load balancer web-lb"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q do not mention %q", warnings, want)
+		}
+	}
+
+	if _, err := h.PreviewDeleteTag("missing"); err == nil {
+		t.Errorf("expected an error for a missing tag")
+	}
+}
@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+var (
+	uptimeCheckTypes   = []string{"http", "https", "ping"}
+	uptimeRegions      = []string{"us_east", "us_west", "eu_west", "se_asia"}
+	uptimeAlertTypes   = []string{"latency", "down", "down_global", "ssl_expiry"}
+	uptimeAlertPeriods = []string{"2m", "3m", "5m", "10m", "15m", "30m", "1h"}
+)
+
+// Overall status of a check in UptimeState.
+const (
+	uptimeUp             = "up"
+	uptimeRegionalOutage = "regional_outage"
+	uptimeGlobalOutage   = "global_outage"
+	uptimeUnknown        = "unknown"
+)
+
+// UptimeCheckOptions describes a new uptime check.
+type UptimeCheckOptions struct {
+	Name    string
+	Type    string
+	Target  string
+	Regions []string
+	Enabled *bool
+}
+
+// UptimeCheckUpdate holds the fields to change on an uptime check.
+type UptimeCheckUpdate struct {
+	Name    *string
+	Type    *string
+	Target  *string
+	Regions []string
+	Enabled *bool
+}
+
+// UptimeAlertOptions describes a new alert on an uptime check.
+type UptimeAlertOptions struct {
+	Name       string
+	Type       string
+	Threshold  int
+	Comparison string
+	Period     string
+	Emails     []string
+	Slack      []godo.SlackDetails
+}
+
+// UptimeAlertUpdate holds the fields to change on an uptime alert.
+type UptimeAlertUpdate struct {
+	Name       *string
+	Type       *string
+	Threshold  *int
+	Comparison *string
+	Period     *string
+	Emails     []string
+	Slack      []godo.SlackDetails
+}
+
+// UptimeState is the current state of a check, region by region, with a
+// verdict telling a regional outage from a global one.
+type UptimeState struct {
+	CheckID        string                     `json:"check_id"`
+	Name           string                     `json:"name"`
+	Target         string                     `json:"target"`
+	Enabled        bool                       `json:"enabled"`
+	Status         string                     `json:"status"`
+	Summary        string                     `json:"summary"`
+	DownRegions    []string                   `json:"down_regions"`
+	Regions        []UptimeRegionState        `json:"regions"`
+	PreviousOutage *godo.UptimePreviousOutage `json:"previous_outage,omitempty"`
+}
+
+// UptimeRegionState is what the probes in one region see.
+type UptimeRegionState struct {
+	Region                    string  `json:"region"`
+	Status                    string  `json:"status"`
+	StatusChangedAt           string  `json:"status_changed_at,omitempty"`
+	ThirtyDayUptimePercentage float32 `json:"thirty_day_uptime_percentage"`
+}
+
+func (h *Handler) ListUptimeChecks(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	checks, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.UptimeCheck, *godo.Response, error) {
+		return client.UptimeChecks.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_uptime_checks")
+	}
+
+	return h.HandleSuccess(listResult("checks", checks, meta), "list_uptime_checks")
+}
+
+func (h *Handler) GetUptimeCheck(checkID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	check, _, err := client.UptimeChecks.Get(context.Background(), checkID)
+	if err != nil {
+		return h.HandleError(err, "get_uptime_check")
+	}
+
+	return h.HandleSuccess(check, "get_uptime_check")
+}
+
+// CreateUptimeCheck starts probing a URL or host. Without regions the check
+// runs from every region, which is what lets its state tell a regional
+// outage from a global one.
+func (h *Handler) CreateUptimeCheck(opts UptimeCheckOptions) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	regions := opts.Regions
+	if len(regions) == 0 {
+		regions = uptimeRegions
+	}
+	if err := validateUptimeCheck(opts.Name, opts.Type, opts.Target, regions); err != nil {
+		return h.HandleError(err, "create_uptime_check")
+	}
+	enabled := true
+	if opts.Enabled != nil {
+		enabled = *opts.Enabled
+	}
+
+	check, _, err := client.UptimeChecks.Create(context.Background(), &godo.CreateUptimeCheckRequest{
+		Name:    opts.Name,
+		Type:    opts.Type,
+		Target:  opts.Target,
+		Regions: regions,
+		Enabled: enabled,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_uptime_check")
+	}
+
+	return h.HandleSuccess(check, "create_uptime_check")
+}
+
+// UpdateUptimeCheck changes the given fields of a check. The API replaces
+// the whole check, so the current one is read first and merged.
+func (h *Handler) UpdateUptimeCheck(checkID string, update UptimeCheckUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	current, _, err := client.UptimeChecks.Get(context.Background(), checkID)
+	if err != nil {
+		return h.HandleError(err, "update_uptime_check")
+	}
+
+	updateRequest := &godo.UpdateUptimeCheckRequest{
+		Name:    current.Name,
+		Type:    current.Type,
+		Target:  current.Target,
+		Regions: current.Regions,
+		Enabled: current.Enabled,
+	}
+	if update.Name != nil {
+		updateRequest.Name = *update.Name
+	}
+	if update.Type != nil {
+		updateRequest.Type = *update.Type
+	}
+	if update.Target != nil {
+		updateRequest.Target = *update.Target
+	}
+	if update.Regions != nil {
+		updateRequest.Regions = update.Regions
+	}
+	if update.Enabled != nil {
+		updateRequest.Enabled = *update.Enabled
+	}
+	if err := validateUptimeCheck(updateRequest.Name, updateRequest.Type, updateRequest.Target, updateRequest.Regions); err != nil {
+		return h.HandleError(err, "update_uptime_check")
+	}
+
+	check, _, err := client.UptimeChecks.Update(context.Background(), checkID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_uptime_check")
+	}
+
+	return h.HandleSuccess(check, "update_uptime_check")
+}
+
+func (h *Handler) DeleteUptimeCheck(checkID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.UptimeChecks.Delete(context.Background(), checkID)
+	if err != nil {
+		return h.HandleError(err, "delete_uptime_check")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Uptime check %s deleted successfully", checkID),
+	}, "delete_uptime_check")
+}
+
+// PreviewDeleteUptimeCheck lists the alerts deleted along with the check.
+func (h *Handler) PreviewDeleteUptimeCheck(checkID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	check, _, err := client.UptimeChecks.Get(context.Background(), checkID)
+	if err != nil {
+		return nil, err
+	}
+	alerts, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]godo.UptimeAlert, *godo.Response, error) {
+		return client.UptimeChecks.ListAlerts(context.Background(), checkID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "uptime_check",
+		ID:           check.ID,
+		Name:         check.Name,
+	}
+	for _, alert := range alerts {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "uptime_alert",
+			"id":   alert.ID,
+			"name": alert.Name,
+		})
+	}
+	if len(alerts) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d alert(s) on the check are deleted with it", len(alerts)))
+	}
+	if check.Enabled {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s stops being monitored and its uptime history is lost", check.Target))
+	}
+
+	return preview, nil
+}
+
+// GetUptimeCheckState reports what each region sees and whether the target
+// is up everywhere, down in some regions (a regional outage, often network
+// trouble between that region and the target) or down everywhere (a global
+// outage of the target itself).
+func (h *Handler) GetUptimeCheckState(checkID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	check, _, err := client.UptimeChecks.Get(context.Background(), checkID)
+	if err != nil {
+		return h.HandleError(err, "get_uptime_check_state")
+	}
+	state, _, err := client.UptimeChecks.GetState(context.Background(), checkID)
+	if err != nil {
+		return h.HandleError(err, "get_uptime_check_state")
+	}
+
+	return h.HandleSuccess(uptimeState(check, state), "get_uptime_check_state")
+}
+
+func (h *Handler) ListUptimeAlerts(checkID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	alerts, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.UptimeAlert, *godo.Response, error) {
+		return client.UptimeChecks.ListAlerts(context.Background(), checkID, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_uptime_alerts")
+	}
+
+	return h.HandleSuccess(listResult("alerts", alerts, meta), "list_uptime_alerts")
+}
+
+func (h *Handler) GetUptimeAlert(checkID, alertID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	alert, _, err := client.UptimeChecks.GetAlert(context.Background(), checkID, alertID)
+	if err != nil {
+		return h.HandleError(err, "get_uptime_alert")
+	}
+
+	return h.HandleSuccess(alert, "get_uptime_alert")
+}
+
+// CreateUptimeAlert notifies by email or Slack when a check is down, slow or
+// its certificate nears expiry. The comparison defaults to the one that
+// makes sense for the type.
+func (h *Handler) CreateUptimeAlert(checkID string, opts UptimeAlertOptions) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	comparison := opts.Comparison
+	if comparison == "" {
+		comparison = defaultUptimeComparison(opts.Type)
+	}
+	period := opts.Period
+	if period == "" {
+		period = uptimeAlertPeriods[0]
+	}
+	notifications := &godo.Notifications{Email: nonNil(opts.Emails), Slack: opts.Slack}
+	if notifications.Slack == nil {
+		notifications.Slack = []godo.SlackDetails{}
+	}
+	if err := validateUptimeAlert(opts.Name, opts.Type, opts.Threshold, comparison, period, notifications); err != nil {
+		return h.HandleError(err, "create_uptime_alert")
+	}
+
+	alert, _, err := client.UptimeChecks.CreateAlert(context.Background(), checkID, &godo.CreateUptimeAlertRequest{
+		Name:          opts.Name,
+		Type:          opts.Type,
+		Threshold:     opts.Threshold,
+		Comparison:    godo.UptimeAlertComp(comparison),
+		Notifications: notifications,
+		Period:        period,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_uptime_alert")
+	}
+
+	return h.HandleSuccess(alert, "create_uptime_alert")
+}
+
+// UpdateUptimeAlert changes the given fields of an alert. The API replaces
+// the whole alert, so the current one is read first and merged.
+func (h *Handler) UpdateUptimeAlert(checkID, alertID string, update UptimeAlertUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	current, _, err := client.UptimeChecks.GetAlert(context.Background(), checkID, alertID)
+	if err != nil {
+		return h.HandleError(err, "update_uptime_alert")
+	}
+
+	notifications := &godo.Notifications{Email: []string{}, Slack: []godo.SlackDetails{}}
+	if current.Notifications != nil {
+		*notifications = *current.Notifications
+	}
+	updateRequest := &godo.UpdateUptimeAlertRequest{
+		Name:          current.Name,
+		Type:          current.Type,
+		Threshold:     current.Threshold,
+		Comparison:    current.Comparison,
+		Notifications: notifications,
+		Period:        current.Period,
+	}
+	if update.Name != nil {
+		updateRequest.Name = *update.Name
+	}
+	if update.Type != nil {
+		updateRequest.Type = *update.Type
+	}
+	if update.Threshold != nil {
+		updateRequest.Threshold = *update.Threshold
+	}
+	if update.Comparison != nil {
+		updateRequest.Comparison = godo.UptimeAlertComp(*update.Comparison)
+	}
+	if update.Period != nil {
+		updateRequest.Period = *update.Period
+	}
+	if update.Emails != nil {
+		notifications.Email = update.Emails
+	}
+	if update.Slack != nil {
+		notifications.Slack = update.Slack
+	}
+	if err := validateUptimeAlert(updateRequest.Name, updateRequest.Type, updateRequest.Threshold, string(updateRequest.Comparison), updateRequest.Period, notifications); err != nil {
+		return h.HandleError(err, "update_uptime_alert")
+	}
+
+	alert, _, err := client.UptimeChecks.UpdateAlert(context.Background(), checkID, alertID, updateRequest)
+	if err != nil {
+		return h.HandleError(err, "update_uptime_alert")
+	}
+
+	return h.HandleSuccess(alert, "update_uptime_alert")
+}
+
+func (h *Handler) DeleteUptimeAlert(checkID, alertID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.UptimeChecks.DeleteAlert(context.Background(), checkID, alertID)
+	if err != nil {
+		return h.HandleError(err, "delete_uptime_alert")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("Uptime alert %s deleted successfully", alertID),
+	}, "delete_uptime_alert")
+}
+
+// PreviewDeleteUptimeAlert names the check that stops notifying.
+func (h *Handler) PreviewDeleteUptimeAlert(checkID, alertID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	check, _, err := client.UptimeChecks.Get(context.Background(), checkID)
+	if err != nil {
+		return nil, err
+	}
+	alert, _, err := client.UptimeChecks.GetAlert(context.Background(), checkID, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletionPreview{
+		ResourceType: "uptime_alert",
+		ID:           alert.ID,
+		Name:         alert.Name,
+		AttachedResources: []map[string]interface{}{
+			{"type": "uptime_check", "id": check.ID, "name": check.Name},
+		},
+		Warnings: []string{fmt.Sprintf("No more %s alerts are sent for %s", alert.Type, check.Target)},
+	}, nil
+}
+
+// uptimeState sorts the regions of a check's state and decides whether the
+// target is up, down in some regions or down in all of them. Regions that
+// report neither up nor down (e.g. a check that has not run yet) are left
+// out of the verdict.
+func uptimeState(check *godo.UptimeCheck, state *godo.UptimeCheckState) UptimeState {
+	result := UptimeState{
+		CheckID:     check.ID,
+		Name:        check.Name,
+		Target:      check.Target,
+		Enabled:     check.Enabled,
+		DownRegions: []string{},
+		Regions:     []UptimeRegionState{},
+	}
+
+	var up []string
+	for region, regionState := range state.Regions {
+		status := strings.ToLower(regionState.Status)
+		result.Regions = append(result.Regions, UptimeRegionState{
+			Region:                    region,
+			Status:                    status,
+			StatusChangedAt:           regionState.StatusChangedAt,
+			ThirtyDayUptimePercentage: regionState.ThirtyDayUptimePercentage,
+		})
+		switch status {
+		case "up":
+			up = append(up, region)
+		case "down":
+			result.DownRegions = append(result.DownRegions, region)
+		}
+	}
+	slices.SortFunc(result.Regions, func(a, b UptimeRegionState) int { return strings.Compare(a.Region, b.Region) })
+	slices.Sort(result.DownRegions)
+
+	down := result.DownRegions
+	switch {
+	case len(up) == 0 && len(down) == 0:
+		result.Status = uptimeUnknown
+		result.Summary = "No region has reported a result yet"
+	case len(down) == 0:
+		result.Status = uptimeUp
+		result.Summary = fmt.Sprintf("Up in all %d reporting region(s)", len(up))
+	case len(up) == 0:
+		result.Status = uptimeGlobalOutage
+		result.Summary = fmt.Sprintf("Down in every reporting region (%s): the target itself is likely down", strings.Join(down, ", "))
+	default:
+		result.Status = uptimeRegionalOutage
+		result.Summary = fmt.Sprintf("Down in %s but up in %d other region(s): likely a network problem near the failing region(s) rather than the target", strings.Join(down, ", "), len(up))
+	}
+	if !check.Enabled {
+		result.Summary += "; the check is disabled, so this may be stale"
+	}
+	if state.PreviousOutage.StartedAt != "" {
+		outage := state.PreviousOutage
+		result.PreviousOutage = &outage
+	}
+	return result
+}
+
+func validateUptimeCheck(name, checkType, target string, regions []string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("name is required")
+	case !slices.Contains(uptimeCheckTypes, checkType):
+		return fmt.Errorf("invalid check type %q; try: %s", checkType, strings.Join(suggest(checkType, uptimeCheckTypes), ", "))
+	case target == "":
+		return fmt.Errorf("target is required")
+	case checkType == "ping" && strings.Contains(target, "://"):
+		return fmt.Errorf("ping checks take a host name or IP address, not a URL: %s", target)
+	case checkType != "ping" && !strings.HasPrefix(target, checkType+"://"):
+		return fmt.Errorf("%s checks take a %s:// URL, got %s", checkType, checkType, target)
+	case len(regions) == 0:
+		return fmt.Errorf("at least one region is required: %s", strings.Join(uptimeRegions, ", "))
+	}
+	for _, region := range regions {
+		if !slices.Contains(uptimeRegions, region) {
+			return fmt.Errorf("invalid region %q; try: %s", region, strings.Join(suggest(region, uptimeRegions), ", "))
+		}
+	}
+	return nil
+}
+
+func validateUptimeAlert(name, alertType string, threshold int, comparison, period string, notifications *godo.Notifications) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("name is required")
+	case !slices.Contains(uptimeAlertTypes, alertType):
+		return fmt.Errorf("invalid alert type %q; try: %s", alertType, strings.Join(suggest(alertType, uptimeAlertTypes), ", "))
+	case (alertType == "latency" || alertType == "ssl_expiry") && threshold <= 0:
+		return fmt.Errorf("%s alerts need a threshold (milliseconds for latency, days for ssl_expiry)", alertType)
+	case comparison != string(godo.UptimeAlertGreaterThan) && comparison != string(godo.UptimeAlertLessThan):
+		return fmt.Errorf("invalid comparison %q: expected greater_than or less_than", comparison)
+	case !slices.Contains(uptimeAlertPeriods, period):
+		return fmt.Errorf("invalid period %q: expected one of %s", period, strings.Join(uptimeAlertPeriods, ", "))
+	case len(notifications.Email) == 0 && len(notifications.Slack) == 0:
+		return fmt.Errorf("at least one email or Slack destination is required")
+	}
+	return nil
+}
+
+// defaultUptimeComparison alerts when a certificate has fewer than threshold
+// days left and otherwise when a value goes above the threshold.
+func defaultUptimeComparison(alertType string) string {
+	if alertType == "ssl_expiry" {
+		return string(godo.UptimeAlertLessThan)
+	}
+	return string(godo.UptimeAlertGreaterThan)
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestCreateUptimeCheck(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name        string
+		opts        UptimeCheckOptions
+		wantRegions []string
+		wantEnabled bool
+		wantErr     []string
+	}{
+		{name: "all regions by default", opts: UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"}, wantRegions: uptimeRegions, wantEnabled: true},
+		{name: "ping in two regions", opts: UptimeCheckOptions{Name: "host", Type: "ping", Target: "203.0.113.10", Regions: []string{"eu_west", "se_asia"}, Enabled: &disabled}, wantRegions: []string{"eu_west", "se_asia"}},
+		{name: "no name", opts: UptimeCheckOptions{Type: "https", Target: "https://example.com"}, wantErr: []string{"name is required"}},
+		{name: "type typo", opts: UptimeCheckOptions{Name: "web", Type: "htps", Target: "https://example.com"}, wantErr: []string{`invalid check type "htps"`, "try: http"}},
+		{name: "scheme does not match type", opts: UptimeCheckOptions{Name: "web", Type: "https", Target: "http://example.com"}, wantErr: []string{"https checks take a https:// URL"}},
+		{name: "ping a URL", opts: UptimeCheckOptions{Name: "web", Type: "ping", Target: "https://example.com"}, wantErr: []string{"ping checks take a host name or IP address"}},
+		{name: "region typo", opts: UptimeCheckOptions{Name: "web", Type: "http", Target: "http://example.com", Regions: []string{"eu-west"}}, wantErr: []string{`invalid region "eu-west"`, "try: eu_west"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			resp, err := h.CreateUptimeCheck(tt.opts)
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_uptime_check", tt.wantErr...)
+				if fake.UptimeChecks.Len() != 0 {
+					t.Error("check was created")
+				}
+				return
+			}
+
+			var check godo.UptimeCheck
+			decodeResponse(t, resp, err, &check)
+			if !slices.Equal(check.Regions, tt.wantRegions) || check.Enabled != tt.wantEnabled || check.Target != tt.opts.Target {
+				t.Errorf("check = %+v", check)
+			}
+		})
+	}
+}
+
+func TestUpdateUptimeCheck(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var check godo.UptimeCheck
+	resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"})
+	decodeResponse(t, resp, err, &check)
+
+	name := "api"
+	target := "https://api.example.com/health"
+	httpType := "http"
+	disabled := false
+	steps := []struct {
+		name    string
+		update  UptimeCheckUpdate
+		wantErr string
+		check   func(c godo.UptimeCheck) bool
+	}{
+		{
+			name:   "name and target",
+			update: UptimeCheckUpdate{Name: &name, Target: &target},
+			check: func(c godo.UptimeCheck) bool {
+				return c.Name == name && c.Target == target && c.Type == "https" && len(c.Regions) == 4 && c.Enabled
+			},
+		},
+		{name: "type without matching target", update: UptimeCheckUpdate{Type: &httpType}, wantErr: "http checks take a http:// URL"},
+		{name: "unknown region", update: UptimeCheckUpdate{Regions: []string{"us_central"}}, wantErr: `invalid region "us_central"`},
+		{
+			name:   "regions and disable",
+			update: UptimeCheckUpdate{Regions: []string{"us_east"}, Enabled: &disabled},
+			check: func(c godo.UptimeCheck) bool {
+				return slices.Equal(c.Regions, []string{"us_east"}) && !c.Enabled && c.Name == name
+			},
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			resp, err := h.UpdateUptimeCheck(check.ID, step.update)
+			if step.wantErr != "" {
+				expectError(t, resp, err, "update_uptime_check", step.wantErr)
+				return
+			}
+			var updated godo.UptimeCheck
+			decodeResponse(t, resp, err, &updated)
+			if !step.check(updated) {
+				t.Errorf("check = %+v", updated)
+			}
+		})
+	}
+
+	if state, _ := fake.UptimeStates.Get(check.ID); len(state.Regions) != 1 {
+		t.Errorf("state regions = %v, want only us_east", state.Regions)
+	}
+	resp, err = h.UpdateUptimeCheck("uptime-missing", UptimeCheckUpdate{Name: &name})
+	expectError(t, resp, err, "update_uptime_check", "404")
+}
+
+func TestGetUptimeCheckState(t *testing.T) {
+	tests := []struct {
+		name        string
+		down        []string
+		unknown     []string
+		wantStatus  string
+		wantDown    []string
+		wantSummary string
+	}{
+		{name: "up everywhere", wantStatus: "up", wantDown: []string{}, wantSummary: "Up in all 4 reporting region(s)"},
+		{name: "one region down", down: []string{"se_asia"}, wantStatus: "regional_outage", wantDown: []string{"se_asia"}, wantSummary: "Down in se_asia but up in 3 other region(s)"},
+		{name: "two regions down", down: []string{"us_west", "eu_west"}, wantStatus: "regional_outage", wantDown: []string{"eu_west", "us_west"}, wantSummary: "Down in eu_west, us_west but up in 2"},
+		{name: "down everywhere", down: uptimeRegions, wantStatus: "global_outage", wantDown: []string{"eu_west", "se_asia", "us_east", "us_west"}, wantSummary: "Down in every reporting region (eu_west, se_asia, us_east, us_west)"},
+		{name: "down where reported", down: []string{"us_east"}, unknown: []string{"us_west", "eu_west", "se_asia"}, wantStatus: "global_outage", wantDown: []string{"us_east"}, wantSummary: "Down in every reporting region (us_east)"},
+		{name: "not run yet", unknown: uptimeRegions, wantStatus: "unknown", wantDown: []string{}, wantSummary: "No region has reported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			var check godo.UptimeCheck
+			resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"})
+			decodeResponse(t, resp, err, &check)
+			for _, region := range tt.down {
+				fake.SetUptimeRegionStatus(check.ID, region, "DOWN")
+			}
+			for _, region := range tt.unknown {
+				fake.SetUptimeRegionStatus(check.ID, region, "UNKNOWN")
+			}
+
+			var state UptimeState
+			resp, err = h.GetUptimeCheckState(check.ID)
+			decodeResponse(t, resp, err, &state)
+			if state.Status != tt.wantStatus || !slices.Equal(state.DownRegions, tt.wantDown) {
+				t.Errorf("status = %s with down regions %v, want %s with %v", state.Status, state.DownRegions, tt.wantStatus, tt.wantDown)
+			}
+			if !strings.Contains(state.Summary, tt.wantSummary) {
+				t.Errorf("summary = %q, want it to contain %q", state.Summary, tt.wantSummary)
+			}
+			if len(state.Regions) != 4 || state.Regions[0].Region != "eu_west" || state.Regions[3].Region != "us_west" {
+				t.Errorf("regions = %+v, want all four sorted", state.Regions)
+			}
+		})
+	}
+}
+
+func TestGetUptimeCheckStateDetails(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	disabled := false
+	var check godo.UptimeCheck
+	resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "http", Target: "http://example.com", Regions: []string{"us_east"}, Enabled: &disabled})
+	decodeResponse(t, resp, err, &check)
+	fake.UptimeStates.Update(check.ID, func(state *godo.UptimeCheckState) {
+		state.PreviousOutage = godo.UptimePreviousOutage{Region: "us_east", StartedAt: "2024-05-01T10:00:00Z", EndedAt: "2024-05-01T10:05:00Z", DurationSeconds: 300}
+	})
+
+	var state UptimeState
+	resp, err = h.GetUptimeCheckState(check.ID)
+	decodeResponse(t, resp, err, &state)
+	if state.PreviousOutage == nil || state.PreviousOutage.DurationSeconds != 300 {
+		t.Errorf("previous outage = %+v", state.PreviousOutage)
+	}
+	if !strings.Contains(state.Summary, "the check is disabled") || state.Enabled {
+		t.Errorf("summary = %q, want the disabled check called out", state.Summary)
+	}
+	if state.Regions[0].Status != "up" || state.Regions[0].ThirtyDayUptimePercentage != 100 {
+		t.Errorf("region = %+v", state.Regions[0])
+	}
+
+	resp, err = h.GetUptimeCheckState("uptime-missing")
+	expectError(t, resp, err, "get_uptime_check_state", "404")
+}
+
+func TestCreateUptimeAlert(t *testing.T) {
+	valid := UptimeAlertOptions{Name: "slow", Type: "latency", Threshold: 500, Emails: []string{"ops@example.com"}}
+	tests := []struct {
+		name           string
+		modify         func(*UptimeAlertOptions)
+		wantComparison godo.UptimeAlertComp
+		wantErr        []string
+	}{
+		{name: "latency defaults", modify: func(o *UptimeAlertOptions) {}, wantComparison: godo.UptimeAlertGreaterThan},
+		{name: "certificate expiry", modify: func(o *UptimeAlertOptions) { o.Type = "ssl_expiry"; o.Threshold = 14 }, wantComparison: godo.UptimeAlertLessThan},
+		{name: "down without threshold", modify: func(o *UptimeAlertOptions) { o.Type = "down"; o.Threshold = 0 }, wantComparison: godo.UptimeAlertGreaterThan},
+		{name: "slack only", modify: func(o *UptimeAlertOptions) {
+			o.Emails = nil
+			o.Slack = []godo.SlackDetails{{URL: "https://hooks.slack.com/services/T0/B0/x", Channel: "#ops"}}
+		}, wantComparison: godo.UptimeAlertGreaterThan},
+		{name: "type typo", modify: func(o *UptimeAlertOptions) { o.Type = "down-global" }, wantErr: []string{`invalid alert type "down-global"`, "try: down_global"}},
+		{name: "latency without threshold", modify: func(o *UptimeAlertOptions) { o.Threshold = 0 }, wantErr: []string{"latency alerts need a threshold"}},
+		{name: "comparison", modify: func(o *UptimeAlertOptions) { o.Comparison = ">" }, wantErr: []string{`invalid comparison ">"`}},
+		{name: "period", modify: func(o *UptimeAlertOptions) { o.Period = "1m" }, wantErr: []string{`invalid period "1m"`, "2m, 3m, 5m"}},
+		{name: "no destination", modify: func(o *UptimeAlertOptions) { o.Emails = nil }, wantErr: []string{"at least one email or Slack destination"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			var check godo.UptimeCheck
+			resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"})
+			decodeResponse(t, resp, err, &check)
+			opts := valid
+			tt.modify(&opts)
+
+			resp, err = h.CreateUptimeAlert(check.ID, opts)
+			if tt.wantErr != nil {
+				expectError(t, resp, err, "create_uptime_alert", tt.wantErr...)
+				if fake.UptimeAlerts.Len() != 0 {
+					t.Error("alert was created")
+				}
+				return
+			}
+
+			var alert godo.UptimeAlert
+			decodeResponse(t, resp, err, &alert)
+			if alert.Comparison != tt.wantComparison || alert.Period != "2m" || alert.Notifications == nil {
+				t.Errorf("alert = %+v", alert)
+			}
+		})
+	}
+
+	h, _ := newTestHandler(t)
+	resp, err := h.CreateUptimeAlert("uptime-missing", valid)
+	expectError(t, resp, err, "create_uptime_alert", "404")
+}
+
+func TestUptimeAlertCRUD(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var check godo.UptimeCheck
+	resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"})
+	decodeResponse(t, resp, err, &check)
+	var alert godo.UptimeAlert
+	resp, err = h.CreateUptimeAlert(check.ID, UptimeAlertOptions{Name: "down", Type: "down_global", Emails: []string{"ops@example.com"}})
+	decodeResponse(t, resp, err, &alert)
+
+	var alerts []godo.UptimeAlert
+	resp, err = h.ListUptimeAlerts(check.ID, 0, 0)
+	meta := decodeList(t, resp, err, "alerts", &alerts)
+	if meta.Total != 1 || alerts[0].ID != alert.ID {
+		t.Errorf("alerts = %+v", alerts)
+	}
+
+	var got godo.UptimeAlert
+	resp, err = h.GetUptimeAlert(check.ID, alert.ID)
+	decodeResponse(t, resp, err, &got)
+	if got.Name != "down" {
+		t.Errorf("alert = %+v", got)
+	}
+
+	period := "10m"
+	slack := []godo.SlackDetails{{URL: "https://hooks.slack.com/services/T0/B0/x", Channel: "#ops"}}
+	var updated godo.UptimeAlert
+	resp, err = h.UpdateUptimeAlert(check.ID, alert.ID, UptimeAlertUpdate{Period: &period, Slack: slack})
+	decodeResponse(t, resp, err, &updated)
+	if updated.Period != "10m" || updated.Type != "down_global" || len(updated.Notifications.Email) != 1 || len(updated.Notifications.Slack) != 1 {
+		t.Errorf("updated alert = %+v", updated)
+	}
+	latency := "latency"
+	resp, err = h.UpdateUptimeAlert(check.ID, alert.ID, UptimeAlertUpdate{Type: &latency})
+	expectError(t, resp, err, "update_uptime_alert", "latency alerts need a threshold")
+
+	preview, err := h.PreviewDeleteUptimeAlert(check.ID, alert.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteUptimeAlert: %v", err)
+	}
+	if preview.ResourceType != "uptime_alert" || preview.AttachedResources[0]["id"] != check.ID || len(preview.Warnings) != 1 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	resp, err = h.DeleteUptimeAlert(check.ID, alert.ID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.UptimeAlerts.Len() != 0 {
+		t.Error("alert was not deleted")
+	}
+	resp, err = h.GetUptimeAlert(check.ID, alert.ID)
+	expectError(t, resp, err, "get_uptime_alert", "404")
+}
+
+func TestUptimeCheckListAndDelete(t *testing.T) {
+	h, fake := newTestHandler(t)
+
+	var check godo.UptimeCheck
+	resp, err := h.CreateUptimeCheck(UptimeCheckOptions{Name: "web", Type: "https", Target: "https://example.com"})
+	decodeResponse(t, resp, err, &check)
+	for _, name := range []string{"down", "slow"} {
+		resp, err = h.CreateUptimeAlert(check.ID, UptimeAlertOptions{Name: name, Type: "latency", Threshold: 800, Emails: []string{"ops@example.com"}})
+		decodeResponse(t, resp, err, &godo.UptimeAlert{})
+	}
+
+	var checks []godo.UptimeCheck
+	resp, err = h.ListUptimeChecks(0, 0)
+	meta := decodeList(t, resp, err, "checks", &checks)
+	if meta.Total != 1 || checks[0].ID != check.ID {
+		t.Errorf("checks = %+v", checks)
+	}
+
+	var got godo.UptimeCheck
+	resp, err = h.GetUptimeCheck(check.ID)
+	decodeResponse(t, resp, err, &got)
+	if got.Name != "web" {
+		t.Errorf("check = %+v", got)
+	}
+
+	preview, err := h.PreviewDeleteUptimeCheck(check.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteUptimeCheck: %v", err)
+	}
+	if preview.ResourceType != "uptime_check" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 2 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	resp, err = h.DeleteUptimeCheck(check.ID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if fake.UptimeChecks.Len() != 0 || fake.UptimeAlerts.Len() != 0 {
+		t.Error("check and its alerts were not deleted")
+	}
+	resp, err = h.DeleteUptimeCheck(check.ID)
+	expectError(t, resp, err, "delete_uptime_check", "404")
+}
@@ -3,25 +3,26 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
-func (h *Handler) ListVolumes(region string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ListVolumes(region string, page, perPage int) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
-	listOptions := &godo.ListVolumeParams{}
-	if region != "" {
-		listOptions.Region = region
-	}
-	
-	volumes, _, err := client.Storage.ListVolumes(context.Background(), listOptions)
+	volumes, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]godo.Volume, *godo.Response, error) {
+		return client.Storage.ListVolumes(context.Background(), &godo.ListVolumeParams{
+			Region:      region,
+			ListOptions: opt,
+		})
+	})
 	if err != nil {
 		return h.HandleError(err, "list_volumes")
 	}
 
-	return h.HandleSuccess(volumes, "list_volumes")
+	return h.HandleSuccess(listResult("volumes", volumes, meta), "list_volumes")
 }
 
 func (h *Handler) GetVolume(volumeID string) (*mcp_golang.ToolResponse, error) {
@@ -35,9 +36,16 @@ func (h *Handler) GetVolume(volumeID string) (*mcp_golang.ToolResponse, error) {
 	return h.HandleSuccess(volume, "get_volume")
 }
 
-func (h *Handler) CreateVolume(name, region string, sizeGigaBytes int64, description string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) CreateVolume(name, region string, sizeGigaBytes int64, description, projectID string) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
+	if err := h.validateVolumeRegion(region); err != nil {
+		return h.HandleError(err, "create_volume")
+	}
+	if err := h.checkProject(projectID); err != nil {
+		return h.HandleError(err, "create_volume")
+	}
+
 	createRequest := &godo.VolumeCreateRequest{
 		Name:          name,
 		Region:        region,
@@ -49,6 +57,9 @@ func (h *Handler) CreateVolume(name, region string, sizeGigaBytes int64, descrip
 	if err != nil {
 		return h.HandleError(err, "create_volume")
 	}
+	if err := h.assignToProject(projectID, volume); err != nil {
+		return h.HandleError(err, "create_volume")
+	}
 
 	return h.HandleSuccess(volume, "create_volume")
 }
@@ -67,7 +78,7 @@ func (h *Handler) DeleteVolume(volumeID string) (*mcp_golang.ToolResponse, error
 	}, "delete_volume")
 }
 
-func (h *Handler) AttachVolume(volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) AttachVolume(volumeID string, dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	action, _, err := client.StorageActions.Attach(context.Background(), volumeID, dropletID)
@@ -75,10 +86,10 @@ func (h *Handler) AttachVolume(volumeID string, dropletID int) (*mcp_golang.Tool
 		return h.HandleError(err, "attach_volume")
 	}
 
-	return h.HandleSuccess(action, "attach_volume")
+	return h.actionResult(action, wait, "attach_volume")
 }
 
-func (h *Handler) DetachVolume(volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) DetachVolume(volumeID string, dropletID int, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	action, _, err := client.StorageActions.DetachByDropletID(context.Background(), volumeID, dropletID)
@@ -86,10 +97,10 @@ func (h *Handler) DetachVolume(volumeID string, dropletID int) (*mcp_golang.Tool
 		return h.HandleError(err, "detach_volume")
 	}
 
-	return h.HandleSuccess(action, "detach_volume")
+	return h.actionResult(action, wait, "detach_volume")
 }
 
-func (h *Handler) ResizeVolume(volumeID string, sizeGigaBytes int64, region string) (*mcp_golang.ToolResponse, error) {
+func (h *Handler) ResizeVolume(volumeID string, sizeGigaBytes int64, region string, wait time.Duration) (*mcp_golang.ToolResponse, error) {
 	client := h.doClient.GetClient()
 	
 	action, _, err := client.StorageActions.Resize(context.Background(), volumeID, int(sizeGigaBytes), region)
@@ -97,7 +108,7 @@ func (h *Handler) ResizeVolume(volumeID string, sizeGigaBytes int64, region stri
 		return h.HandleError(err, "resize_volume")
 	}
 
-	return h.HandleSuccess(action, "resize_volume")
+	return h.actionResult(action, wait, "resize_volume")
 }
 
 func (h *Handler) CreateVolumeSnapshot(volumeID, name, description string) (*mcp_golang.ToolResponse, error) {
@@ -115,4 +126,35 @@ func (h *Handler) CreateVolumeSnapshot(volumeID, name, description string) (*mcp
 	}
 
 	return h.HandleSuccess(snapshot, "create_volume_snapshot")
-}
\ No newline at end of file
+}
+
+func (h *Handler) PreviewDeleteVolume(volumeID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	volume, _, err := client.Storage.GetVolume(context.Background(), volumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType:         "volume",
+		ID:                   volume.ID,
+		Name:                 volume.Name,
+		EstimatedMonthlyCost: float64(volume.SizeGigaBytes) * volumePricePerGB,
+	}
+	if volume.Region != nil {
+		preview.Region = volume.Region.Slug
+	}
+
+	for _, dropletID := range volume.DropletIDs {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "droplet",
+			"id":   dropletID,
+		})
+	}
+	if len(volume.DropletIDs) > 0 {
+		preview.Warnings = append(preview.Warnings, "The volume is attached to a droplet and must be detached before it can be deleted")
+	}
+
+	return preview, nil
+}
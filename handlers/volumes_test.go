@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"digitalocean-mcp-server/internal/fakedo"
+	"net/http"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func seedVolume(fake *fakedo.Server, name, region string, size int64, dropletIDs ...int) string {
+	id := fake.NextUUID("vol")
+	if dropletIDs == nil {
+		dropletIDs = []int{}
+	}
+	fake.Volumes.Put(id, godo.Volume{
+		ID:            id,
+		Name:          name,
+		Region:        &godo.Region{Slug: region},
+		SizeGigaBytes: size,
+		DropletIDs:    dropletIDs,
+	})
+	return id
+}
+
+func TestListVolumes(t *testing.T) {
+	h, fake := newTestHandler(t)
+	seedVolume(fake, "data-nyc", "nyc3", 10)
+	seedVolume(fake, "data-ams", "ams3", 20)
+	seedVolume(fake, "logs-nyc", "nyc3", 30)
+
+	tests := []struct {
+		name      string
+		region    string
+		wantNames []string
+	}{
+		{name: "all regions", wantNames: []string{"data-nyc", "data-ams", "logs-nyc"}},
+		{name: "filtered by region", region: "nyc3", wantNames: []string{"data-nyc", "logs-nyc"}},
+		{name: "region without volumes", region: "sfo3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var volumes []godo.Volume
+			resp, err := h.ListVolumes(tt.region, 0, 0)
+			decodeList(t, resp, err, "volumes", &volumes)
+
+			if len(volumes) != len(tt.wantNames) {
+				t.Fatalf("got %d volumes, want %d", len(volumes), len(tt.wantNames))
+			}
+			for i, volume := range volumes {
+				if volume.Name != tt.wantNames[i] {
+					t.Errorf("volume %d = %q, want %q", i, volume.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetVolume(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedVolume(fake, "data", "nyc3", 10)
+
+	var volume godo.Volume
+	resp, err := h.GetVolume(id)
+	decodeResponse(t, resp, err, &volume)
+	if volume.ID != id || volume.SizeGigaBytes != 10 {
+		t.Errorf("got volume %+v", volume)
+	}
+
+	resp, err = h.GetVolume("vol-missing")
+	expectError(t, resp, err, "get_volume", "404")
+}
+
+func TestCreateVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr string
+	}{
+		{name: "created", size: 100},
+		{name: "missing size", size: 0, wantErr: "size_gigabytes are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+
+			resp, err := h.CreateVolume("data", "nyc3", tt.size, "app data", "")
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_volume", "422", tt.wantErr)
+				return
+			}
+
+			var volume godo.Volume
+			decodeResponse(t, resp, err, &volume)
+			stored, ok := fake.Volumes.Get(volume.ID)
+			if !ok || stored.Description != "app data" || stored.SizeGigaBytes != tt.size {
+				t.Errorf("stored volume = %+v", stored)
+			}
+		})
+	}
+}
+
+func TestDeleteVolume(t *testing.T) {
+	h, fake := newTestHandler(t)
+	droplet := seedDroplets(fake, 1)[0]
+	detached := seedVolume(fake, "free", "nyc3", 10)
+	attached := seedVolume(fake, "busy", "nyc3", 10, droplet)
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr string
+	}{
+		{name: "detached volume", id: detached},
+		{name: "attached volume", id: attached, wantErr: "409"},
+		{name: "missing volume", id: "vol-missing", wantErr: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.DeleteVolume(tt.id)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "delete_volume", tt.wantErr)
+				return
+			}
+			var result map[string]string
+			decodeResponse(t, resp, err, &result)
+			if _, ok := fake.Volumes.Get(tt.id); ok {
+				t.Errorf("volume still exists after delete")
+			}
+		})
+	}
+}
+
+func TestVolumeActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      string
+		run     func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error)
+		check   func(t *testing.T, volume godo.Volume, dropletID int)
+		wantErr string
+	}{
+		{
+			name: "attach",
+			op:   "attach_volume",
+			run: func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+				return h.AttachVolume(volumeID, dropletID, 0)
+			},
+			check: func(t *testing.T, volume godo.Volume, dropletID int) {
+				if len(volume.DropletIDs) != 1 || volume.DropletIDs[0] != dropletID {
+					t.Errorf("droplet_ids = %v", volume.DropletIDs)
+				}
+			},
+		},
+		{
+			name: "attach to missing droplet",
+			op:   "attach_volume",
+			run: func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+				return h.AttachVolume(volumeID, 1, 0)
+			},
+			wantErr: "404",
+		},
+		{
+			name: "detach when not attached",
+			op:   "detach_volume",
+			run: func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+				return h.DetachVolume(volumeID, dropletID, 0)
+			},
+			wantErr: "not attached",
+		},
+		{
+			name: "grow",
+			op:   "resize_volume",
+			run: func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+				return h.ResizeVolume(volumeID, 50, "nyc3", 0)
+			},
+			check: func(t *testing.T, volume godo.Volume, dropletID int) {
+				if volume.SizeGigaBytes != 50 {
+					t.Errorf("size = %d, want 50", volume.SizeGigaBytes)
+				}
+			},
+		},
+		{
+			name: "shrink",
+			op:   "resize_volume",
+			run: func(h *Handler, volumeID string, dropletID int) (*mcp_golang.ToolResponse, error) {
+				return h.ResizeVolume(volumeID, 5, "nyc3", 0)
+			},
+			wantErr: "larger size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			dropletID := seedDroplets(fake, 1)[0]
+			volumeID := seedVolume(fake, "data", "nyc3", 10)
+
+			resp, err := tt.run(h, volumeID, dropletID)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, tt.op, tt.wantErr)
+				return
+			}
+			var action godo.Action
+			decodeResponse(t, resp, err, &action)
+			if action.Status != "completed" {
+				t.Errorf("action status = %q", action.Status)
+			}
+			volume, _ := fake.Volumes.Get(volumeID)
+			tt.check(t, volume, dropletID)
+		})
+	}
+}
+
+func TestCreateVolumeSnapshot(t *testing.T) {
+	h, fake := newTestHandler(t)
+	id := seedVolume(fake, "data", "nyc3", 40)
+
+	var snapshot godo.Snapshot
+	resp, err := h.CreateVolumeSnapshot(id, "nightly", "")
+	decodeResponse(t, resp, err, &snapshot)
+	if snapshot.ResourceID != id || snapshot.ResourceType != "volume" || snapshot.Name != "nightly" {
+		t.Errorf("got snapshot %+v", snapshot)
+	}
+
+	fake.FailNext("POST", "/v2/volumes/"+id+"/snapshots", http.StatusInternalServerError, "snapshot backend unavailable")
+	resp, err = h.CreateVolumeSnapshot(id, "nightly", "")
+	expectError(t, resp, err, "create_volume_snapshot", "500", "snapshot backend unavailable")
+}
+
+func TestPreviewDeleteVolume(t *testing.T) {
+	h, fake := newTestHandler(t)
+	droplet := seedDroplets(fake, 1)[0]
+	id := seedVolume(fake, "data", "nyc3", 100, droplet)
+
+	preview, err := h.PreviewDeleteVolume(id)
+	if err != nil {
+		t.Fatalf("PreviewDeleteVolume: %v", err)
+	}
+	if preview.EstimatedMonthlyCost != 100*volumePricePerGB || preview.Region != "nyc3" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if len(preview.AttachedResources) != 1 || len(preview.Warnings) != 1 {
+		t.Errorf("expected the attached droplet and a warning, got %+v", preview)
+	}
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+// vpcMemberTypes are the resource types list_vpc_members can filter on.
+var vpcMemberTypes = []string{"droplet", "load_balancer", "kubernetes"}
+
+// VPCUpdate holds the VPC fields to change. Nil pointers keep the current
+// value; a region's default VPC can only be changed by promoting another one.
+type VPCUpdate struct {
+	Name        *string
+	Description *string
+	MakeDefault bool
+}
+
+func (h *Handler) ListVPCs(page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	vpcs, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.VPC, *godo.Response, error) {
+		return client.VPCs.List(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_vpcs")
+	}
+
+	return h.HandleSuccess(listResult("vpcs", vpcs, meta), "list_vpcs")
+}
+
+func (h *Handler) GetVPC(vpcID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	vpc, _, err := client.VPCs.Get(context.Background(), vpcID)
+	if err != nil {
+		return h.HandleError(err, "get_vpc")
+	}
+
+	return h.HandleSuccess(vpc, "get_vpc")
+}
+
+func (h *Handler) CreateVPC(name, region, description, ipRange string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if ipRange != "" {
+		if err := validateVPCIPRange(ipRange); err != nil {
+			return h.HandleError(err, "create_vpc")
+		}
+	}
+
+	createRequest := &godo.VPCCreateRequest{
+		Name:        name,
+		RegionSlug:  region,
+		Description: description,
+		IPRange:     ipRange,
+	}
+
+	vpc, _, err := client.VPCs.Create(context.Background(), createRequest)
+	if err != nil {
+		return h.HandleError(err, "create_vpc")
+	}
+
+	return h.HandleSuccess(vpc, "create_vpc")
+}
+
+func (h *Handler) UpdateVPC(vpcID string, update VPCUpdate) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	// Set sends only the given fields, so unchanged ones keep their value
+	var fields []godo.VPCSetField
+	if update.Name != nil {
+		if *update.Name == "" {
+			return h.HandleError(fmt.Errorf("name cannot be empty"), "update_vpc")
+		}
+		fields = append(fields, godo.VPCSetName(*update.Name))
+	}
+	if update.Description != nil {
+		fields = append(fields, godo.VPCSetDescription(*update.Description))
+	}
+	if update.MakeDefault {
+		fields = append(fields, godo.VPCSetDefault())
+	}
+	if len(fields) == 0 {
+		return h.HandleError(fmt.Errorf("nothing to update: set name, description or make_default"), "update_vpc")
+	}
+
+	vpc, _, err := client.VPCs.Set(context.Background(), vpcID, fields...)
+	if err != nil {
+		return h.HandleError(err, "update_vpc")
+	}
+
+	return h.HandleSuccess(vpc, "update_vpc")
+}
+
+func (h *Handler) DeleteVPC(vpcID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.VPCs.Delete(context.Background(), vpcID)
+	if err != nil {
+		return h.HandleError(err, "delete_vpc")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("VPC %s deleted successfully", vpcID),
+	}, "delete_vpc")
+}
+
+// PreviewDeleteVPC lists the VPC's members and peerings. The API refuses to
+// delete a default VPC or one that still has either, so the warnings say what
+// has to go first.
+func (h *Handler) PreviewDeleteVPC(vpcID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	vpc, _, err := client.VPCs.Get(context.Background(), vpcID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "vpc",
+		ID:           vpc.ID,
+		Name:         vpc.Name,
+		Region:       vpc.RegionSlug,
+	}
+	if vpc.Default {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("This is the default VPC of %s and cannot be deleted; make another VPC the default first", vpc.RegionSlug))
+	}
+
+	members, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]*godo.VPCMember, *godo.Response, error) {
+		return client.VPCs.ListMembers(context.Background(), vpcID, nil, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "member",
+			"urn":  member.URN,
+			"name": member.Name,
+		})
+	}
+	if len(members) > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("The VPC still has %d member(s); move or delete them before deleting it", len(members)))
+	}
+
+	peerings, _, err := paginate(0, 0, func(opt *godo.ListOptions) ([]*godo.VPCPeering, *godo.Response, error) {
+		return client.VPCs.ListVPCPeeringsByVPCID(context.Background(), vpcID, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, peering := range peerings {
+		preview.AttachedResources = append(preview.AttachedResources, map[string]interface{}{
+			"type": "vpc_peering",
+			"id":   peering.ID,
+			"name": peering.Name,
+		})
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("VPC peering %s (%s) must be deleted first", peering.Name, peering.ID))
+	}
+
+	return preview, nil
+}
+
+func (h *Handler) ListVPCMembers(vpcID, resourceType string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if resourceType != "" && !slices.Contains(vpcMemberTypes, resourceType) {
+		return h.HandleError(fmt.Errorf("unsupported resource type %q (expected one of %s)", resourceType, strings.Join(vpcMemberTypes, ", ")), "list_vpc_members")
+	}
+
+	var request *godo.VPCListMembersRequest
+	if resourceType != "" {
+		request = &godo.VPCListMembersRequest{ResourceType: resourceType}
+	}
+
+	members, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.VPCMember, *godo.Response, error) {
+		return client.VPCs.ListMembers(context.Background(), vpcID, request, opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_vpc_members")
+	}
+
+	return h.HandleSuccess(listResult("members", members, meta), "list_vpc_members")
+}
+
+// ListVPCPeerings lists every peering, or only those of vpcID when it is set.
+func (h *Handler) ListVPCPeerings(vpcID string, page, perPage int) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	peerings, meta, err := paginate(page, perPage, func(opt *godo.ListOptions) ([]*godo.VPCPeering, *godo.Response, error) {
+		if vpcID != "" {
+			return client.VPCs.ListVPCPeeringsByVPCID(context.Background(), vpcID, opt)
+		}
+		return client.VPCs.ListVPCPeerings(context.Background(), opt)
+	})
+	if err != nil {
+		return h.HandleError(err, "list_vpc_peerings")
+	}
+
+	return h.HandleSuccess(listResult("vpc_peerings", peerings, meta), "list_vpc_peerings")
+}
+
+func (h *Handler) GetVPCPeering(peeringID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	peering, _, err := client.VPCs.GetVPCPeering(context.Background(), peeringID)
+	if err != nil {
+		return h.HandleError(err, "get_vpc_peering")
+	}
+
+	return h.HandleSuccess(peering, "get_vpc_peering")
+}
+
+func (h *Handler) CreateVPCPeering(name string, vpcIDs []string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	if len(vpcIDs) != 2 || vpcIDs[0] == vpcIDs[1] {
+		return h.HandleError(fmt.Errorf("vpc_ids must name exactly two different VPCs"), "create_vpc_peering")
+	}
+
+	peering, _, err := client.VPCs.CreateVPCPeering(context.Background(), &godo.VPCPeeringCreateRequest{
+		Name:   name,
+		VPCIDs: vpcIDs,
+	})
+	if err != nil {
+		return h.HandleError(err, "create_vpc_peering")
+	}
+
+	return h.HandleSuccess(peering, "create_vpc_peering")
+}
+
+func (h *Handler) UpdateVPCPeering(peeringID, name string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	peering, _, err := client.VPCs.UpdateVPCPeering(context.Background(), peeringID, &godo.VPCPeeringUpdateRequest{Name: name})
+	if err != nil {
+		return h.HandleError(err, "update_vpc_peering")
+	}
+
+	return h.HandleSuccess(peering, "update_vpc_peering")
+}
+
+func (h *Handler) DeleteVPCPeering(peeringID string) (*mcp_golang.ToolResponse, error) {
+	client := h.doClient.GetClient()
+
+	_, err := client.VPCs.DeleteVPCPeering(context.Background(), peeringID)
+	if err != nil {
+		return h.HandleError(err, "delete_vpc_peering")
+	}
+
+	return h.HandleSuccess(map[string]string{
+		"status":  "success",
+		"message": fmt.Sprintf("VPC peering %s deleted successfully", peeringID),
+	}, "delete_vpc_peering")
+}
+
+// PreviewDeleteVPCPeering names the two VPCs that lose their private route.
+func (h *Handler) PreviewDeleteVPCPeering(peeringID string) (*DeletionPreview, error) {
+	client := h.doClient.GetClient()
+
+	peering, _, err := client.VPCs.GetVPCPeering(context.Background(), peeringID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DeletionPreview{
+		ResourceType: "vpc_peering",
+		ID:           peering.ID,
+		Name:         peering.Name,
+	}
+	var names []string
+	for _, id := range peering.VPCIDs {
+		attached := map[string]interface{}{"type": "vpc", "id": id}
+		name := id
+		if vpc, _, err := client.VPCs.Get(context.Background(), id); err == nil {
+			attached["name"] = vpc.Name
+			attached["ip_range"] = vpc.IPRange
+			name = vpc.Name
+		}
+		preview.AttachedResources = append(preview.AttachedResources, attached)
+		names = append(names, name)
+	}
+	preview.Warnings = append(preview.Warnings, fmt.Sprintf("Resources in %s can no longer reach each other over private networking", strings.Join(names, " and ")))
+
+	return preview, nil
+}
+
+// validateVPCIPRange applies the API's rules for a VPC range: an RFC 1918
+// IPv4 network no larger than /16 and no smaller than /28.
+func validateVPCIPRange(ipRange string) error {
+	prefix, err := netip.ParsePrefix(ipRange)
+	if err != nil {
+		return fmt.Errorf("ip_range %q is not in CIDR notation", ipRange)
+	}
+	if !prefix.Addr().Is4() || !prefix.Addr().IsPrivate() {
+		return fmt.Errorf("ip_range %s must be a private IPv4 network (10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16)", ipRange)
+	}
+	if prefix.Bits() < 16 || prefix.Bits() > 28 {
+		return fmt.Errorf("ip_range %s must be between /16 and /28", ipRange)
+	}
+	return nil
+}
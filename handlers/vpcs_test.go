@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestCreateVPC(t *testing.T) {
+	tests := []struct {
+		name    string
+		ipRange string
+		wantErr string
+	}{
+		{name: "explicit range", ipRange: "10.20.0.0/24"},
+		{name: "assigned range"},
+		{name: "not CIDR", ipRange: "10.20.0.0", wantErr: "not in CIDR notation"},
+		{name: "public range", ipRange: "203.0.113.0/24", wantErr: "must be a private IPv4 network"},
+		{name: "too large", ipRange: "10.0.0.0/8", wantErr: "between /16 and /28"},
+		{name: "overlapping", ipRange: "10.10.4.0/24", wantErr: "overlaps VPC default-nyc3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestHandler(t)
+			fake.AddVPC("default-nyc3", "nyc3", "10.10.0.0/20", true)
+
+			resp, err := h.CreateVPC("prod", "nyc3", "production network", tt.ipRange)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "create_vpc", tt.wantErr)
+				return
+			}
+			var vpc godo.VPC
+			decodeResponse(t, resp, err, &vpc)
+			stored, ok := fake.VPCs.Get(vpc.ID)
+			if !ok || stored.RegionSlug != "nyc3" || stored.Default || stored.IPRange == "" {
+				t.Errorf("stored VPC = %+v", stored)
+			}
+			if tt.ipRange != "" && stored.IPRange != tt.ipRange {
+				t.Errorf("ip range = %s, want %s", stored.IPRange, tt.ipRange)
+			}
+		})
+	}
+}
+
+func TestUpdateVPC(t *testing.T) {
+	h, fake := newTestHandler(t)
+	defaultID := fake.AddVPC("default-nyc3", "nyc3", "10.10.0.0/20", true)
+	id := fake.AddVPC("prod", "nyc3", "10.20.0.0/20", false)
+	name, description := "production", ""
+	fake.VPCs.Update(id, func(v *godo.VPC) { v.Description = "old" })
+
+	var vpc godo.VPC
+	resp, err := h.UpdateVPC(id, VPCUpdate{Name: &name, Description: &description})
+	decodeResponse(t, resp, err, &vpc)
+	if vpc.Name != "production" || vpc.Description != "" || vpc.Default {
+		t.Errorf("updated VPC = %+v", vpc)
+	}
+
+	resp, err = h.UpdateVPC(id, VPCUpdate{MakeDefault: true})
+	decodeResponse(t, resp, err, &vpc)
+	if previous, _ := fake.VPCs.Get(defaultID); !vpc.Default || previous.Default || vpc.Name != "production" {
+		t.Errorf("after make_default: new %+v, previous default %+v", vpc, previous)
+	}
+
+	resp, err = h.UpdateVPC(id, VPCUpdate{})
+	expectError(t, resp, err, "update_vpc", "nothing to update")
+
+	resp, err = h.UpdateVPC("vpc-missing", VPCUpdate{Name: &name})
+	expectError(t, resp, err, "update_vpc", "404")
+}
+
+func TestVPCMembers(t *testing.T) {
+	h, fake := newTestHandler(t)
+	vpcID := fake.AddVPC("prod", "nyc3", "10.20.0.0/20", false)
+	otherID := fake.AddVPC("staging", "sfo3", "10.30.0.0/20", false)
+	dropletIDs := seedDroplets(fake, 2)
+	fake.Droplets.Update(dropletIDs[0], func(d *godo.Droplet) { d.VPCUUID = vpcID })
+
+	resp, err := h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpRule}, nil, vpcID, "")
+	decodeResponse(t, resp, err, &godo.LoadBalancer{})
+	resp, err = h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 1, vpcID, "")
+	decodeResponse(t, resp, err, &godo.KubernetesCluster{})
+
+	resp, err = h.CreateLoadBalancer("web-lb", "round_robin", "nyc3", []godo.ForwardingRule{httpRule}, nil, otherID, "")
+	expectError(t, resp, err, "create_load_balancer", "422", "is in sfo3, not nyc3")
+	resp, err = h.CreateK8SCluster("prod", "nyc3", "1.29.1-do.0", "s-2vcpu-4gb", 1, "vpc-missing", "")
+	expectError(t, resp, err, "create_k8s_cluster", "422", "does not exist")
+
+	tests := []struct {
+		resourceType string
+		want         []string
+		wantErr      string
+	}{
+		{want: []string{"do:droplet:", "do:loadbalancer:", "do:kubernetes:"}},
+		{resourceType: "droplet", want: []string{"do:droplet:"}},
+		{resourceType: "kubernetes", want: []string{"do:kubernetes:"}},
+		{resourceType: "database", wantErr: `unsupported resource type "database"`},
+	}
+
+	for _, tt := range tests {
+		t.Run("type "+tt.resourceType, func(t *testing.T) {
+			resp, err := h.ListVPCMembers(vpcID, tt.resourceType, 0, 0)
+			if tt.wantErr != "" {
+				expectError(t, resp, err, "list_vpc_members", tt.wantErr)
+				return
+			}
+			var members []godo.VPCMember
+			decodeList(t, resp, err, "members", &members)
+			if len(members) != len(tt.want) {
+				t.Fatalf("members = %+v, want prefixes %v", members, tt.want)
+			}
+			for i, member := range members {
+				if !strings.HasPrefix(member.URN, tt.want[i]) {
+					t.Errorf("member %d URN = %s, want prefix %s", i, member.URN, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVPCPeerings(t *testing.T) {
+	h, fake := newTestHandler(t)
+	prodID := fake.AddVPC("prod", "nyc3", "10.20.0.0/20", false)
+	stagingID := fake.AddVPC("staging", "sfo3", "10.30.0.0/20", false)
+	overlapID := fake.AddVPC("legacy", "ams3", "10.20.0.0/16", false)
+	fake.AddVPC("dev", "lon1", "10.40.0.0/20", false)
+
+	var peering godo.VPCPeering
+	resp, err := h.CreateVPCPeering("prod-staging", []string{prodID, stagingID})
+	decodeResponse(t, resp, err, &peering)
+	if peering.Status != "ACTIVE" || len(peering.VPCIDs) != 2 {
+		t.Errorf("created peering = %+v", peering)
+	}
+
+	resp, err = h.CreateVPCPeering("again", []string{stagingID, prodID})
+	expectError(t, resp, err, "create_vpc_peering", "409", "already peered")
+	resp, err = h.CreateVPCPeering("overlap", []string{prodID, overlapID})
+	expectError(t, resp, err, "create_vpc_peering", "422", "overlapping IP ranges")
+	resp, err = h.CreateVPCPeering("self", []string{prodID, prodID})
+	expectError(t, resp, err, "create_vpc_peering", "exactly two different VPCs")
+
+	var peerings []godo.VPCPeering
+	resp, err = h.ListVPCPeerings(stagingID, 0, 0)
+	decodeList(t, resp, err, "vpc_peerings", &peerings)
+	if len(peerings) != 1 || peerings[0].ID != peering.ID {
+		t.Errorf("peerings of staging = %+v", peerings)
+	}
+	resp, err = h.ListVPCPeerings(overlapID, 0, 0)
+	decodeList(t, resp, err, "vpc_peerings", &peerings)
+	if len(peerings) != 0 {
+		t.Errorf("peerings of legacy = %+v", peerings)
+	}
+
+	resp, err = h.UpdateVPCPeering(peering.ID, "prod-to-staging")
+	decodeResponse(t, resp, err, &peering)
+	resp, err = h.GetVPCPeering(peering.ID)
+	decodeResponse(t, resp, err, &peering)
+	if peering.Name != "prod-to-staging" {
+		t.Errorf("renamed peering = %+v", peering)
+	}
+
+	resp, err = h.DeleteVPC(prodID)
+	expectError(t, resp, err, "delete_vpc", "409", "part of a peering")
+
+	resp, err = h.DeleteVPCPeering(peering.ID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	resp, err = h.DeleteVPC(prodID)
+	decodeResponse(t, resp, err, &map[string]string{})
+	if _, ok := fake.VPCs.Get(prodID); ok {
+		t.Errorf("VPC %s was not deleted", prodID)
+	}
+}
+
+func TestDeleteVPC(t *testing.T) {
+	h, fake := newTestHandler(t)
+	defaultID := fake.AddVPC("default-nyc3", "nyc3", "10.10.0.0/20", true)
+	busyID := fake.AddVPC("prod", "nyc3", "10.20.0.0/20", false)
+	dropletID := seedDroplets(fake, 1)[0]
+	fake.Droplets.Update(dropletID, func(d *godo.Droplet) { d.VPCUUID = busyID })
+
+	resp, err := h.DeleteVPC(defaultID)
+	expectError(t, resp, err, "delete_vpc", "403", "default VPC")
+	resp, err = h.DeleteVPC(busyID)
+	expectError(t, resp, err, "delete_vpc", "409", "still has members")
+
+	fake.FailNext(http.MethodGet, "/v2/vpcs/"+busyID, http.StatusServiceUnavailable, "vpc service degraded")
+	resp, err = h.GetVPC(busyID)
+	expectError(t, resp, err, "get_vpc", "503", "vpc service degraded")
+
+	var vpcs []godo.VPC
+	resp, err = h.ListVPCs(0, 0)
+	decodeList(t, resp, err, "vpcs", &vpcs)
+	if len(vpcs) != 2 {
+		t.Errorf("listed VPCs = %+v", vpcs)
+	}
+}
+
+func TestPreviewDeleteVPC(t *testing.T) {
+	h, fake := newTestHandler(t)
+	defaultID := fake.AddVPC("default-nyc3", "nyc3", "10.10.0.0/20", true)
+	prodID := fake.AddVPC("prod", "nyc3", "10.20.0.0/20", false)
+	stagingID := fake.AddVPC("staging", "sfo3", "10.30.0.0/20", false)
+	dropletID := seedDroplets(fake, 1)[0]
+	fake.Droplets.Update(dropletID, func(d *godo.Droplet) { d.VPCUUID = prodID })
+	var peering godo.VPCPeering
+	resp, err := h.CreateVPCPeering("prod-staging", []string{prodID, stagingID})
+	decodeResponse(t, resp, err, &peering)
+
+	preview, err := h.PreviewDeleteVPC(prodID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteVPC: %v", err)
+	}
+	if preview.ResourceType != "vpc" || preview.Region != "nyc3" || len(preview.AttachedResources) != 2 || len(preview.Warnings) != 2 {
+		t.Errorf("prod preview = %+v", preview)
+	}
+
+	preview, err = h.PreviewDeleteVPC(defaultID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteVPC: %v", err)
+	}
+	if len(preview.Warnings) != 1 || !strings.Contains(preview.Warnings[0], "default VPC of nyc3") {
+		t.Errorf("default preview warnings = %v", preview.Warnings)
+	}
+
+	preview, err = h.PreviewDeleteVPCPeering(peering.ID)
+	if err != nil {
+		t.Fatalf("PreviewDeleteVPCPeering: %v", err)
+	}
+	if len(preview.AttachedResources) != 2 || preview.AttachedResources[1]["name"] != "staging" || !strings.Contains(preview.Warnings[0], "prod and staging") {
+		t.Errorf("peering preview = %+v", preview)
+	}
+
+	if _, err := h.PreviewDeleteVPC("vpc-missing"); err == nil {
+		t.Errorf("expected an error for a missing VPC")
+	}
+}
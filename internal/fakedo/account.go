@@ -0,0 +1,36 @@
+package fakedo
+
+import (
+	"net/http"
+)
+
+func (s *Server) registerAccount() {
+	s.handle("GET /v2/account", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"account": s.Account})
+	})
+
+	s.handle("GET /v2/sizes", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "sizes", s.Sizes.List())
+	})
+
+	s.handle("GET /v2/regions", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "regions", s.Regions.List())
+	})
+
+	s.handle("GET /v2/actions", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "actions", s.Actions.List())
+	})
+
+	s.handle("GET /v2/actions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		action, ok := s.pollAction(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"action": action})
+	})
+}
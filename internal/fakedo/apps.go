@@ -0,0 +1,429 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// AppDeployment is a deployment together with the app it belongs to.
+type AppDeployment struct {
+	AppID string
+	godo.Deployment
+}
+
+var (
+	appRegions = []string{"ams", "blr", "fra", "lon", "nyc", "sfo", "sgp", "syd", "tor"}
+	logTypes   = []godo.AppLogType{godo.AppLogTypeBuild, godo.AppLogTypeDeploy, godo.AppLogTypeRun, godo.AppLogTypeRunRestarted}
+)
+
+// terminalPhases are the deployment phases a deployment never leaves.
+var terminalPhases = []godo.DeploymentPhase{
+	godo.DeploymentPhase_Active,
+	godo.DeploymentPhase_Superseded,
+	godo.DeploymentPhase_Error,
+	godo.DeploymentPhase_Canceled,
+}
+
+func (s *Server) registerApps() {
+	s.handle("GET /v2/apps", func(w http.ResponseWriter, r *http.Request) {
+		apps := s.Apps.List()
+		for i := range apps {
+			apps[i] = s.withDeployments(apps[i])
+		}
+		listResponse(w, r, "apps", apps)
+	})
+
+	s.handle("GET /v2/apps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		app, ok := s.Apps.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"app": s.withDeployments(app)})
+	})
+
+	s.handle("POST /v2/apps", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.AppCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := validateAppSpec(req.Spec); message != "" {
+			writeError(w, http.StatusBadRequest, message)
+			return
+		}
+		if len(s.Apps.Filter(func(a godo.App) bool { return a.Spec.Name == req.Spec.Name })) > 0 {
+			writeError(w, http.StatusConflict, fmt.Sprintf("an app named %s already exists", req.Spec.Name))
+			return
+		}
+		if message := s.checkProject(req.ProjectID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		app := s.newApp(req.Spec)
+		app.ProjectID = req.ProjectID
+		s.Apps.Put(app.ID, app)
+		if req.ProjectID != "" {
+			s.AssignToProject(req.ProjectID, app.URN())
+		}
+		s.newDeployment(app.ID, "initial deployment")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"app": s.withDeployments(app)})
+	})
+
+	s.handle("PUT /v2/apps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.AppUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		app, ok := s.Apps.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		if message := validateAppSpec(req.Spec); message != "" {
+			writeError(w, http.StatusBadRequest, message)
+			return
+		}
+		if req.Spec.Region != "" && req.Spec.Region != app.Region.Slug {
+			writeError(w, http.StatusBadRequest, "the region of an app cannot be changed")
+			return
+		}
+
+		s.Apps.Update(id, func(a *godo.App) {
+			a.Spec = req.Spec
+			a.Domains = appDomains(req.Spec)
+			a.UpdatedAt = time.Now().UTC()
+		})
+		s.newDeployment(id, "app spec updated")
+		app, _ = s.Apps.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"app": s.withDeployments(app)})
+	})
+
+	s.handle("DELETE /v2/apps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !s.Apps.Delete(id) {
+			notFound(w)
+			return
+		}
+		for _, deployment := range s.deploymentsOf(id) {
+			s.AppDeployments.Delete(deployment.ID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/apps/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.Apps.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		var deployments []godo.Deployment
+		for _, deployment := range s.deploymentsOf(id) {
+			deployments = append(deployments, deployment.Deployment)
+		}
+		listResponse(w, r, "deployments", deployments)
+	})
+
+	s.handle("GET /v2/apps/{id}/deployments/{deployment}", func(w http.ResponseWriter, r *http.Request) {
+		deployment, ok := s.findDeployment(r.PathValue("id"), r.PathValue("deployment"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"deployment": deployment.Deployment})
+	})
+
+	s.handle("POST /v2/apps/{id}/deployments", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.DeploymentCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.Apps.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		cause := "manual"
+		if req.ForceBuild {
+			cause = "manual, forced rebuild"
+		}
+		deployment := s.newDeployment(id, cause)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"deployment": deployment.Deployment})
+	})
+
+	s.handle("POST /v2/apps/{id}/deployments/{deployment}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		deployment, ok := s.findDeployment(r.PathValue("id"), r.PathValue("deployment"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		if slices.Contains(terminalPhases, deployment.Phase) {
+			writeError(w, http.StatusConflict, fmt.Sprintf("deployment is %s and can no longer be canceled", deployment.Phase))
+			return
+		}
+		s.AppDeployments.Update(deployment.ID, func(d *AppDeployment) {
+			d.Phase = godo.DeploymentPhase_Canceled
+			d.PhaseLastUpdatedAt = time.Now().UTC()
+		})
+		deployment, _ = s.AppDeployments.Get(deployment.ID)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"deployment": deployment.Deployment})
+	})
+
+	s.handle("GET /v2/apps/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		app, ok := s.Apps.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		active := s.withDeployments(app).ActiveDeployment
+		if active == nil {
+			writeError(w, http.StatusNotFound, "the app has no active deployment")
+			return
+		}
+		deployment, _ := s.AppDeployments.Get(active.ID)
+		s.writeLogs(w, r, app, deployment)
+	})
+
+	s.handle("GET /v2/apps/{id}/deployments/{deployment}/logs", func(w http.ResponseWriter, r *http.Request) {
+		app, ok := s.Apps.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		deployment, ok := s.findDeployment(app.ID, r.PathValue("deployment"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		s.writeLogs(w, r, app, deployment)
+	})
+
+	s.handle("GET /v2/apps/{id}/alerts", func(w http.ResponseWriter, r *http.Request) {
+		app, ok := s.Apps.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"alerts": appAlerts(app)})
+	})
+}
+
+// AddApp stores an app built from spec with one active deployment, and
+// returns its ID.
+func (s *Server) AddApp(spec *godo.AppSpec) string {
+	app := s.newApp(spec)
+	s.Apps.Put(app.ID, app)
+	deployment := s.newDeployment(app.ID, "initial deployment")
+	s.AppDeployments.Update(deployment.ID, func(d *AppDeployment) { d.Phase = godo.DeploymentPhase_Active })
+	return app.ID
+}
+
+func (s *Server) newApp(spec *godo.AppSpec) godo.App {
+	region := spec.Region
+	if region == "" {
+		region = "nyc"
+	}
+	id := s.NextUUID("app")
+	ingress := fmt.Sprintf("https://%s-%s.ondigitalocean.app", spec.Name, id)
+	now := time.Now().UTC()
+	return godo.App{
+		ID:             id,
+		Spec:           spec,
+		DefaultIngress: ingress,
+		LiveURL:        ingress,
+		Region:         &godo.AppRegion{Slug: region},
+		Domains:        appDomains(spec),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// newDeployment queues a deployment of the app's current spec. Deployments
+// still in progress are superseded by it.
+func (s *Server) newDeployment(appID, cause string) AppDeployment {
+	app, _ := s.Apps.Get(appID)
+	for _, previous := range s.deploymentsOf(appID) {
+		if !slices.Contains(terminalPhases, previous.Phase) {
+			s.AppDeployments.Update(previous.ID, func(d *AppDeployment) { d.Phase = godo.DeploymentPhase_Superseded })
+		}
+	}
+
+	now := time.Now().UTC()
+	deployment := AppDeployment{
+		AppID: appID,
+		Deployment: godo.Deployment{
+			ID:                 s.NextUUID("deployment"),
+			Spec:               app.Spec,
+			Cause:              cause,
+			Phase:              godo.DeploymentPhase_PendingBuild,
+			PhaseLastUpdatedAt: now,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		},
+	}
+	for _, service := range app.Spec.Services {
+		deployment.Services = append(deployment.Services, &godo.DeploymentService{Name: service.Name})
+	}
+	for _, site := range app.Spec.StaticSites {
+		deployment.StaticSites = append(deployment.StaticSites, &godo.DeploymentStaticSite{Name: site.Name})
+	}
+	for _, worker := range app.Spec.Workers {
+		deployment.Workers = append(deployment.Workers, &godo.DeploymentWorker{Name: worker.Name})
+	}
+	for _, job := range app.Spec.Jobs {
+		deployment.Jobs = append(deployment.Jobs, &godo.DeploymentJob{Name: job.Name})
+	}
+	s.AppDeployments.Put(deployment.ID, deployment)
+	return deployment
+}
+
+// deploymentsOf returns the app's deployments, newest first.
+func (s *Server) deploymentsOf(appID string) []AppDeployment {
+	deployments := s.AppDeployments.Filter(func(d AppDeployment) bool { return d.AppID == appID })
+	slices.Reverse(deployments)
+	return deployments
+}
+
+func (s *Server) findDeployment(appID, deploymentID string) (AppDeployment, bool) {
+	deployment, ok := s.AppDeployments.Get(deploymentID)
+	if !ok || deployment.AppID != appID {
+		return AppDeployment{}, false
+	}
+	return deployment, true
+}
+
+// withDeployments fills in the app's active and in-progress deployments.
+func (s *Server) withDeployments(app godo.App) godo.App {
+	app.ActiveDeployment, app.InProgressDeployment = nil, nil
+	for _, deployment := range s.deploymentsOf(app.ID) {
+		d := deployment.Deployment
+		switch {
+		case d.Phase == godo.DeploymentPhase_Active && app.ActiveDeployment == nil:
+			app.ActiveDeployment = &d
+		case !slices.Contains(terminalPhases, d.Phase) && app.InProgressDeployment == nil:
+			app.InProgressDeployment = &d
+		}
+	}
+	return app
+}
+
+func (s *Server) writeLogs(w http.ResponseWriter, r *http.Request, app godo.App, deployment AppDeployment) {
+	query := r.URL.Query()
+	logType := godo.AppLogType(query.Get("type"))
+	if !slices.Contains(logTypes, logType) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid log type %q", logType))
+		return
+	}
+	component := query.Get("component_name")
+	if component != "" && !slices.Contains(appComponentNames(app.Spec), component) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("component %s not found", component))
+		return
+	}
+	if logType == godo.AppLogTypeBuild && deployment.Phase == godo.DeploymentPhase_PendingBuild {
+		writeError(w, http.StatusNotFound, "the deployment has not started building yet")
+		return
+	}
+
+	base := fmt.Sprintf("https://logs.apps.fake/%s/%s/%s", app.ID, deployment.ID, logType)
+	if component != "" {
+		base += "/" + component
+	}
+	logs := godo.AppLogs{}
+	if query.Get("follow") == "true" {
+		logs.LiveURL = base + "/live"
+	} else {
+		logs.HistoricURLs = []string{base + "/1.log"}
+	}
+	writeJSON(w, http.StatusOK, logs)
+}
+
+// validateAppSpec applies the API's checks to an app spec and returns the
+// error message, or "" when the spec is acceptable.
+func validateAppSpec(spec *godo.AppSpec) string {
+	if spec == nil || spec.Name == "" {
+		return "spec.name is required"
+	}
+	if spec.Region != "" && !slices.Contains(appRegions, spec.Region) {
+		return fmt.Sprintf("spec.region %q is not a valid App Platform region", spec.Region)
+	}
+	names := appComponentNames(spec)
+	if len(names) == 0 {
+		return "the spec needs at least one service, static site, worker, job or function"
+	}
+	for i, name := range names {
+		if name == "" {
+			return "every component needs a name"
+		}
+		if slices.Contains(names[:i], name) {
+			return fmt.Sprintf("component name %s is used more than once", name)
+		}
+	}
+	return ""
+}
+
+// appComponentNames lists the names of the spec's deployable components.
+func appComponentNames(spec *godo.AppSpec) []string {
+	var names []string
+	for _, service := range spec.Services {
+		names = append(names, service.Name)
+	}
+	for _, site := range spec.StaticSites {
+		names = append(names, site.Name)
+	}
+	for _, worker := range spec.Workers {
+		names = append(names, worker.Name)
+	}
+	for _, job := range spec.Jobs {
+		names = append(names, job.Name)
+	}
+	for _, function := range spec.Functions {
+		names = append(names, function.Name)
+	}
+	return names
+}
+
+func appDomains(spec *godo.AppSpec) []*godo.AppDomain {
+	var domains []*godo.AppDomain
+	for i, domain := range spec.Domains {
+		domains = append(domains, &godo.AppDomain{
+			ID:    fmt.Sprintf("domain-%d", i+1),
+			Spec:  domain,
+			Phase: godo.AppJobSpecKindPHASE_Active,
+		})
+	}
+	return domains
+}
+
+// appAlerts derives the app's alerts from the app and component alert specs.
+func appAlerts(app godo.App) []*godo.AppAlert {
+	var alerts []*godo.AppAlert
+	add := func(component string, specs []*godo.AppAlertSpec) {
+		for _, spec := range specs {
+			alerts = append(alerts, &godo.AppAlert{
+				ID:            fmt.Sprintf("%s-alert-%d", app.ID, len(alerts)+1),
+				ComponentName: component,
+				Spec:          spec,
+				Phase:         godo.AppAlertPhase_Active,
+			})
+		}
+	}
+	add("", app.Spec.Alerts)
+	for _, service := range app.Spec.Services {
+		add(service.Name, service.Alerts)
+	}
+	for _, worker := range app.Spec.Workers {
+		add(worker.Name, worker.Alerts)
+	}
+	for _, job := range app.Spec.Jobs {
+		add(job.Name, job.Alerts)
+	}
+	for _, function := range app.Spec.Functions {
+		add(function.Name, function.Alerts)
+	}
+	return alerts
+}
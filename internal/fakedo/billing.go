@@ -0,0 +1,147 @@
+package fakedo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// Invoice is an issued invoice with its line items and summary.
+type Invoice struct {
+	godo.InvoiceListItem
+	Items   []godo.InvoiceItem
+	Summary godo.InvoiceSummary
+}
+
+func (s *Server) registerBilling() {
+	s.handle("GET /v2/customers/my/balance", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.Balance)
+	})
+
+	s.handle("GET /v2/customers/my/billing_history", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "billing_history", s.BillingHistory.List())
+	})
+
+	s.handle("GET /v2/customers/my/invoices", func(w http.ResponseWriter, r *http.Request) {
+		var invoices []godo.InvoiceListItem
+		for _, invoice := range s.Invoices.List() {
+			invoices = append(invoices, invoice.InvoiceListItem)
+		}
+		if invoices == nil {
+			invoices = []godo.InvoiceListItem{}
+		}
+		pageItems, links, meta := paginate(r, invoices)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"invoices":        pageItems,
+			"invoice_preview": s.InvoicePreview,
+			"links":           links,
+			"meta":            meta,
+		})
+	})
+
+	s.handle("GET /v2/customers/my/invoices/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		invoice, ok := s.Invoices.Get(r.PathValue("uuid"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		listResponse(w, r, "invoice_items", invoice.Items)
+	})
+
+	s.handle("GET /v2/customers/my/invoices/{uuid}/summary", func(w http.ResponseWriter, r *http.Request) {
+		invoice, ok := s.Invoices.Get(r.PathValue("uuid"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, invoice.Summary)
+	})
+
+	s.handle("GET /v2/customers/my/invoices/{uuid}/csv", func(w http.ResponseWriter, r *http.Request) {
+		invoice, ok := s.Invoices.Get(r.PathValue("uuid"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		var buf bytes.Buffer
+		out := csv.NewWriter(&buf)
+		out.Write([]string{"product", "group_description", "description", "hours", "start", "end", "USD", "project_name", "category"})
+		for _, item := range invoice.Items {
+			out.Write([]string{
+				item.Product,
+				item.GroupDescription,
+				item.Description,
+				item.Duration,
+				item.StartTime.Format(time.RFC3339),
+				item.EndTime.Format(time.RFC3339),
+				item.Amount,
+				item.ProjectName,
+				item.Category,
+			})
+		}
+		out.Flush()
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write(buf.Bytes())
+	})
+}
+
+// AddInvoice stores an invoice for period ("2024-01") with the given line
+// items. The amount and the summary's product charges are the sum of the
+// item amounts.
+func (s *Server) AddInvoice(period string, items ...godo.InvoiceItem) string {
+	id := s.NextUUID("invoice")
+	var total float64
+	charges := map[string]float64{}
+	var products []string
+	for _, item := range items {
+		amount := parseAmount(item.Amount)
+		total += amount
+		if _, ok := charges[item.Product]; !ok {
+			products = append(products, item.Product)
+		}
+		charges[item.Product] += amount
+	}
+
+	summary := godo.InvoiceSummary{
+		InvoiceUUID:    id,
+		BillingPeriod:  period,
+		Amount:         formatAmount(total),
+		UserName:       s.Account.Name,
+		UserEmail:      s.Account.Email,
+		ProductCharges: godo.InvoiceSummaryBreakdown{Name: "Product usage charges", Amount: formatAmount(total)},
+		Taxes:          godo.InvoiceSummaryBreakdown{Name: "Taxes", Amount: "0.00"},
+	}
+	for _, product := range products {
+		summary.ProductCharges.Items = append(summary.ProductCharges.Items, godo.InvoiceSummaryBreakdownItem{
+			Name:   product,
+			Amount: formatAmount(charges[product]),
+			Count:  "1",
+		})
+	}
+
+	s.Invoices.Put(id, Invoice{
+		InvoiceListItem: godo.InvoiceListItem{
+			InvoiceUUID:   id,
+			Amount:        formatAmount(total),
+			InvoicePeriod: period,
+			UpdatedAt:     time.Now().UTC(),
+		},
+		Items:   items,
+		Summary: summary,
+	})
+	return id
+}
+
+func parseAmount(amount string) float64 {
+	value, _ := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	return value
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
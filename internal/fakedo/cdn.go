@@ -0,0 +1,133 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerCDN() {
+	s.handle("GET /v2/cdn/endpoints", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "endpoints", s.CDNEndpoints.List())
+	})
+
+	s.handle("GET /v2/cdn/endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		endpoint, ok := s.CDNEndpoints.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"endpoint": endpoint})
+	})
+
+	s.handle("POST /v2/cdn/endpoints", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.CDNCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !strings.HasSuffix(req.Origin, ".digitaloceanspaces.com") {
+			writeError(w, http.StatusUnprocessableEntity, "origin must be a Spaces bucket endpoint")
+			return
+		}
+		if len(s.CDNEndpoints.Filter(func(c godo.CDN) bool { return c.Origin == req.Origin })) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, "a CDN endpoint already exists for this origin")
+			return
+		}
+		if req.TTL == 0 {
+			req.TTL = 3600
+		}
+		if message := s.checkCDN(req.TTL, req.CustomDomain, req.CertificateID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		endpoint := godo.CDN{
+			ID:            s.NextUUID("cdn"),
+			Origin:        req.Origin,
+			Endpoint:      strings.Replace(req.Origin, ".digitaloceanspaces.com", ".cdn.digitaloceanspaces.com", 1),
+			CreatedAt:     time.Now().UTC().Truncate(time.Second),
+			TTL:           req.TTL,
+			CertificateID: req.CertificateID,
+			CustomDomain:  req.CustomDomain,
+		}
+		s.CDNEndpoints.Put(endpoint.ID, endpoint)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"endpoint": endpoint})
+	})
+
+	// The same path updates either the TTL or the custom domain
+	s.handle("PUT /v2/cdn/endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req struct {
+			TTL           *uint32 `json:"ttl"`
+			CustomDomain  *string `json:"custom_domain"`
+			CertificateID string  `json:"certificate_id"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		endpoint, ok := s.CDNEndpoints.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		if req.TTL != nil {
+			endpoint.TTL = *req.TTL
+		}
+		if req.CustomDomain != nil {
+			endpoint.CustomDomain = *req.CustomDomain
+			endpoint.CertificateID = req.CertificateID
+		}
+		if message := s.checkCDN(endpoint.TTL, endpoint.CustomDomain, endpoint.CertificateID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		s.CDNEndpoints.Put(id, endpoint)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"endpoint": endpoint})
+	})
+
+	s.handle("DELETE /v2/cdn/endpoints/{id}/cache", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.CDNFlushCacheRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.CDNEndpoints.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		if len(req.Files) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "files are required")
+			return
+		}
+		purged, _ := s.CachePurges.Get(id)
+		s.CachePurges.Put(id, append(purged, req.Files...))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("DELETE /v2/cdn/endpoints/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.CDNEndpoints.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// checkCDN returns an error message for settings the API would reject.
+func (s *Server) checkCDN(ttl uint32, customDomain, certificateID string) string {
+	switch {
+	case !containsInt([]int{60, 600, 3600, 86400, 604800}, int(ttl)):
+		return fmt.Sprintf("invalid ttl: %d", ttl)
+	case customDomain != "" && certificateID == "":
+		return "certificate_id is required with a custom domain"
+	case certificateID != "":
+		if _, ok := s.Certificates.Get(certificateID); !ok {
+			return fmt.Sprintf("certificate %s not found", certificateID)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package fakedo
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerCertificates() {
+	s.handle("GET /v2/certificates", func(w http.ResponseWriter, r *http.Request) {
+		certificates := s.Certificates.List()
+		if name := r.URL.Query().Get("name"); name != "" {
+			certificates = s.Certificates.Filter(func(c godo.Certificate) bool { return c.Name == name })
+		}
+		listResponse(w, r, "certificates", certificates)
+	})
+
+	s.handle("GET /v2/certificates/{id}", func(w http.ResponseWriter, r *http.Request) {
+		certificate, ok := s.Certificates.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"certificate": certificate})
+	})
+
+	s.handle("POST /v2/certificates", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.CertificateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		if len(s.Certificates.Filter(func(c godo.Certificate) bool { return c.Name == req.Name })) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, "a certificate with this name already exists")
+			return
+		}
+
+		certificate := godo.Certificate{
+			ID:      s.NextUUID("cert"),
+			Name:    req.Name,
+			Created: time.Now().UTC().Format(time.RFC3339),
+			State:   "verified",
+			Type:    req.Type,
+		}
+		switch req.Type {
+		case "lets_encrypt":
+			if len(req.DNSNames) == 0 {
+				writeError(w, http.StatusUnprocessableEntity, "dns_names are required for lets_encrypt certificates")
+				return
+			}
+			for _, name := range req.DNSNames {
+				if !s.managesDomain(strings.TrimPrefix(name, "*.")) {
+					writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s is not managed by DigitalOcean DNS", name))
+					return
+				}
+			}
+			certificate.DNSNames = req.DNSNames
+			certificate.NotAfter = time.Now().UTC().AddDate(0, 0, 90).Format(time.RFC3339)
+			certificate.SHA1Fingerprint = fmt.Sprintf("%040x", s.NextID())
+		case "custom", "":
+			certificate.Type = "custom"
+			leaf, message := parseLeafCertificate(req.LeafCertificate, req.PrivateKey)
+			if message != "" {
+				writeError(w, http.StatusUnprocessableEntity, message)
+				return
+			}
+			sum := sha1.Sum(leaf.Raw)
+			certificate.DNSNames = leaf.DNSNames
+			certificate.NotAfter = leaf.NotAfter.UTC().Format(time.RFC3339)
+			certificate.SHA1Fingerprint = hex.EncodeToString(sum[:])
+		default:
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid type: %s", req.Type))
+			return
+		}
+
+		s.Certificates.Put(certificate.ID, certificate)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"certificate": certificate})
+	})
+
+	s.handle("DELETE /v2/certificates/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.Certificates.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// AddCertificate stores a verified Let's Encrypt certificate for dnsNames
+// and returns it.
+func (s *Server) AddCertificate(name string, dnsNames ...string) godo.Certificate {
+	certificate := godo.Certificate{
+		ID:              s.NextUUID("cert"),
+		Name:            name,
+		DNSNames:        dnsNames,
+		NotAfter:        time.Now().UTC().AddDate(0, 0, 90).Format(time.RFC3339),
+		SHA1Fingerprint: fmt.Sprintf("%040x", s.NextID()),
+		Created:         time.Now().UTC().Format(time.RFC3339),
+		State:           "verified",
+		Type:            "lets_encrypt",
+	}
+	s.Certificates.Put(certificate.ID, certificate)
+	return certificate
+}
+
+// checkCertificates returns an error message when a forwarding rule names a
+// certificate that does not exist.
+func (s *Server) checkCertificates(rules []godo.ForwardingRule) string {
+	for _, rule := range rules {
+		if rule.CertificateID == "" {
+			continue
+		}
+		if _, ok := s.Certificates.Get(rule.CertificateID); !ok {
+			return fmt.Sprintf("certificate %s not found", rule.CertificateID)
+		}
+	}
+	return ""
+}
+
+// managesDomain reports whether name is a DNS domain on the account or a
+// subdomain of one.
+func (s *Server) managesDomain(name string) bool {
+	for _, domain := range s.Domains.List() {
+		if name == domain.Name || strings.HasSuffix(name, "."+domain.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLeafCertificate parses an uploaded PEM certificate and checks that a
+// private key came with it.
+func parseLeafCertificate(leafPEM, keyPEM string) (*x509.Certificate, string) {
+	if leafPEM == "" || keyPEM == "" {
+		return nil, "leaf_certificate and private_key are required for custom certificates"
+	}
+	block, _ := pem.Decode([]byte(leafPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, "leaf_certificate is not a PEM-encoded certificate"
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Sprintf("leaf_certificate is invalid: %v", err)
+	}
+	if keyBlock, _ := pem.Decode([]byte(keyPEM)); keyBlock == nil {
+		return nil, "private_key is not PEM-encoded"
+	}
+	return leaf, ""
+}
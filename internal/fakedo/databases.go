@@ -0,0 +1,713 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// DatabaseCluster is a managed database cluster together with the
+// sub-resources the API serves under it.
+type DatabaseCluster struct {
+	godo.Database
+	Pools         []godo.DatabasePool
+	Replicas      []godo.DatabaseReplica
+	Backups       []godo.DatabaseBackup
+	FirewallRules []godo.DatabaseFirewallRule
+	Config        map[string]interface{}
+}
+
+// databaseEngine describes how the fake models each engine.
+type databaseEngine struct {
+	protocol  string
+	port      int
+	defaultDB string
+	// users, dbs, pools and replicas report whether the engine supports them
+	users, dbs, pools, replicas bool
+	config                      map[string]interface{}
+}
+
+var databaseEngines = map[string]databaseEngine{
+	"pg": {
+		protocol: "postgresql", port: 25060, defaultDB: "defaultdb",
+		users: true, dbs: true, pools: true, replicas: true,
+		config: map[string]interface{}{"autovacuum_naptime": 60, "timezone": "UTC"},
+	},
+	"mysql": {
+		protocol: "mysql", port: 25060, defaultDB: "defaultdb",
+		users: true, dbs: true, replicas: true,
+		config: map[string]interface{}{"connect_timeout": 10, "sql_require_primary_key": true},
+	},
+	"redis": {
+		protocol: "rediss", port: 25061,
+		config: map[string]interface{}{"redis_maxmemory_policy": "noeviction", "redis_timeout": 300},
+	},
+	"valkey": {
+		protocol: "rediss", port: 25061,
+		config: map[string]interface{}{"valkey_maxmemory_policy": "noeviction", "valkey_timeout": 300},
+	},
+	"mongodb": {
+		protocol: "mongodb+srv", port: 27017, defaultDB: "admin",
+		users: true, dbs: true,
+		config: map[string]interface{}{"default_read_concern": "majority", "verbosity": 1},
+	},
+}
+
+var (
+	maintenanceDays    = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+	maintenanceHour    = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$`)
+	trustedSourceTypes = []string{"ip_addr", "droplet", "k8s", "tag", "app"}
+	poolModes          = []string{"session", "transaction", "statement"}
+)
+
+func (s *Server) registerDatabases() {
+	s.handle("GET /v2/databases", func(w http.ResponseWriter, r *http.Request) {
+		databases := []godo.Database{}
+		for _, cluster := range s.Databases.List() {
+			databases = append(databases, cluster.Database)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"databases": databases})
+	})
+
+	s.handle("GET /v2/databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.Databases.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"database": cluster.Database})
+	})
+
+	s.handle("POST /v2/databases", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Region == "" || req.SizeSlug == "" || req.EngineSlug == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name, engine, size and region are required")
+			return
+		}
+		if _, ok := databaseEngines[req.EngineSlug]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid engine: %s", req.EngineSlug))
+			return
+		}
+		if !strings.HasPrefix(req.SizeSlug, "db-") {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid size: %s", req.SizeSlug))
+			return
+		}
+		if req.NumNodes < 1 || req.NumNodes > 3 {
+			writeError(w, http.StatusUnprocessableEntity, "num_nodes must be between 1 and 3")
+			return
+		}
+		if message := s.checkVPC(req.PrivateNetworkUUID, req.Region); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if message := s.checkProject(req.ProjectID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if len(s.Databases.Filter(func(c DatabaseCluster) bool { return c.Name == req.Name })) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("a database cluster named %s already exists", req.Name))
+			return
+		}
+
+		cluster := s.AddDatabaseCluster(req.Name, req.EngineSlug, req.Region, req.SizeSlug, req.NumNodes)
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+			c.VersionSlug = req.Version
+			c.PrivateNetworkUUID = req.PrivateNetworkUUID
+			c.Tags = req.Tags
+			c.StorageSizeMib = req.StorageSizeMib
+			c.Status = "creating"
+			for _, rule := range req.Rules {
+				c.FirewallRules = append(c.FirewallRules, s.newFirewallRule(c.ID, rule.Type, rule.Value))
+			}
+		})
+		s.ensureTags(req.Tags)
+		if req.ProjectID != "" {
+			s.AssignToProject(req.ProjectID, cluster.URN())
+		}
+		cluster, _ = s.Databases.Get(cluster.ID)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"database": cluster.Database})
+	})
+
+	s.handle("DELETE /v2/databases/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.Databases.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("PUT /v2/databases/{id}/resize", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseResizeRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !strings.HasPrefix(req.SizeSlug, "db-") || req.NumNodes < 1 || req.NumNodes > 3 {
+			writeError(w, http.StatusUnprocessableEntity, "size and num_nodes (1-3) are required")
+			return
+		}
+		if req.StorageSizeMib != 0 && req.StorageSizeMib < cluster.StorageSizeMib {
+			writeError(w, http.StatusUnprocessableEntity, "storage cannot be reduced")
+			return
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+			c.SizeSlug = req.SizeSlug
+			c.NumNodes = req.NumNodes
+			if req.StorageSizeMib != 0 {
+				c.StorageSizeMib = req.StorageSizeMib
+			}
+			c.Status = "resizing"
+		})
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	s.handle("PUT /v2/databases/{id}/maintenance", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseUpdateMaintenanceRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !containsString(maintenanceDays, req.Day) || !maintenanceHour.MatchString(req.Hour) {
+			writeError(w, http.StatusUnprocessableEntity, "day must be a lowercase weekday and hour must be HH:MM")
+			return
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+			c.MaintenanceWindow = &godo.DatabaseMaintenanceWindow{Day: req.Day, Hour: req.Hour}
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/databases/{id}/backups", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		backups := append([]godo.DatabaseBackup{}, cluster.Backups...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"backups": backups})
+	})
+
+	s.registerDatabaseUsers()
+	s.registerDatabaseDBs()
+	s.registerDatabasePools()
+	s.registerDatabaseReplicas()
+
+	s.handle("GET /v2/databases/{id}/firewall", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		rules := append([]godo.DatabaseFirewallRule{}, cluster.FirewallRules...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"rules": rules})
+	})
+
+	s.handle("PUT /v2/databases/{id}/firewall", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseUpdateFirewallRulesRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		rules := []godo.DatabaseFirewallRule{}
+		for _, rule := range req.Rules {
+			if !containsString(trustedSourceTypes, rule.Type) || rule.Value == "" {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid rule %s %q", rule.Type, rule.Value))
+				return
+			}
+			rules = append(rules, s.newFirewallRule(cluster.ID, rule.Type, rule.Value))
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.FirewallRules = rules })
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/databases/{id}/config", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"config": cluster.Config})
+	})
+
+	s.handle("PATCH /v2/databases/{id}/config", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Config map[string]interface{} `json:"config"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if len(req.Config) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "config is required")
+			return
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+			for key, value := range req.Config {
+				c.Config[key] = value
+			}
+		})
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *Server) registerDatabaseUsers() {
+	s.handle("GET /v2/databases/{id}/users", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		users := append([]godo.DatabaseUser{}, cluster.Users...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"users": users})
+	})
+
+	s.handle("GET /v2/databases/{id}/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for _, user := range cluster.Users {
+			if user.Name == r.PathValue("name") {
+				writeJSON(w, http.StatusOK, map[string]interface{}{"user": user})
+				return
+			}
+		}
+		notFound(w)
+	})
+
+	s.handle("POST /v2/databases/{id}/users", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseCreateUserRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !databaseEngines[cluster.EngineSlug].users {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s clusters do not support additional users", cluster.EngineSlug))
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		for _, user := range cluster.Users {
+			if user.Name == req.Name {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("user %s already exists", req.Name))
+				return
+			}
+		}
+		user := godo.DatabaseUser{Name: req.Name, Role: "normal", Password: s.newPassword(), MySQLSettings: req.MySQLSettings, Settings: req.Settings}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.Users = append(c.Users, user) })
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"user": user})
+	})
+
+	s.handle("POST /v2/databases/{id}/users/{name}/reset_auth", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		name := r.PathValue("name")
+		for i, user := range cluster.Users {
+			if user.Name == name {
+				user.Password = s.newPassword()
+				s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+					c.Users[i] = user
+					c.refreshConnections()
+				})
+				writeJSON(w, http.StatusOK, map[string]interface{}{"user": user})
+				return
+			}
+		}
+		notFound(w)
+	})
+
+	s.handle("DELETE /v2/databases/{id}/users/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		name := r.PathValue("name")
+		if name == "doadmin" {
+			writeError(w, http.StatusUnprocessableEntity, "the doadmin user cannot be deleted")
+			return
+		}
+		for i, user := range cluster.Users {
+			if user.Name == name {
+				s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+					c.Users = append(c.Users[:i:i], c.Users[i+1:]...)
+				})
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		notFound(w)
+	})
+}
+
+func (s *Server) registerDatabaseDBs() {
+	s.handle("GET /v2/databases/{id}/dbs", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		dbs := []godo.DatabaseDB{}
+		for _, name := range cluster.DBNames {
+			dbs = append(dbs, godo.DatabaseDB{Name: name})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"dbs": dbs})
+	})
+
+	s.handle("POST /v2/databases/{id}/dbs", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseCreateDBRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !databaseEngines[cluster.EngineSlug].dbs {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s clusters do not support databases", cluster.EngineSlug))
+			return
+		}
+		if req.Name == "" || containsString(cluster.DBNames, req.Name) {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("database %q is invalid or already exists", req.Name))
+			return
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.DBNames = append(c.DBNames, req.Name) })
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"db": godo.DatabaseDB{Name: req.Name}})
+	})
+
+	s.handle("DELETE /v2/databases/{id}/dbs/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		name := r.PathValue("name")
+		if !containsString(cluster.DBNames, name) {
+			notFound(w)
+			return
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.DBNames = removeString(c.DBNames, name) })
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) registerDatabasePools() {
+	s.handle("GET /v2/databases/{id}/pools", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		pools := append([]godo.DatabasePool{}, cluster.Pools...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"pools": pools})
+	})
+
+	s.handle("GET /v2/databases/{id}/pools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for _, pool := range cluster.Pools {
+			if pool.Name == r.PathValue("name") {
+				writeJSON(w, http.StatusOK, map[string]interface{}{"pool": pool})
+				return
+			}
+		}
+		notFound(w)
+	})
+
+	s.handle("POST /v2/databases/{id}/pools", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseCreatePoolRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !databaseEngines[cluster.EngineSlug].pools {
+			writeError(w, http.StatusUnprocessableEntity, "connection pools are only supported on PostgreSQL clusters")
+			return
+		}
+		for _, pool := range cluster.Pools {
+			if pool.Name == req.Name {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("pool %s already exists", req.Name))
+				return
+			}
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		if message := cluster.validatePool(req.User, req.Database, req.Mode, req.Size); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		pool := godo.DatabasePool{Name: req.Name, User: req.User, Database: req.Database, Mode: req.Mode, Size: req.Size}
+		pool.Connection = cluster.connection(req.User, req.Name)
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.Pools = append(c.Pools, pool) })
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"pool": pool})
+	})
+
+	s.handle("PUT /v2/databases/{id}/pools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseUpdatePoolRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for i, pool := range cluster.Pools {
+			if pool.Name != r.PathValue("name") {
+				continue
+			}
+			if req.User == "" {
+				req.User = pool.User
+			}
+			if message := cluster.validatePool(req.User, req.Database, req.Mode, req.Size); message != "" {
+				writeError(w, http.StatusUnprocessableEntity, message)
+				return
+			}
+			pool.User, pool.Database, pool.Mode, pool.Size = req.User, req.Database, req.Mode, req.Size
+			pool.Connection = cluster.connection(pool.User, pool.Name)
+			s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.Pools[i] = pool })
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		notFound(w)
+	})
+
+	s.handle("DELETE /v2/databases/{id}/pools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for i, pool := range cluster.Pools {
+			if pool.Name == r.PathValue("name") {
+				s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+					c.Pools = append(c.Pools[:i:i], c.Pools[i+1:]...)
+				})
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		notFound(w)
+	})
+}
+
+func (s *Server) registerDatabaseReplicas() {
+	s.handle("GET /v2/databases/{id}/replicas", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		replicas := append([]godo.DatabaseReplica{}, cluster.Replicas...)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"replicas": replicas})
+	})
+
+	s.handle("GET /v2/databases/{id}/replicas/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for _, replica := range cluster.Replicas {
+			if replica.Name == r.PathValue("name") {
+				writeJSON(w, http.StatusOK, map[string]interface{}{"replica": replica})
+				return
+			}
+		}
+		notFound(w)
+	})
+
+	s.handle("POST /v2/databases/{id}/replicas", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DatabaseCreateReplicaRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		if !databaseEngines[cluster.EngineSlug].replicas {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s clusters do not support read replicas", cluster.EngineSlug))
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		if req.Region == "" {
+			req.Region = cluster.RegionSlug
+		}
+		if req.Size == "" {
+			req.Size = cluster.SizeSlug
+		}
+		if message := s.checkVPC(req.PrivateNetworkUUID, req.Region); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		replica := godo.DatabaseReplica{
+			ID:                 s.NextUUID("replica"),
+			Name:               req.Name,
+			Region:             req.Region,
+			Size:               req.Size,
+			Status:             "forking",
+			CreatedAt:          time.Now().UTC(),
+			PrivateNetworkUUID: req.PrivateNetworkUUID,
+			Tags:               req.Tags,
+			StorageSizeMib:     req.StorageSizeMib,
+			Connection:         cluster.connection("doadmin", req.Name),
+		}
+		s.Databases.Update(cluster.ID, func(c *DatabaseCluster) { c.Replicas = append(c.Replicas, replica) })
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"replica": replica})
+	})
+
+	s.handle("DELETE /v2/databases/{id}/replicas/{name}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.findDatabase(w, r)
+		if !ok {
+			return
+		}
+		for i, replica := range cluster.Replicas {
+			if replica.Name == r.PathValue("name") {
+				s.Databases.Update(cluster.ID, func(c *DatabaseCluster) {
+					c.Replicas = append(c.Replicas[:i:i], c.Replicas[i+1:]...)
+				})
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		notFound(w)
+	})
+}
+
+// AddDatabaseCluster stores an online cluster with the engine's admin user and
+// default database, and returns it.
+func (s *Server) AddDatabaseCluster(name, engine, region, size string, numNodes int) DatabaseCluster {
+	spec := databaseEngines[engine]
+	cluster := DatabaseCluster{
+		Database: godo.Database{
+			ID:         s.NextUUID("db"),
+			Name:       name,
+			EngineSlug: engine,
+			RegionSlug: region,
+			SizeSlug:   size,
+			NumNodes:   numNodes,
+			Status:     "online",
+			CreatedAt:  time.Now().UTC(),
+			Users:      []godo.DatabaseUser{{Name: "doadmin", Role: "primary", Password: s.newPassword()}},
+			MaintenanceWindow: &godo.DatabaseMaintenanceWindow{
+				Day:  "sunday",
+				Hour: "03:00:00",
+			},
+		},
+		Backups: []godo.DatabaseBackup{{CreatedAt: time.Now().UTC().Add(-24 * time.Hour), SizeGigabytes: 0.04}},
+		Config:  map[string]interface{}{},
+	}
+	if spec.defaultDB != "" {
+		cluster.DBNames = []string{spec.defaultDB}
+	}
+	for key, value := range spec.config {
+		cluster.Config[key] = value
+	}
+	cluster.refreshConnections()
+	s.Databases.Put(cluster.ID, cluster)
+	return cluster
+}
+
+func (s *Server) findDatabase(w http.ResponseWriter, r *http.Request) (DatabaseCluster, bool) {
+	cluster, ok := s.Databases.Get(r.PathValue("id"))
+	if !ok {
+		notFound(w)
+	}
+	return cluster, ok
+}
+
+func (s *Server) newPassword() string {
+	return fmt.Sprintf("AVNS_secret%d", s.NextID())
+}
+
+func (s *Server) newFirewallRule(clusterID, ruleType, value string) godo.DatabaseFirewallRule {
+	return godo.DatabaseFirewallRule{
+		UUID:        s.NextUUID("rule"),
+		ClusterUUID: clusterID,
+		Type:        ruleType,
+		Value:       value,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+// refreshConnections rebuilds the public and private connections for the
+// admin user, so they carry its current password.
+func (c *DatabaseCluster) refreshConnections() {
+	c.Connection = c.connection("doadmin", c.Name)
+	c.PrivateConnection = c.connection("doadmin", "private-"+c.Name)
+}
+
+// connection builds connection details for user on a host derived from name.
+func (c *DatabaseCluster) connection(user, name string) *godo.DatabaseConnection {
+	spec := databaseEngines[c.EngineSlug]
+	password := ""
+	for _, u := range c.Users {
+		if u.Name == user {
+			password = u.Password
+		}
+	}
+	host := fmt.Sprintf("%s-do-user-1.db.ondigitalocean.com", name)
+	database := spec.defaultDB
+	uri := url.URL{
+		Scheme: spec.protocol,
+		User:   url.UserPassword(user, password),
+		Host:   fmt.Sprintf("%s:%d", host, spec.port),
+	}
+	if database != "" {
+		uri.Path = "/" + database
+	}
+	return &godo.DatabaseConnection{
+		Protocol: spec.protocol,
+		URI:      uri.String(),
+		Database: database,
+		Host:     host,
+		Port:     spec.port,
+		User:     user,
+		Password: password,
+		SSL:      true,
+	}
+}
+
+func (c *DatabaseCluster) validatePool(user, database, mode string, size int) string {
+	var users []string
+	for _, u := range c.Users {
+		users = append(users, u.Name)
+	}
+	switch {
+	case !containsString(users, user):
+		return fmt.Sprintf("user %s does not exist", user)
+	case !containsString(c.DBNames, database):
+		return fmt.Sprintf("database %s does not exist", database)
+	case !containsString(poolModes, mode):
+		return fmt.Sprintf("mode must be one of %s", strings.Join(poolModes, ", "))
+	case size < 1:
+		return "size must be at least 1"
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+// DomainRecord is a DNS record together with the domain it belongs to.
+type DomainRecord struct {
+	Domain string
+	godo.DomainRecord
+}
+
+var recordTypes = []string{"A", "AAAA", "CAA", "CNAME", "MX", "NS", "SRV", "TXT"}
+
+var defaultNameServers = []string{"ns1.digitalocean.com", "ns2.digitalocean.com", "ns3.digitalocean.com"}
+
+func (s *Server) registerDomains() {
+	s.handle("GET /v2/domains", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "domains", s.Domains.List())
+	})
+
+	s.handle("GET /v2/domains/{domain}", func(w http.ResponseWriter, r *http.Request) {
+		domain, ok := s.Domains.Get(r.PathValue("domain"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		domain.ZoneFile = s.zoneFile(domain)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"domain": domain})
+	})
+
+	s.handle("POST /v2/domains", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.DomainCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || !strings.Contains(req.Name, ".") {
+			writeError(w, http.StatusUnprocessableEntity, "name is invalid")
+			return
+		}
+		if _, exists := s.Domains.Get(req.Name); exists {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s already exists", req.Name))
+			return
+		}
+
+		domain := godo.Domain{Name: req.Name, TTL: 1800}
+		s.Domains.Put(domain.Name, domain)
+		s.putRecord(domain.Name, godo.DomainRecord{Type: "SOA", Name: "@", Data: "1800", TTL: 1800})
+		for _, ns := range defaultNameServers {
+			s.putRecord(domain.Name, godo.DomainRecord{Type: "NS", Name: "@", Data: ns, TTL: 1800})
+		}
+		if req.IPAddress != "" {
+			s.putRecord(domain.Name, godo.DomainRecord{Type: "A", Name: "@", Data: req.IPAddress, TTL: 1800})
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"domain": domain})
+	})
+
+	s.handle("DELETE /v2/domains/{domain}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("domain")
+		if !s.Domains.Delete(name) {
+			notFound(w)
+			return
+		}
+		for _, record := range s.domainRecords(name) {
+			s.DomainRecords.Delete(record.ID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/domains/{domain}/records", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("domain")
+		if _, ok := s.Domains.Get(name); !ok {
+			notFound(w)
+			return
+		}
+		// Like the real API, the name filter takes a fully qualified name
+		recordType := r.URL.Query().Get("type")
+		recordName := r.URL.Query().Get("name")
+		records := []godo.DomainRecord{}
+		for _, record := range s.domainRecords(name) {
+			if recordType != "" && record.Type != recordType {
+				continue
+			}
+			if recordName != "" && qualifiedName(record.Name, name) != recordName {
+				continue
+			}
+			records = append(records, record.DomainRecord)
+		}
+		listResponse(w, r, "domain_records", records)
+	})
+
+	s.handle("GET /v2/domains/{domain}/records/{id}", func(w http.ResponseWriter, r *http.Request) {
+		record, ok := s.findRecord(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"domain_record": record.DomainRecord})
+	})
+
+	s.handle("POST /v2/domains/{domain}/records", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("domain")
+		if _, ok := s.Domains.Get(name); !ok {
+			notFound(w)
+			return
+		}
+		var req godo.DomainRecordEditRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := validateRecord(req); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if req.TTL == 0 {
+			req.TTL = 1800
+		}
+		record := s.putRecord(name, recordFromRequest(req))
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"domain_record": record})
+	})
+
+	s.handle("PUT /v2/domains/{domain}/records/{id}", func(w http.ResponseWriter, r *http.Request) {
+		record, ok := s.findRecord(w, r)
+		if !ok {
+			return
+		}
+		var req godo.DomainRecordEditRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Type != "" && req.Type != record.Type {
+			writeError(w, http.StatusUnprocessableEntity, "type cannot be changed")
+			return
+		}
+		req.Type = record.Type
+		if message := validateRecord(req); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		id := record.ID
+		record.DomainRecord = recordFromRequest(req)
+		record.ID = id
+		s.DomainRecords.Put(id, record)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"domain_record": record.DomainRecord})
+	})
+
+	s.handle("DELETE /v2/domains/{domain}/records/{id}", func(w http.ResponseWriter, r *http.Request) {
+		record, ok := s.findRecord(w, r)
+		if !ok {
+			return
+		}
+		s.DomainRecords.Delete(record.ID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// AddDomain stores a domain with the given records and returns their IDs.
+func (s *Server) AddDomain(name string, records ...godo.DomainRecord) []int {
+	s.Domains.Put(name, godo.Domain{Name: name, TTL: 1800})
+	ids := make([]int, len(records))
+	for i, record := range records {
+		if record.TTL == 0 {
+			record.TTL = 1800
+		}
+		ids[i] = s.putRecord(name, record).ID
+	}
+	return ids
+}
+
+func (s *Server) putRecord(domain string, record godo.DomainRecord) godo.DomainRecord {
+	record.ID = s.NextID()
+	s.DomainRecords.Put(record.ID, DomainRecord{Domain: domain, DomainRecord: record})
+	return record
+}
+
+func (s *Server) domainRecords(domain string) []DomainRecord {
+	return s.DomainRecords.Filter(func(record DomainRecord) bool {
+		return record.Domain == domain
+	})
+}
+
+func (s *Server) findRecord(w http.ResponseWriter, r *http.Request) (DomainRecord, bool) {
+	id, ok := intPathValue(w, r, "id")
+	if !ok {
+		return DomainRecord{}, false
+	}
+	record, ok := s.DomainRecords.Get(id)
+	if !ok || record.Domain != r.PathValue("domain") {
+		notFound(w)
+		return DomainRecord{}, false
+	}
+	return record, true
+}
+
+// zoneFile renders the domain's records in BIND format.
+func (s *Server) zoneFile(domain godo.Domain) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n$TTL %d\n", domain.Name, domain.TTL)
+	for _, record := range s.domainRecords(domain.Name) {
+		data := record.Data
+		switch record.Type {
+		case "MX":
+			data = fmt.Sprintf("%d %s", record.Priority, data)
+		case "SRV":
+			data = fmt.Sprintf("%d %d %d %s", record.Priority, record.Weight, record.Port, data)
+		case "CAA":
+			data = fmt.Sprintf("%d %s %q", record.Flags, record.Tag, data)
+		case "TXT":
+			data = fmt.Sprintf("%q", data)
+		}
+		fmt.Fprintf(&b, "%s\t%d\tIN\t%s\t%s\n", record.Name, record.TTL, record.Type, data)
+	}
+	return b.String()
+}
+
+func validateRecord(req godo.DomainRecordEditRequest) string {
+	if !containsString(recordTypes, req.Type) {
+		return fmt.Sprintf("record type %q is not supported", req.Type)
+	}
+	if req.Name == "" {
+		return "name is required"
+	}
+	if req.Data == "" {
+		return "data is required"
+	}
+	if req.Type == "SRV" && req.Port == 0 {
+		return "port is required for SRV records"
+	}
+	if req.Type == "CAA" && req.Tag == "" {
+		return "tag is required for CAA records"
+	}
+	return ""
+}
+
+func recordFromRequest(req godo.DomainRecordEditRequest) godo.DomainRecord {
+	return godo.DomainRecord{
+		Type:     req.Type,
+		Name:     req.Name,
+		Data:     req.Data,
+		Priority: req.Priority,
+		Port:     req.Port,
+		TTL:      req.TTL,
+		Weight:   req.Weight,
+		Flags:    req.Flags,
+		Tag:      req.Tag,
+	}
+}
+
+func qualifiedName(name, domain string) string {
+	if name == "@" {
+		return domain
+	}
+	return name + "." + domain
+}
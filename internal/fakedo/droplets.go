@@ -0,0 +1,414 @@
+package fakedo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+type dropletCreateRequest struct {
+	Name       string            `json:"name"`
+	Names      []string          `json:"names"`
+	Region     string            `json:"region"`
+	Size       string            `json:"size"`
+	Image      json.RawMessage   `json:"image"`
+	Backups    bool              `json:"backups"`
+	IPv6       bool              `json:"ipv6"`
+	Monitoring bool              `json:"monitoring"`
+	Tags       []string          `json:"tags"`
+	VPCUUID    string            `json:"vpc_uuid"`
+	Volumes    []json.RawMessage `json:"volumes"`
+	SSHKeys    []json.RawMessage `json:"ssh_keys"`
+	UserData   string            `json:"user_data"`
+}
+
+// DropletSetup holds the parts of a droplet create request that the API does
+// not return on the droplet itself. SSH keys are IDs or fingerprints.
+type DropletSetup struct {
+	SSHKeys  []string
+	UserData string
+}
+
+func (s *Server) registerDroplets() {
+	s.handle("GET /v2/droplets", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.URL.Query().Get("tag_name")
+		droplets := s.Droplets.Filter(func(d godo.Droplet) bool {
+			return tag == "" || containsString(d.Tags, tag)
+		})
+		listResponse(w, r, "droplets", droplets)
+	})
+
+	s.handle("GET /v2/droplets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		droplet, ok := s.Droplets.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"droplet": droplet})
+	})
+
+	s.handle("POST /v2/droplets", func(w http.ResponseWriter, r *http.Request) {
+		var req dropletCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		names := req.Names
+		if len(names) == 0 {
+			names = []string{req.Name}
+		}
+		for _, name := range names {
+			if name == "" || req.Region == "" || req.Size == "" || len(req.Image) == 0 {
+				writeError(w, http.StatusUnprocessableEntity, "name, region, size and image are required")
+				return
+			}
+		}
+		size, ok := s.Sizes.Get(req.Size)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("You specified an invalid size for Droplet creation: %s", req.Size))
+			return
+		}
+
+		var volumeIDs []string
+		for _, raw := range req.Volumes {
+			var volume struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &volume); err != nil {
+				json.Unmarshal(raw, &volume.ID)
+			}
+			stored, ok := s.Volumes.Get(volume.ID)
+			if !ok || regionSlug(stored.Region) != req.Region {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("volume %s does not exist in region %s", volume.ID, req.Region))
+				return
+			}
+			volumeIDs = append(volumeIDs, volume.ID)
+		}
+
+		setup := DropletSetup{UserData: req.UserData}
+		for _, raw := range req.SSHKeys {
+			var key interface{}
+			json.Unmarshal(raw, &key)
+			switch key := key.(type) {
+			case float64:
+				setup.SSHKeys = append(setup.SSHKeys, strconv.Itoa(int(key)))
+			case string:
+				setup.SSHKeys = append(setup.SSHKeys, key)
+			}
+		}
+
+		if message := s.checkVPC(req.VPCUUID, req.Region); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		image := &godo.Image{}
+		if err := json.Unmarshal(req.Image, &image.Slug); err != nil {
+			json.Unmarshal(req.Image, &image.ID)
+		}
+
+		// Tags named on a new droplet are created if they do not exist yet
+		s.ensureTags(req.Tags)
+
+		var created []godo.Droplet
+		var links []godo.LinkAction
+		for _, name := range names {
+			droplet := godo.Droplet{
+				ID:        s.NextID(),
+				Name:      name,
+				Memory:    size.Memory,
+				Vcpus:     size.Vcpus,
+				Disk:      size.Disk,
+				Region:    &godo.Region{Slug: req.Region, Name: req.Region, Available: true},
+				Image:     image,
+				Size:      &size,
+				SizeSlug:  size.Slug,
+				Status:    "new",
+				Created:   time.Now().UTC().Format(time.RFC3339),
+				Tags:      req.Tags,
+				VolumeIDs: volumeIDs,
+				VPCUUID:   req.VPCUUID,
+			}
+			if req.Backups {
+				droplet.Features = append(droplet.Features, "backups")
+			}
+			if req.IPv6 {
+				droplet.Features = append(droplet.Features, "ipv6")
+			}
+			if req.Monitoring {
+				droplet.Features = append(droplet.Features, "monitoring")
+			}
+			s.Droplets.Put(droplet.ID, droplet)
+			s.DropletSetups.Put(droplet.ID, setup)
+			created = append(created, droplet)
+			for _, volumeID := range volumeIDs {
+				s.Volumes.Update(volumeID, func(v *godo.Volume) {
+					v.DropletIDs = append(v.DropletIDs, droplet.ID)
+				})
+			}
+
+			rel := "create"
+			if len(req.Names) > 0 {
+				rel = "multiple_create"
+			}
+			action := s.newAction("create", "droplet", droplet.ID, req.Region)
+			links = append(links, godo.LinkAction{ID: action.ID, Rel: rel, HREF: fmt.Sprintf("%s/v2/actions/%d", s.server.URL, action.ID)})
+			if action.Status == godo.ActionCompleted {
+				s.setDropletStatus(droplet.ID, "active")
+			}
+		}
+
+		if len(req.Names) > 0 {
+			writeJSON(w, http.StatusAccepted, map[string]interface{}{"droplets": created, "links": godo.Links{Actions: links}})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"droplet": created[0], "links": godo.Links{Actions: links}})
+	})
+
+	s.handle("DELETE /v2/droplets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		if !s.Droplets.Delete(id) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("DELETE /v2/droplets", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.URL.Query().Get("tag_name")
+		if tag == "" {
+			writeError(w, http.StatusBadRequest, "tag_name is required")
+			return
+		}
+		for _, droplet := range s.dropletsWithTag(tag) {
+			s.Droplets.Delete(droplet.ID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/droplets/actions", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.URL.Query().Get("tag_name")
+		if tag == "" {
+			writeError(w, http.StatusBadRequest, "tag_name is required")
+			return
+		}
+		var req map[string]interface{}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		actionType, _ := req["type"].(string)
+		if !containsString(tagActionTypes, actionType) {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("action type %s cannot be applied by tag", actionType))
+			return
+		}
+
+		// Droplets the action does not apply to, such as powered-off
+		// droplets for power_off, are skipped.
+		actions := []godo.Action{}
+		for _, droplet := range s.dropletsWithTag(tag) {
+			if s.applyDropletAction(droplet, actionType, req) != "" {
+				continue
+			}
+			actions = append(actions, s.newAction(actionType, "droplet", droplet.ID, regionSlug(droplet.Region)))
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"actions": actions})
+	})
+
+	s.handle("GET /v2/droplets/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		if _, ok := s.Droplets.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		actions := s.Actions.Filter(func(a godo.Action) bool {
+			return a.ResourceType == "droplet" && a.ResourceID == id
+		})
+		listResponse(w, r, "actions", actions)
+	})
+
+	s.handle("GET /v2/droplets/{id}/kernels", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		if _, ok := s.Droplets.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		listResponse(w, r, "kernels", defaultKernels)
+	})
+
+	s.handle("POST /v2/droplets/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		droplet, ok := s.Droplets.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+
+		var req map[string]interface{}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		actionType, _ := req["type"].(string)
+
+		if message := s.applyDropletAction(droplet, actionType, req); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		action := s.newAction(actionType, "droplet", id, regionSlug(droplet.Region))
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"action": action})
+	})
+}
+
+// applyDropletAction changes a droplet as the action would. It returns an
+// error message for actions the API would reject.
+func (s *Server) applyDropletAction(droplet godo.Droplet, actionType string, req map[string]interface{}) string {
+	id := droplet.ID
+	switch actionType {
+	case "resize":
+		slug, _ := req["size"].(string)
+		size, ok := s.Sizes.Get(slug)
+		if !ok {
+			return fmt.Sprintf("invalid size: %s", slug)
+		}
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.Size = &size
+			d.SizeSlug = size.Slug
+			d.Memory = size.Memory
+			d.Vcpus = size.Vcpus
+		})
+	case "snapshot":
+		name, _ := req["name"].(string)
+		snapshotID := s.NextID()
+		s.Snapshots.Put(strconv.Itoa(snapshotID), godo.Snapshot{
+			ID:            strconv.Itoa(snapshotID),
+			Name:          name,
+			ResourceID:    strconv.Itoa(id),
+			ResourceType:  "droplet",
+			Regions:       []string{regionSlug(droplet.Region)},
+			SizeGigaBytes: float64(droplet.Disk),
+			MinDiskSize:   droplet.Disk,
+			Created:       time.Now().UTC().Format(time.RFC3339),
+		})
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.SnapshotIDs = append(d.SnapshotIDs, snapshotID)
+		})
+	case "power_on":
+		if droplet.Status == "active" {
+			return "Droplet is already powered on."
+		}
+		s.setDropletStatus(id, "active")
+	case "power_off", "shutdown":
+		if droplet.Status == "off" {
+			return "Droplet is already powered off."
+		}
+		s.setDropletStatus(id, "off")
+	case "power_cycle", "reboot":
+		s.setDropletStatus(id, "active")
+	case "rebuild":
+		var ref string
+		switch image := req["image"].(type) {
+		case string:
+			ref = image
+		case float64:
+			ref = strconv.FormatFloat(image, 'f', -1, 64)
+		}
+		image, ok := s.findImage(ref)
+		if !ok {
+			return fmt.Sprintf("invalid image: %s", ref)
+		}
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.Image = &image
+			d.Status = "active"
+		})
+	case "rename":
+		name, _ := req["name"].(string)
+		if name == "" {
+			return "name is required"
+		}
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.Name = name
+		})
+	case "password_reset":
+	case "enable_backups":
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			if !containsString(d.Features, "backups") {
+				d.Features = append(d.Features, "backups")
+			}
+		})
+	case "disable_backups":
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.Features = removeString(d.Features, "backups")
+			d.BackupIDs = nil
+		})
+	case "enable_ipv6":
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			if !containsString(d.Features, "ipv6") {
+				d.Features = append(d.Features, "ipv6")
+			}
+		})
+	case "change_kernel":
+		kernelID, _ := req["kernel"].(float64)
+		kernel, ok := findKernel(int(kernelID))
+		if !ok {
+			return fmt.Sprintf("invalid kernel: %d", int(kernelID))
+		}
+		s.Droplets.Update(id, func(d *godo.Droplet) {
+			d.Kernel = &kernel
+		})
+	case "":
+		return "action type is required"
+	default:
+		return fmt.Sprintf("unsupported action type: %s", actionType)
+	}
+	return ""
+}
+
+// tagActionTypes are the droplet actions the API accepts with tag_name.
+var tagActionTypes = []string{"power_cycle", "power_on", "power_off", "shutdown", "enable_ipv6", "enable_backups", "disable_backups", "snapshot"}
+
+func (s *Server) dropletsWithTag(tag string) []godo.Droplet {
+	return s.Droplets.Filter(func(d godo.Droplet) bool {
+		return containsString(d.Tags, tag)
+	})
+}
+
+func (s *Server) setDropletStatus(id int, status string) {
+	s.Droplets.Update(id, func(d *godo.Droplet) {
+		d.Status = status
+	})
+}
+
+var defaultKernels = []godo.Kernel{
+	{ID: 7515, Name: "DigitalOcean GrubLoader v0.2 (20160714)", Version: "2016.07.13"},
+	{ID: 8743, Name: "Ubuntu 22.04 x64 vmlinuz-5.15.0-25-generic", Version: "5.15.0-25-generic"},
+	{ID: 9102, Name: "Ubuntu 24.04 x64 vmlinuz-6.8.0-31-generic", Version: "6.8.0-31-generic"},
+}
+
+func findKernel(id int) (godo.Kernel, bool) {
+	for _, kernel := range defaultKernels {
+		if kernel.ID == id {
+			return kernel, true
+		}
+	}
+	return godo.Kernel{}, false
+}
@@ -0,0 +1,535 @@
+// Package fakedo implements an in-process, stateful fake of the DigitalOcean
+// API for tests. Point client.NewDOClientWithBaseURL at Server.URL() to run
+// handlers against it.
+package fakedo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/metrics"
+)
+
+// Server is a fake DigitalOcean API backed by in-memory state.
+type Server struct {
+	server *httptest.Server
+	mux    *http.ServeMux
+	// spaces serves the S3-compatible Spaces API, which has its own host.
+	spaces    *httptest.Server
+	spacesMux *http.ServeMux
+
+	mu       sync.Mutex
+	nextID   int
+	failures map[string][]failure
+	// pending counts the polls left before an in-progress action completes.
+	pending map[int]int
+
+	Droplets      *Table[int, godo.Droplet]
+	DropletSetups *Table[int, DropletSetup]
+	Volumes       *Table[string, godo.Volume]
+	Snapshots     *Table[string, godo.Snapshot]
+	Images        *Table[int, godo.Image]
+	ReservedIPs   *Table[string, godo.ReservedIP]
+	ReservedIPV6s *Table[string, godo.ReservedIPV6]
+	Firewalls     *Table[string, godo.Firewall]
+	LoadBalancers *Table[string, godo.LoadBalancer]
+	Clusters      *Table[string, godo.KubernetesCluster]
+	// ClusterResources holds the volumes, snapshots and load balancers a
+	// cluster created, keyed by cluster ID.
+	ClusterResources *Table[string, godo.KubernetesAssociatedResources]
+	Actions          *Table[int, godo.Action]
+	Sizes            *Table[string, godo.Size]
+	Regions          *Table[string, godo.Region]
+	Registry         *godo.Registry
+	Repositories     *Table[string, godo.Repository]
+	RepoTags         *Table[string, godo.RepositoryTag]
+	// Manifests are keyed by "repository@digest".
+	Manifests          *Table[string, godo.RepositoryManifest]
+	GarbageCollections *Table[string, godo.GarbageCollection]
+	Domains            *Table[string, godo.Domain]
+	DomainRecords      *Table[int, DomainRecord]
+	Tags               *Table[string, godo.Tag]
+	VPCs               *Table[string, godo.VPC]
+	VPCPeerings        *Table[string, godo.VPCPeering]
+	Databases          *Table[string, DatabaseCluster]
+	Apps               *Table[string, godo.App]
+	AppDeployments     *Table[string, AppDeployment]
+	SSHKeys            *Table[int, godo.Key]
+	BillingHistory     *Table[int, godo.BillingHistoryEntry]
+	Invoices           *Table[string, Invoice]
+	Projects           *Table[string, godo.Project]
+	ProjectResources   *Table[string, ProjectResource]
+	AlertPolicies      *Table[string, godo.AlertPolicy]
+	Account            godo.Account
+	Balance            godo.Balance
+	// InvoicePreview is the month-to-date usage returned with the invoice list.
+	InvoicePreview godo.InvoiceListItem
+	// KubernetesOptions lists the versions, regions and node sizes DOKS
+	// accepts.
+	KubernetesOptions godo.KubernetesOptions
+	// DropletMetrics holds the series SetDropletMetrics stored, keyed by
+	// "dropletID/metric".
+	DropletMetrics *Table[string, []metrics.SampleStream]
+	// SpacesKeys are Spaces access keys keyed by access key, with their
+	// secrets.
+	SpacesKeys    *Table[string, godo.SpacesKey]
+	SpacesBuckets *Table[string, SpacesBucket]
+	// SpacesObjects are keyed by "bucket/key".
+	SpacesObjects *Table[string, SpacesObject]
+	Certificates  *Table[string, godo.Certificate]
+	CDNEndpoints  *Table[string, godo.CDN]
+	// CachePurges records the paths purged from each CDN endpoint's cache.
+	CachePurges  *Table[string, []string]
+	UptimeChecks *Table[string, godo.UptimeCheck]
+	UptimeAlerts *Table[string, UptimeAlert]
+	// UptimeStates holds the status each check reports per region, keyed by
+	// check ID.
+	UptimeStates *Table[string, godo.UptimeCheckState]
+	// FunctionsNamespaces are keyed by namespace ID and FunctionsTriggers
+	// by "namespace/name".
+	FunctionsNamespaces *Table[string, godo.FunctionsNamespace]
+	FunctionsTriggers   *Table[string, godo.FunctionsTrigger]
+
+	// ActionPolls is how many times GET /v2/actions/{id} reports a new action
+	// as in-progress before it completes. Zero completes actions immediately.
+	ActionPolls int
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// New starts a fake API server. It is closed automatically when the test ends.
+func New(t interface {
+	Cleanup(func())
+}) *Server {
+	s := &Server{
+		mux:                 http.NewServeMux(),
+		spacesMux:           http.NewServeMux(),
+		nextID:              1000,
+		failures:            make(map[string][]failure),
+		pending:             make(map[int]int),
+		Droplets:            NewTable[int, godo.Droplet](),
+		DropletSetups:       NewTable[int, DropletSetup](),
+		Volumes:             NewTable[string, godo.Volume](),
+		Snapshots:           NewTable[string, godo.Snapshot](),
+		Images:              NewTable[int, godo.Image](),
+		ReservedIPs:         NewTable[string, godo.ReservedIP](),
+		ReservedIPV6s:       NewTable[string, godo.ReservedIPV6](),
+		Firewalls:           NewTable[string, godo.Firewall](),
+		LoadBalancers:       NewTable[string, godo.LoadBalancer](),
+		Clusters:            NewTable[string, godo.KubernetesCluster](),
+		ClusterResources:    NewTable[string, godo.KubernetesAssociatedResources](),
+		Actions:             NewTable[int, godo.Action](),
+		Sizes:               NewTable[string, godo.Size](),
+		Regions:             NewTable[string, godo.Region](),
+		Repositories:        NewTable[string, godo.Repository](),
+		RepoTags:            NewTable[string, godo.RepositoryTag](),
+		Manifests:           NewTable[string, godo.RepositoryManifest](),
+		GarbageCollections:  NewTable[string, godo.GarbageCollection](),
+		Domains:             NewTable[string, godo.Domain](),
+		DomainRecords:       NewTable[int, DomainRecord](),
+		Tags:                NewTable[string, godo.Tag](),
+		VPCs:                NewTable[string, godo.VPC](),
+		VPCPeerings:         NewTable[string, godo.VPCPeering](),
+		Databases:           NewTable[string, DatabaseCluster](),
+		Apps:                NewTable[string, godo.App](),
+		AppDeployments:      NewTable[string, AppDeployment](),
+		SSHKeys:             NewTable[int, godo.Key](),
+		BillingHistory:      NewTable[int, godo.BillingHistoryEntry](),
+		Invoices:            NewTable[string, Invoice](),
+		Projects:            NewTable[string, godo.Project](),
+		ProjectResources:    NewTable[string, ProjectResource](),
+		AlertPolicies:       NewTable[string, godo.AlertPolicy](),
+		DropletMetrics:      NewTable[string, []metrics.SampleStream](),
+		SpacesKeys:          NewTable[string, godo.SpacesKey](),
+		SpacesBuckets:       NewTable[string, SpacesBucket](),
+		SpacesObjects:       NewTable[string, SpacesObject](),
+		Certificates:        NewTable[string, godo.Certificate](),
+		CDNEndpoints:        NewTable[string, godo.CDN](),
+		CachePurges:         NewTable[string, []string](),
+		UptimeChecks:        NewTable[string, godo.UptimeCheck](),
+		UptimeAlerts:        NewTable[string, UptimeAlert](),
+		UptimeStates:        NewTable[string, godo.UptimeCheckState](),
+		FunctionsNamespaces: NewTable[string, godo.FunctionsNamespace](),
+		FunctionsTriggers:   NewTable[string, godo.FunctionsTrigger](),
+		Account: godo.Account{
+			DropletLimit:  25,
+			Email:         "test@example.com",
+			UUID:          "account-uuid",
+			EmailVerified: true,
+			Status:        "active",
+		},
+		Balance: godo.Balance{
+			MonthToDateBalance: "0.00",
+			AccountBalance:     "0.00",
+			MonthToDateUsage:   "0.00",
+			GeneratedAt:        time.Now().UTC(),
+		},
+		KubernetesOptions: godo.KubernetesOptions{
+			Versions: []*godo.KubernetesVersion{
+				{Slug: "1.30.2-do.0", KubernetesVersion: "1.30.2"},
+				{Slug: "1.29.1-do.0", KubernetesVersion: "1.29.1"},
+			},
+			Regions: []*godo.KubernetesRegion{
+				{Slug: "nyc1", Name: "New York 1"},
+				{Slug: "nyc3", Name: "New York 3"},
+				{Slug: "sfo3", Name: "San Francisco 3"},
+				{Slug: "ams3", Name: "Amsterdam 3"},
+			},
+			Sizes: []*godo.KubernetesNodeSize{
+				{Slug: "s-1vcpu-2gb", Name: "s-1vcpu-2gb"},
+				{Slug: "s-2vcpu-2gb", Name: "s-2vcpu-2gb"},
+				{Slug: "s-2vcpu-4gb", Name: "s-2vcpu-4gb"},
+				{Slug: "s-4vcpu-8gb", Name: "s-4vcpu-8gb"},
+			},
+		},
+		InvoicePreview: godo.InvoiceListItem{
+			InvoiceUUID:   "preview",
+			Amount:        "0.00",
+			InvoicePeriod: time.Now().UTC().Format("2006-01"),
+			UpdatedAt:     time.Now().UTC(),
+		},
+	}
+
+	for _, size := range defaultSizes {
+		s.Sizes.Put(size.Slug, size)
+	}
+	// Every account has a default project
+	s.Projects.Put(defaultProjectID, godo.Project{
+		ID:          defaultProjectID,
+		OwnerUUID:   s.Account.UUID,
+		Name:        "Default",
+		Description: "Default project",
+		Purpose:     "Other",
+		Environment: "Production",
+		IsDefault:   true,
+	})
+	for _, region := range defaultRegions {
+		// A region offers the sizes that list it
+		region.Sizes = []string{}
+		for _, size := range defaultSizes {
+			if containsString(size.Regions, region.Slug) {
+				region.Sizes = append(region.Sizes, size.Slug)
+			}
+		}
+		s.Regions.Put(region.Slug, region)
+	}
+
+	s.registerDroplets()
+	s.registerVolumes()
+	s.registerSnapshots()
+	s.registerImages()
+	s.registerReservedIPs()
+	s.registerFirewalls()
+	s.registerLoadBalancers()
+	s.registerKubernetes()
+	s.registerRegistry()
+	s.registerDomains()
+	s.registerTags()
+	s.registerVPCs()
+	s.registerDatabases()
+	s.registerApps()
+	s.registerSSHKeys()
+	s.registerBilling()
+	s.registerProjects()
+	s.registerMonitoring()
+	s.registerSpacesKeys()
+	s.registerSpaces()
+	s.registerCertificates()
+	s.registerCDN()
+	s.registerUptime()
+	s.registerFunctions()
+	s.registerAccount()
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+	s.spaces = httptest.NewServer(http.HandlerFunc(s.serveSpaces))
+	t.Cleanup(s.spaces.Close)
+
+	return s
+}
+
+// URL returns the base URL to pass to godo.SetBaseURL.
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// FailNext makes the next request matching method and path (e.g. "GET",
+// "/v2/droplets/1") fail with the given status and message.
+func (s *Server) FailNext(method, path string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + path
+	s.failures[key] = append(s.failures[key], failure{status: status, message: message})
+}
+
+// NextID returns a fresh numeric ID shared by every resource type.
+func (s *Server) NextID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return s.nextID
+}
+
+// NextUUID returns a fresh string ID.
+func (s *Server) NextUUID(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, s.NextID())
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	key := r.Method + " " + r.URL.Path
+	var injected *failure
+	if queue := s.failures[key]; len(queue) > 0 {
+		injected = &queue[0]
+		s.failures[key] = queue[1:]
+	}
+	s.mu.Unlock()
+
+	if injected != nil {
+		writeError(w, injected.status, injected.message)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// newAction records an action against a resource. It is completed unless
+// ActionPolls is set, in which case it completes after that many polls.
+func (s *Server) newAction(actionType, resourceType string, resourceID int, region string) godo.Action {
+	now := &godo.Timestamp{Time: time.Now().UTC()}
+	action := godo.Action{
+		ID:           s.NextID(),
+		Status:       godo.ActionCompleted,
+		Type:         actionType,
+		StartedAt:    now,
+		CompletedAt:  now,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		RegionSlug:   region,
+	}
+	s.mu.Lock()
+	if s.ActionPolls > 0 {
+		action.Status = godo.ActionInProgress
+		action.CompletedAt = nil
+		s.pending[action.ID] = s.ActionPolls
+	}
+	s.mu.Unlock()
+	s.Actions.Put(action.ID, action)
+	return action
+}
+
+// pollAction returns an action as a client polling it would see it, advancing
+// in-progress actions towards completion.
+func (s *Server) pollAction(id int) (godo.Action, bool) {
+	s.mu.Lock()
+	remaining, pending := s.pending[id]
+	if pending {
+		if remaining > 0 {
+			s.pending[id] = remaining - 1
+		} else {
+			delete(s.pending, id)
+		}
+	}
+	s.mu.Unlock()
+
+	if pending && remaining == 0 {
+		s.Actions.Update(id, func(a *godo.Action) {
+			if a.Status == godo.ActionInProgress {
+				a.Status = godo.ActionCompleted
+				a.CompletedAt = &godo.Timestamp{Time: time.Now().UTC()}
+			}
+		})
+	}
+	action, ok := s.Actions.Get(id)
+	if ok && action.Type == "create" && action.ResourceType == "droplet" && action.Status == godo.ActionCompleted {
+		s.setDropletStatus(action.ResourceID, "active")
+	}
+	return action, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	id := "error"
+	switch status {
+	case http.StatusNotFound:
+		id = "not_found"
+	case http.StatusUnprocessableEntity:
+		id = "unprocessable_entity"
+	case http.StatusUnauthorized:
+		id = "unauthorized"
+	case http.StatusTooManyRequests:
+		id = "too_many_requests"
+	}
+	writeJSON(w, status, map[string]string{
+		"id":      id,
+		"message": message,
+	})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "The resource you were accessing could not be found.")
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func intPathValue(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		notFound(w)
+		return 0, false
+	}
+	return value, true
+}
+
+// paginate slices items according to the page and per_page query parameters
+// and builds the links and meta objects the real API returns.
+func paginate[T any](r *http.Request, items []T) ([]T, *godo.Links, *godo.Meta) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 20
+	}
+	if perPage > 200 {
+		perPage = 200
+	}
+
+	total := len(items)
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	pageURL := func(p int) string {
+		u := *r.URL
+		u.Scheme = "http"
+		u.Host = r.Host
+		q := u.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	pages := &godo.Pages{}
+	if page > 1 {
+		pages.First = pageURL(1)
+		pages.Prev = pageURL(page - 1)
+	}
+	if page < lastPage {
+		pages.Next = pageURL(page + 1)
+		pages.Last = pageURL(lastPage)
+	}
+
+	return items[start:end], &godo.Links{Pages: pages}, &godo.Meta{Total: total}
+}
+
+// listResponse writes a paginated list under key.
+func listResponse[T any](w http.ResponseWriter, r *http.Request, key string, items []T) {
+	if items == nil {
+		items = []T{}
+	}
+	pageItems, links, meta := paginate(r, items)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		key:     pageItems,
+		"links": links,
+		"meta":  meta,
+	})
+}
+
+var defaultRegions = []godo.Region{
+	{Slug: "nyc1", Name: "New York 1", Available: true, Features: []string{"backups", "ipv6", "metadata", "install_agent"}},
+	{Slug: "nyc2", Name: "New York 2", Available: false, Features: []string{"backups", "ipv6", "metadata"}},
+	{Slug: "nyc3", Name: "New York 3", Available: true, Features: []string{"backups", "ipv6", "metadata", "install_agent", "storage", "image_transfer"}},
+	{Slug: "sfo3", Name: "San Francisco 3", Available: true, Features: []string{"backups", "ipv6", "metadata", "install_agent", "storage", "image_transfer"}},
+	{Slug: "ams3", Name: "Amsterdam 3", Available: true, Features: []string{"backups", "ipv6", "metadata", "install_agent", "storage", "image_transfer"}},
+}
+
+var defaultSizes = []godo.Size{
+	{Slug: "s-1vcpu-1gb", Memory: 1024, Vcpus: 1, Disk: 25, PriceMonthly: 6, PriceHourly: 0.00893, Available: true, Transfer: 1, Regions: []string{"nyc1", "nyc3", "sfo3", "ams3"}},
+	{Slug: "s-1vcpu-2gb", Memory: 2048, Vcpus: 1, Disk: 50, PriceMonthly: 12, PriceHourly: 0.01786, Available: true, Transfer: 2, Regions: []string{"nyc1", "nyc3", "sfo3", "ams3"}},
+	{Slug: "s-2vcpu-2gb", Memory: 2048, Vcpus: 2, Disk: 60, PriceMonthly: 18, PriceHourly: 0.02679, Available: true, Transfer: 3, Regions: []string{"nyc1", "nyc3", "sfo3", "ams3"}},
+	{Slug: "s-2vcpu-4gb", Memory: 4096, Vcpus: 2, Disk: 80, PriceMonthly: 24, PriceHourly: 0.03571, Available: true, Transfer: 4, Regions: []string{"nyc3", "sfo3", "ams3"}},
+	{Slug: "s-4vcpu-8gb", Memory: 8192, Vcpus: 4, Disk: 160, PriceMonthly: 48, PriceHourly: 0.07143, Available: true, Transfer: 5, Regions: []string{"nyc3", "ams3"}},
+	{Slug: "s-8vcpu-16gb", Memory: 16384, Vcpus: 8, Disk: 320, PriceMonthly: 96, PriceHourly: 0.14286, Available: false, Transfer: 6, Regions: []string{}},
+}
+
+func regionSlug(region *godo.Region) string {
+	if region == nil {
+		return ""
+	}
+	return region.Slug
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeInt(values []int, value int) []int {
+	result := []int{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func removeString(values []string, value string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
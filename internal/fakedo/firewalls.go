@@ -0,0 +1,166 @@
+package fakedo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerFirewalls() {
+	s.handle("GET /v2/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "firewalls", s.Firewalls.List())
+	})
+
+	s.handle("GET /v2/firewalls/{id}", func(w http.ResponseWriter, r *http.Request) {
+		firewall, ok := s.Firewalls.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"firewall": firewall})
+	})
+
+	s.handle("POST /v2/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.FirewallRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+
+		firewall := godo.Firewall{
+			ID:            s.NextUUID("fw"),
+			Name:          req.Name,
+			Status:        "succeeded",
+			InboundRules:  req.InboundRules,
+			OutboundRules: req.OutboundRules,
+			DropletIDs:    req.DropletIDs,
+			Tags:          req.Tags,
+			Created:       time.Now().UTC().Format(time.RFC3339),
+		}
+		s.Firewalls.Put(firewall.ID, firewall)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"firewall": firewall})
+	})
+
+	s.handle("PUT /v2/firewalls/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.FirewallRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.Firewalls.Update(id, func(f *godo.Firewall) {
+			f.Name = req.Name
+			f.InboundRules = req.InboundRules
+			f.OutboundRules = req.OutboundRules
+			f.DropletIDs = req.DropletIDs
+			f.Tags = req.Tags
+		}) {
+			notFound(w)
+			return
+		}
+		firewall, _ := s.Firewalls.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"firewall": firewall})
+	})
+
+	s.handle("DELETE /v2/firewalls/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.Firewalls.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("/v2/firewalls/{id}/droplets", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			DropletIDs []int `json:"droplet_ids"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.Firewalls.Update(r.PathValue("id"), func(f *godo.Firewall) {
+			for _, id := range req.DropletIDs {
+				f.DropletIDs = removeInt(f.DropletIDs, id)
+				if r.Method == http.MethodPost {
+					f.DropletIDs = append(f.DropletIDs, id)
+				}
+			}
+		}) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("/v2/firewalls/{id}/tags", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tags []string `json:"tags"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.Firewalls.Update(r.PathValue("id"), func(f *godo.Firewall) {
+			for _, tag := range req.Tags {
+				f.Tags = removeString(f.Tags, tag)
+				if r.Method == http.MethodPost {
+					f.Tags = append(f.Tags, tag)
+				}
+			}
+		}) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("/v2/firewalls/{id}/rules", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.FirewallRulesRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.Firewalls.Update(r.PathValue("id"), func(f *godo.Firewall) {
+			if r.Method == http.MethodPost {
+				f.InboundRules = append(f.InboundRules, req.InboundRules...)
+				f.OutboundRules = append(f.OutboundRules, req.OutboundRules...)
+				return
+			}
+			f.InboundRules = removeRules(f.InboundRules, req.InboundRules)
+			f.OutboundRules = removeRules(f.OutboundRules, req.OutboundRules)
+		}) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// removeRules drops every rule in remove that has a matching protocol and
+// port range.
+func removeRules[T godo.InboundRule | godo.OutboundRule](rules, remove []T) []T {
+	result := []T{}
+	for _, rule := range rules {
+		keep := true
+		for _, r := range remove {
+			if ruleKey(rule) == ruleKey(r) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func ruleKey[T godo.InboundRule | godo.OutboundRule](rule T) string {
+	switch r := any(rule).(type) {
+	case godo.InboundRule:
+		return r.Protocol + "/" + r.PortRange
+	case godo.OutboundRule:
+		return r.Protocol + "/" + r.PortRange
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+var functionsRegions = []string{"ams3", "blr1", "fra1", "lon1", "nyc1", "sfo3", "sgp1", "syd1", "tor1"}
+
+func (s *Server) registerFunctions() {
+	s.handle("GET /v2/functions/namespaces", func(w http.ResponseWriter, r *http.Request) {
+		// The endpoint is not paginated
+		writeJSON(w, http.StatusOK, map[string]interface{}{"namespaces": s.FunctionsNamespaces.List()})
+	})
+
+	s.handle("GET /v2/functions/namespaces/{ns}", func(w http.ResponseWriter, r *http.Request) {
+		namespace, ok := s.FunctionsNamespaces.Get(r.PathValue("ns"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"namespace": namespace})
+	})
+
+	s.handle("POST /v2/functions/namespaces", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.FunctionsNamespaceCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		switch {
+		case req.Label == "":
+			writeError(w, http.StatusUnprocessableEntity, "label is required")
+			return
+		case !containsString(functionsRegions, req.Region):
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid region: %s", req.Region))
+			return
+		case len(s.FunctionsNamespaces.Filter(func(n godo.FunctionsNamespace) bool { return n.Label == req.Label })) > 0:
+			writeError(w, http.StatusUnprocessableEntity, "a namespace with this label already exists")
+			return
+		}
+
+		id := s.NextUUID("fn")
+		now := time.Now().UTC().Truncate(time.Second)
+		namespace := godo.FunctionsNamespace{
+			ApiHost:   fmt.Sprintf("https://faas-%s-%s.doserverless.co", req.Region, strings.TrimPrefix(id, "fn-")),
+			Namespace: id,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Label:     req.Label,
+			Region:    req.Region,
+			UUID:      s.NextUUID("uuid"),
+			Key:       s.NextUUID("key"),
+		}
+		s.FunctionsNamespaces.Put(id, namespace)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"namespace": namespace})
+	})
+
+	s.handle("DELETE /v2/functions/namespaces/{ns}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("ns")
+		if !s.FunctionsNamespaces.Delete(id) {
+			notFound(w)
+			return
+		}
+		for _, trigger := range s.functionsTriggers(id) {
+			s.FunctionsTriggers.Delete(triggerKey(id, trigger.Name))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/functions/namespaces/{ns}/triggers", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("ns")
+		if _, ok := s.FunctionsNamespaces.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"triggers": s.functionsTriggers(id)})
+	})
+
+	s.handle("GET /v2/functions/namespaces/{ns}/triggers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		trigger, ok := s.FunctionsTriggers.Get(triggerKey(r.PathValue("ns"), r.PathValue("name")))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"trigger": trigger})
+	})
+
+	s.handle("POST /v2/functions/namespaces/{ns}/triggers", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("ns")
+		var req godo.FunctionsTriggerCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.FunctionsNamespaces.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		switch {
+		case req.Name == "" || req.Function == "":
+			writeError(w, http.StatusUnprocessableEntity, "name and function are required")
+			return
+		case req.Type != "SCHEDULED":
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid type: %s", req.Type))
+			return
+		case !validCron(req.ScheduledDetails):
+			writeError(w, http.StatusUnprocessableEntity, "scheduled_details.cron must have 5 fields")
+			return
+		}
+		if _, ok := s.FunctionsTriggers.Get(triggerKey(id, req.Name)); ok {
+			writeError(w, http.StatusConflict, "a trigger with this name already exists")
+			return
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		trigger := godo.FunctionsTrigger{
+			Namespace:        id,
+			Function:         req.Function,
+			Type:             req.Type,
+			Name:             req.Name,
+			IsEnabled:        req.IsEnabled,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			ScheduledDetails: req.ScheduledDetails,
+		}
+		s.FunctionsTriggers.Put(triggerKey(id, req.Name), trigger)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"trigger": trigger})
+	})
+
+	s.handle("PUT /v2/functions/namespaces/{ns}/triggers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		key := triggerKey(r.PathValue("ns"), r.PathValue("name"))
+		var req godo.FunctionsTriggerUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.FunctionsTriggers.Get(key); !ok {
+			notFound(w)
+			return
+		}
+		if req.ScheduledDetails != nil && !validCron(req.ScheduledDetails) {
+			writeError(w, http.StatusUnprocessableEntity, "scheduled_details.cron must have 5 fields")
+			return
+		}
+
+		s.FunctionsTriggers.Update(key, func(trigger *godo.FunctionsTrigger) {
+			if req.IsEnabled != nil {
+				trigger.IsEnabled = *req.IsEnabled
+			}
+			if req.ScheduledDetails != nil {
+				trigger.ScheduledDetails = req.ScheduledDetails
+			}
+			trigger.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		})
+		trigger, _ := s.FunctionsTriggers.Get(key)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"trigger": trigger})
+	})
+
+	s.handle("DELETE /v2/functions/namespaces/{ns}/triggers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.FunctionsTriggers.Delete(triggerKey(r.PathValue("ns"), r.PathValue("name"))) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) functionsTriggers(namespace string) []godo.FunctionsTrigger {
+	return s.FunctionsTriggers.Filter(func(trigger godo.FunctionsTrigger) bool {
+		return trigger.Namespace == namespace
+	})
+}
+
+func triggerKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func validCron(details *godo.TriggerScheduledDetails) bool {
+	return details != nil && len(strings.Fields(details.Cron)) == 5
+}
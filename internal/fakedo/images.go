@@ -0,0 +1,125 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerImages() {
+	s.handle("GET /v2/images", func(w http.ResponseWriter, r *http.Request) {
+		imageType := r.URL.Query().Get("type")
+		private := r.URL.Query().Get("private") == "true"
+		images := s.Images.Filter(func(image godo.Image) bool {
+			if private {
+				return !image.Public
+			}
+			switch imageType {
+			case "distribution":
+				return image.Public && image.Type != "application"
+			case "application":
+				return image.Type == "application"
+			}
+			return true
+		})
+		listResponse(w, r, "images", images)
+	})
+
+	s.handle("GET /v2/images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		image, ok := s.findImage(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"image": image})
+	})
+
+	s.handle("PUT /v2/images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		var req godo.ImageUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.Images.Update(id, func(image *godo.Image) {
+			if req.Name != "" {
+				image.Name = req.Name
+			}
+			if req.Description != "" {
+				image.Description = req.Description
+			}
+		}) {
+			notFound(w)
+			return
+		}
+		image, _ := s.Images.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"image": image})
+	})
+
+	s.handle("DELETE /v2/images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		if !s.Images.Delete(id) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/images/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intPathValue(w, r, "id")
+		if !ok {
+			return
+		}
+		if _, ok := s.Images.Get(id); !ok {
+			notFound(w)
+			return
+		}
+
+		var req struct {
+			Type   string `json:"type"`
+			Region string `json:"region"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		switch req.Type {
+		case "transfer":
+			s.Images.Update(id, func(image *godo.Image) {
+				if !containsString(image.Regions, req.Region) {
+					image.Regions = append(image.Regions, req.Region)
+				}
+			})
+		case "convert":
+			s.Images.Update(id, func(image *godo.Image) {
+				image.Type = "snapshot"
+			})
+		default:
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("unsupported image action: %s", req.Type))
+			return
+		}
+
+		action := s.newAction(req.Type, "image", id, req.Region)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"action": action})
+	})
+}
+
+// findImage looks an image up by numeric ID or by slug.
+func (s *Server) findImage(idOrSlug string) (godo.Image, bool) {
+	if id, err := strconv.Atoi(idOrSlug); err == nil {
+		return s.Images.Get(id)
+	}
+	for _, image := range s.Images.List() {
+		if image.Slug == idOrSlug {
+			return image, true
+		}
+	}
+	return godo.Image{}, false
+}
@@ -0,0 +1,311 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerKubernetes() {
+	s.handle("GET /v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "kubernetes_clusters", s.Clusters.List())
+	})
+
+	s.handle("GET /v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"options": s.KubernetesOptions})
+	})
+
+	s.handle("GET /v2/kubernetes/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.Clusters.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"kubernetes_cluster": cluster})
+	})
+
+	s.handle("POST /v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.KubernetesClusterCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.RegionSlug == "" || req.VersionSlug == "" || len(req.NodePools) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "name, region, version and node_pools are required")
+			return
+		}
+		if message := s.checkVPC(req.VPCUUID, req.RegionSlug); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		cluster := godo.KubernetesCluster{
+			ID:          s.NextUUID("k8s"),
+			Name:        req.Name,
+			RegionSlug:  req.RegionSlug,
+			VersionSlug: req.VersionSlug,
+			VPCUUID:     req.VPCUUID,
+			Tags:        req.Tags,
+			Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusProvisioning},
+			CreatedAt:   time.Now().UTC(),
+		}
+		for _, poolReq := range req.NodePools {
+			if _, ok := s.Sizes.Get(poolReq.Size); !ok {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid node pool size: %s", poolReq.Size))
+				return
+			}
+			cluster.NodePools = append(cluster.NodePools, s.newNodePool(poolReq))
+		}
+
+		s.Clusters.Put(cluster.ID, cluster)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"kubernetes_cluster": cluster})
+	})
+
+	s.handle("DELETE /v2/kubernetes/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !s.Clusters.Delete(id) {
+			notFound(w)
+			return
+		}
+		s.ClusterResources.Delete(id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/kubernetes/clusters/{id}/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.Clusters.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		fmt.Fprintf(w, kubeconfigTemplate, cluster.ID, cluster.Name, cluster.Name, cluster.Name, cluster.Name, cluster.Name)
+	})
+
+	s.handle("GET /v2/kubernetes/clusters/{id}/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.Clusters.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		listResponse(w, r, "node_pools", cluster.NodePools)
+	})
+
+	s.handle("GET /v2/kubernetes/clusters/{id}/node_pools/{pool}", func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := s.Clusters.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		for _, pool := range cluster.NodePools {
+			if pool.ID == r.PathValue("pool") {
+				writeJSON(w, http.StatusOK, map[string]interface{}{"node_pool": pool})
+				return
+			}
+		}
+		notFound(w)
+	})
+
+	s.handle("POST /v2/kubernetes/clusters/{id}/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.KubernetesNodePoolCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Count <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "name and count are required")
+			return
+		}
+		if _, ok := s.Sizes.Get(req.Size); !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid node pool size: %s", req.Size))
+			return
+		}
+
+		pool := s.newNodePool(&req)
+		if !s.Clusters.Update(r.PathValue("id"), func(c *godo.KubernetesCluster) {
+			c.NodePools = append(clonePools(c.NodePools), pool)
+		}) {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"node_pool": pool})
+	})
+
+	s.handle("PUT /v2/kubernetes/clusters/{id}/node_pools/{pool}", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.KubernetesNodePoolUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.MinNodes != nil && req.MaxNodes != nil && *req.MinNodes > *req.MaxNodes {
+			writeError(w, http.StatusUnprocessableEntity, "min_nodes must not exceed max_nodes")
+			return
+		}
+
+		pool, ok := s.updateNodePool(r.PathValue("id"), r.PathValue("pool"), func(pool *godo.KubernetesNodePool) {
+			if req.Name != "" {
+				pool.Name = req.Name
+			}
+			if req.Count != nil {
+				for len(pool.Nodes) < *req.Count {
+					pool.Nodes = append(pool.Nodes, s.newNode(pool.Name))
+				}
+				pool.Nodes = pool.Nodes[:*req.Count]
+				pool.Count = *req.Count
+			}
+			if req.Tags != nil {
+				pool.Tags = req.Tags
+			}
+			if req.Labels != nil {
+				pool.Labels = req.Labels
+			}
+			if req.Taints != nil {
+				pool.Taints = *req.Taints
+			}
+			if req.AutoScale != nil {
+				pool.AutoScale = *req.AutoScale
+			}
+			if req.MinNodes != nil {
+				pool.MinNodes = *req.MinNodes
+			}
+			if req.MaxNodes != nil {
+				pool.MaxNodes = *req.MaxNodes
+			}
+		})
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"node_pool": pool})
+	})
+
+	s.handle("DELETE /v2/kubernetes/clusters/{id}/node_pools/{pool}", func(w http.ResponseWriter, r *http.Request) {
+		poolID := r.PathValue("pool")
+		found := false
+		s.Clusters.Update(r.PathValue("id"), func(c *godo.KubernetesCluster) {
+			pools := []*godo.KubernetesNodePool{}
+			for _, pool := range c.NodePools {
+				if pool.ID == poolID {
+					found = true
+					continue
+				}
+				pools = append(pools, pool)
+			}
+			c.NodePools = pools
+		})
+		if !found {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("DELETE /v2/kubernetes/clusters/{id}/node_pools/{pool}/nodes/{node}", func(w http.ResponseWriter, r *http.Request) {
+		nodeID := r.PathValue("node")
+		replace := r.URL.Query().Get("replace") == "1"
+		found := false
+		_, ok := s.updateNodePool(r.PathValue("id"), r.PathValue("pool"), func(pool *godo.KubernetesNodePool) {
+			nodes := []*godo.KubernetesNode{}
+			for _, node := range pool.Nodes {
+				if node.ID == nodeID {
+					found = true
+					continue
+				}
+				nodes = append(nodes, node)
+			}
+			if !found {
+				return
+			}
+			if replace {
+				nodes = append(nodes, s.newNode(pool.Name))
+			} else {
+				pool.Count--
+			}
+			pool.Nodes = nodes
+		})
+		if !ok || !found {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	s.handle("GET /v2/kubernetes/clusters/{id}/destroy_with_associated_resources", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.Clusters.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		resources, _ := s.ClusterResources.Get(id)
+		writeJSON(w, http.StatusOK, resources)
+	})
+}
+
+// updateNodePool applies fn to a copy of the pool so earlier responses that
+// share the old pointer are not modified.
+func (s *Server) updateNodePool(clusterID, poolID string, fn func(*godo.KubernetesNodePool)) (*godo.KubernetesNodePool, bool) {
+	var updated *godo.KubernetesNodePool
+	s.Clusters.Update(clusterID, func(c *godo.KubernetesCluster) {
+		c.NodePools = clonePools(c.NodePools)
+		for _, pool := range c.NodePools {
+			if pool.ID == poolID {
+				fn(pool)
+				updated = pool
+			}
+		}
+	})
+	return updated, updated != nil
+}
+
+func clonePools(pools []*godo.KubernetesNodePool) []*godo.KubernetesNodePool {
+	cloned := make([]*godo.KubernetesNodePool, len(pools))
+	for i, pool := range pools {
+		copied := *pool
+		copied.Nodes = append([]*godo.KubernetesNode(nil), pool.Nodes...)
+		cloned[i] = &copied
+	}
+	return cloned
+}
+
+func (s *Server) newNodePool(req *godo.KubernetesNodePoolCreateRequest) *godo.KubernetesNodePool {
+	pool := &godo.KubernetesNodePool{
+		ID:        s.NextUUID("pool"),
+		Name:      req.Name,
+		Size:      req.Size,
+		Count:     req.Count,
+		Tags:      req.Tags,
+		Labels:    req.Labels,
+		Taints:    req.Taints,
+		AutoScale: req.AutoScale,
+		MinNodes:  req.MinNodes,
+		MaxNodes:  req.MaxNodes,
+	}
+	for i := 0; i < req.Count; i++ {
+		pool.Nodes = append(pool.Nodes, s.newNode(pool.Name))
+	}
+	return pool
+}
+
+func (s *Server) newNode(poolName string) *godo.KubernetesNode {
+	id := s.NextID()
+	return &godo.KubernetesNode{
+		ID:        fmt.Sprintf("node-%d", id),
+		Name:      fmt.Sprintf("%s-%d", poolName, id),
+		Status:    &godo.KubernetesNodeStatus{State: "provisioning"},
+		DropletID: fmt.Sprintf("%d", s.NextID()),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://%s.k8s.ondigitalocean.com
+  name: do-%s
+contexts:
+- context:
+    cluster: do-%s
+    user: do-%s-admin
+  name: do-%s
+current-context: do-%s
+users: []
+`
@@ -0,0 +1,162 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerLoadBalancers() {
+	s.handle("GET /v2/load_balancers", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "load_balancers", s.LoadBalancers.List())
+	})
+
+	s.handle("GET /v2/load_balancers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		loadBalancer, ok := s.LoadBalancers.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"load_balancer": loadBalancer})
+	})
+
+	s.handle("POST /v2/load_balancers", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.LoadBalancerRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Region == "" || len(req.ForwardingRules) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "name, region and forwarding_rules are required")
+			return
+		}
+		if message := s.checkVPC(req.VPCUUID, req.Region); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if message := s.checkProject(req.ProjectID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if message := s.checkCertificates(req.ForwardingRules); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		id := s.NextID()
+		loadBalancer := godo.LoadBalancer{
+			ID:                  fmt.Sprintf("lb-%d", id),
+			Name:                req.Name,
+			IP:                  fmt.Sprintf("198.51.100.%d", id%250+1),
+			SizeUnit:            req.SizeUnit,
+			Algorithm:           req.Algorithm,
+			Status:              "new",
+			Created:             time.Now().UTC().Format(time.RFC3339),
+			ForwardingRules:     req.ForwardingRules,
+			HealthCheck:         req.HealthCheck,
+			Region:              &godo.Region{Slug: req.Region, Name: req.Region, Available: true},
+			DropletIDs:          req.DropletIDs,
+			Tag:                 req.Tag,
+			RedirectHttpToHttps: req.RedirectHttpToHttps,
+			EnableProxyProtocol: req.EnableProxyProtocol,
+			VPCUUID:             req.VPCUUID,
+		}
+		s.LoadBalancers.Put(loadBalancer.ID, loadBalancer)
+		if req.ProjectID != "" {
+			s.AssignToProject(req.ProjectID, loadBalancer.URN())
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"load_balancer": loadBalancer})
+	})
+
+	s.handle("PUT /v2/load_balancers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.LoadBalancerRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := s.checkCertificates(req.ForwardingRules); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+		if !s.LoadBalancers.Update(id, func(lb *godo.LoadBalancer) {
+			lb.Name = req.Name
+			lb.Algorithm = req.Algorithm
+			lb.ForwardingRules = req.ForwardingRules
+			lb.DropletIDs = req.DropletIDs
+			lb.Tag = req.Tag
+		}) {
+			notFound(w)
+			return
+		}
+		loadBalancer, _ := s.LoadBalancers.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"load_balancer": loadBalancer})
+	})
+
+	s.handle("DELETE /v2/load_balancers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.LoadBalancers.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("/v2/load_balancers/{id}/droplets", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			DropletIDs []int `json:"droplet_ids"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !s.LoadBalancers.Update(r.PathValue("id"), func(lb *godo.LoadBalancer) {
+			for _, id := range req.DropletIDs {
+				lb.DropletIDs = removeInt(lb.DropletIDs, id)
+				if r.Method == http.MethodPost {
+					lb.DropletIDs = append(lb.DropletIDs, id)
+				}
+			}
+		}) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("/v2/load_balancers/{id}/forwarding_rules", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ForwardingRules []godo.ForwardingRule `json:"forwarding_rules"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			if message := s.checkCertificates(req.ForwardingRules); message != "" {
+				writeError(w, http.StatusUnprocessableEntity, message)
+				return
+			}
+		}
+		if !s.LoadBalancers.Update(r.PathValue("id"), func(lb *godo.LoadBalancer) {
+			if r.Method == http.MethodPost {
+				lb.ForwardingRules = append(lb.ForwardingRules, req.ForwardingRules...)
+				return
+			}
+			kept := []godo.ForwardingRule{}
+			for _, rule := range lb.ForwardingRules {
+				remove := false
+				for _, r := range req.ForwardingRules {
+					if rule.EntryProtocol == r.EntryProtocol && rule.EntryPort == r.EntryPort {
+						remove = true
+					}
+				}
+				if !remove {
+					kept = append(kept, rule)
+				}
+			}
+			lb.ForwardingRules = kept
+		}) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
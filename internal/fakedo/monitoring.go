@@ -0,0 +1,156 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/metrics"
+)
+
+func (s *Server) registerMonitoring() {
+	s.handle("GET /v2/monitoring/metrics/droplet/{metric}", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		hostID, err := strconv.Atoi(query.Get("host_id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "host_id is required")
+			return
+		}
+		if _, ok := s.Droplets.Get(hostID); !ok {
+			notFound(w)
+			return
+		}
+		start, startErr := strconv.ParseInt(query.Get("start"), 10, 64)
+		end, endErr := strconv.ParseInt(query.Get("end"), 10, 64)
+		if startErr != nil || endErr != nil || start >= end {
+			writeError(w, http.StatusBadRequest, "start and end must be unix timestamps with start before end")
+			return
+		}
+
+		metric := r.PathValue("metric")
+		series, _ := s.DropletMetrics.Get(metricsKey(hostID, metric))
+		result := []metrics.SampleStream{}
+		for _, stream := range series {
+			if metric == "bandwidth" && (string(stream.Metric["interface"]) != query.Get("interface") || string(stream.Metric["direction"]) != query.Get("direction")) {
+				continue
+			}
+			values := []metrics.SamplePair{}
+			for _, value := range stream.Values {
+				if unix := value.Timestamp.Unix(); unix >= start && unix <= end {
+					values = append(values, value)
+				}
+			}
+			result = append(result, metrics.SampleStream{Metric: stream.Metric, Values: values})
+		}
+		writeJSON(w, http.StatusOK, godo.MetricsResponse{
+			Status: "success",
+			Data:   godo.MetricsData{ResultType: "matrix", Result: result},
+		})
+	})
+
+	s.handle("GET /v2/monitoring/alerts", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "policies", s.AlertPolicies.List())
+	})
+
+	s.handle("GET /v2/monitoring/alerts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := s.AlertPolicies.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"policy": policy})
+	})
+
+	s.handle("POST /v2/monitoring/alerts", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.AlertPolicyCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := checkAlertPolicy(req.Type, req.Compare, req.Window, req.Alerts); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		policy := godo.AlertPolicy{
+			UUID:        s.NextUUID("alert"),
+			Type:        req.Type,
+			Description: req.Description,
+			Compare:     req.Compare,
+			Value:       req.Value,
+			Window:      req.Window,
+			Entities:    req.Entities,
+			Tags:        req.Tags,
+			Alerts:      req.Alerts,
+			Enabled:     req.Enabled == nil || *req.Enabled,
+		}
+		s.AlertPolicies.Put(policy.UUID, policy)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"policy": policy})
+	})
+
+	s.handle("PUT /v2/monitoring/alerts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.AlertPolicyUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.AlertPolicies.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		if message := checkAlertPolicy(req.Type, req.Compare, req.Window, req.Alerts); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		// PUT replaces the whole policy
+		s.AlertPolicies.Update(id, func(p *godo.AlertPolicy) {
+			p.Type = req.Type
+			p.Description = req.Description
+			p.Compare = req.Compare
+			p.Value = req.Value
+			p.Window = req.Window
+			p.Entities = req.Entities
+			p.Tags = req.Tags
+			p.Alerts = req.Alerts
+			p.Enabled = req.Enabled == nil || *req.Enabled
+		})
+		policy, _ := s.AlertPolicies.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"policy": policy})
+	})
+
+	s.handle("DELETE /v2/monitoring/alerts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.AlertPolicies.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// SetDropletMetrics stores the series returned for a droplet metric, named
+// as in the API path (e.g. "cpu", "memory_total", "load_5"). Bandwidth
+// series are labelled with "interface" and "direction".
+func (s *Server) SetDropletMetrics(dropletID int, metric string, series ...metrics.SampleStream) {
+	s.DropletMetrics.Put(metricsKey(dropletID, metric), series)
+}
+
+func metricsKey(dropletID int, metric string) string {
+	return fmt.Sprintf("%d/%s", dropletID, metric)
+}
+
+// checkAlertPolicy returns an error message for a policy the API would
+// reject.
+func checkAlertPolicy(policyType string, compare godo.AlertPolicyComp, window string, alerts godo.Alerts) string {
+	switch {
+	case policyType == "" || compare == "" || window == "":
+		return "type, compare and window are required"
+	case compare != godo.GreaterThan && compare != godo.LessThan:
+		return fmt.Sprintf("invalid compare: %s", compare)
+	case !containsString([]string{"5m", "10m", "30m", "1h"}, window):
+		return fmt.Sprintf("invalid window: %s", window)
+	case len(alerts.Email) == 0 && len(alerts.Slack) == 0:
+		return "alerts must include at least one email or slack destination"
+	}
+	return ""
+}
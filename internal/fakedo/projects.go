@@ -0,0 +1,254 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// ProjectResource is a resource assigned to a project. A resource belongs to
+// at most one project, so the table is keyed by URN.
+type ProjectResource struct {
+	ProjectID string
+	godo.ProjectResource
+}
+
+// defaultProjectID is the ID of the project every account starts with.
+const defaultProjectID = "project-default"
+
+// projectURNTypes are the resource types the assign endpoint accepts.
+var projectURNTypes = []string{"droplet", "volume", "floatingip", "reservedip", "reservedipv6", "loadbalancer", "domain", "kubernetes", "dbaas", "app", "space", "bucket"}
+
+func (s *Server) registerProjects() {
+	s.handle("GET /v2/projects", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "projects", s.Projects.List())
+	})
+
+	s.handle("GET /v2/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.findProject(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"project": project})
+	})
+
+	s.handle("POST /v2/projects", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.CreateProjectRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Purpose == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name and purpose are required")
+			return
+		}
+		project := s.AddProject(req.Name)
+		s.Projects.Update(project.ID, func(p *godo.Project) {
+			p.Description = req.Description
+			p.Purpose = req.Purpose
+			p.Environment = req.Environment
+		})
+		project, _ = s.Projects.Get(project.ID)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"project": project})
+	})
+
+	s.handle("PATCH /v2/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name        *string `json:"name"`
+			Description *string `json:"description"`
+			Purpose     *string `json:"purpose"`
+			Environment *string `json:"environment"`
+			IsDefault   *bool   `json:"is_default"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		project, ok := s.findProject(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		if req.Name != nil && *req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name cannot be empty")
+			return
+		}
+		if req.IsDefault != nil && !*req.IsDefault && project.IsDefault {
+			writeError(w, http.StatusUnprocessableEntity, "an account needs a default project; make another project the default instead")
+			return
+		}
+
+		if req.IsDefault != nil && *req.IsDefault {
+			// Only one project is the default
+			for _, other := range s.Projects.Filter(func(p godo.Project) bool { return p.IsDefault }) {
+				s.Projects.Update(other.ID, func(p *godo.Project) { p.IsDefault = false })
+			}
+		}
+		s.Projects.Update(project.ID, func(p *godo.Project) {
+			if req.Name != nil {
+				p.Name = *req.Name
+			}
+			if req.Description != nil {
+				p.Description = *req.Description
+			}
+			if req.Purpose != nil {
+				p.Purpose = *req.Purpose
+			}
+			if req.Environment != nil {
+				p.Environment = *req.Environment
+			}
+			if req.IsDefault != nil && *req.IsDefault {
+				p.IsDefault = true
+			}
+			p.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		})
+		project, _ = s.Projects.Get(project.ID)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"project": project})
+	})
+
+	s.handle("DELETE /v2/projects/{id}", func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.findProject(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		if project.IsDefault {
+			writeError(w, http.StatusPreconditionFailed, "cannot delete the default project")
+			return
+		}
+		if len(s.projectResources(project.ID)) > 0 {
+			writeError(w, http.StatusPreconditionFailed, "cannot delete a project with resources; move them to another project first")
+			return
+		}
+		s.Projects.Delete(project.ID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/projects/{id}/resources", func(w http.ResponseWriter, r *http.Request) {
+		project, ok := s.findProject(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		listResponse(w, r, "resources", s.projectResources(project.ID))
+	})
+
+	s.handle("POST /v2/projects/{id}/resources", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Resources []string `json:"resources"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		project, ok := s.findProject(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		if len(req.Resources) == 0 {
+			writeError(w, http.StatusUnprocessableEntity, "resources is required")
+			return
+		}
+		for _, urn := range req.Resources {
+			if !validProjectURN(urn) {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid urn: %s", urn))
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"resources": s.AssignToProject(project.ID, req.Resources...)})
+	})
+}
+
+// AddProject stores a project named name.
+func (s *Server) AddProject(name string) godo.Project {
+	now := time.Now().UTC().Format(time.RFC3339)
+	project := godo.Project{
+		ID:          s.NextUUID("project"),
+		OwnerUUID:   s.Account.UUID,
+		Name:        name,
+		Purpose:     "Other",
+		Environment: "Development",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.Projects.Put(project.ID, project)
+	return project
+}
+
+// AssignToProject moves the resources to projectID, taking them out of any
+// project they were in.
+func (s *Server) AssignToProject(projectID string, urns ...string) []godo.ProjectResource {
+	assigned := []godo.ProjectResource{}
+	for _, urn := range urns {
+		resource := godo.ProjectResource{
+			URN:        urn,
+			AssignedAt: time.Now().UTC().Format(time.RFC3339),
+			Links:      &godo.ProjectResourceLinks{Self: projectResourceLink(urn)},
+			Status:     "ok",
+		}
+		s.ProjectResources.Delete(urn)
+		s.ProjectResources.Put(urn, ProjectResource{ProjectID: projectID, ProjectResource: resource})
+		assigned = append(assigned, resource)
+	}
+	return assigned
+}
+
+// checkProject returns an error message when a resource is created with a
+// project_id that does not exist.
+func (s *Server) checkProject(projectID string) string {
+	if projectID == "" {
+		return ""
+	}
+	if _, ok := s.Projects.Get(projectID); !ok {
+		return fmt.Sprintf("project %s not found", projectID)
+	}
+	return ""
+}
+
+// findProject looks a project up by ID, or the default project for
+// "default".
+func (s *Server) findProject(id string) (godo.Project, bool) {
+	if id == "default" {
+		projects := s.Projects.Filter(func(p godo.Project) bool { return p.IsDefault })
+		if len(projects) == 0 {
+			return godo.Project{}, false
+		}
+		return projects[0], true
+	}
+	return s.Projects.Get(id)
+}
+
+func (s *Server) projectResources(projectID string) []godo.ProjectResource {
+	var resources []godo.ProjectResource
+	for _, resource := range s.ProjectResources.Filter(func(r ProjectResource) bool { return r.ProjectID == projectID }) {
+		resources = append(resources, resource.ProjectResource)
+	}
+	return resources
+}
+
+func validProjectURN(urn string) bool {
+	parts := strings.SplitN(urn, ":", 3)
+	return len(parts) == 3 && parts[0] == "do" && containsString(projectURNTypes, parts[1]) && parts[2] != ""
+}
+
+func projectResourceLink(urn string) string {
+	parts := strings.SplitN(urn, ":", 3)
+	collections := map[string]string{
+		"droplet":      "droplets",
+		"volume":       "volumes",
+		"floatingip":   "floating_ips",
+		"reservedip":   "reserved_ips",
+		"reservedipv6": "reserved_ipv6",
+		"loadbalancer": "load_balancers",
+		"domain":       "domains",
+		"kubernetes":   "kubernetes/clusters",
+		"dbaas":        "databases",
+		"app":          "apps",
+	}
+	if collection, ok := collections[parts[1]]; ok {
+		return fmt.Sprintf("https://api.digitalocean.com/v2/%s/%s", collection, parts[2])
+	}
+	return ""
+}
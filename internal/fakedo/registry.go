@@ -0,0 +1,260 @@
+package fakedo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerRegistry() {
+	s.handle("GET /v2/registry", func(w http.ResponseWriter, r *http.Request) {
+		if s.Registry == nil {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"registry": s.Registry})
+	})
+
+	s.handle("GET /v2/registry/{registry}/repositories", func(w http.ResponseWriter, r *http.Request) {
+		registry := r.PathValue("registry")
+		if !s.hasRegistry(registry) {
+			notFound(w)
+			return
+		}
+		repositories := s.Repositories.Filter(func(repo godo.Repository) bool {
+			return repo.RegistryName == registry
+		})
+		listResponse(w, r, "repositories", repositories)
+	})
+
+	s.handle("GET /v2/registry/{registry}/repositories/{repository}/tags", func(w http.ResponseWriter, r *http.Request) {
+		registry := r.PathValue("registry")
+		repository := r.PathValue("repository")
+		if !s.hasRegistry(registry) {
+			notFound(w)
+			return
+		}
+		tags := s.RepoTags.Filter(func(tag godo.RepositoryTag) bool {
+			return tag.RegistryName == registry && tag.Repository == repository
+		})
+		listResponse(w, r, "tags", tags)
+	})
+
+	s.handle("DELETE /v2/registry/{registry}/repositories/{repository}/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		repository := r.PathValue("repository")
+		if !s.hasRegistry(r.PathValue("registry")) {
+			notFound(w)
+			return
+		}
+		tag, ok := s.RepoTags.Get(repository + ":" + r.PathValue("tag"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		s.removeTag(tag)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/registry/{registry}/repositories/{repository}/digests", func(w http.ResponseWriter, r *http.Request) {
+		registry := r.PathValue("registry")
+		repository := r.PathValue("repository")
+		if !s.hasRegistry(registry) {
+			notFound(w)
+			return
+		}
+		manifests := s.Manifests.Filter(func(m godo.RepositoryManifest) bool {
+			return m.RegistryName == registry && m.Repository == repository
+		})
+		listResponse(w, r, "manifests", manifests)
+	})
+
+	s.handle("DELETE /v2/registry/{registry}/repositories/{repository}/digests/{digest}", func(w http.ResponseWriter, r *http.Request) {
+		repository := r.PathValue("repository")
+		if !s.hasRegistry(r.PathValue("registry")) {
+			notFound(w)
+			return
+		}
+		key := repository + "@" + r.PathValue("digest")
+		manifest, ok := s.Manifests.Get(key)
+		if !ok {
+			notFound(w)
+			return
+		}
+		for _, name := range manifest.Tags {
+			if tag, ok := s.RepoTags.Get(repository + ":" + name); ok {
+				s.removeTag(tag)
+			}
+		}
+		s.Manifests.Delete(key)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/registry/{registry}/garbage-collection", func(w http.ResponseWriter, r *http.Request) {
+		registry := r.PathValue("registry")
+		if !s.hasRegistry(registry) {
+			notFound(w)
+			return
+		}
+		var req godo.StartGarbageCollectionRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.activeGarbageCollection(registry); ok {
+			writeError(w, http.StatusConflict, "a garbage collection is already running for this registry")
+			return
+		}
+		now := time.Now().UTC()
+		gc := godo.GarbageCollection{
+			UUID:         s.NextUUID("gc"),
+			RegistryName: registry,
+			Status:       "requested",
+			Type:         req.Type,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		s.GarbageCollections.Put(gc.UUID, gc)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"garbage_collection": gc})
+	})
+
+	s.handle("GET /v2/registry/{registry}/garbage-collection", func(w http.ResponseWriter, r *http.Request) {
+		gc, ok := s.activeGarbageCollection(r.PathValue("registry"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"garbage_collection": gc})
+	})
+
+	s.handle("GET /v2/registry/{registry}/garbage-collections", func(w http.ResponseWriter, r *http.Request) {
+		registry := r.PathValue("registry")
+		if !s.hasRegistry(registry) {
+			notFound(w)
+			return
+		}
+		gcs := s.GarbageCollections.Filter(func(gc godo.GarbageCollection) bool {
+			return gc.RegistryName == registry
+		})
+		listResponse(w, r, "garbage_collections", gcs)
+	})
+
+	s.handle("PUT /v2/registry/{registry}/garbage-collection/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.UpdateGarbageCollectionRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		uuid := r.PathValue("uuid")
+		gc, ok := s.GarbageCollections.Get(uuid)
+		if !ok || gc.RegistryName != r.PathValue("registry") {
+			notFound(w)
+			return
+		}
+		if req.Cancel {
+			if !gcActive(gc) {
+				writeError(w, http.StatusPreconditionFailed, fmt.Sprintf("garbage collection is already %s", gc.Status))
+				return
+			}
+			s.GarbageCollections.Update(uuid, func(gc *godo.GarbageCollection) {
+				gc.Status = "cancelled"
+				gc.UpdatedAt = time.Now().UTC()
+			})
+		}
+		gc, _ = s.GarbageCollections.Get(uuid)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"garbage_collection": gc})
+	})
+
+	s.handle("GET /v2/registry/docker-credentials", func(w http.ResponseWriter, r *http.Request) {
+		if s.Registry == nil {
+			notFound(w)
+			return
+		}
+		// The auth value encodes the requested access and expiry so tests
+		// can check what was asked for.
+		access := "read"
+		if r.URL.Query().Get("read_write") == "true" {
+			access = "read-write"
+		}
+		secret := fmt.Sprintf("%s:expiry=%s", access, r.URL.Query().Get("expiry_seconds"))
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"auths": map[string]interface{}{
+				"registry.digitalocean.com": map[string]string{
+					"auth": base64.StdEncoding.EncodeToString([]byte(secret)),
+				},
+			},
+		})
+	})
+}
+
+func (s *Server) hasRegistry(name string) bool {
+	return s.Registry != nil && s.Registry.Name == name
+}
+
+// AddRepositoryTag stores a tag and creates or updates its repository and,
+// when the tag has a digest, its manifest.
+func (s *Server) AddRepositoryTag(tag godo.RepositoryTag) {
+	s.RepoTags.Put(tag.Repository+":"+tag.Tag, tag)
+	key := tag.RegistryName + "/" + tag.Repository
+	if !s.Repositories.Update(key, func(repo *godo.Repository) {
+		repo.TagCount++
+		repo.LatestTag = &tag
+	}) {
+		s.Repositories.Put(key, godo.Repository{
+			RegistryName: tag.RegistryName,
+			Name:         tag.Repository,
+			LatestTag:    &tag,
+			TagCount:     1,
+		})
+	}
+
+	if tag.ManifestDigest == "" {
+		return
+	}
+	manifestKey := tag.Repository + "@" + tag.ManifestDigest
+	if !s.Manifests.Update(manifestKey, func(m *godo.RepositoryManifest) {
+		m.Tags = append(m.Tags, tag.Tag)
+	}) {
+		s.Manifests.Put(manifestKey, godo.RepositoryManifest{
+			RegistryName:        tag.RegistryName,
+			Repository:          tag.Repository,
+			Digest:              tag.ManifestDigest,
+			CompressedSizeBytes: tag.CompressedSizeBytes,
+			SizeBytes:           tag.SizeBytes,
+			UpdatedAt:           tag.UpdatedAt,
+			Tags:                []string{tag.Tag},
+		})
+	}
+}
+
+// removeTag deletes a tag and detaches it from its repository and manifest.
+// The manifest itself stays, untagged, as it does in the real registry.
+func (s *Server) removeTag(tag godo.RepositoryTag) {
+	s.RepoTags.Delete(tag.Repository + ":" + tag.Tag)
+	s.Repositories.Update(tag.RegistryName+"/"+tag.Repository, func(repo *godo.Repository) {
+		repo.TagCount--
+		if repo.LatestTag != nil && repo.LatestTag.Tag == tag.Tag {
+			repo.LatestTag = nil
+		}
+	})
+	s.Manifests.Update(tag.Repository+"@"+tag.ManifestDigest, func(m *godo.RepositoryManifest) {
+		m.Tags = removeString(m.Tags, tag.Tag)
+	})
+}
+
+func (s *Server) activeGarbageCollection(registry string) (godo.GarbageCollection, bool) {
+	for _, gc := range s.GarbageCollections.List() {
+		if gc.RegistryName == registry && gcActive(gc) {
+			return gc, true
+		}
+	}
+	return godo.GarbageCollection{}, false
+}
+
+func gcActive(gc godo.GarbageCollection) bool {
+	switch gc.Status {
+	case "succeeded", "failed", "cancelled":
+		return false
+	}
+	return true
+}
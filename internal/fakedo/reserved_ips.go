@@ -0,0 +1,191 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerReservedIPs() {
+	s.handle("GET /v2/reserved_ips", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "reserved_ips", s.ReservedIPs.List())
+	})
+
+	s.handle("GET /v2/reserved_ips/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		reservedIP, ok := s.ReservedIPs.Get(r.PathValue("ip"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"reserved_ip": reservedIP})
+	})
+
+	s.handle("POST /v2/reserved_ips", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.ReservedIPCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := s.checkProject(req.ProjectID); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		reservedIP := godo.ReservedIP{
+			IP:        fmt.Sprintf("203.0.113.%d", s.NextID()%250+1),
+			ProjectID: req.ProjectID,
+		}
+		switch {
+		case req.DropletID > 0:
+			droplet, ok := s.Droplets.Get(req.DropletID)
+			if !ok {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Droplet %d not found", req.DropletID))
+				return
+			}
+			reservedIP.Droplet = &droplet
+			reservedIP.Region = droplet.Region
+		case req.Region != "":
+			reservedIP.Region = &godo.Region{Slug: req.Region, Name: req.Region, Available: true}
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "region or droplet_id is required")
+			return
+		}
+
+		s.ReservedIPs.Put(reservedIP.IP, reservedIP)
+		if req.ProjectID != "" {
+			s.AssignToProject(req.ProjectID, reservedIP.URN())
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"reserved_ip": reservedIP})
+	})
+
+	s.handle("DELETE /v2/reserved_ips/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ReservedIPs.Delete(r.PathValue("ip")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/reserved_ips/{ip}/actions", func(w http.ResponseWriter, r *http.Request) {
+		ip := r.PathValue("ip")
+		reservedIP, ok := s.ReservedIPs.Get(ip)
+		if !ok {
+			notFound(w)
+			return
+		}
+		droplet, ok := s.reservedIPAction(w, r, reservedIP.Droplet != nil, regionSlug(reservedIP.Region))
+		if !ok {
+			return
+		}
+		s.ReservedIPs.Update(ip, func(f *godo.ReservedIP) {
+			f.Droplet = droplet
+		})
+
+		actionType := "assign"
+		if droplet == nil {
+			actionType = "unassign"
+		}
+		action := s.newAction(actionType, "reserved_ip", 0, regionSlug(reservedIP.Region))
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"action": action})
+	})
+
+	s.handle("GET /v2/reserved_ipv6", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "reserved_ipv6s", s.ReservedIPV6s.List())
+	})
+
+	s.handle("GET /v2/reserved_ipv6/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		reservedIP, ok := s.ReservedIPV6s.Get(r.PathValue("ip"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"reserved_ipv6": reservedIP})
+	})
+
+	s.handle("POST /v2/reserved_ipv6", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.ReservedIPV6CreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Region == "" {
+			writeError(w, http.StatusUnprocessableEntity, "region_slug is required")
+			return
+		}
+
+		reservedIP := godo.ReservedIPV6{
+			IP:         fmt.Sprintf("2001:db8:%x::1", s.NextID()),
+			RegionSlug: req.Region,
+			ReservedAt: time.Now().UTC().Truncate(time.Second),
+		}
+		s.ReservedIPV6s.Put(reservedIP.IP, reservedIP)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"reserved_ipv6": reservedIP})
+	})
+
+	s.handle("DELETE /v2/reserved_ipv6/{ip}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.ReservedIPV6s.Delete(r.PathValue("ip")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/reserved_ipv6/{ip}/actions", func(w http.ResponseWriter, r *http.Request) {
+		ip := r.PathValue("ip")
+		reservedIP, ok := s.ReservedIPV6s.Get(ip)
+		if !ok {
+			notFound(w)
+			return
+		}
+		droplet, ok := s.reservedIPAction(w, r, reservedIP.Droplet != nil, reservedIP.RegionSlug)
+		if !ok {
+			return
+		}
+		s.ReservedIPV6s.Update(ip, func(f *godo.ReservedIPV6) {
+			f.Droplet = droplet
+		})
+
+		actionType := "assign"
+		if droplet == nil {
+			actionType = "unassign"
+		}
+		action := s.newAction(actionType, "reserved_ipv6", 0, reservedIP.RegionSlug)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"action": action})
+	})
+}
+
+// reservedIPAction decodes an assign or unassign action and returns the
+// droplet the IP should point at, nil to unassign it. It writes the error
+// response itself when the action is rejected.
+func (s *Server) reservedIPAction(w http.ResponseWriter, r *http.Request, assigned bool, region string) (*godo.Droplet, bool) {
+	var req struct {
+		Type      string `json:"type"`
+		DropletID int    `json:"droplet_id"`
+	}
+	if !decodeBody(w, r, &req) {
+		return nil, false
+	}
+
+	switch req.Type {
+	case "assign":
+		droplet, ok := s.Droplets.Get(req.DropletID)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Droplet %d not found", req.DropletID))
+			return nil, false
+		}
+		if slug := regionSlug(droplet.Region); slug != region {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Droplet %d is in %s but the reserved IP is in %s", req.DropletID, slug, region))
+			return nil, false
+		}
+		return &droplet, true
+	case "unassign":
+		if !assigned {
+			writeError(w, http.StatusUnprocessableEntity, "Reserved IP is not assigned")
+			return nil, false
+		}
+		return nil, true
+	default:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("unsupported reserved IP action: %s", req.Type))
+		return nil, false
+	}
+}
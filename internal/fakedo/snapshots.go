@@ -0,0 +1,34 @@
+package fakedo
+
+import (
+	"net/http"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerSnapshots() {
+	s.handle("GET /v2/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		resourceType := r.URL.Query().Get("resource_type")
+		snapshots := s.Snapshots.Filter(func(snapshot godo.Snapshot) bool {
+			return resourceType == "" || snapshot.ResourceType == resourceType
+		})
+		listResponse(w, r, "snapshots", snapshots)
+	})
+
+	s.handle("GET /v2/snapshots/{id}", func(w http.ResponseWriter, r *http.Request) {
+		snapshot, ok := s.Snapshots.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"snapshot": snapshot})
+	})
+
+	s.handle("DELETE /v2/snapshots/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.Snapshots.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,119 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerSpacesKeys() {
+	s.handle("GET /v2/spaces/keys", func(w http.ResponseWriter, r *http.Request) {
+		keys := []godo.SpacesKey{}
+		for _, key := range s.SpacesKeys.List() {
+			keys = append(keys, withoutSecret(key))
+		}
+		listResponse(w, r, "keys", keys)
+	})
+
+	s.handle("GET /v2/spaces/keys/{access_key}", func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.SpacesKeys.Get(r.PathValue("access_key"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"key": withoutSecret(key)})
+	})
+
+	// The secret is only ever returned when the key is created
+	s.handle("POST /v2/spaces/keys", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.SpacesKeyCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := checkSpacesKey(req.Name, req.Grants); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		key := s.AddSpacesKey(req.Name, req.Grants...)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"key": key})
+	})
+
+	s.handle("PUT /v2/spaces/keys/{access_key}", func(w http.ResponseWriter, r *http.Request) {
+		accessKey := r.PathValue("access_key")
+		var req godo.SpacesKeyUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.SpacesKeys.Get(accessKey); !ok {
+			notFound(w)
+			return
+		}
+		if message := checkSpacesKey(req.Name, req.Grants); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		s.SpacesKeys.Update(accessKey, func(k *godo.SpacesKey) {
+			k.Name = req.Name
+			k.Grants = req.Grants
+		})
+		key, _ := s.SpacesKeys.Get(accessKey)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"key": withoutSecret(key)})
+	})
+
+	s.handle("DELETE /v2/spaces/keys/{access_key}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.SpacesKeys.Delete(r.PathValue("access_key")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// AddSpacesKey stores an access key with grants and returns it, secret
+// included.
+func (s *Server) AddSpacesKey(name string, grants ...*godo.Grant) godo.SpacesKey {
+	id := s.NextID()
+	key := godo.SpacesKey{
+		Name:      name,
+		AccessKey: fmt.Sprintf("DO%018d", id),
+		SecretKey: fmt.Sprintf("spaces-secret-%d", id),
+		Grants:    grants,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	s.SpacesKeys.Put(key.AccessKey, key)
+	return key
+}
+
+func withoutSecret(key godo.SpacesKey) godo.SpacesKey {
+	key.SecretKey = ""
+	return key
+}
+
+// checkSpacesKey returns an error message for a key the API would reject.
+func checkSpacesKey(name string, grants []*godo.Grant) string {
+	if name == "" {
+		return "name is required"
+	}
+	if len(grants) == 0 {
+		return "at least one grant is required"
+	}
+	for _, grant := range grants {
+		switch grant.Permission {
+		case godo.SpacesKeyRead, godo.SpacesKeyReadWrite:
+			if grant.Bucket == "" {
+				return fmt.Sprintf("%s grants require a bucket", grant.Permission)
+			}
+		case godo.SpacesKeyFullAccess:
+			if grant.Bucket != "" {
+				return "fullaccess grants apply to all buckets and cannot name one"
+			}
+		default:
+			return fmt.Sprintf("invalid permission: %s", grant.Permission)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package fakedo
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerSSHKeys() {
+	s.handle("GET /v2/account/keys", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "ssh_keys", s.SSHKeys.List())
+	})
+
+	s.handle("GET /v2/account/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.findSSHKey(r.PathValue("key"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ssh_key": key})
+	})
+
+	s.handle("POST /v2/account/keys", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.KeyCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		fingerprint, ok := fingerprintOf(req.PublicKey)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Key invalid, key should be of the format `type key [comment]`")
+			return
+		}
+		if _, exists := s.findSSHKey(fingerprint); exists {
+			writeError(w, http.StatusUnprocessableEntity, "SSH Key is already in use on your account")
+			return
+		}
+
+		key := godo.Key{
+			ID:          s.NextID(),
+			Name:        req.Name,
+			Fingerprint: fingerprint,
+			PublicKey:   req.PublicKey,
+		}
+		s.SSHKeys.Put(key.ID, key)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"ssh_key": key})
+	})
+
+	s.handle("PUT /v2/account/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.KeyUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		key, ok := s.findSSHKey(r.PathValue("key"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		s.SSHKeys.Update(key.ID, func(k *godo.Key) { k.Name = req.Name })
+		key, _ = s.SSHKeys.Get(key.ID)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ssh_key": key})
+	})
+
+	s.handle("DELETE /v2/account/keys/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key, ok := s.findSSHKey(r.PathValue("key"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		s.SSHKeys.Delete(key.ID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// AddSSHKey stores a key for publicKey and returns it. The public key must be
+// valid.
+func (s *Server) AddSSHKey(name, publicKey string) godo.Key {
+	fingerprint, ok := fingerprintOf(publicKey)
+	if !ok {
+		panic("fakedo: invalid public key")
+	}
+	key := godo.Key{ID: s.NextID(), Name: name, Fingerprint: fingerprint, PublicKey: publicKey}
+	s.SSHKeys.Put(key.ID, key)
+	return key
+}
+
+// findSSHKey looks a key up by ID or fingerprint, as the API's path does.
+func (s *Server) findSSHKey(ref string) (godo.Key, bool) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.SSHKeys.Get(id)
+	}
+	keys := s.SSHKeys.Filter(func(k godo.Key) bool { return k.Fingerprint == ref })
+	if len(keys) == 0 {
+		return godo.Key{}, false
+	}
+	return keys[0], true
+}
+
+// fingerprintOf returns the MD5 fingerprint of an authorized_keys line.
+func fingerprintOf(publicKey string) (string, bool) {
+	fields := strings.Fields(publicKey)
+	if len(fields) < 2 {
+		return "", false
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil || len(blob) == 0 {
+		return "", false
+	}
+	sum := md5.Sum(blob)
+	pairs := make([]string, len(sum))
+	for i, b := range sum {
+		pairs[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(pairs, ":"), true
+}
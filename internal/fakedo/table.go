@@ -0,0 +1,90 @@
+package fakedo
+
+import "sync"
+
+// Table is an insertion-ordered, concurrency-safe map of fake resources.
+type Table[K comparable, V any] struct {
+	mu   sync.Mutex
+	keys []K
+	rows map[K]V
+}
+
+func NewTable[K comparable, V any]() *Table[K, V] {
+	return &Table[K, V]{
+		rows: make(map[K]V),
+	}
+}
+
+// Put inserts or replaces the row stored under key.
+func (t *Table[K, V]) Put(key K, value V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.rows[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.rows[key] = value
+}
+
+func (t *Table[K, V]) Get(key K) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value, ok := t.rows[key]
+	return value, ok
+}
+
+// Update applies fn to the row stored under key and reports whether it existed.
+func (t *Table[K, V]) Update(key K, fn func(*V)) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value, ok := t.rows[key]
+	if !ok {
+		return false
+	}
+	fn(&value)
+	t.rows[key] = value
+	return true
+}
+
+func (t *Table[K, V]) Delete(key K) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.rows[key]; !ok {
+		return false
+	}
+	delete(t.rows, key)
+	for i, k := range t.keys {
+		if k == key {
+			t.keys = append(t.keys[:i], t.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// List returns the rows in insertion order.
+func (t *Table[K, V]) List() []V {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	values := make([]V, 0, len(t.keys))
+	for _, key := range t.keys {
+		values = append(values, t.rows[key])
+	}
+	return values
+}
+
+// Filter returns the rows for which keep returns true, in insertion order.
+func (t *Table[K, V]) Filter(keep func(V) bool) []V {
+	var values []V
+	for _, value := range t.List() {
+		if keep(value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func (t *Table[K, V]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.keys)
+}
@@ -0,0 +1,177 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+)
+
+var tagNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_\-:]{1,255}$`)
+
+func (s *Server) registerTags() {
+	s.handle("GET /v2/tags", func(w http.ResponseWriter, r *http.Request) {
+		tags := s.Tags.List()
+		for i := range tags {
+			tags[i].Resources = s.taggedResources(tags[i].Name)
+		}
+		listResponse(w, r, "tags", tags)
+	})
+
+	s.handle("GET /v2/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tag, ok := s.Tags.Get(r.PathValue("tag"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		tag.Resources = s.taggedResources(tag.Name)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tag": tag})
+	})
+
+	s.handle("POST /v2/tags", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.TagCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if !tagNamePattern.MatchString(req.Name) {
+			writeError(w, http.StatusUnprocessableEntity, "tag names may only contain letters, numbers, colons, dashes and underscores")
+			return
+		}
+		// Creating an existing tag succeeds and returns it unchanged
+		s.ensureTags([]string{req.Name})
+		tag := godo.Tag{Name: req.Name, Resources: s.taggedResources(req.Name)}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"tag": tag})
+	})
+
+	s.handle("DELETE /v2/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("tag")
+		if !s.Tags.Delete(name) {
+			notFound(w)
+			return
+		}
+		for _, droplet := range s.Droplets.List() {
+			s.setResourceTag(godo.Resource{ID: strconv.Itoa(droplet.ID), Type: godo.DropletResourceType}, name, false)
+		}
+		for _, volume := range s.Volumes.List() {
+			s.setResourceTag(godo.Resource{ID: volume.ID, Type: godo.VolumeResourceType}, name, false)
+		}
+		for _, image := range s.Images.List() {
+			s.setResourceTag(godo.Resource{ID: strconv.Itoa(image.ID), Type: godo.ImageResourceType}, name, false)
+		}
+		for _, lb := range s.LoadBalancers.List() {
+			s.setResourceTag(godo.Resource{ID: lb.ID, Type: godo.LoadBalancerResourceType}, name, false)
+		}
+		for _, cluster := range s.Databases.List() {
+			s.setResourceTag(godo.Resource{ID: cluster.ID, Type: godo.DatabaseResourceType}, name, false)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tagResources := func(add bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			name := r.PathValue("tag")
+			if _, ok := s.Tags.Get(name); !ok {
+				notFound(w)
+				return
+			}
+			var req godo.TagResourcesRequest
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			if len(req.Resources) == 0 {
+				writeError(w, http.StatusUnprocessableEntity, "resources are required")
+				return
+			}
+			for _, resource := range req.Resources {
+				if !s.resourceExists(resource) {
+					writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s %s does not exist", resource.Type, resource.ID))
+					return
+				}
+			}
+			for _, resource := range req.Resources {
+				s.setResourceTag(resource, name, add)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	s.handle("POST /v2/tags/{tag}/resources", tagResources(true))
+	s.handle("DELETE /v2/tags/{tag}/resources", tagResources(false))
+}
+
+func (s *Server) ensureTags(names []string) {
+	for _, name := range names {
+		if _, ok := s.Tags.Get(name); !ok {
+			s.Tags.Put(name, godo.Tag{Name: name})
+		}
+	}
+}
+
+// taggedResources counts the resources carrying a tag.
+func (s *Server) taggedResources(name string) *godo.TaggedResources {
+	droplets := len(s.dropletsWithTag(name))
+	volumes := len(s.Volumes.Filter(func(v godo.Volume) bool { return containsString(v.Tags, name) }))
+	images := len(s.Images.Filter(func(i godo.Image) bool { return containsString(i.Tags, name) }))
+	lbs := len(s.LoadBalancers.Filter(func(lb godo.LoadBalancer) bool { return containsString(lb.Tags, name) }))
+	databases := len(s.Databases.Filter(func(c DatabaseCluster) bool { return containsString(c.Tags, name) }))
+	return &godo.TaggedResources{
+		Count:           droplets + volumes + images + lbs + databases,
+		Droplets:        &godo.TaggedDropletsResources{Count: droplets},
+		Volumes:         &godo.TaggedVolumesResources{Count: volumes},
+		Images:          &godo.TaggedImagesResources{Count: images},
+		VolumeSnapshots: &godo.TaggedVolumeSnapshotsResources{},
+		Databases:       &godo.TaggedDatabasesResources{Count: databases},
+	}
+}
+
+func (s *Server) resourceExists(resource godo.Resource) bool {
+	switch resource.Type {
+	case godo.DropletResourceType:
+		id, err := strconv.Atoi(resource.ID)
+		_, ok := s.Droplets.Get(id)
+		return err == nil && ok
+	case godo.VolumeResourceType:
+		_, ok := s.Volumes.Get(resource.ID)
+		return ok
+	case godo.ImageResourceType:
+		id, err := strconv.Atoi(resource.ID)
+		_, ok := s.Images.Get(id)
+		return err == nil && ok
+	case godo.LoadBalancerResourceType:
+		_, ok := s.LoadBalancers.Get(resource.ID)
+		return ok
+	case godo.DatabaseResourceType:
+		_, ok := s.Databases.Get(resource.ID)
+		return ok
+	}
+	return false
+}
+
+// setResourceTag adds or removes a tag on a resource.
+func (s *Server) setResourceTag(resource godo.Resource, name string, add bool) {
+	update := func(tags []string) []string {
+		if !add && !containsString(tags, name) {
+			return tags
+		}
+		tags = removeString(tags, name)
+		if add {
+			tags = append(tags, name)
+		}
+		return tags
+	}
+	switch resource.Type {
+	case godo.DropletResourceType:
+		id, _ := strconv.Atoi(resource.ID)
+		s.Droplets.Update(id, func(d *godo.Droplet) { d.Tags = update(d.Tags) })
+	case godo.VolumeResourceType:
+		s.Volumes.Update(resource.ID, func(v *godo.Volume) { v.Tags = update(v.Tags) })
+	case godo.ImageResourceType:
+		id, _ := strconv.Atoi(resource.ID)
+		s.Images.Update(id, func(i *godo.Image) { i.Tags = update(i.Tags) })
+	case godo.LoadBalancerResourceType:
+		s.LoadBalancers.Update(resource.ID, func(lb *godo.LoadBalancer) { lb.Tags = update(lb.Tags) })
+	case godo.DatabaseResourceType:
+		s.Databases.Update(resource.ID, func(c *DatabaseCluster) { c.Tags = update(c.Tags) })
+	}
+}
@@ -0,0 +1,278 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// UptimeAlert is an alert with the check it belongs to.
+type UptimeAlert struct {
+	CheckID string
+	godo.UptimeAlert
+}
+
+var uptimeRegions = []string{"us_east", "us_west", "eu_west", "se_asia"}
+
+func (s *Server) registerUptime() {
+	s.handle("GET /v2/uptime/checks", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "checks", s.UptimeChecks.List())
+	})
+
+	s.handle("GET /v2/uptime/checks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		check, ok := s.UptimeChecks.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"check": check})
+	})
+
+	s.handle("GET /v2/uptime/checks/{id}/state", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.UptimeChecks.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		state, _ := s.UptimeStates.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"state": state})
+	})
+
+	s.handle("POST /v2/uptime/checks", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.CreateUptimeCheckRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if message := checkUptimeCheck(req.Name, req.Type, req.Target, req.Regions); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		check := godo.UptimeCheck{
+			ID:      s.NextUUID("uptime"),
+			Name:    req.Name,
+			Type:    req.Type,
+			Target:  req.Target,
+			Regions: req.Regions,
+			Enabled: req.Enabled,
+		}
+		s.UptimeChecks.Put(check.ID, check)
+		s.UptimeStates.Put(check.ID, godo.UptimeCheckState{Regions: upRegions(check.Regions, nil)})
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"check": check})
+	})
+
+	s.handle("PUT /v2/uptime/checks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.UpdateUptimeCheckRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.UptimeChecks.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		if message := checkUptimeCheck(req.Name, req.Type, req.Target, req.Regions); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		// PUT replaces the whole check; regions keep their state
+		check := godo.UptimeCheck{
+			ID:      id,
+			Name:    req.Name,
+			Type:    req.Type,
+			Target:  req.Target,
+			Regions: req.Regions,
+			Enabled: req.Enabled,
+		}
+		s.UptimeChecks.Put(id, check)
+		s.UptimeStates.Update(id, func(state *godo.UptimeCheckState) {
+			state.Regions = upRegions(check.Regions, state.Regions)
+		})
+		writeJSON(w, http.StatusOK, map[string]interface{}{"check": check})
+	})
+
+	s.handle("DELETE /v2/uptime/checks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !s.UptimeChecks.Delete(id) {
+			notFound(w)
+			return
+		}
+		s.UptimeStates.Delete(id)
+		for _, alert := range s.uptimeAlerts(id) {
+			s.UptimeAlerts.Delete(alert.ID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/uptime/checks/{id}/alerts", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.UptimeChecks.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		alerts := []godo.UptimeAlert{}
+		for _, alert := range s.uptimeAlerts(id) {
+			alerts = append(alerts, alert.UptimeAlert)
+		}
+		listResponse(w, r, "alerts", alerts)
+	})
+
+	s.handle("GET /v2/uptime/checks/{id}/alerts/{alert}", func(w http.ResponseWriter, r *http.Request) {
+		alert, ok := s.UptimeAlerts.Get(r.PathValue("alert"))
+		if !ok || alert.CheckID != r.PathValue("id") {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"alert": alert.UptimeAlert})
+	})
+
+	s.handle("POST /v2/uptime/checks/{id}/alerts", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.CreateUptimeAlertRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if _, ok := s.UptimeChecks.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		if message := checkUptimeAlert(req.Name, req.Type, req.Comparison, req.Period, req.Notifications); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		alert := UptimeAlert{
+			CheckID: id,
+			UptimeAlert: godo.UptimeAlert{
+				ID:            s.NextUUID("uptime-alert"),
+				Name:          req.Name,
+				Type:          req.Type,
+				Threshold:     req.Threshold,
+				Comparison:    req.Comparison,
+				Notifications: req.Notifications,
+				Period:        req.Period,
+			},
+		}
+		s.UptimeAlerts.Put(alert.ID, alert)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"alert": alert.UptimeAlert})
+	})
+
+	s.handle("PUT /v2/uptime/checks/{id}/alerts/{alert}", func(w http.ResponseWriter, r *http.Request) {
+		id, alertID := r.PathValue("id"), r.PathValue("alert")
+		var req godo.UpdateUptimeAlertRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if alert, ok := s.UptimeAlerts.Get(alertID); !ok || alert.CheckID != id {
+			notFound(w)
+			return
+		}
+		if message := checkUptimeAlert(req.Name, req.Type, req.Comparison, req.Period, req.Notifications); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		// PUT replaces the whole alert
+		alert := UptimeAlert{
+			CheckID: id,
+			UptimeAlert: godo.UptimeAlert{
+				ID:            alertID,
+				Name:          req.Name,
+				Type:          req.Type,
+				Threshold:     req.Threshold,
+				Comparison:    req.Comparison,
+				Notifications: req.Notifications,
+				Period:        req.Period,
+			},
+		}
+		s.UptimeAlerts.Put(alertID, alert)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"alert": alert.UptimeAlert})
+	})
+
+	s.handle("DELETE /v2/uptime/checks/{id}/alerts/{alert}", func(w http.ResponseWriter, r *http.Request) {
+		alertID := r.PathValue("alert")
+		if alert, ok := s.UptimeAlerts.Get(alertID); !ok || alert.CheckID != r.PathValue("id") {
+			notFound(w)
+			return
+		}
+		s.UptimeAlerts.Delete(alertID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// SetUptimeRegionStatus sets the status ("UP" or "DOWN") a check reports
+// from one region, as the probes in that region would.
+func (s *Server) SetUptimeRegionStatus(checkID, region, status string) {
+	s.UptimeStates.Update(checkID, func(state *godo.UptimeCheckState) {
+		current := state.Regions[region]
+		if current.Status != status {
+			current.Status = status
+			current.StatusChangedAt = time.Now().UTC().Format(time.RFC3339)
+		}
+		state.Regions[region] = current
+	})
+}
+
+func (s *Server) uptimeAlerts(checkID string) []UptimeAlert {
+	return s.UptimeAlerts.Filter(func(alert UptimeAlert) bool {
+		return alert.CheckID == checkID
+	})
+}
+
+// upRegions returns the state of each region, keeping known states and
+// starting new regions as up.
+func upRegions(regions []string, known map[string]godo.UptimeRegion) map[string]godo.UptimeRegion {
+	now := time.Now().UTC().Format(time.RFC3339)
+	result := make(map[string]godo.UptimeRegion, len(regions))
+	for _, region := range regions {
+		state, ok := known[region]
+		if !ok {
+			state = godo.UptimeRegion{Status: "UP", StatusChangedAt: now, ThirtyDayUptimePercentage: 100}
+		}
+		result[region] = state
+	}
+	return result
+}
+
+// checkUptimeCheck returns an error message for a check the API would
+// reject.
+func checkUptimeCheck(name, checkType, target string, regions []string) string {
+	switch {
+	case name == "" || target == "":
+		return "name and target are required"
+	case !containsString([]string{"http", "https", "ping"}, checkType):
+		return fmt.Sprintf("invalid type: %s", checkType)
+	case checkType != "ping" && !strings.HasPrefix(target, checkType+"://"):
+		return fmt.Sprintf("target must be a %s:// URL", checkType)
+	case len(regions) == 0:
+		return "at least one region is required"
+	}
+	for _, region := range regions {
+		if !containsString(uptimeRegions, region) {
+			return fmt.Sprintf("invalid region: %s", region)
+		}
+	}
+	return ""
+}
+
+// checkUptimeAlert returns an error message for an alert the API would
+// reject.
+func checkUptimeAlert(name, alertType string, comparison godo.UptimeAlertComp, period string, notifications *godo.Notifications) string {
+	switch {
+	case name == "":
+		return "name is required"
+	case !containsString([]string{"latency", "down", "down_global", "ssl_expiry"}, alertType):
+		return fmt.Sprintf("invalid type: %s", alertType)
+	case comparison != "" && comparison != godo.UptimeAlertGreaterThan && comparison != godo.UptimeAlertLessThan:
+		return fmt.Sprintf("invalid comparison: %s", comparison)
+	case !containsString([]string{"2m", "3m", "5m", "10m", "15m", "30m", "1h"}, period):
+		return fmt.Sprintf("invalid period: %s", period)
+	case notifications == nil || len(notifications.Email) == 0 && len(notifications.Slack) == 0:
+		return "notifications must include at least one email or slack destination"
+	}
+	return ""
+}
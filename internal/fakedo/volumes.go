@@ -0,0 +1,156 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func (s *Server) registerVolumes() {
+	s.handle("GET /v2/volumes", func(w http.ResponseWriter, r *http.Request) {
+		region := r.URL.Query().Get("region")
+		name := r.URL.Query().Get("name")
+		volumes := s.Volumes.Filter(func(v godo.Volume) bool {
+			return (region == "" || regionSlug(v.Region) == region) && (name == "" || v.Name == name)
+		})
+		listResponse(w, r, "volumes", volumes)
+	})
+
+	s.handle("GET /v2/volumes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		volume, ok := s.Volumes.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"volume": volume})
+	})
+
+	s.handle("POST /v2/volumes", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.VolumeCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.Region == "" || req.SizeGigaBytes <= 0 {
+			writeError(w, http.StatusUnprocessableEntity, "name, region and size_gigabytes are required")
+			return
+		}
+
+		volume := godo.Volume{
+			ID:              s.NextUUID("vol"),
+			Region:          &godo.Region{Slug: req.Region, Name: req.Region, Available: true},
+			Name:            req.Name,
+			SizeGigaBytes:   req.SizeGigaBytes,
+			Description:     req.Description,
+			DropletIDs:      []int{},
+			CreatedAt:       time.Now().UTC(),
+			FilesystemType:  req.FilesystemType,
+			FilesystemLabel: req.FilesystemLabel,
+			Tags:            req.Tags,
+		}
+		s.Volumes.Put(volume.ID, volume)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"volume": volume})
+	})
+
+	s.handle("DELETE /v2/volumes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		volume, ok := s.Volumes.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		if len(volume.DropletIDs) > 0 {
+			writeError(w, http.StatusConflict, "Volume is currently attached to a Droplet")
+			return
+		}
+		s.Volumes.Delete(id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("POST /v2/volumes/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		volume, ok := s.Volumes.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+
+		var req struct {
+			Type          string `json:"type"`
+			DropletID     int    `json:"droplet_id"`
+			SizeGigaBytes int    `json:"size_gigabytes"`
+			Region        string `json:"region"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		switch req.Type {
+		case "attach":
+			if _, ok := s.Droplets.Get(req.DropletID); !ok {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("Droplet %d not found", req.DropletID))
+				return
+			}
+			s.Volumes.Update(id, func(v *godo.Volume) {
+				v.DropletIDs = append(v.DropletIDs, req.DropletID)
+			})
+			s.Droplets.Update(req.DropletID, func(d *godo.Droplet) {
+				d.VolumeIDs = append(d.VolumeIDs, id)
+			})
+		case "detach":
+			if !containsInt(volume.DropletIDs, req.DropletID) {
+				writeError(w, http.StatusUnprocessableEntity, "Volume is not attached to this Droplet")
+				return
+			}
+			s.Volumes.Update(id, func(v *godo.Volume) {
+				v.DropletIDs = removeInt(v.DropletIDs, req.DropletID)
+			})
+			s.Droplets.Update(req.DropletID, func(d *godo.Droplet) {
+				d.VolumeIDs = removeString(d.VolumeIDs, id)
+			})
+		case "resize":
+			if int64(req.SizeGigaBytes) <= volume.SizeGigaBytes {
+				writeError(w, http.StatusUnprocessableEntity, "Volumes can only be resized to a larger size")
+				return
+			}
+			s.Volumes.Update(id, func(v *godo.Volume) {
+				v.SizeGigaBytes = int64(req.SizeGigaBytes)
+			})
+		default:
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("unsupported volume action: %s", req.Type))
+			return
+		}
+
+		action := s.newAction(req.Type, "volume", 0, regionSlug(volume.Region))
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"action": action})
+	})
+
+	s.handle("POST /v2/volumes/{id}/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		volume, ok := s.Volumes.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+
+		var req godo.SnapshotCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+
+		snapshot := godo.Snapshot{
+			ID:            s.NextUUID("snap"),
+			Name:          req.Name,
+			ResourceID:    id,
+			ResourceType:  "volume",
+			Regions:       []string{regionSlug(volume.Region)},
+			SizeGigaBytes: float64(volume.SizeGigaBytes),
+			MinDiskSize:   int(volume.SizeGigaBytes),
+			Created:       time.Now().UTC().Format(time.RFC3339),
+			Tags:          req.Tags,
+		}
+		s.Snapshots.Put(snapshot.ID, snapshot)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"snapshot": snapshot})
+	})
+}
@@ -0,0 +1,333 @@
+package fakedo
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// vpcMemberPrefixes maps the resource_type filter of the members endpoint to
+// the URN prefix of matching resources.
+var vpcMemberPrefixes = map[string]string{
+	"droplet":       "do:droplet:",
+	"load_balancer": "do:loadbalancer:",
+	"kubernetes":    "do:kubernetes:",
+}
+
+func (s *Server) registerVPCs() {
+	s.handle("GET /v2/vpcs", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "vpcs", s.VPCs.List())
+	})
+
+	s.handle("GET /v2/vpcs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vpc, ok := s.VPCs.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"vpc": vpc})
+	})
+
+	s.handle("POST /v2/vpcs", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.VPCCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || req.RegionSlug == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name and region are required")
+			return
+		}
+		if req.IPRange == "" {
+			req.IPRange = s.freeIPRange()
+		}
+		if message := s.validateIPRange(req.IPRange); message != "" {
+			writeError(w, http.StatusUnprocessableEntity, message)
+			return
+		}
+
+		id := s.NextUUID("vpc")
+		vpc := godo.VPC{
+			ID:          id,
+			URN:         "do:vpc:" + id,
+			Name:        req.Name,
+			Description: req.Description,
+			IPRange:     req.IPRange,
+			RegionSlug:  req.RegionSlug,
+			CreatedAt:   time.Now().UTC(),
+			// The first VPC in a region becomes its default
+			Default: len(s.VPCs.Filter(func(v godo.VPC) bool { return v.RegionSlug == req.RegionSlug })) == 0,
+		}
+		s.VPCs.Put(vpc.ID, vpc)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"vpc": vpc})
+	})
+
+	updateVPC := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req struct {
+			Name        *string `json:"name"`
+			Description *string `json:"description"`
+			Default     *bool   `json:"default"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		vpc, ok := s.VPCs.Get(id)
+		if !ok {
+			notFound(w)
+			return
+		}
+		if req.Name != nil && *req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name cannot be empty")
+			return
+		}
+		if req.Default != nil && !*req.Default && vpc.Default {
+			writeError(w, http.StatusUnprocessableEntity, "a region's default VPC can only be changed by making another VPC the default")
+			return
+		}
+		if req.Default != nil && *req.Default {
+			for _, other := range s.VPCs.List() {
+				if other.RegionSlug == vpc.RegionSlug {
+					s.VPCs.Update(other.ID, func(v *godo.VPC) { v.Default = false })
+				}
+			}
+		}
+		s.VPCs.Update(id, func(v *godo.VPC) {
+			if req.Name != nil {
+				v.Name = *req.Name
+			}
+			if req.Description != nil {
+				v.Description = *req.Description
+			}
+			if req.Default != nil && *req.Default {
+				v.Default = true
+			}
+		})
+		vpc, _ = s.VPCs.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"vpc": vpc})
+	}
+	s.handle("PUT /v2/vpcs/{id}", updateVPC)
+	s.handle("PATCH /v2/vpcs/{id}", updateVPC)
+
+	s.handle("DELETE /v2/vpcs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		vpc, ok := s.VPCs.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		if vpc.Default {
+			writeError(w, http.StatusForbidden, "cannot delete the default VPC of a region")
+			return
+		}
+		if len(s.vpcMembers(vpc.ID, "")) > 0 {
+			writeError(w, http.StatusConflict, "cannot delete a VPC that still has members")
+			return
+		}
+		if len(s.vpcPeerings(vpc.ID)) > 0 {
+			writeError(w, http.StatusConflict, "cannot delete a VPC that is part of a peering")
+			return
+		}
+		s.VPCs.Delete(vpc.ID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.handle("GET /v2/vpcs/{id}/members", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.VPCs.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		resourceType := r.URL.Query().Get("resource_type")
+		if _, known := vpcMemberPrefixes[resourceType]; resourceType != "" && !known {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid resource_type: %s", resourceType))
+			return
+		}
+		listResponse(w, r, "members", s.vpcMembers(id, resourceType))
+	})
+
+	s.handle("GET /v2/vpcs/{id}/peerings", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if _, ok := s.VPCs.Get(id); !ok {
+			notFound(w)
+			return
+		}
+		listResponse(w, r, "vpc_peerings", s.vpcPeerings(id))
+	})
+
+	s.handle("GET /v2/vpc_peerings", func(w http.ResponseWriter, r *http.Request) {
+		listResponse(w, r, "vpc_peerings", s.VPCPeerings.List())
+	})
+
+	s.handle("GET /v2/vpc_peerings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		peering, ok := s.VPCPeerings.Get(r.PathValue("id"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"vpc_peering": peering})
+	})
+
+	s.handle("POST /v2/vpc_peerings", func(w http.ResponseWriter, r *http.Request) {
+		var req godo.VPCPeeringCreateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" || len(req.VPCIDs) != 2 || req.VPCIDs[0] == req.VPCIDs[1] {
+			writeError(w, http.StatusUnprocessableEntity, "name and two distinct vpc_ids are required")
+			return
+		}
+		var ranges []netip.Prefix
+		for _, id := range req.VPCIDs {
+			vpc, ok := s.VPCs.Get(id)
+			if !ok {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("vpc %s does not exist", id))
+				return
+			}
+			prefix, _ := netip.ParsePrefix(vpc.IPRange)
+			ranges = append(ranges, prefix)
+		}
+		if ranges[0].Overlaps(ranges[1]) {
+			writeError(w, http.StatusUnprocessableEntity, "cannot peer VPCs with overlapping IP ranges")
+			return
+		}
+		for _, existing := range s.vpcPeerings(req.VPCIDs[0]) {
+			if slices.Contains(existing.VPCIDs, req.VPCIDs[1]) {
+				writeError(w, http.StatusConflict, "the VPCs are already peered")
+				return
+			}
+		}
+
+		peering := godo.VPCPeering{
+			ID:        s.NextUUID("peering"),
+			Name:      req.Name,
+			VPCIDs:    req.VPCIDs,
+			CreatedAt: time.Now().UTC(),
+			Status:    "ACTIVE",
+		}
+		s.VPCPeerings.Put(peering.ID, peering)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"vpc_peering": peering})
+	})
+
+	s.handle("PATCH /v2/vpc_peerings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		var req godo.VPCPeeringUpdateRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name is required")
+			return
+		}
+		if !s.VPCPeerings.Update(id, func(p *godo.VPCPeering) { p.Name = req.Name }) {
+			notFound(w)
+			return
+		}
+		peering, _ := s.VPCPeerings.Get(id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"vpc_peering": peering})
+	})
+
+	s.handle("DELETE /v2/vpc_peerings/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !s.VPCPeerings.Delete(r.PathValue("id")) {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// AddVPC stores a VPC in region with the given IP range and returns its ID.
+func (s *Server) AddVPC(name, region, ipRange string, isDefault bool) string {
+	id := s.NextUUID("vpc")
+	s.VPCs.Put(id, godo.VPC{
+		ID:         id,
+		URN:        "do:vpc:" + id,
+		Name:       name,
+		IPRange:    ipRange,
+		RegionSlug: region,
+		Default:    isDefault,
+		CreatedAt:  time.Now().UTC(),
+	})
+	return id
+}
+
+// vpcMembers lists the droplets, load balancers and clusters placed in a VPC,
+// optionally limited to one resource type.
+func (s *Server) vpcMembers(vpcID, resourceType string) []godo.VPCMember {
+	members := []godo.VPCMember{}
+	add := func(kind, id, name string, created time.Time) {
+		if resourceType == "" || resourceType == kind {
+			members = append(members, godo.VPCMember{URN: vpcMemberPrefixes[kind] + id, Name: name, CreatedAt: created})
+		}
+	}
+	for _, droplet := range s.Droplets.List() {
+		if droplet.VPCUUID == vpcID {
+			created, _ := time.Parse(time.RFC3339, droplet.Created)
+			add("droplet", strconv.Itoa(droplet.ID), droplet.Name, created)
+		}
+	}
+	for _, lb := range s.LoadBalancers.List() {
+		if lb.VPCUUID == vpcID {
+			created, _ := time.Parse(time.RFC3339, lb.Created)
+			add("load_balancer", lb.ID, lb.Name, created)
+		}
+	}
+	for _, cluster := range s.Clusters.List() {
+		if cluster.VPCUUID == vpcID {
+			add("kubernetes", cluster.ID, cluster.Name, cluster.CreatedAt)
+		}
+	}
+	return members
+}
+
+func (s *Server) vpcPeerings(vpcID string) []godo.VPCPeering {
+	return s.VPCPeerings.Filter(func(p godo.VPCPeering) bool { return slices.Contains(p.VPCIDs, vpcID) })
+}
+
+// validateIPRange checks a VPC range the way the API does: a private network
+// between /16 and /28 that does not overlap another VPC.
+func (s *Server) validateIPRange(ipRange string) string {
+	prefix, err := netip.ParsePrefix(ipRange)
+	if err != nil || !prefix.Addr().Is4() || !prefix.Addr().IsPrivate() {
+		return fmt.Sprintf("ip_range %s is not a private IPv4 network", ipRange)
+	}
+	if prefix.Bits() < 16 || prefix.Bits() > 28 {
+		return "ip_range must be between /16 and /28"
+	}
+	for _, vpc := range s.VPCs.List() {
+		if other, err := netip.ParsePrefix(vpc.IPRange); err == nil && other.Overlaps(prefix) {
+			return fmt.Sprintf("ip_range %s overlaps VPC %s (%s)", ipRange, vpc.Name, vpc.IPRange)
+		}
+	}
+	return ""
+}
+
+// freeIPRange picks the first unused /20 in 10.100.0.0/16 onwards.
+func (s *Server) freeIPRange() string {
+	for i := 100; i < 255; i++ {
+		ipRange := fmt.Sprintf("10.%d.0.0/20", i)
+		if s.validateIPRange(ipRange) == "" {
+			return ipRange
+		}
+	}
+	return ""
+}
+
+// checkVPC reports why vpcUUID cannot hold a resource in region, or "" if it
+// can. An empty vpcUUID is always accepted.
+func (s *Server) checkVPC(vpcUUID, region string) string {
+	if vpcUUID == "" {
+		return ""
+	}
+	vpc, ok := s.VPCs.Get(vpcUUID)
+	if !ok {
+		return fmt.Sprintf("vpc %s does not exist", vpcUUID)
+	}
+	if vpc.RegionSlug != region {
+		return fmt.Sprintf("vpc %s is in %s, not %s", vpcUUID, vpc.RegionSlug, region)
+	}
+	return ""
+}
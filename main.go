@@ -2,17 +2,21 @@ package main
 
 import (
 	"digitalocean-mcp-server/server"
+	"flag"
 	"log"
 	"os"
 )
 
 func main() {
-	done := make(chan struct{})
+	opts := server.OptionsFromEnv()
+	flag.StringVar(&opts.Transport, "transport", opts.Transport, "MCP transport: stdio, http (streamable HTTP) or sse")
+	flag.StringVar(&opts.Addr, "addr", opts.Addr, "Listen address for the http and sse transports")
+	flag.StringVar(&opts.PolicyFile, "policy", opts.PolicyFile, "Path to a JSON tool policy file")
+	flag.BoolVar(&opts.ReadOnly, "read-only", opts.ReadOnly, "Only register tools that do not modify infrastructure")
+	flag.Parse()
 
-	if err := server.Start(); err != nil {
+	if err := server.Start(opts); err != nil {
 		log.Printf("Server failed to start: %v", err)
 		os.Exit(1)
 	}
-	<-done
-
 }
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"crypto/rand"
+	"digitalocean-mcp-server/handlers"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+const confirmationTTL = 5 * time.Minute
+
+// confirmationHint is appended to the description of every gated tool so the
+// model knows it has to call the tool twice.
+const confirmationHint = "Destructive: the first call returns a preview and a confirmation_token; call again with the same arguments plus confirmation_token to proceed."
+
+// confirmable is implemented by argument types that embed types.ConfirmArgs.
+type confirmable interface {
+	GetConfirmationToken() string
+}
+
+// scopedPreview is implemented by previews of calls that look their targets up
+// as they run, such as handlers.DeletionPreview for the by-tag droplet tools.
+type scopedPreview interface {
+	ConfirmationScope() string
+}
+
+type pendingConfirmation struct {
+	tool        string
+	fingerprint string
+	// scope is the preview's ConfirmationScope; a non-empty scope is checked
+	// again before the confirmed call runs.
+	scope     string
+	expiresAt time.Time
+}
+
+// confirmationGate wraps destructive tools in a two-phase protocol. The first
+// call runs the tool's Preview and issues a single-use token bound to the tool
+// name and arguments; only a second call presenting that token runs Handler.
+// When the preview has a confirmation scope, the token is also bound to it:
+// the preview runs again on confirmation and the call is refused if the
+// matched resources changed.
+type confirmationGate struct {
+	handler *handlers.Handler
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+func newConfirmationGate(handler *handlers.Handler, ttl time.Duration) *confirmationGate {
+	return &confirmationGate{
+		handler: handler,
+		ttl:     ttl,
+		now:     time.Now,
+		pending: make(map[string]pendingConfirmation),
+	}
+}
+
+// wrap returns a handler with the same signature as tool.Handler that enforces
+// the confirmation protocol.
+func (g *confirmationGate) wrap(tool ToolDefinition) (interface{}, error) {
+	handlerValue := reflect.ValueOf(tool.Handler)
+	previewValue := reflect.ValueOf(tool.Preview)
+	handlerType := handlerValue.Type()
+	previewType := previewValue.Type()
+
+	if handlerType.Kind() != reflect.Func || handlerType.NumIn() != 1 || handlerType.NumOut() != 2 || handlerType.Out(0) != reflect.TypeOf(&mcp_golang.ToolResponse{}) {
+		return nil, fmt.Errorf("tool %s: handler must have the signature func(args) (*mcp_golang.ToolResponse, error)", tool.Name)
+	}
+	argsType := handlerType.In(0)
+	if !argsType.Implements(reflect.TypeOf((*confirmable)(nil)).Elem()) {
+		return nil, fmt.Errorf("tool %s: %s must embed types.ConfirmArgs", tool.Name, argsType)
+	}
+	if previewType.Kind() != reflect.Func || previewType.NumIn() != 1 || previewType.In(0) != argsType || previewType.NumOut() != 2 {
+		return nil, fmt.Errorf("tool %s: preview must have the signature func(%s) (T, error)", tool.Name, argsType)
+	}
+
+	wrapped := reflect.MakeFunc(handlerType, func(in []reflect.Value) []reflect.Value {
+		args := in[0].Interface()
+
+		fingerprint, err := argsFingerprint(args)
+		if err != nil {
+			return toolResult(g.handler.HandleError(err, tool.Name))
+		}
+
+		token := args.(confirmable).GetConfirmationToken()
+		if token == "" {
+			out := previewValue.Call(in)
+			if err, _ := out[1].Interface().(error); err != nil {
+				return toolResult(g.handler.HandleError(err, tool.Name+" (preview)"))
+			}
+
+			token, expiresAt, err := g.issue(tool.Name, fingerprint, previewScope(out[0]))
+			if err != nil {
+				return toolResult(g.handler.HandleError(err, tool.Name))
+			}
+
+			return toolResult(g.handler.HandleSuccess(map[string]interface{}{
+				"status":             "confirmation_required",
+				"preview":            out[0].Interface(),
+				"confirmation_token": token,
+				"expires_at":         expiresAt.UTC().Format(time.RFC3339),
+				"message":            fmt.Sprintf("Nothing has been changed yet. Call %s again with the same arguments and this confirmation_token to proceed.", tool.Name),
+			}, tool.Name))
+		}
+
+		scope, err := g.redeem(token, tool.Name, fingerprint)
+		if err != nil {
+			return toolResult(g.handler.HandleError(err, tool.Name))
+		}
+		if scope != "" {
+			out := previewValue.Call(in)
+			if err, _ := out[1].Interface().(error); err != nil {
+				return toolResult(g.handler.HandleError(err, tool.Name+" (preview)"))
+			}
+			if previewScope(out[0]) != scope {
+				return toolResult(g.handler.HandleError(fmt.Errorf("the resources matched by this call changed since the preview; call %s without a token to review them again", tool.Name), tool.Name))
+			}
+		}
+
+		return handlerValue.Call(in)
+	})
+
+	return wrapped.Interface(), nil
+}
+
+func (g *confirmationGate) issue(tool, fingerprint, scope string) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for existing, pending := range g.pending {
+		if now.After(pending.expiresAt) {
+			delete(g.pending, existing)
+		}
+	}
+
+	expiresAt := now.Add(g.ttl)
+	g.pending[token] = pendingConfirmation{
+		tool:        tool,
+		fingerprint: fingerprint,
+		scope:       scope,
+		expiresAt:   expiresAt,
+	}
+
+	return token, expiresAt, nil
+}
+
+// redeem consumes token if it was issued for this exact tool call and returns
+// the scope of its preview. Tokens are single-use, so a failed or successful
+// redemption both invalidate it.
+func (g *confirmationGate) redeem(token, tool, fingerprint string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	pending, ok := g.pending[token]
+	delete(g.pending, token)
+
+	switch {
+	case !ok:
+		return "", fmt.Errorf("unknown or already used confirmation token; call %s without a token to get a new preview", tool)
+	case g.now().After(pending.expiresAt):
+		return "", fmt.Errorf("confirmation token expired; call %s without a token to get a new preview", tool)
+	case pending.tool != tool || pending.fingerprint != fingerprint:
+		return "", fmt.Errorf("confirmation token was issued for a different call; call %s without a token to get a new preview", tool)
+	}
+
+	return pending.scope, nil
+}
+
+func previewScope(preview reflect.Value) string {
+	if scoped, ok := preview.Interface().(scopedPreview); ok {
+		return scoped.ConfirmationScope()
+	}
+	return ""
+}
+
+// argsFingerprint serializes the tool arguments without the confirmation token
+// so a token only confirms the call that was previewed.
+func argsFingerprint(args interface{}) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	delete(fields, "confirmation_token")
+
+	// encoding/json sorts map keys, so the result is stable.
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func toolResult(response *mcp_golang.ToolResponse, err error) []reflect.Value {
+	return []reflect.Value{reflect.ValueOf(response), reflect.ValueOf(&err).Elem()}
+}
@@ -0,0 +1,373 @@
+package server
+
+import (
+	"digitalocean-mcp-server/client"
+	"digitalocean-mcp-server/handlers"
+	"digitalocean-mcp-server/internal/fakedo"
+	"digitalocean-mcp-server/types"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	mcp_golang "github.com/metoro-io/mcp-golang"
+)
+
+func newGatedDeleteDroplet(t *testing.T) (func(types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error), *confirmationGate, *fakedo.Server) {
+	t.Helper()
+
+	fake := fakedo.New(t)
+	doClient, err := client.NewDOClientWithBaseURL("test-token", fake.URL())
+	if err != nil {
+		t.Fatalf("NewDOClientWithBaseURL: %v", err)
+	}
+	handler := handlers.NewHandler(doClient)
+
+	gate := newConfirmationGate(handler, confirmationTTL)
+	wrapped, err := gate.wrap(ToolDefinition{
+		Name: "delete_droplet",
+		Handler: func(arguments types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error) {
+			return handler.DeleteDroplet(arguments.DropletID)
+		},
+		Preview: func(arguments types.DeleteDropletArgs) (*handlers.DeletionPreview, error) {
+			return handler.PreviewDeleteDroplet(arguments.DropletID)
+		},
+	})
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+
+	for _, id := range []int{1, 2} {
+		fake.Droplets.Put(id, godo.Droplet{ID: id, Name: "web", Size: &godo.Size{PriceMonthly: 6}})
+	}
+
+	return wrapped.(func(types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error)), gate, fake
+}
+
+func requestConfirmation(t *testing.T, deleteDroplet func(types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error), id int) string {
+	t.Helper()
+
+	resp, err := deleteDroplet(types.DeleteDropletArgs{DropletID: id})
+	if err != nil {
+		t.Fatalf("preview call failed: %v", err)
+	}
+	var result struct {
+		Status            string                   `json:"status"`
+		ConfirmationToken string                   `json:"confirmation_token"`
+		Preview           handlers.DeletionPreview `json:"preview"`
+	}
+	if err := json.Unmarshal([]byte(resp.Content[0].TextContent.Text), &result); err != nil {
+		t.Fatalf("invalid preview response: %v", err)
+	}
+	if result.Status != "confirmation_required" || result.ConfirmationToken == "" || result.Preview.EstimatedMonthlyCost != 6 {
+		t.Fatalf("unexpected preview response %+v", result)
+	}
+	return result.ConfirmationToken
+}
+
+func TestConfirmationGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		confirm func(gate *confirmationGate, token string) types.DeleteDropletArgs
+		wantErr string
+	}{
+		{
+			name: "matching token deletes",
+			confirm: func(gate *confirmationGate, token string) types.DeleteDropletArgs {
+				return types.DeleteDropletArgs{DropletID: 1, ConfirmArgs: types.ConfirmArgs{ConfirmationToken: token}}
+			},
+		},
+		{
+			name: "token for different arguments",
+			confirm: func(gate *confirmationGate, token string) types.DeleteDropletArgs {
+				return types.DeleteDropletArgs{DropletID: 2, ConfirmArgs: types.ConfirmArgs{ConfirmationToken: token}}
+			},
+			wantErr: "issued for a different call",
+		},
+		{
+			name: "unknown token",
+			confirm: func(gate *confirmationGate, token string) types.DeleteDropletArgs {
+				return types.DeleteDropletArgs{DropletID: 1, ConfirmArgs: types.ConfirmArgs{ConfirmationToken: "bogus"}}
+			},
+			wantErr: "unknown or already used",
+		},
+		{
+			name: "expired token",
+			confirm: func(gate *confirmationGate, token string) types.DeleteDropletArgs {
+				gate.now = func() time.Time { return time.Now().Add(confirmationTTL + time.Second) }
+				return types.DeleteDropletArgs{DropletID: 1, ConfirmArgs: types.ConfirmArgs{ConfirmationToken: token}}
+			},
+			wantErr: "expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleteDroplet, gate, fake := newGatedDeleteDroplet(t)
+
+			token := requestConfirmation(t, deleteDroplet, 1)
+			if _, ok := fake.Droplets.Get(1); !ok {
+				t.Fatalf("the preview call must not delete anything")
+			}
+
+			_, err := deleteDroplet(tt.confirm(gate, token))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if fake.Droplets.Len() != 2 {
+					t.Errorf("a rejected confirmation must not delete anything")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("confirmed call failed: %v", err)
+			}
+			if _, ok := fake.Droplets.Get(1); ok {
+				t.Errorf("droplet 1 was not deleted")
+			}
+
+			_, err = deleteDroplet(tt.confirm(gate, token))
+			if err == nil || !strings.Contains(err.Error(), "already used") {
+				t.Errorf("reusing a token should fail, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfirmationGatePreviewError(t *testing.T) {
+	deleteDroplet, _, _ := newGatedDeleteDroplet(t)
+
+	_, err := deleteDroplet(types.DeleteDropletArgs{DropletID: 99})
+	if err == nil || !strings.Contains(err.Error(), "error in delete_droplet (preview)") {
+		t.Errorf("error = %v, want a preview error", err)
+	}
+}
+
+func TestConfirmationTokenLifecycle(t *testing.T) {
+	start := time.Date(2024, time.May, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		redeem  func(gate *confirmationGate, token string) error
+		wantErr string
+	}{
+		{
+			name: "redeemed at the end of its lifetime",
+			redeem: func(gate *confirmationGate, token string) error {
+				_, err := at(gate, start.Add(confirmationTTL)).redeem(token, "delete_droplet", `{"droplet_id":1}`)
+				return err
+			},
+		},
+		{
+			name: "redeemed after it expired",
+			redeem: func(gate *confirmationGate, token string) error {
+				_, err := at(gate, start.Add(confirmationTTL+time.Second)).redeem(token, "delete_droplet", `{"droplet_id":1}`)
+				return err
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "redeemed for another tool",
+			redeem: func(gate *confirmationGate, token string) error {
+				_, err := gate.redeem(token, "delete_volume", `{"droplet_id":1}`)
+				return err
+			},
+			wantErr: "issued for a different call",
+		},
+		{
+			name: "redeemed for other arguments",
+			redeem: func(gate *confirmationGate, token string) error {
+				_, err := gate.redeem(token, "delete_droplet", `{"droplet_id":2}`)
+				return err
+			},
+			wantErr: "issued for a different call",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := at(newConfirmationGate(handlers.NewHandler(nil), confirmationTTL), start)
+			token, expiresAt, err := gate.issue("delete_droplet", `{"droplet_id":1}`, "")
+			if err != nil || len(token) != 32 || !expiresAt.Equal(start.Add(confirmationTTL)) {
+				t.Fatalf("issue = %q, %v, %v", token, expiresAt, err)
+			}
+
+			err = tt.redeem(gate, token)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("redeem: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("redeem error = %v, want %q", err, tt.wantErr)
+			}
+
+			// Whatever the outcome, the token cannot be used again
+			_, err = at(gate, start).redeem(token, "delete_droplet", `{"droplet_id":1}`)
+			if err == nil || !strings.Contains(err.Error(), "unknown or already used") {
+				t.Errorf("second redeem error = %v, want the token to be used up", err)
+			}
+		})
+	}
+}
+
+func TestConfirmationGateDropsExpiredTokens(t *testing.T) {
+	start := time.Date(2024, time.May, 15, 10, 0, 0, 0, time.UTC)
+	gate := at(newConfirmationGate(handlers.NewHandler(nil), confirmationTTL), start)
+	first, _, _ := gate.issue("delete_droplet", `{"droplet_id":1}`, "")
+	second, _, _ := gate.issue("delete_droplet", `{"droplet_id":1}`, "")
+	if first == second {
+		t.Fatalf("tokens must be unique, got %q twice", first)
+	}
+
+	at(gate, start.Add(confirmationTTL+time.Second)).issue("delete_droplet", `{"droplet_id":2}`, "")
+	if len(gate.pending) != 1 {
+		t.Errorf("pending tokens = %d, want only the new one", len(gate.pending))
+	}
+}
+
+func TestArgsFingerprint(t *testing.T) {
+	preview, err := argsFingerprint(types.DeleteDropletArgs{DropletID: 1})
+	if err != nil {
+		t.Fatalf("argsFingerprint: %v", err)
+	}
+	confirm, _ := argsFingerprint(types.DeleteDropletArgs{DropletID: 1, ConfirmArgs: types.ConfirmArgs{ConfirmationToken: "abc"}})
+	other, _ := argsFingerprint(types.DeleteDropletArgs{DropletID: 2})
+
+	if preview != confirm {
+		t.Errorf("the token must not be part of the fingerprint: %s != %s", preview, confirm)
+	}
+	if preview == other {
+		t.Errorf("different arguments share the fingerprint %s", preview)
+	}
+}
+
+func TestConfirmationGateWrapRejectsInvalidTools(t *testing.T) {
+	gate := newConfirmationGate(handlers.NewHandler(nil), confirmationTTL)
+	tests := []struct {
+		name    string
+		tool    ToolDefinition
+		wantErr string
+	}{
+		{
+			name: "arguments without a token",
+			tool: ToolDefinition{
+				Name:    "delete_droplet",
+				Handler: func(arguments types.GetDropletArgs) (*mcp_golang.ToolResponse, error) { return nil, nil },
+				Preview: func(arguments types.GetDropletArgs) (*handlers.DeletionPreview, error) { return nil, nil },
+			},
+			wantErr: "must embed types.ConfirmArgs",
+		},
+		{
+			name: "preview for other arguments",
+			tool: ToolDefinition{
+				Name:    "delete_droplet",
+				Handler: func(arguments types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error) { return nil, nil },
+				Preview: func(arguments types.GetDropletArgs) (*handlers.DeletionPreview, error) { return nil, nil },
+			},
+			wantErr: "preview must have the signature",
+		},
+		{
+			name: "handler without a response",
+			tool: ToolDefinition{
+				Name:    "delete_droplet",
+				Handler: func(arguments types.DeleteDropletArgs) error { return nil },
+				Preview: func(arguments types.DeleteDropletArgs) (*handlers.DeletionPreview, error) { return nil, nil },
+			},
+			wantErr: "handler must have the signature",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gate.wrap(tt.tool); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("wrap error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// at pins the gate's clock to now.
+func at(gate *confirmationGate, now time.Time) *confirmationGate {
+	gate.now = func() time.Time { return now }
+	return gate
+}
+
+func TestConfirmationGateByTagScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(fake *fakedo.Server)
+		wantErr string
+		wantLen int
+	}{
+		{name: "unchanged", wantLen: 1},
+		{
+			name: "droplet tagged after the preview",
+			change: func(fake *fakedo.Server) {
+				fake.Droplets.Update(3, func(droplet *godo.Droplet) { droplet.Tags = []string{"web"} })
+			},
+			wantErr: "changed since the preview",
+			wantLen: 3,
+		},
+		{
+			name: "droplet untagged after the preview",
+			change: func(fake *fakedo.Server) {
+				fake.Droplets.Update(2, func(droplet *godo.Droplet) { droplet.Tags = nil })
+			},
+			wantErr: "changed since the preview",
+			wantLen: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := fakedo.New(t)
+			doClient, err := client.NewDOClientWithBaseURL("test-token", fake.URL())
+			if err != nil {
+				t.Fatalf("NewDOClientWithBaseURL: %v", err)
+			}
+			handler := handlers.NewHandler(doClient)
+			wrapped, err := newConfirmationGate(handler, confirmationTTL).wrap(ToolDefinition{
+				Name: "delete_droplets_by_tag",
+				Handler: func(arguments types.DeleteDropletsByTagArgs) (*mcp_golang.ToolResponse, error) {
+					return handler.DeleteDropletsByTag(arguments.Tag)
+				},
+				Preview: func(arguments types.DeleteDropletsByTagArgs) (*handlers.DeletionPreview, error) {
+					return handler.PreviewDeleteDropletsByTag(arguments.Tag)
+				},
+			})
+			if err != nil {
+				t.Fatalf("wrap: %v", err)
+			}
+			deleteByTag := wrapped.(func(types.DeleteDropletsByTagArgs) (*mcp_golang.ToolResponse, error))
+
+			fake.Droplets.Put(1, godo.Droplet{ID: 1, Name: "web-1", Tags: []string{"web"}})
+			fake.Droplets.Put(2, godo.Droplet{ID: 2, Name: "web-2", Tags: []string{"web"}})
+			fake.Droplets.Put(3, godo.Droplet{ID: 3, Name: "db-1", Tags: []string{"db"}})
+
+			resp, err := deleteByTag(types.DeleteDropletsByTagArgs{Tag: "web"})
+			if err != nil {
+				t.Fatalf("preview call failed: %v", err)
+			}
+			var result struct {
+				ConfirmationToken string `json:"confirmation_token"`
+			}
+			if err := json.Unmarshal([]byte(resp.Content[0].TextContent.Text), &result); err != nil || result.ConfirmationToken == "" {
+				t.Fatalf("invalid preview response: %v", err)
+			}
+			if tt.change != nil {
+				tt.change(fake)
+			}
+
+			_, err = deleteByTag(types.DeleteDropletsByTagArgs{Tag: "web", ConfirmArgs: types.ConfirmArgs{ConfirmationToken: result.ConfirmationToken}})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("confirmed call failed: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if fake.Droplets.Len() != tt.wantLen {
+				t.Errorf("droplets left = %d, want %d", fake.Droplets.Len(), tt.wantLen)
+			}
+		})
+	}
+}
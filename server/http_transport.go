@@ -0,0 +1,585 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+const (
+	// maxMessageSize caps the size of a single JSON-RPC message read from a client
+	maxMessageSize = 4 << 20
+
+	streamableEndpoint = "/mcp"
+	sseEndpoint        = "/sse"
+	sseMessageEndpoint = "/message"
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// HTTPAccess restricts who can use an HTTP transport. Requests carrying an
+// Origin header, which browsers always send, must come from a loopback origin
+// or one listed in AllowedOrigins; this stops DNS rebinding attacks from web
+// pages. Token, when set, must be presented as "Authorization: Bearer <token>".
+type HTTPAccess struct {
+	Token string
+	// AllowedOrigins are full origins such as "https://app.example.com"
+	AllowedOrigins []string
+}
+
+// HTTPTransport serves MCP over HTTP. In streamable mode clients POST each
+// JSON-RPC message to /mcp and receive the response in the HTTP reply. In SSE
+// mode clients hold a GET /sse event stream open, POST messages to the
+// per-session endpoint announced on that stream, and receive responses as
+// "message" events.
+//
+// A single transport is shared by every connected client, so incoming request
+// IDs are rewritten to transport-unique IDs before they reach the protocol and
+// restored before the response is written back.
+type HTTPTransport struct {
+	mode   string
+	addr   string
+	access HTTPAccess
+	server *http.Server
+
+	mu       sync.Mutex
+	nextID   transport.RequestId
+	pending  map[transport.RequestId]*pendingRequest
+	sessions map[string]*sseSession
+	// draining is set by Shutdown; drained is closed once no request is
+	// pending any more.
+	draining  bool
+	drained   chan struct{}
+	shutdown  chan struct{}
+	onMessage func(ctx context.Context, message *transport.BaseJsonRpcMessage)
+	onError   func(error)
+	onClose   func()
+}
+
+type pendingRequest struct {
+	originalID transport.RequestId
+	reply      chan *transport.BaseJsonRpcMessage
+	session    *sseSession
+}
+
+type sseSession struct {
+	id     string
+	ctx    context.Context
+	events chan []byte
+	done   chan struct{}
+}
+
+// NewHTTPTransport creates a transport listening on addr. mode must be
+// TransportHTTP (streamable HTTP) or TransportSSE.
+func NewHTTPTransport(mode, addr string, access HTTPAccess) (*HTTPTransport, error) {
+	if mode != TransportHTTP && mode != TransportSSE {
+		return nil, fmt.Errorf("unsupported HTTP transport mode: %s", mode)
+	}
+
+	t := &HTTPTransport{
+		mode:     mode,
+		addr:     addr,
+		access:   access,
+		pending:  make(map[transport.RequestId]*pendingRequest),
+		sessions: make(map[string]*sseSession),
+		shutdown: make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	if mode == TransportHTTP {
+		mux.HandleFunc(streamableEndpoint, t.handleStreamable)
+	} else {
+		mux.HandleFunc(sseEndpoint, t.handleSSEStream)
+		mux.HandleFunc(sseMessageEndpoint, t.handleSSEMessage)
+	}
+	t.server = &http.Server{
+		Addr:    addr,
+		Handler: t.authorize(mux),
+	}
+
+	return t, nil
+}
+
+// Start binds the listen address and serves requests in the background.
+func (t *HTTPTransport) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", t.addr, err)
+	}
+	t.addr = listener.Addr().String()
+
+	go func() {
+		if err := t.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			t.reportError(fmt.Errorf("HTTP server error: %w", err))
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the transport is listening on.
+func (t *HTTPTransport) Addr() string {
+	return t.addr
+}
+
+// Send routes a message from the server back to the client that is waiting for it.
+func (t *HTTPTransport) Send(ctx context.Context, message *transport.BaseJsonRpcMessage) error {
+	var id transport.RequestId
+	switch message.Type {
+	case transport.BaseMessageTypeJSONRPCResponseType:
+		id = message.JsonRpcResponse.Id
+	case transport.BaseMessageTypeJSONRPCErrorType:
+		id = message.JsonRpcError.Id
+	case transport.BaseMessageTypeJSONRPCNotificationType:
+		return t.broadcast(message)
+	default:
+		return fmt.Errorf("unsupported outgoing message type: %s", message.Type)
+	}
+
+	t.mu.Lock()
+	req, ok := t.pending[id]
+	t.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no pending request for id %d", id)
+	}
+	// The request stays pending until its response is queued, so Shutdown
+	// cannot close the SSE stream between the two.
+	defer t.finish(id)
+
+	if message.Type == transport.BaseMessageTypeJSONRPCResponseType {
+		message.JsonRpcResponse.Id = req.originalID
+	} else {
+		message.JsonRpcError.Id = req.originalID
+	}
+
+	if req.reply != nil {
+		req.reply <- message
+		return nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return req.session.send(data)
+}
+
+// Close stops the HTTP server immediately, dropping in-flight requests.
+func (t *HTTPTransport) Close() error {
+	t.closeStreams()
+	err := t.server.Close()
+	t.notifyClose()
+	return err
+}
+
+// Shutdown stops accepting new messages and waits for in-flight tool calls to
+// deliver their responses or for ctx to expire. Only then are open SSE streams
+// closed and the HTTP server stopped.
+func (t *HTTPTransport) Shutdown(ctx context.Context) error {
+	var err error
+	select {
+	case <-t.drain():
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	t.closeStreams()
+	if shutdownErr := t.server.Shutdown(ctx); err == nil {
+		err = shutdownErr
+	}
+	t.notifyClose()
+	return err
+}
+
+func (t *HTTPTransport) SetCloseHandler(handler func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onClose = handler
+}
+
+func (t *HTTPTransport) SetErrorHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onError = handler
+}
+
+func (t *HTTPTransport) SetMessageHandler(handler func(ctx context.Context, message *transport.BaseJsonRpcMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onMessage = handler
+}
+
+// authorize rejects requests from origins that are not allowed and, when a
+// token is configured, requests that do not present it.
+func (t *HTTPTransport) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !t.originAllowed(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if t.access.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(t.access.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t *HTTPTransport) originAllowed(origin string) bool {
+	for _, allowed := range t.access.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	return isLoopback(parsed.Host)
+}
+
+// isLoopback reports whether a host or host:port names this machine only.
+func isLoopback(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (t *HTTPTransport) handleStreamable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	reply := make(chan *transport.BaseJsonRpcMessage, 1)
+	id, isRequest, err := t.dispatch(r.Context(), body, &pendingRequest{reply: reply})
+	if err != nil {
+		dispatchError(w, err)
+		return
+	}
+	if !isRequest {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	select {
+	case message := <-reply:
+		data, err := json.Marshal(message)
+		if err != nil {
+			http.Error(w, "failed to marshal response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	case <-r.Context().Done():
+		t.finish(id)
+	}
+}
+
+func (t *HTTPTransport) handleSSEStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Only GET method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	session, err := t.openSession()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer t.closeSession(session)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", sseMessageEndpoint, session.id)
+	flusher.Flush()
+
+	for {
+		select {
+		case data := <-session.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-t.shutdown:
+			// Responses queued by the last in-flight calls still go out
+			for {
+				select {
+				case data := <-session.events:
+					fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+					flusher.Flush()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *HTTPTransport) handleSSEMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t.mu.Lock()
+	session, ok := t.sessions[r.URL.Query().Get("sessionId")]
+	t.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	// The response is delivered over the event stream, so the tool call must
+	// outlive this POST and is bound to the session instead.
+	if _, _, err := t.dispatch(session.ctx, body, &pendingRequest{session: session}); err != nil {
+		dispatchError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// dispatch decodes a client message and hands it to the protocol. Requests are
+// registered as pending under a fresh ID so their response can be routed back.
+func (t *HTTPTransport) dispatch(ctx context.Context, body []byte, req *pendingRequest) (transport.RequestId, bool, error) {
+	t.mu.Lock()
+	handler := t.onMessage
+	draining := t.draining
+	t.mu.Unlock()
+	switch {
+	case draining:
+		return 0, false, errShuttingDown
+	case handler == nil:
+		return 0, false, fmt.Errorf("server is not ready")
+	}
+
+	var request transport.BaseJSONRPCRequest
+	if err := json.Unmarshal(body, &request); err == nil {
+		t.mu.Lock()
+		if t.draining {
+			t.mu.Unlock()
+			return 0, false, errShuttingDown
+		}
+		t.nextID++
+		id := t.nextID
+		req.originalID = request.Id
+		t.pending[id] = req
+		t.mu.Unlock()
+
+		request.Id = id
+		handler(ctx, transport.NewBaseMessageRequest(&request))
+		return id, true, nil
+	}
+
+	var notification transport.BaseJSONRPCNotification
+	if err := json.Unmarshal(body, &notification); err == nil {
+		handler(ctx, transport.NewBaseMessageNotification(&notification))
+		return 0, false, nil
+	}
+
+	// Clients may answer server requests; this server never issues any, so
+	// well-formed responses are accepted and dropped.
+	var response transport.BaseJSONRPCResponse
+	if err := json.Unmarshal(body, &response); err == nil {
+		return 0, false, nil
+	}
+	var errorResponse transport.BaseJSONRPCError
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		return 0, false, nil
+	}
+
+	return 0, false, fmt.Errorf("invalid JSON-RPC message")
+}
+
+func dispatchError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errShuttingDown) {
+		status = http.StatusServiceUnavailable
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// finish drops a request that has been answered or abandoned.
+func (t *HTTPTransport) finish(id transport.RequestId) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, id)
+	t.checkDrainedLocked()
+}
+
+// drain stops dispatching new messages and returns a channel that is closed
+// once every pending request has been answered.
+func (t *HTTPTransport) drain() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.draining {
+		t.draining = true
+		t.drained = make(chan struct{})
+		t.checkDrainedLocked()
+	}
+	return t.drained
+}
+
+func (t *HTTPTransport) checkDrainedLocked() {
+	if !t.draining || len(t.pending) > 0 {
+		return
+	}
+	select {
+	case <-t.drained:
+	default:
+		close(t.drained)
+	}
+}
+
+func (t *HTTPTransport) broadcast(message *transport.BaseJsonRpcMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	t.mu.Lock()
+	sessions := make([]*sseSession, 0, len(t.sessions))
+	for _, session := range t.sessions {
+		sessions = append(sessions, session)
+	}
+	t.mu.Unlock()
+
+	for _, session := range sessions {
+		if err := session.send(data); err != nil {
+			log.Printf("Failed to notify SSE session %s: %v", session.id, err)
+		}
+	}
+	return nil
+}
+
+func (t *HTTPTransport) openSession() (*sseSession, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &sseSession{
+		id:     hex.EncodeToString(buf),
+		ctx:    ctx,
+		events: make(chan []byte, 16),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		<-session.done
+		cancel()
+	}()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.shutdown:
+		cancel()
+		return nil, errShuttingDown
+	default:
+	}
+	if t.draining {
+		cancel()
+		return nil, errShuttingDown
+	}
+	t.sessions[session.id] = session
+	return session, nil
+}
+
+func (t *HTTPTransport) closeSession(session *sseSession) {
+	t.mu.Lock()
+	delete(t.sessions, session.id)
+	for id, req := range t.pending {
+		if req.session == session {
+			delete(t.pending, id)
+		}
+	}
+	t.checkDrainedLocked()
+	t.mu.Unlock()
+	close(session.done)
+}
+
+func (t *HTTPTransport) closeStreams() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.shutdown:
+	default:
+		close(t.shutdown)
+	}
+}
+
+func (t *HTTPTransport) notifyClose() {
+	t.mu.Lock()
+	handler := t.onClose
+	t.mu.Unlock()
+	if handler != nil {
+		handler()
+	}
+}
+
+func (t *HTTPTransport) reportError(err error) {
+	t.mu.Lock()
+	handler := t.onError
+	t.mu.Unlock()
+	if handler != nil {
+		handler(err)
+	} else {
+		log.Println(err)
+	}
+}
+
+func (s *sseSession) send(data []byte) error {
+	select {
+	case s.events <- data:
+		return nil
+	case <-s.done:
+		return fmt.Errorf("session %s is closed", s.id)
+	}
+}
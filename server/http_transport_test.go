@@ -0,0 +1,430 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"digitalocean-mcp-server/internal/fakedo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+// startHTTPServer serves every tool over a new transport, backed by the fake
+// API with two droplets.
+func startHTTPServer(t *testing.T, mode string) *HTTPTransport {
+	t.Helper()
+
+	fake := fakedo.New(t)
+	fake.Droplets.Put(1, godo.Droplet{ID: 1, Name: "web-1"})
+	fake.Droplets.Put(2, godo.Droplet{ID: 2, Name: "web-2"})
+	t.Setenv("DIGITALOCEAN_ACCESS_TOKEN", "test-token")
+	t.Setenv("DIGITALOCEAN_API_URL", fake.URL())
+
+	tr, err := NewHTTPTransport(mode, "127.0.0.1:0", HTTPAccess{})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %v", err)
+	}
+	server, err := NewServer(tr, &ToolPolicy{})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+	return tr
+}
+
+// startStubTransport starts a transport whose messages go to handle instead of
+// the MCP protocol.
+func startStubTransport(t *testing.T, mode string, handle func(request *transport.BaseJSONRPCRequest)) *HTTPTransport {
+	t.Helper()
+	return startStubTransportWithAccess(t, mode, HTTPAccess{}, handle)
+}
+
+func startStubTransportWithAccess(t *testing.T, mode string, access HTTPAccess, handle func(request *transport.BaseJSONRPCRequest)) *HTTPTransport {
+	t.Helper()
+
+	tr, err := NewHTTPTransport(mode, "127.0.0.1:0", access)
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %v", err)
+	}
+	tr.SetMessageHandler(func(ctx context.Context, message *transport.BaseJsonRpcMessage) {
+		if message.Type == transport.BaseMessageTypeJSONRPCRequestType {
+			go handle(message.JsonRpcRequest)
+		}
+	})
+	if err := tr.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { tr.Close() })
+	return tr
+}
+
+// reply answers request with a result naming the ID the protocol saw.
+func reply(t *testing.T, tr *HTTPTransport, request *transport.BaseJSONRPCRequest) {
+	err := tr.Send(context.Background(), transport.NewBaseMessageResponse(&transport.BaseJSONRPCResponse{
+		Jsonrpc: "2.0",
+		Id:      request.Id,
+		Result:  json.RawMessage(fmt.Sprintf(`{"seen_id":%d}`, request.Id)),
+	}))
+	if err != nil {
+		t.Errorf("Send: %v", err)
+	}
+}
+
+func toolCall(id int, name, arguments string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":%s}}`, id, name, arguments)
+}
+
+func post(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+type jsonRPCResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+}
+
+// toolText returns the text of the first content item of a tools/call result.
+func (r jsonRPCResponse) toolText(t *testing.T) string {
+	t.Helper()
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(r.Result, &result); err != nil || len(result.Content) == 0 {
+		t.Fatalf("invalid tool result %s: %v", r.Result, err)
+	}
+	return result.Content[0].Text
+}
+
+// sseClient reads events from an open GET /sse stream.
+type sseClient struct {
+	body     io.ReadCloser
+	reader   *bufio.Reader
+	endpoint string
+}
+
+func openSSE(t *testing.T, tr *HTTPTransport) *sseClient {
+	t.Helper()
+
+	resp, err := http.Get("http://" + tr.Addr() + sseEndpoint)
+	if err != nil {
+		t.Fatalf("GET /sse: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /sse status = %d", resp.StatusCode)
+	}
+	client := &sseClient{body: resp.Body, reader: bufio.NewReader(resp.Body)}
+	t.Cleanup(func() { client.body.Close() })
+
+	event, data, err := client.next()
+	if err != nil || event != "endpoint" || !strings.HasPrefix(data, sseMessageEndpoint+"?sessionId=") {
+		t.Fatalf("first event = %q %q, %v; want the message endpoint", event, data, err)
+	}
+	client.endpoint = "http://" + tr.Addr() + data
+	return client
+}
+
+// next returns the next event on the stream.
+func (c *sseClient) next() (event, data string, err error) {
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return "", "", err
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && event != "":
+			return event, data, nil
+		}
+	}
+}
+
+func (c *sseClient) response(t *testing.T) jsonRPCResponse {
+	t.Helper()
+	event, data, err := c.next()
+	if err != nil || event != "message" {
+		t.Fatalf("event = %q, %v; want a message", event, err)
+	}
+	var resp jsonRPCResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("invalid message %q: %v", data, err)
+	}
+	return resp
+}
+
+func TestStreamableHTTPToolCall(t *testing.T) {
+	tr := startHTTPServer(t, TransportHTTP)
+	url := "http://" + tr.Addr() + streamableEndpoint
+
+	resp := post(t, url, toolCall(7, "get_droplet", `{"droplet_id":2}`))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var result jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if result.ID != 7 || !strings.Contains(result.toolText(t), "web-2") {
+		t.Errorf("response = %d %s, want id 7 with droplet web-2", result.ID, result.Result)
+	}
+
+	if resp := post(t, url, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status = %d, want 202", resp.StatusCode)
+	}
+	if resp := post(t, url, `not json`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid message status = %d, want 400", resp.StatusCode)
+	}
+	if resp, err := http.Get(url); err != nil || resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /mcp = %v, %v; want 405", resp, err)
+	}
+}
+
+func TestSSEToolCall(t *testing.T) {
+	tr := startHTTPServer(t, TransportSSE)
+
+	// Both sessions use request ID 1; each must get its own answer back
+	first, second := openSSE(t, tr), openSSE(t, tr)
+	for _, call := range []struct {
+		client    *sseClient
+		dropletID int
+	}{{first, 1}, {second, 2}} {
+		resp := post(t, call.client.endpoint, toolCall(1, "get_droplet", fmt.Sprintf(`{"droplet_id":%d}`, call.dropletID)))
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("POST /message status = %d, want 202", resp.StatusCode)
+		}
+	}
+
+	for _, want := range []struct {
+		client *sseClient
+		name   string
+	}{{first, "web-1"}, {second, "web-2"}} {
+		result := want.client.response(t)
+		if result.ID != 1 || !strings.Contains(result.toolText(t), want.name) {
+			t.Errorf("response = %d %s, want id 1 with droplet %s", result.ID, result.Result, want.name)
+		}
+	}
+}
+
+func TestSSEUnknownSession(t *testing.T) {
+	tr := startHTTPServer(t, TransportSSE)
+	openSSE(t, tr)
+
+	for _, url := range []string{
+		"http://" + tr.Addr() + sseMessageEndpoint + "?sessionId=bogus",
+		"http://" + tr.Addr() + sseMessageEndpoint,
+	} {
+		if resp := post(t, url, toolCall(1, "list_droplets", `{}`)); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("POST %s status = %d, want 404", url, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPTransportRewritesRequestIDs(t *testing.T) {
+	var mu sync.Mutex
+	var seen []transport.RequestId
+	arrived := make(chan *transport.BaseJSONRPCRequest, 2)
+	tr := startStubTransport(t, TransportHTTP, func(request *transport.BaseJSONRPCRequest) {
+		mu.Lock()
+		seen = append(seen, request.Id)
+		mu.Unlock()
+		arrived <- request
+	})
+	url := "http://" + tr.Addr() + streamableEndpoint
+
+	// Two clients are in flight with the same ID at once
+	results := make(chan jsonRPCResponse, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			resp, err := http.Post(url, "application/json", strings.NewReader(toolCall(5, "list_droplets", `{}`)))
+			if err != nil {
+				t.Errorf("POST: %v", err)
+				results <- jsonRPCResponse{}
+				return
+			}
+			defer resp.Body.Close()
+			var result jsonRPCResponse
+			json.NewDecoder(resp.Body).Decode(&result)
+			results <- result
+		}()
+	}
+	requests := []*transport.BaseJSONRPCRequest{<-arrived, <-arrived}
+	if requests[0].Id == requests[1].Id {
+		t.Fatalf("both requests reached the protocol as id %d", requests[0].Id)
+	}
+	for _, request := range requests {
+		reply(t, tr, request)
+	}
+
+	answered := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		result := <-results
+		if result.ID != 5 {
+			t.Errorf("client got id %d, want its own id 5", result.ID)
+		}
+		answered[string(result.Result)] = true
+	}
+	if len(answered) != 2 {
+		t.Errorf("clients got %v, want one answer each", answered)
+	}
+
+	if err := tr.Send(context.Background(), transport.NewBaseMessageResponse(&transport.BaseJSONRPCResponse{Jsonrpc: "2.0", Id: seen[0]})); err == nil {
+		t.Errorf("answering a request twice should fail")
+	}
+}
+
+func TestHTTPTransportShutdownWaitsForInFlightCalls(t *testing.T) {
+	started := make(chan *transport.BaseJSONRPCRequest, 1)
+	tr := startStubTransport(t, TransportSSE, func(request *transport.BaseJSONRPCRequest) {
+		started <- request
+	})
+	client := openSSE(t, tr)
+
+	if resp := post(t, client.endpoint, toolCall(3, "delete_droplet", `{"droplet_id":1}`)); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /message status = %d, want 202", resp.StatusCode)
+	}
+	request := <-started
+
+	done := make(chan error, 1)
+	go func() { done <- tr.Shutdown(context.Background()) }()
+
+	// While draining the server still answers, but takes no new calls
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp := post(t, client.endpoint, toolCall(4, "list_droplets", `{}`))
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("POST /message status = %d while draining, want 503", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned %v before the in-flight call finished", err)
+	default:
+	}
+
+	reply(t, tr, request)
+	if result := client.response(t); result.ID != 3 {
+		t.Errorf("response id = %d, want 3", result.ID)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the call finished")
+	}
+	if _, _, err := client.next(); !errors.Is(err, io.EOF) {
+		t.Errorf("stream read after shutdown = %v, want EOF", err)
+	}
+}
+
+func TestHTTPTransportShutdownTimeout(t *testing.T) {
+	started := make(chan *transport.BaseJSONRPCRequest, 1)
+	tr := startStubTransport(t, TransportHTTP, func(request *transport.BaseJSONRPCRequest) {
+		started <- request
+	})
+
+	go http.Post("http://"+tr.Addr()+streamableEndpoint, "application/json", strings.NewReader(toolCall(1, "list_droplets", `{}`)))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := tr.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want the deadline error", err)
+	}
+}
+
+func TestHTTPTransportAccess(t *testing.T) {
+	tr := startStubTransportWithAccess(t, TransportHTTP, HTTPAccess{
+		Token:          "s3cret",
+		AllowedOrigins: []string{"https://app.example.com/"},
+	}, func(request *transport.BaseJSONRPCRequest) {})
+	url := "http://" + tr.Addr() + streamableEndpoint
+	notification := `{"jsonrpc":"2.0","method":"notifications/initialized"}`
+
+	tests := []struct {
+		name          string
+		origin        string
+		authorization string
+		want          int
+	}{
+		{"no origin", "", "Bearer s3cret", http.StatusAccepted},
+		{"localhost origin", "http://localhost:6274", "Bearer s3cret", http.StatusAccepted},
+		{"loopback ip origin", "http://127.0.0.1:6274", "Bearer s3cret", http.StatusAccepted},
+		{"allowed origin", "https://app.example.com", "Bearer s3cret", http.StatusAccepted},
+		{"rebound origin", "http://attacker.example:8080", "Bearer s3cret", http.StatusForbidden},
+		{"null origin", "null", "Bearer s3cret", http.StatusForbidden},
+		{"missing token", "", "", http.StatusUnauthorized},
+		{"wrong token", "", "Bearer guess", http.StatusUnauthorized},
+		{"wrong scheme", "", "Basic s3cret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(notification))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSEOriginRejected(t *testing.T) {
+	tr := startHTTPServer(t, TransportSSE)
+	client := openSSE(t, tr)
+
+	for _, url := range []string{"http://" + tr.Addr() + sseEndpoint, client.endpoint} {
+		method := http.MethodGet
+		if url == client.endpoint {
+			method = http.MethodPost
+		}
+		req, _ := http.NewRequest(method, url, strings.NewReader(toolCall(1, "list_droplets", `{}`)))
+		req.Header.Set("Origin", "http://attacker.example")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s %s status = %d, want 403", method, url, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Tool verbs used by ToolPolicy
+const (
+	VerbRead    = "read"
+	VerbWrite   = "write"
+	VerbDestroy = "destroy"
+)
+
+var readPrefixes = []string{"list_", "get_", "test_"}
+
+// ToolPolicy decides which tools are registered. Denied tools are never
+// registered, so clients do not see them in tools/list. Deny rules win over
+// allow rules, and an empty allow list allows everything.
+type ToolPolicy struct {
+	// ReadOnly registers only tools with the read verb
+	ReadOnly        bool     `json:"read_only"`
+	AllowVerbs      []string `json:"allow_verbs"`
+	AllowCategories []string `json:"allow_categories"`
+	DenyCategories  []string `json:"deny_categories"`
+	// AllowTools and DenyTools hold glob patterns such as "list_*"
+	AllowTools []string `json:"allow_tools"`
+	DenyTools  []string `json:"deny_tools"`
+}
+
+// LoadToolPolicy reads the policy from the JSON file at path, if any, and then
+// applies the MCP_READ_ONLY, MCP_ALLOW_VERBS, MCP_ALLOW_CATEGORIES,
+// MCP_DENY_CATEGORIES, MCP_ALLOW_TOOLS and MCP_DENY_TOOLS environment
+// variables on top of it. List variables are comma-separated and extend the
+// lists from the file.
+func LoadToolPolicy(path string) (*ToolPolicy, error) {
+	policy := &ToolPolicy{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file: %w", err)
+		}
+		if err := json.Unmarshal(data, policy); err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+		}
+	}
+
+	if value := os.Getenv("MCP_READ_ONLY"); value != "" {
+		readOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_READ_ONLY value %q", value)
+		}
+		policy.ReadOnly = policy.ReadOnly || readOnly
+	}
+	policy.AllowVerbs = append(policy.AllowVerbs, envList("MCP_ALLOW_VERBS")...)
+	policy.AllowCategories = append(policy.AllowCategories, envList("MCP_ALLOW_CATEGORIES")...)
+	policy.DenyCategories = append(policy.DenyCategories, envList("MCP_DENY_CATEGORIES")...)
+	policy.AllowTools = append(policy.AllowTools, envList("MCP_ALLOW_TOOLS")...)
+	policy.DenyTools = append(policy.DenyTools, envList("MCP_DENY_TOOLS")...)
+
+	return policy, nil
+}
+
+// Validate rejects unknown verbs and categories and malformed glob patterns so
+// a typo cannot silently expose or hide tools.
+func (p *ToolPolicy) Validate(tools []ToolDefinition) error {
+	for _, verb := range p.AllowVerbs {
+		if verb != VerbRead && verb != VerbWrite && verb != VerbDestroy {
+			return fmt.Errorf("unknown verb %q in tool policy (expected %s, %s or %s)", verb, VerbRead, VerbWrite, VerbDestroy)
+		}
+	}
+
+	categories := make(map[string]bool)
+	for _, tool := range tools {
+		categories[tool.Category] = true
+	}
+	for _, category := range append(append([]string{}, p.AllowCategories...), p.DenyCategories...) {
+		if !categories[category] {
+			return fmt.Errorf("unknown category %q in tool policy", category)
+		}
+	}
+
+	for _, pattern := range append(append([]string{}, p.AllowTools...), p.DenyTools...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q in tool policy: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether tool should be registered.
+func (p *ToolPolicy) Allows(tool ToolDefinition) bool {
+	verb := toolVerb(tool)
+
+	if p.ReadOnly && verb != VerbRead {
+		return false
+	}
+	if len(p.AllowVerbs) > 0 && !contains(p.AllowVerbs, verb) {
+		return false
+	}
+	if contains(p.DenyCategories, tool.Category) {
+		return false
+	}
+	if len(p.AllowCategories) > 0 && !contains(p.AllowCategories, tool.Category) {
+		return false
+	}
+	if matchesAny(p.DenyTools, tool.Name) {
+		return false
+	}
+	if len(p.AllowTools) > 0 && !matchesAny(p.AllowTools, tool.Name) {
+		return false
+	}
+
+	return true
+}
+
+// toolVerb returns the declared verb of a tool, or derives it: tools with a
+// confirmation preview or a delete_ prefix destroy, list_/get_/test_ tools
+// read, and everything else writes.
+func toolVerb(tool ToolDefinition) string {
+	if tool.Verb != "" {
+		return tool.Verb
+	}
+	if tool.Preview != nil || strings.HasPrefix(tool.Name, "delete_") {
+		return VerbDestroy
+	}
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(tool.Name, prefix) {
+			return VerbRead
+		}
+	}
+	return VerbWrite
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var policyTestTools = []ToolDefinition{
+	{Name: "list_droplets", Category: "droplet"},
+	{Name: "create_droplet", Category: "droplet"},
+	{Name: "delete_droplet", Category: "droplet"},
+	{Name: "get_registry", Category: "registry"},
+	{Name: "create_k8s_cluster", Category: "kubernetes"},
+	{Name: "resize_droplet", Category: "droplet", Verb: VerbDestroy},
+}
+
+func TestToolPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ToolPolicy
+		want   []string
+	}{
+		{
+			name:   "empty policy allows everything",
+			policy: ToolPolicy{},
+			want:   []string{"list_droplets", "create_droplet", "delete_droplet", "get_registry", "create_k8s_cluster", "resize_droplet"},
+		},
+		{
+			name:   "read only",
+			policy: ToolPolicy{ReadOnly: true},
+			want:   []string{"list_droplets", "get_registry"},
+		},
+		{
+			name:   "allow verbs",
+			policy: ToolPolicy{AllowVerbs: []string{VerbRead, VerbWrite}},
+			want:   []string{"list_droplets", "create_droplet", "get_registry", "create_k8s_cluster"},
+		},
+		{
+			name:   "deny category wins over allow",
+			policy: ToolPolicy{AllowCategories: []string{"droplet", "registry"}, DenyCategories: []string{"registry"}},
+			want:   []string{"list_droplets", "create_droplet", "delete_droplet", "resize_droplet"},
+		},
+		{
+			name:   "tool globs",
+			policy: ToolPolicy{AllowTools: []string{"*_droplet*"}, DenyTools: []string{"delete_*"}},
+			want:   []string{"list_droplets", "create_droplet", "resize_droplet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tool := range policyTestTools {
+				if tt.policy.Allows(tool) {
+					got = append(got, tool.Name)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("allowed %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("allowed %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestToolPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ToolPolicy
+		wantErr bool
+	}{
+		{name: "valid", policy: ToolPolicy{AllowVerbs: []string{VerbRead}, DenyCategories: []string{"registry"}, DenyTools: []string{"delete_*"}}},
+		{name: "unknown verb", policy: ToolPolicy{AllowVerbs: []string{"nuke"}}, wantErr: true},
+		{name: "unknown category", policy: ToolPolicy{AllowCategories: []string{"droplets"}}, wantErr: true},
+		{name: "bad glob", policy: ToolPolicy{DenyTools: []string{"delete_["}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(policyTestTools)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadToolPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(`{"deny_tools": ["delete_*"], "allow_categories": ["droplet"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MCP_READ_ONLY", "true")
+	t.Setenv("MCP_DENY_TOOLS", "get_*, resize_*")
+
+	policy, err := LoadToolPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadToolPolicy: %v", err)
+	}
+	if !policy.ReadOnly {
+		t.Errorf("MCP_READ_ONLY was not applied")
+	}
+	want := []string{"delete_*", "get_*", "resize_*"}
+	if len(policy.DenyTools) != len(want) {
+		t.Fatalf("deny_tools = %v, want %v", policy.DenyTools, want)
+	}
+	for i := range want {
+		if policy.DenyTools[i] != want[i] {
+			t.Errorf("deny_tools = %v, want %v", policy.DenyTools, want)
+		}
+	}
+
+	t.Setenv("MCP_READ_ONLY", "maybe")
+	if _, err := LoadToolPolicy(""); err == nil {
+		t.Errorf("expected an error for an invalid MCP_READ_ONLY value")
+	}
+}
+
+func TestToolVerb(t *testing.T) {
+	preview := func(arguments struct{}) (struct{}, error) { return struct{}{}, nil }
+	tests := []struct {
+		tool ToolDefinition
+		want string
+	}{
+		{tool: ToolDefinition{Name: "list_droplets"}, want: VerbRead},
+		{tool: ToolDefinition{Name: "get_droplet"}, want: VerbRead},
+		{tool: ToolDefinition{Name: "test_droplet_ssh"}, want: VerbRead},
+		{tool: ToolDefinition{Name: "create_droplet"}, want: VerbWrite},
+		{tool: ToolDefinition{Name: "delete_tag"}, want: VerbDestroy},
+		{tool: ToolDefinition{Name: "rebuild_droplet", Preview: preview}, want: VerbDestroy},
+		// A declared verb wins over the name
+		{tool: ToolDefinition{Name: "get_kubeconfig", Verb: VerbWrite}, want: VerbWrite},
+		{tool: ToolDefinition{Name: "delete_droplet", Verb: VerbRead}, want: VerbRead},
+	}
+
+	for _, tt := range tests {
+		if got := toolVerb(tt.tool); got != tt.want {
+			t.Errorf("toolVerb(%s) = %s, want %s", tt.tool.Name, got, tt.want)
+		}
+	}
+}
+
+func TestToolPolicyGlobs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "list_*", name: "list_droplets", want: true},
+		{pattern: "list_*", name: "get_droplet", want: false},
+		{pattern: "*_droplet", name: "delete_droplet", want: true},
+		{pattern: "*_droplet", name: "delete_droplets_by_tag", want: false},
+		{pattern: "get_droplet?", name: "get_droplets", want: true},
+		{pattern: "[gl]*_droplet*", name: "list_droplets", want: true},
+		{pattern: "[gl]*_droplet*", name: "delete_droplet", want: false},
+		{pattern: "get_droplet", name: "get_droplet", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesAny([]string{tt.pattern}, tt.name); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadToolPolicyEnvironment(t *testing.T) {
+	t.Setenv("MCP_ALLOW_VERBS", "read,write")
+	t.Setenv("MCP_ALLOW_CATEGORIES", "droplet, ,registry")
+	t.Setenv("MCP_DENY_CATEGORIES", "kubernetes")
+	t.Setenv("MCP_ALLOW_TOOLS", "*_droplet*")
+	t.Setenv("MCP_READ_ONLY", "false")
+
+	policy, err := LoadToolPolicy("")
+	if err != nil {
+		t.Fatalf("LoadToolPolicy: %v", err)
+	}
+	if err := policy.Validate(policyTestTools); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if policy.ReadOnly || len(policy.AllowVerbs) != 2 || len(policy.AllowCategories) != 2 || len(policy.DenyCategories) != 1 || len(policy.AllowTools) != 1 {
+		t.Fatalf("policy = %+v", policy)
+	}
+
+	var allowed []string
+	for _, tool := range policyTestTools {
+		if policy.Allows(tool) {
+			allowed = append(allowed, tool.Name)
+		}
+	}
+	if len(allowed) != 2 || allowed[0] != "list_droplets" || allowed[1] != "create_droplet" {
+		t.Errorf("allowed %v, want list_droplets and create_droplet", allowed)
+	}
+}
+
+func TestLoadToolPolicyFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"deny_tools": "delete_*"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "missing.json"), invalid} {
+		if _, err := LoadToolPolicy(path); err == nil {
+			t.Errorf("LoadToolPolicy(%s) should fail", filepath.Base(path))
+		}
+	}
+}
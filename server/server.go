@@ -1,42 +1,152 @@
 package server
 
 import (
+	"context"
 	"digitalocean-mcp-server/client"
 	"digitalocean-mcp-server/handlers"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	mcp_golang "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport"
 	"github.com/metoro-io/mcp-golang/transport/stdio"
 )
 
-func NewServer() (*mcp_golang.Server, error) {
+// Supported values for Options.Transport
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+	TransportSSE   = "sse"
+)
+
+const (
+	defaultHTTPAddr = "127.0.0.1:8080"
+	shutdownTimeout = 30 * time.Second
+)
+
+// Options controls how the server is exposed to MCP clients.
+type Options struct {
+	Transport string
+	Addr      string
+	// PolicyFile is an optional JSON ToolPolicy; ReadOnly forces read-only
+	// mode on top of it.
+	PolicyFile string
+	ReadOnly   bool
+	// Access restricts who can call the HTTP transports.
+	Access HTTPAccess
+}
+
+// OptionsFromEnv reads MCP_TRANSPORT, MCP_HTTP_ADDR, MCP_HTTP_TOKEN,
+// MCP_HTTP_ALLOWED_ORIGINS and MCP_POLICY_FILE, falling back to stdio and
+// 127.0.0.1:8080 when they are unset.
+func OptionsFromEnv() Options {
+	opts := Options{
+		Transport:  os.Getenv("MCP_TRANSPORT"),
+		Addr:       os.Getenv("MCP_HTTP_ADDR"),
+		PolicyFile: os.Getenv("MCP_POLICY_FILE"),
+		Access: HTTPAccess{
+			Token:          os.Getenv("MCP_HTTP_TOKEN"),
+			AllowedOrigins: envList("MCP_HTTP_ALLOWED_ORIGINS"),
+		},
+	}
+	if opts.Transport == "" {
+		opts.Transport = TransportStdio
+	}
+	if opts.Addr == "" {
+		opts.Addr = defaultHTTPAddr
+	}
+	return opts
+}
+
+func NewServer(tr transport.Transport, policy *ToolPolicy) (*mcp_golang.Server, error) {
 	doClient, err := client.NewDOClient()
 	if err != nil {
 		return nil, err
 	}
 
+	// Spaces credentials are optional; without them only the Spaces tools
+	// fail
+	spacesClient, err := client.NewSpacesClientFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	handler := handlers.NewHandler(doClient)
-	server := mcp_golang.NewServer(stdio.NewStdioServerTransport())
+	if spacesClient != nil {
+		handler.SetSpacesClient(spacesClient)
+	}
+	server := mcp_golang.NewServer(tr)
 
-	if err := RegisterTools(server, handler); err != nil {
+	if err := RegisterTools(server, handler, policy); err != nil {
 		return nil, err
 	}
 
 	return server, nil
 }
 
-func Start() error {
-	server, err := NewServer()
+func newTransport(opts Options) (transport.Transport, error) {
+	switch opts.Transport {
+	case TransportStdio:
+		return stdio.NewStdioServerTransport(), nil
+	case TransportHTTP, TransportSSE:
+		return NewHTTPTransport(opts.Transport, opts.Addr, opts.Access)
+	default:
+		return nil, fmt.Errorf("unknown transport %q (expected %s, %s or %s)", opts.Transport, TransportStdio, TransportHTTP, TransportSSE)
+	}
+}
+
+// Start serves MCP over the configured transport and blocks until the process
+// receives SIGINT or SIGTERM. HTTP transports are shut down gracefully,
+// letting in-flight tool calls finish.
+func Start(opts Options) error {
+	tr, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	policy, err := LoadToolPolicy(opts.PolicyFile)
+	if err != nil {
+		return err
+	}
+	policy.ReadOnly = policy.ReadOnly || opts.ReadOnly
+
+	server, err := NewServer(tr, policy)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		log.Printf("Failed to create server: %v", err)
 		return err
 	}
 
-	log.Println("Starting DigitalOcean MCP server...")
+	log.Printf("Starting DigitalOcean MCP server (%s transport)...", opts.Transport)
 	if err := server.Serve(); err != nil {
-		log.Fatalf("Server error: %v", err)
+		log.Printf("Server error: %v", err)
 		return err
 	}
 
+	httpTransport, isHTTP := tr.(*HTTPTransport)
+	if isHTTP {
+		log.Printf("Listening on %s", httpTransport.Addr())
+		if opts.Access.Token == "" && !isLoopback(httpTransport.Addr()) {
+			log.Printf("Warning: %s is reachable from other hosts and MCP_HTTP_TOKEN is not set; anyone who can connect can use the DigitalOcean token", httpTransport.Addr())
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down DigitalOcean MCP server...")
+	if isHTTP {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpTransport.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Graceful shutdown failed: %v", err)
+			return err
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
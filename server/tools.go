@@ -1,33 +1,128 @@
 package server
 
 import (
+	"digitalocean-mcp-server/client"
 	"digitalocean-mcp-server/handlers"
 	"digitalocean-mcp-server/types"
 	"log"
 
+	"github.com/digitalocean/godo"
 	mcp_golang "github.com/metoro-io/mcp-golang"
 )
 
 type ToolDefinition struct {
 	Name        string
+	Category    string
 	Description string
 	Handler     interface{}
+	// Verb overrides the read/write/destroy classification that ToolPolicy
+	// otherwise derives from the tool name.
+	Verb string
+	// Preview marks the tool as destructive. It takes the same arguments as
+	// Handler and describes what would be destroyed; Handler only runs once
+	// the caller presents the confirmation token issued with the preview.
+	Preview interface{}
 }
 
-func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
+func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler, policy *ToolPolicy) error {
 	tools := []ToolDefinition{
 		// Test connection
 		{
 			Name:        "test_connection",
+			Category:    "account",
 			Description: "Test connection to DigitalOcean API",
 			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.TestConnection()
 			},
 		},
-		
+
+		// Account and billing tools
+		{
+			Name:        "get_account",
+			Category:    "account",
+			Description: "Get account details: email, status, droplet and volume limits and team",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetAccount()
+			},
+		},
+		{
+			Name:        "get_balance",
+			Category:    "billing",
+			Description: "Get the account balance and month-to-date usage",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetBalance()
+			},
+		},
+		{
+			Name:        "list_billing_history",
+			Category:    "billing",
+			Description: "List billing history entries such as invoices, payments and credits",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListBillingHistory(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "list_invoices",
+			Category:    "billing",
+			Description: "List invoices together with a preview of the current month's usage",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListInvoices(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_invoice",
+			Category:    "billing",
+			Description: "Get the line items of an invoice",
+			Handler: func(arguments types.GetInvoiceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetInvoice(arguments.InvoiceUUID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_invoice_summary",
+			Category:    "billing",
+			Description: "Get an invoice's totals by product, with taxes and credits",
+			Handler: func(arguments types.InvoiceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetInvoiceSummary(arguments.InvoiceUUID)
+			},
+		},
+		{
+			Name:        "export_invoice_csv",
+			Category:    "billing",
+			Description: "Export an invoice's line items as CSV",
+			Verb:        VerbRead,
+			Handler: func(arguments types.InvoiceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ExportInvoiceCSV(arguments.InvoiceUUID)
+			},
+		},
+
+		// Region and size tools
+		{
+			Name:        "list_regions",
+			Category:    "catalog",
+			Description: "List regions with the sizes and features each offers, optionally only available ones or those with a feature",
+			Handler: func(arguments types.ListRegionsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRegions(arguments.Available, arguments.Feature)
+			},
+		},
+		{
+			Name:        "list_sizes",
+			Category:    "catalog",
+			Description: "List droplet sizes, filtered by availability, region, minimum vCPUs and memory, and maximum monthly price",
+			Handler: func(arguments types.ListSizesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListSizes(handlers.SizeFilter{
+					Region:          arguments.Region,
+					AvailableOnly:   arguments.Available,
+					MinVCPUs:        arguments.MinVCPUs,
+					MinMemoryMB:     arguments.MinMemoryMB,
+					MaxPriceMonthly: arguments.MaxPriceMonthly,
+				})
+			},
+		},
+
 		// Droplet tools
 		{
 			Name:        "list_droplets",
+			Category:    "droplet",
 			Description: "List all droplets in the account",
 			Handler: func(arguments types.ListDropletsArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.ListDroplets(arguments.Page, arguments.PerPage)
@@ -35,6 +130,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "get_droplet",
+			Category:    "droplet",
 			Description: "Get details of a specific droplet",
 			Handler: func(arguments types.GetDropletArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetDroplet(arguments.DropletID)
@@ -42,43 +138,345 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "create_droplet",
-			Description: "Create a new droplet",
+			Category:    "droplet",
+			Description: "Create one or several droplets with optional SSH keys, tags, VPC, user data, volumes and backups",
 			Handler: func(arguments types.CreateDropletArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateDroplet(arguments.Name, arguments.Region, arguments.Size, arguments.Image)
+				return handler.CreateDroplet(handlers.CreateDropletOptions{
+					Name:       arguments.Name,
+					Names:      arguments.Names,
+					Region:     arguments.Region,
+					Size:       arguments.Size,
+					Image:      arguments.Image,
+					SSHKeys:    arguments.SSHKeys,
+					Tags:       arguments.Tags,
+					VPCUUID:    arguments.VPCUUID,
+					UserData:   arguments.UserData,
+					Monitoring: arguments.Monitoring,
+					Backups:    arguments.Backups,
+					IPv6:       arguments.IPv6,
+					Volumes:    arguments.Volumes,
+					ProjectID:  arguments.ProjectID,
+				}, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "delete_droplet",
+			Category:    "droplet",
 			Description: "Delete a droplet",
 			Handler: func(arguments types.DeleteDropletArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteDroplet(arguments.DropletID)
 			},
+			Preview: func(arguments types.DeleteDropletArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDroplet(arguments.DropletID)
+			},
 		},
 		{
 			Name:        "resize_droplet",
+			Category:    "droplet",
 			Description: "Resize a droplet to a different size",
 			Handler: func(arguments types.ResizeDropletArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ResizeDroplet(arguments.DropletID, arguments.Size, arguments.Disk)
+				return handler.ResizeDroplet(arguments.DropletID, arguments.Size, arguments.Disk, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "create_droplet_snapshot",
+			Category:    "droplet",
 			Description: "Create a snapshot of a droplet",
 			Handler: func(arguments types.CreateDropletSnapshotArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateDropletSnapshot(arguments.DropletID, arguments.Name)
+				return handler.CreateDropletSnapshot(arguments.DropletID, arguments.Name, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "power_on_droplet",
+			Category:    "droplet",
+			Description: "Power on a droplet",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerOnDroplet(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "power_off_droplet",
+			Category:    "droplet",
+			Description: "Power off a droplet immediately, like unplugging it",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerOffDroplet(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "shutdown_droplet",
+			Category:    "droplet",
+			Description: "Gracefully shut down a droplet",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ShutdownDroplet(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "power_cycle_droplet",
+			Category:    "droplet",
+			Description: "Power a droplet off and back on",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerCycleDroplet(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "reboot_droplet",
+			Category:    "droplet",
+			Description: "Gracefully reboot a droplet",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.RebootDroplet(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "rebuild_droplet",
+			Category:    "droplet",
+			Description: "Reinstall a droplet from an image, erasing its disk",
+			Handler: func(arguments types.RebuildDropletArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.RebuildDroplet(arguments.DropletID, arguments.Image, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.RebuildDropletArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewRebuildDroplet(arguments.DropletID, arguments.Image)
+			},
+		},
+		{
+			Name:        "rename_droplet",
+			Category:    "droplet",
+			Description: "Rename a droplet",
+			Handler: func(arguments types.RenameDropletArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.RenameDroplet(arguments.DropletID, arguments.Name, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "reset_droplet_password",
+			Category:    "droplet",
+			Description: "Reset the root password of a droplet and email the new one",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ResetDropletPassword(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "enable_droplet_backups",
+			Category:    "droplet",
+			Description: "Enable weekly backups for a droplet",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.EnableDropletBackups(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "disable_droplet_backups",
+			Category:    "droplet",
+			Description: "Disable backups for a droplet",
+			Handler: func(arguments types.DisableDropletBackupsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DisableDropletBackups(arguments.DropletID, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.DisableDropletBackupsArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDisableDropletBackups(arguments.DropletID)
+			},
+		},
+		{
+			Name:        "enable_droplet_ipv6",
+			Category:    "droplet",
+			Description: "Enable IPv6 networking on a droplet",
+			Handler: func(arguments types.DropletActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.EnableDropletIPv6(arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "list_droplet_kernels",
+			Category:    "droplet",
+			Description: "List kernels available to a droplet",
+			Handler: func(arguments types.ListDropletKernelsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDropletKernels(arguments.DropletID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "change_droplet_kernel",
+			Category:    "droplet",
+			Description: "Change the kernel a droplet boots",
+			Handler: func(arguments types.ChangeDropletKernelArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ChangeDropletKernel(arguments.DropletID, arguments.KernelID, arguments.WaitTimeout())
+			},
+		},
+
+		{
+			Name:        "power_on_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Power on every droplet with a tag",
+			Handler: func(arguments types.DropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerOnDropletsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "power_off_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Cut power to every droplet with a tag",
+			Handler: func(arguments types.ConfirmDropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerOffDropletsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.ConfirmDropletTagActionArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewPowerOffDropletsByTag(arguments.Tag)
+			},
+		},
+		{
+			Name:        "shutdown_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Gracefully shut down every droplet with a tag",
+			Handler: func(arguments types.ConfirmDropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ShutdownDropletsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.ConfirmDropletTagActionArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewShutdownDropletsByTag(arguments.Tag)
+			},
+		},
+		{
+			Name:        "power_cycle_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Hard-restart every droplet with a tag",
+			Handler: func(arguments types.ConfirmDropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PowerCycleDropletsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.ConfirmDropletTagActionArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewPowerCycleDropletsByTag(arguments.Tag)
+			},
+		},
+		{
+			Name:        "snapshot_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Snapshot every droplet with a tag",
+			Handler: func(arguments types.SnapshotDropletsByTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.SnapshotDropletsByTag(arguments.Tag, arguments.SnapshotName, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "enable_droplet_backups_by_tag",
+			Category:    "droplet",
+			Description: "Enable weekly backups on every droplet with a tag",
+			Handler: func(arguments types.DropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.EnableDropletBackupsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "disable_droplet_backups_by_tag",
+			Category:    "droplet",
+			Description: "Disable backups on every droplet with a tag, deleting their existing backups",
+			Handler: func(arguments types.ConfirmDropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DisableDropletBackupsByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+			Preview: func(arguments types.ConfirmDropletTagActionArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDisableDropletBackupsByTag(arguments.Tag)
+			},
+		},
+		{
+			Name:        "enable_droplet_ipv6_by_tag",
+			Category:    "droplet",
+			Description: "Enable IPv6 on every droplet with a tag",
+			Handler: func(arguments types.DropletTagActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.EnableDropletIPv6ByTag(arguments.Tag, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "delete_droplets_by_tag",
+			Category:    "droplet",
+			Description: "Permanently delete every droplet with a tag",
+			Handler: func(arguments types.DeleteDropletsByTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDropletsByTag(arguments.Tag)
+			},
+			Preview: func(arguments types.DeleteDropletsByTagArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDropletsByTag(arguments.Tag)
+			},
+		},
+
+		// SSH key tools
+		{
+			Name:        "list_ssh_keys",
+			Category:    "ssh_key",
+			Description: "List the SSH keys on the account",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListSSHKeys(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_ssh_key",
+			Category:    "ssh_key",
+			Description: "Get an SSH key by ID or fingerprint",
+			Handler: func(arguments types.SSHKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSSHKey(arguments.Key)
+			},
+		},
+		{
+			Name:        "get_ssh_key_fingerprint",
+			Category:    "ssh_key",
+			Description: "Compute the MD5 fingerprint of a public key and check whether it is on the account",
+			Handler: func(arguments types.GetSSHKeyFingerprintArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSSHKeyFingerprint(arguments.PublicKey)
+			},
+		},
+		{
+			Name:        "create_ssh_key",
+			Category:    "ssh_key",
+			Description: "Add a public SSH key to the account",
+			Handler: func(arguments types.CreateSSHKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateSSHKey(arguments.Name, arguments.PublicKey)
+			},
+		},
+		{
+			Name:        "update_ssh_key",
+			Category:    "ssh_key",
+			Description: "Rename an SSH key",
+			Handler: func(arguments types.UpdateSSHKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateSSHKey(arguments.Key, arguments.Name)
+			},
+		},
+		{
+			Name:        "delete_ssh_key",
+			Category:    "ssh_key",
+			Description: "Remove an SSH key from the account",
+			Handler: func(arguments types.DeleteSSHKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteSSHKey(arguments.Key)
+			},
+			Preview: func(arguments types.DeleteSSHKeyArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteSSHKey(arguments.Key)
+			},
+		},
+
+		// Action tools
+		{
+			Name:        "get_action",
+			Category:    "action",
+			Description: "Get the status of an action started by another tool",
+			Handler: func(arguments types.GetActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetAction(arguments.ActionID)
+			},
+		},
+		{
+			Name:        "list_actions",
+			Category:    "action",
+			Description: "List recent actions on the account or on one droplet",
+			Handler: func(arguments types.ListActionsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListActions(arguments.DropletID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "wait_for_action",
+			Category:    "action",
+			Description: "Wait until an action completes or errors, polling with backoff",
+			Verb:        VerbRead,
+			Handler: func(arguments types.WaitForActionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.WaitForAction(arguments.ActionID, arguments.Timeout())
 			},
 		},
 		
 		// Volume tools
 		{
 			Name:        "list_volumes",
+			Category:    "volume",
 			Description: "List all volumes in the account",
 			Handler: func(arguments types.ListVolumesArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListVolumes(arguments.Region)
+				return handler.ListVolumes(arguments.Region, arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_volume",
+			Category:    "volume",
 			Description: "Get details of a specific volume",
 			Handler: func(arguments types.GetVolumeArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetVolume(arguments.VolumeID)
@@ -86,41 +484,50 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "create_volume",
-			Description: "Create a new volume",
+			Category:    "volume",
+			Description: "Create a new volume; the region must offer block storage",
 			Handler: func(arguments types.CreateVolumeArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateVolume(arguments.Name, arguments.Region, arguments.SizeGigaBytes, arguments.Description)
+				return handler.CreateVolume(arguments.Name, arguments.Region, arguments.SizeGigaBytes, arguments.Description, arguments.ProjectID)
 			},
 		},
 		{
 			Name:        "delete_volume",
+			Category:    "volume",
 			Description: "Delete a volume",
 			Handler: func(arguments types.DeleteVolumeArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteVolume(arguments.VolumeID)
 			},
+			Preview: func(arguments types.DeleteVolumeArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteVolume(arguments.VolumeID)
+			},
 		},
 		{
 			Name:        "attach_volume",
+			Category:    "volume",
 			Description: "Attach a volume to a droplet",
 			Handler: func(arguments types.AttachVolumeArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.AttachVolume(arguments.VolumeID, arguments.DropletID)
+				return handler.AttachVolume(arguments.VolumeID, arguments.DropletID, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "detach_volume",
+			Category:    "volume",
 			Description: "Detach a volume from a droplet",
 			Handler: func(arguments types.DetachVolumeArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.DetachVolume(arguments.VolumeID, arguments.DropletID)
+				return handler.DetachVolume(arguments.VolumeID, arguments.DropletID, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "resize_volume",
+			Category:    "volume",
 			Description: "Resize a volume",
 			Handler: func(arguments types.ResizeVolumeArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ResizeVolume(arguments.VolumeID, arguments.SizeGigaBytes, arguments.Region)
+				return handler.ResizeVolume(arguments.VolumeID, arguments.SizeGigaBytes, arguments.Region, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "create_volume_snapshot",
+			Category:    "volume",
 			Description: "Create a snapshot of a volume",
 			Handler: func(arguments types.CreateVolumeSnapshotArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.CreateVolumeSnapshot(arguments.VolumeID, arguments.Name, arguments.Description)
@@ -130,27 +537,31 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		// Snapshot tools
 		{
 			Name:        "list_snapshots",
+			Category:    "snapshot",
 			Description: "List all snapshots",
 			Handler: func(arguments types.ListSnapshotsArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListSnapshots(arguments.ResourceType)
+				return handler.ListSnapshots(arguments.ResourceType, arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "list_volume_snapshots",
+			Category:    "snapshot",
 			Description: "List all volume snapshots",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListVolumeSnapshots()
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListVolumeSnapshots(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "list_droplet_snapshots",
+			Category:    "snapshot",
 			Description: "List all droplet snapshots",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListDropletSnapshots()
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDropletSnapshots(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_snapshot",
+			Category:    "snapshot",
 			Description: "Get details of a specific snapshot",
 			Handler: func(arguments types.GetSnapshotArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetSnapshot(arguments.SnapshotID)
@@ -158,22 +569,28 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "delete_snapshot",
+			Category:    "snapshot",
 			Description: "Delete a snapshot",
 			Handler: func(arguments types.DeleteSnapshotArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteSnapshot(arguments.SnapshotID)
 			},
+			Preview: func(arguments types.DeleteSnapshotArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteSnapshot(arguments.SnapshotID)
+			},
 		},
 		
 		// Image tools
 		{
 			Name:        "list_images",
+			Category:    "image",
 			Description: "List all images",
 			Handler: func(arguments types.ListImagesArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListImages(arguments.Type, arguments.IsPublic)
+				return handler.ListImages(arguments.Type, arguments.IsPublic, arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_image",
+			Category:    "image",
 			Description: "Get details of a specific image",
 			Handler: func(arguments types.GetImageArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetImage(arguments.ImageID)
@@ -181,6 +598,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "update_image",
+			Category:    "image",
 			Description: "Update an image",
 			Handler: func(arguments types.UpdateImageArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.UpdateImage(arguments.ImageID, arguments.Name)
@@ -188,80 +606,150 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "delete_image",
+			Category:    "image",
 			Description: "Delete an image",
 			Handler: func(arguments types.DeleteImageArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteImage(arguments.ImageID)
 			},
+			Preview: func(arguments types.DeleteImageArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteImage(arguments.ImageID)
+			},
 		},
 		{
 			Name:        "transfer_image",
+			Category:    "image",
 			Description: "Transfer an image to another region",
 			Handler: func(arguments types.TransferImageArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.TransferImage(arguments.ImageID, arguments.RegionSlug)
+				return handler.TransferImage(arguments.ImageID, arguments.RegionSlug, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "convert_image_to_snapshot",
+			Category:    "image",
 			Description: "Convert an image to snapshot",
 			Handler: func(arguments types.ConvertImageToSnapshotArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ConvertImageToSnapshot(arguments.ImageID)
+				return handler.ConvertImageToSnapshot(arguments.ImageID, arguments.WaitTimeout())
 			},
 		},
 		
 		// Floating IP tools
 		{
 			Name:        "list_floating_ips",
-			Description: "List all floating IPs",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListFloatingIPs()
+			Category:    "floating_ip",
+			Description: "Deprecated: use list_reserved_ips. List all floating IPs",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListFloatingIPs(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_floating_ip",
-			Description: "Get details of a specific floating IP",
+			Category:    "floating_ip",
+			Description: "Deprecated: use get_reserved_ip. Get details of a specific floating IP",
 			Handler: func(arguments types.GetFloatingIPArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetFloatingIP(arguments.IP)
 			},
 		},
 		{
 			Name:        "create_floating_ip",
-			Description: "Create a new floating IP",
+			Category:    "floating_ip",
+			Description: "Deprecated: use create_reserved_ip. Create a new floating IP",
 			Handler: func(arguments types.CreateFloatingIPArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateFloatingIP(arguments.Region, arguments.DropletID)
+				return handler.CreateFloatingIP(arguments.Region, arguments.DropletID, arguments.ProjectID)
 			},
 		},
 		{
 			Name:        "delete_floating_ip",
-			Description: "Delete a floating IP",
+			Category:    "floating_ip",
+			Description: "Deprecated: use delete_reserved_ip. Delete a floating IP",
 			Handler: func(arguments types.DeleteFloatingIPArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteFloatingIP(arguments.IP)
 			},
+			Preview: func(arguments types.DeleteFloatingIPArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteReservedIP(arguments.IP)
+			},
 		},
 		{
 			Name:        "assign_floating_ip",
-			Description: "Assign a floating IP to a droplet",
+			Category:    "floating_ip",
+			Description: "Deprecated: use assign_reserved_ip. Assign a floating IP to a droplet",
 			Handler: func(arguments types.AssignFloatingIPArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.AssignFloatingIP(arguments.IP, arguments.DropletID)
+				return handler.AssignFloatingIP(arguments.IP, arguments.DropletID, arguments.WaitTimeout())
 			},
 		},
 		{
 			Name:        "unassign_floating_ip",
-			Description: "Unassign a floating IP from a droplet",
+			Category:    "floating_ip",
+			Description: "Deprecated: use unassign_reserved_ip. Unassign a floating IP from a droplet",
 			Handler: func(arguments types.UnassignFloatingIPArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.UnassignFloatingIP(arguments.IP)
+				return handler.UnassignFloatingIP(arguments.IP, arguments.WaitTimeout())
+			},
+		},
+		
+		// Reserved IP tools
+		{
+			Name:        "list_reserved_ips",
+			Category:    "reserved_ip",
+			Description: "List reserved IPv4 and IPv6 addresses",
+			Handler: func(arguments types.ListReservedIPsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListReservedIPs(arguments.IPVersion, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_reserved_ip",
+			Category:    "reserved_ip",
+			Description: "Get a reserved IPv4 or IPv6 address and the droplet it is assigned to",
+			Handler: func(arguments types.ReservedIPArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetReservedIP(arguments.IP)
+			},
+		},
+		{
+			Name:        "create_reserved_ip",
+			Category:    "reserved_ip",
+			Description: "Reserve an IPv4 address in a region or for a droplet, or an IPv6 address in a region",
+			Handler: func(arguments types.CreateReservedIPArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateReservedIP(arguments.IPVersion, arguments.Region, arguments.DropletID, arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "delete_reserved_ip",
+			Category:    "reserved_ip",
+			Description: "Release a reserved IPv4 or IPv6 address",
+			Handler: func(arguments types.DeleteReservedIPArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteReservedIP(arguments.IP)
+			},
+			Preview: func(arguments types.DeleteReservedIPArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteReservedIP(arguments.IP)
+			},
+		},
+		{
+			Name:        "assign_reserved_ip",
+			Category:    "reserved_ip",
+			Description: "Assign a reserved IPv4 or IPv6 address to a droplet in the same region",
+			Handler: func(arguments types.AssignReservedIPArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.AssignReservedIP(arguments.IP, arguments.DropletID, arguments.WaitTimeout())
+			},
+		},
+		{
+			Name:        "unassign_reserved_ip",
+			Category:    "reserved_ip",
+			Description: "Unassign a reserved IPv4 or IPv6 address from its droplet",
+			Handler: func(arguments types.UnassignReservedIPArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UnassignReservedIP(arguments.IP, arguments.WaitTimeout())
 			},
 		},
 		
 		// Load Balancer tools
 		{
 			Name:        "list_load_balancers",
+			Category:    "load_balancer",
 			Description: "List all load balancers",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListLoadBalancers()
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListLoadBalancers(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_load_balancer",
+			Category:    "load_balancer",
 			Description: "Get details of a specific load balancer",
 			Handler: func(arguments types.GetLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetLoadBalancer(arguments.LoadBalancerID)
@@ -269,13 +757,15 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "create_load_balancer",
+			Category:    "load_balancer",
 			Description: "Create a new load balancer",
 			Handler: func(arguments types.CreateLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateLoadBalancer(arguments.Name, arguments.Algorithm, arguments.Region, arguments.ForwardingRules, arguments.DropletIDs)
+				return handler.CreateLoadBalancer(arguments.Name, arguments.Algorithm, arguments.Region, arguments.ForwardingRules, arguments.DropletIDs, arguments.VPCUUID, arguments.ProjectID)
 			},
 		},
 		{
 			Name:        "update_load_balancer",
+			Category:    "load_balancer",
 			Description: "Update a load balancer",
 			Handler: func(arguments types.UpdateLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.UpdateLoadBalancer(arguments.LoadBalancerID, arguments.Name, arguments.Algorithm, arguments.Region, arguments.ForwardingRules, arguments.DropletIDs)
@@ -283,13 +773,18 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "delete_load_balancer",
+			Category:    "load_balancer",
 			Description: "Delete a load balancer",
 			Handler: func(arguments types.DeleteLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteLoadBalancer(arguments.LoadBalancerID)
 			},
+			Preview: func(arguments types.DeleteLoadBalancerArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteLoadBalancer(arguments.LoadBalancerID)
+			},
 		},
 		{
 			Name:        "add_droplets_to_load_balancer",
+			Category:    "load_balancer",
 			Description: "Add droplets to a load balancer",
 			Handler: func(arguments types.AddDropletsToLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.AddDropletsToLoadBalancer(arguments.LoadBalancerID, arguments.DropletIDs)
@@ -297,6 +792,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "remove_droplets_from_load_balancer",
+			Category:    "load_balancer",
 			Description: "Remove droplets from a load balancer",
 			Handler: func(arguments types.RemoveDropletsFromLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.RemoveDropletsFromLoadBalancer(arguments.LoadBalancerID, arguments.DropletIDs)
@@ -304,6 +800,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "add_forwarding_rules_to_load_balancer",
+			Category:    "load_balancer",
 			Description: "Add forwarding rules to a load balancer",
 			Handler: func(arguments types.AddForwardingRulesToLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.AddForwardingRulesToLoadBalancer(arguments.LoadBalancerID, arguments.ForwardingRules)
@@ -311,6 +808,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "remove_forwarding_rules_from_load_balancer",
+			Category:    "load_balancer",
 			Description: "Remove forwarding rules from a load balancer",
 			Handler: func(arguments types.RemoveForwardingRulesFromLoadBalancerArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.RemoveForwardingRulesFromLoadBalancer(arguments.LoadBalancerID, arguments.ForwardingRules)
@@ -320,13 +818,15 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		// Firewall tools
 		{
 			Name:        "list_firewalls",
+			Category:    "firewall",
 			Description: "List all firewalls",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListFirewalls()
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListFirewalls(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
 			Name:        "get_firewall",
+			Category:    "firewall",
 			Description: "Get details of a specific firewall",
 			Handler: func(arguments types.GetFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.GetFirewall(arguments.FirewallID)
@@ -334,6 +834,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "create_firewall",
+			Category:    "firewall",
 			Description: "Create a new firewall",
 			Handler: func(arguments types.CreateFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.CreateFirewall(arguments.Name, arguments.InboundRules, arguments.OutboundRules, arguments.DropletIDs, arguments.Tags)
@@ -341,6 +842,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "update_firewall",
+			Category:    "firewall",
 			Description: "Update a firewall",
 			Handler: func(arguments types.UpdateFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.UpdateFirewall(arguments.FirewallID, arguments.Name, arguments.InboundRules, arguments.OutboundRules)
@@ -348,13 +850,18 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "delete_firewall",
+			Category:    "firewall",
 			Description: "Delete a firewall",
 			Handler: func(arguments types.DeleteFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.DeleteFirewall(arguments.FirewallID)
 			},
+			Preview: func(arguments types.DeleteFirewallArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteFirewall(arguments.FirewallID)
+			},
 		},
 		{
 			Name:        "add_droplets_to_firewall",
+			Category:    "firewall",
 			Description: "Add droplets to a firewall",
 			Handler: func(arguments types.AddDropletsToFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.AddDropletsToFirewall(arguments.FirewallID, arguments.DropletIDs)
@@ -362,6 +869,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "remove_droplets_from_firewall",
+			Category:    "firewall",
 			Description: "Remove droplets from a firewall",
 			Handler: func(arguments types.RemoveDropletsFromFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.RemoveDropletsFromFirewall(arguments.FirewallID, arguments.DropletIDs)
@@ -369,6 +877,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "add_tags_to_firewall",
+			Category:    "firewall",
 			Description: "Add tags to a firewall",
 			Handler: func(arguments types.AddTagsToFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.AddTagsToFirewall(arguments.FirewallID, arguments.Tags)
@@ -376,6 +885,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "remove_tags_from_firewall",
+			Category:    "firewall",
 			Description: "Remove tags from a firewall",
 			Handler: func(arguments types.RemoveTagsFromFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.RemoveTagsFromFirewall(arguments.FirewallID, arguments.Tags)
@@ -383,6 +893,7 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "add_rules_to_firewall",
+			Category:    "firewall",
 			Description: "Add rules to a firewall",
 			Handler: func(arguments types.AddRulesToFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.AddRulesToFirewall(arguments.FirewallID, arguments.InboundRules, arguments.OutboundRules)
@@ -390,65 +901,1526 @@ func RegisterTools(server *mcp_golang.Server, handler *handlers.Handler) error {
 		},
 		{
 			Name:        "remove_rules_from_firewall",
+			Category:    "firewall",
 			Description: "Remove rules from a firewall",
 			Handler: func(arguments types.RemoveRulesFromFirewallArgs) (*mcp_golang.ToolResponse, error) {
 				return handler.RemoveRulesFromFirewall(arguments.FirewallID, arguments.InboundRules, arguments.OutboundRules)
 			},
 		},
 		
-		// Registry tools
+		// Domain tools
 		{
-			Name:        "list_registries",
-			Description: "List all container registries",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListRegistries()
+			Name:        "list_domains",
+			Category:    "domain",
+			Description: "List all domains managed by DigitalOcean DNS",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDomains(arguments.Page, arguments.PerPage)
 			},
 		},
 		{
-			Name:        "get_registry",
-			Description: "Get details of a specific registry",
-			Handler: func(arguments types.GetRegistryArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.GetRegistry(arguments.RegistryName)
+			Name:        "get_domain",
+			Category:    "domain",
+			Description: "Get details of a domain, including its zone file",
+			Handler: func(arguments types.GetDomainArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDomain(arguments.Name)
 			},
 		},
-		
-		// Kubernetes tools
 		{
-			Name:        "list_k8s_clusters",
-			Description: "List all Kubernetes clusters",
-			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.ListK8SClusters()
+			Name:        "create_domain",
+			Category:    "domain",
+			Description: "Add a domain to DigitalOcean DNS, optionally pointing its apex at an IP address",
+			Handler: func(arguments types.CreateDomainArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDomain(arguments.Name, arguments.IPAddress, arguments.ProjectID)
 			},
 		},
 		{
-			Name:        "get_k8s_cluster",
-			Description: "Get details of a specific Kubernetes cluster",
-			Handler: func(arguments types.GetK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.GetK8SCluster(arguments.ClusterID)
+			Name:        "delete_domain",
+			Category:    "domain",
+			Description: "Delete a domain and all of its records",
+			Handler: func(arguments types.DeleteDomainArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDomain(arguments.Name)
+			},
+			Preview: func(arguments types.DeleteDomainArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDomain(arguments.Name)
 			},
 		},
 		{
-			Name:        "create_k8s_cluster",
-			Description: "Create a new Kubernetes cluster",
-			Handler: func(arguments types.CreateK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.CreateK8SCluster(arguments.Name, arguments.Region, arguments.Version, arguments.NodePoolSize, arguments.NodeCount)
+			Name:        "list_domain_records",
+			Category:    "domain",
+			Description: "List the DNS records of a domain, optionally filtered by name and type",
+			Handler: func(arguments types.ListDomainRecordsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDomainRecords(arguments.Domain, arguments.Name, arguments.Type, arguments.Page, arguments.PerPage)
 			},
 		},
 		{
-			Name:        "delete_k8s_cluster",
-			Description: "Delete a Kubernetes cluster",
-			Handler: func(arguments types.DeleteK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
-				return handler.DeleteK8SCluster(arguments.ClusterID)
+			Name:        "get_domain_record",
+			Category:    "domain",
+			Description: "Get a single DNS record",
+			Handler: func(arguments types.GetDomainRecordArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDomainRecord(arguments.Domain, arguments.RecordID)
+			},
+		},
+		{
+			Name:        "create_domain_record",
+			Category:    "domain",
+			Description: "Create an A, AAAA, CNAME, MX, TXT, SRV, CAA or NS record",
+			Handler: func(arguments types.CreateDomainRecordArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDomainRecord(arguments.Domain, godo.DomainRecordEditRequest{
+					Type:     arguments.Type,
+					Name:     arguments.Name,
+					Data:     arguments.Data,
+					TTL:      arguments.TTL,
+					Priority: arguments.Priority,
+					Port:     arguments.Port,
+					Weight:   arguments.Weight,
+					Flags:    arguments.Flags,
+					Tag:      arguments.Tag,
+				})
+			},
+		},
+		{
+			Name:        "update_domain_record",
+			Category:    "domain",
+			Description: "Change the name, value, TTL or other fields of a DNS record",
+			Handler: func(arguments types.UpdateDomainRecordArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateDomainRecord(arguments.Domain, arguments.RecordID, handlers.DomainRecordUpdate{
+					Name:     arguments.Name,
+					Data:     arguments.Data,
+					TTL:      arguments.TTL,
+					Priority: arguments.Priority,
+					Port:     arguments.Port,
+					Weight:   arguments.Weight,
+					Flags:    arguments.Flags,
+					Tag:      arguments.Tag,
+				})
+			},
+		},
+		{
+			Name:        "delete_domain_record",
+			Category:    "domain",
+			Description: "Delete a DNS record",
+			Handler: func(arguments types.DeleteDomainRecordArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDomainRecord(arguments.Domain, arguments.RecordID)
+			},
+			Preview: func(arguments types.DeleteDomainRecordArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDomainRecord(arguments.Domain, arguments.RecordID)
 			},
 		},
-	}
 
-	for _, tool := range tools {
+		// Tag tools
+		{
+			Name:        "list_tags",
+			Category:    "tag",
+			Description: "List all tags with the number of resources carrying each",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListTags(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_tag",
+			Category:    "tag",
+			Description: "Get a tag and counts of the resources carrying it",
+			Handler: func(arguments types.GetTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetTag(arguments.Name)
+			},
+		},
+		{
+			Name:        "create_tag",
+			Category:    "tag",
+			Description: "Create a tag",
+			Handler: func(arguments types.CreateTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateTag(arguments.Name)
+			},
+		},
+		{
+			Name:        "delete_tag",
+			Category:    "tag",
+			Description: "Delete a tag and remove it from every resource",
+			Handler: func(arguments types.DeleteTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteTag(arguments.Name)
+			},
+			Preview: func(arguments types.DeleteTagArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteTag(arguments.Name)
+			},
+		},
+		{
+			Name:        "tag_resources",
+			Category:    "tag",
+			Description: "Apply a tag to droplets, volumes, images, databases or load balancers",
+			Handler: func(arguments types.TagResourcesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.TagResources(arguments.Name, arguments.Resources)
+			},
+		},
+		{
+			Name:        "untag_resources",
+			Category:    "tag",
+			Description: "Remove a tag from droplets, volumes, images, databases or load balancers",
+			Handler: func(arguments types.UntagResourcesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UntagResources(arguments.Name, arguments.Resources)
+			},
+		},
+
+		// VPC tools
+		{
+			Name:        "list_vpcs",
+			Category:    "vpc",
+			Description: "List all VPCs",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListVPCs(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_vpc",
+			Category:    "vpc",
+			Description: "Get details of a specific VPC",
+			Handler: func(arguments types.GetVPCArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetVPC(arguments.VPCID)
+			},
+		},
+		{
+			Name:        "create_vpc",
+			Category:    "vpc",
+			Description: "Create a new VPC in a region",
+			Handler: func(arguments types.CreateVPCArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateVPC(arguments.Name, arguments.Region, arguments.Description, arguments.IPRange)
+			},
+		},
+		{
+			Name:        "update_vpc",
+			Category:    "vpc",
+			Description: "Rename a VPC, change its description or make it the region's default",
+			Handler: func(arguments types.UpdateVPCArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateVPC(arguments.VPCID, handlers.VPCUpdate{
+					Name:        arguments.Name,
+					Description: arguments.Description,
+					MakeDefault: arguments.MakeDefault,
+				})
+			},
+		},
+		{
+			Name:        "delete_vpc",
+			Category:    "vpc",
+			Description: "Delete an empty VPC",
+			Handler: func(arguments types.DeleteVPCArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteVPC(arguments.VPCID)
+			},
+			Preview: func(arguments types.DeleteVPCArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteVPC(arguments.VPCID)
+			},
+		},
+		{
+			Name:        "list_vpc_members",
+			Category:    "vpc",
+			Description: "List the droplets, load balancers and Kubernetes clusters in a VPC",
+			Handler: func(arguments types.ListVPCMembersArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListVPCMembers(arguments.VPCID, arguments.ResourceType, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "list_vpc_peerings",
+			Category:    "vpc",
+			Description: "List VPC peerings, optionally only those of one VPC",
+			Handler: func(arguments types.ListVPCPeeringsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListVPCPeerings(arguments.VPCID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_vpc_peering",
+			Category:    "vpc",
+			Description: "Get details of a specific VPC peering",
+			Handler: func(arguments types.GetVPCPeeringArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetVPCPeering(arguments.PeeringID)
+			},
+		},
+		{
+			Name:        "create_vpc_peering",
+			Category:    "vpc",
+			Description: "Connect two VPCs over private networking",
+			Handler: func(arguments types.CreateVPCPeeringArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateVPCPeering(arguments.Name, arguments.VPCIDs)
+			},
+		},
+		{
+			Name:        "update_vpc_peering",
+			Category:    "vpc",
+			Description: "Rename a VPC peering",
+			Handler: func(arguments types.UpdateVPCPeeringArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateVPCPeering(arguments.PeeringID, arguments.Name)
+			},
+		},
+		{
+			Name:        "delete_vpc_peering",
+			Category:    "vpc",
+			Description: "Delete a VPC peering",
+			Handler: func(arguments types.DeleteVPCPeeringArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteVPCPeering(arguments.PeeringID)
+			},
+			Preview: func(arguments types.DeleteVPCPeeringArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteVPCPeering(arguments.PeeringID)
+			},
+		},
+
+		// Database tools
+		{
+			Name:        "list_database_clusters",
+			Category:    "database",
+			Description: "List managed database clusters; credentials are always masked",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabaseClusters(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_database_cluster",
+			Category:    "database",
+			Description: "Get a database cluster with its connection details; credentials are masked unless show_credentials is set",
+			Handler: func(arguments types.GetDatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDatabaseCluster(arguments.ClusterID, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "create_database_cluster",
+			Category:    "database",
+			Description: "Create a managed PostgreSQL, MySQL, Redis, Valkey, MongoDB, Kafka or OpenSearch cluster",
+			Handler: func(arguments types.CreateDatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDatabaseCluster(&godo.DatabaseCreateRequest{
+					Name:               arguments.Name,
+					EngineSlug:         arguments.Engine,
+					Version:            arguments.Version,
+					SizeSlug:           arguments.Size,
+					Region:             arguments.Region,
+					NumNodes:           arguments.NumNodes,
+					PrivateNetworkUUID: arguments.VPCUUID,
+					Tags:               arguments.Tags,
+					StorageSizeMib:     arguments.StorageSizeMib,
+					Rules:              arguments.TrustedSources,
+					ProjectID:          arguments.ProjectID,
+				}, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "resize_database_cluster",
+			Category:    "database",
+			Description: "Change the node size, node count or storage of a database cluster",
+			Handler: func(arguments types.ResizeDatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ResizeDatabaseCluster(arguments.ClusterID, handlers.DatabaseResize{
+					Size:           arguments.Size,
+					NumNodes:       arguments.NumNodes,
+					StorageSizeMib: arguments.StorageSizeMib,
+				})
+			},
+		},
+		{
+			Name:        "delete_database_cluster",
+			Category:    "database",
+			Description: "Delete a database cluster with its data, backups and read replicas",
+			Handler: func(arguments types.DeleteDatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDatabaseCluster(arguments.ClusterID)
+			},
+			Preview: func(arguments types.DeleteDatabaseClusterArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDatabaseCluster(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "list_databases",
+			Category:    "database",
+			Description: "List the databases in a cluster",
+			Handler: func(arguments types.ListDatabaseClusterItemsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabases(arguments.ClusterID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "create_database",
+			Category:    "database",
+			Description: "Create a database in a cluster",
+			Handler: func(arguments types.CreateDatabaseArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDatabase(arguments.ClusterID, arguments.Name)
+			},
+		},
+		{
+			Name:        "delete_database",
+			Category:    "database",
+			Description: "Delete a database and its data from a cluster",
+			Handler: func(arguments types.DeleteDatabaseArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDatabase(arguments.ClusterID, arguments.Name)
+			},
+			Preview: func(arguments types.DeleteDatabaseArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDatabase(arguments.ClusterID, arguments.Name)
+			},
+		},
+		{
+			Name:        "list_database_users",
+			Category:    "database",
+			Description: "List the users of a cluster; passwords are masked unless show_credentials is set",
+			Handler: func(arguments types.ListDatabaseClusterCredentialsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabaseUsers(arguments.ClusterID, arguments.Page, arguments.PerPage, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "get_database_user",
+			Category:    "database",
+			Description: "Get a database user; the password is masked unless show_credentials is set",
+			Handler: func(arguments types.GetDatabaseUserArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDatabaseUser(arguments.ClusterID, arguments.Name, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "create_database_user",
+			Category:    "database",
+			Description: "Create a database user; the generated password is masked unless show_credentials is set",
+			Handler: func(arguments types.CreateDatabaseUserArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDatabaseUser(arguments.ClusterID, arguments.Name, arguments.MySQLAuthPlugin, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "reset_database_user_password",
+			Category:    "database",
+			Description: "Generate a new password for a database user",
+			Handler: func(arguments types.ResetDatabaseUserPasswordArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ResetDatabaseUserPassword(arguments.ClusterID, arguments.Name, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "delete_database_user",
+			Category:    "database",
+			Description: "Delete a database user",
+			Handler: func(arguments types.DeleteDatabaseUserArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDatabaseUser(arguments.ClusterID, arguments.Name)
+			},
+			Preview: func(arguments types.DeleteDatabaseUserArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDatabaseUser(arguments.ClusterID, arguments.Name)
+			},
+		},
+		{
+			Name:        "list_database_pools",
+			Category:    "database",
+			Description: "List PostgreSQL connection pools; credentials are masked unless show_credentials is set",
+			Handler: func(arguments types.ListDatabaseClusterCredentialsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabasePools(arguments.ClusterID, arguments.Page, arguments.PerPage, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "create_database_pool",
+			Category:    "database",
+			Description: "Create a PostgreSQL connection pool",
+			Handler: func(arguments types.CreateDatabasePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDatabasePool(arguments.ClusterID, &godo.DatabaseCreatePoolRequest{
+					Name:     arguments.Name,
+					Database: arguments.Database,
+					User:     arguments.User,
+					Size:     arguments.Size,
+					Mode:     arguments.Mode,
+				}, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "update_database_pool",
+			Category:    "database",
+			Description: "Change the database, user, size or mode of a connection pool",
+			Handler: func(arguments types.UpdateDatabasePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateDatabasePool(arguments.ClusterID, arguments.Name, handlers.DatabasePoolUpdate{
+					User:     arguments.User,
+					Database: arguments.Database,
+					Mode:     arguments.Mode,
+					Size:     arguments.Size,
+				})
+			},
+		},
+		{
+			Name:        "delete_database_pool",
+			Category:    "database",
+			Description: "Delete a connection pool",
+			Handler: func(arguments types.DeleteDatabasePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDatabasePool(arguments.ClusterID, arguments.Name)
+			},
+			Preview: func(arguments types.DeleteDatabasePoolArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDatabasePool(arguments.ClusterID, arguments.Name)
+			},
+		},
+		{
+			Name:        "list_database_replicas",
+			Category:    "database",
+			Description: "List the read replicas of a cluster; credentials are masked unless show_credentials is set",
+			Handler: func(arguments types.ListDatabaseClusterCredentialsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabaseReplicas(arguments.ClusterID, arguments.Page, arguments.PerPage, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "create_database_replica",
+			Category:    "database",
+			Description: "Create a read replica of a PostgreSQL or MySQL cluster",
+			Handler: func(arguments types.CreateDatabaseReplicaArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateDatabaseReplica(arguments.ClusterID, &godo.DatabaseCreateReplicaRequest{
+					Name:               arguments.Name,
+					Region:             arguments.Region,
+					Size:               arguments.Size,
+					PrivateNetworkUUID: arguments.VPCUUID,
+					Tags:               arguments.Tags,
+				}, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "delete_database_replica",
+			Category:    "database",
+			Description: "Delete a read replica",
+			Handler: func(arguments types.DeleteDatabaseReplicaArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteDatabaseReplica(arguments.ClusterID, arguments.Name)
+			},
+			Preview: func(arguments types.DeleteDatabaseReplicaArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteDatabaseReplica(arguments.ClusterID, arguments.Name)
+			},
+		},
+		{
+			Name:        "list_database_backups",
+			Category:    "database",
+			Description: "List the backups of a cluster",
+			Handler: func(arguments types.ListDatabaseClusterItemsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListDatabaseBackups(arguments.ClusterID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "update_database_maintenance_window",
+			Category:    "database",
+			Description: "Move the weekly maintenance window of a cluster",
+			Handler: func(arguments types.UpdateDatabaseMaintenanceWindowArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateDatabaseMaintenanceWindow(arguments.ClusterID, arguments.Day, arguments.Hour)
+			},
+		},
+		{
+			Name:        "get_database_firewall_rules",
+			Category:    "database",
+			Description: "List the trusted sources allowed to connect to a cluster",
+			Handler: func(arguments types.DatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDatabaseFirewallRules(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "update_database_firewall_rules",
+			Category:    "database",
+			Description: "Replace the trusted sources allowed to connect to a cluster",
+			Handler: func(arguments types.UpdateDatabaseFirewallRulesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateDatabaseFirewallRules(arguments.ClusterID, arguments.Rules)
+			},
+		},
+		{
+			Name:        "get_database_config",
+			Category:    "database",
+			Description: "Get the engine configuration of a cluster",
+			Handler: func(arguments types.DatabaseClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDatabaseConfig(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "update_database_config",
+			Category:    "database",
+			Description: "Change engine configuration settings of a cluster",
+			Handler: func(arguments types.UpdateDatabaseConfigArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateDatabaseConfig(arguments.ClusterID, arguments.Config)
+			},
+		},
+
+		// App Platform tools
+		{
+			Name:        "list_apps",
+			Category:    "app",
+			Description: "List all App Platform apps",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListApps(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_app",
+			Category:    "app",
+			Description: "Get an app with its spec, live URL and active deployment",
+			Handler: func(arguments types.GetAppArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetApp(arguments.AppID)
+			},
+		},
+		{
+			Name:        "create_app",
+			Category:    "app",
+			Description: "Create an app from a YAML or JSON app spec and start its first deployment",
+			Handler: func(arguments types.CreateAppArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateApp(arguments.Spec, arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "update_app",
+			Category:    "app",
+			Description: "Replace an app's spec with a YAML or JSON spec and redeploy it",
+			Handler: func(arguments types.UpdateAppArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateApp(arguments.AppID, arguments.Spec, arguments.UpdateAllSourceVersions)
+			},
+		},
+		{
+			Name:        "delete_app",
+			Category:    "app",
+			Description: "Delete an app and its dev databases",
+			Handler: func(arguments types.DeleteAppArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteApp(arguments.AppID)
+			},
+			Preview: func(arguments types.DeleteAppArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteApp(arguments.AppID)
+			},
+		},
+		{
+			Name:        "list_app_deployments",
+			Category:    "app",
+			Description: "List an app's deployments, newest first",
+			Handler: func(arguments types.ListAppDeploymentsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListAppDeployments(arguments.AppID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_app_deployment",
+			Category:    "app",
+			Description: "Get a deployment with its phase and progress",
+			Handler: func(arguments types.AppDeploymentArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetAppDeployment(arguments.AppID, arguments.DeploymentID)
+			},
+		},
+		{
+			Name:        "create_app_deployment",
+			Category:    "app",
+			Description: "Redeploy an app's current spec, optionally forcing a rebuild",
+			Handler: func(arguments types.CreateAppDeploymentArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateAppDeployment(arguments.AppID, arguments.ForceBuild)
+			},
+		},
+		{
+			Name:        "cancel_app_deployment",
+			Category:    "app",
+			Description: "Cancel a deployment that is still building or deploying",
+			Handler: func(arguments types.AppDeploymentArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CancelAppDeployment(arguments.AppID, arguments.DeploymentID)
+			},
+		},
+		{
+			Name:        "get_app_logs",
+			Category:    "app",
+			Description: "Get URLs of an app's build, deploy or runtime logs, or a URL that streams them live",
+			Handler: func(arguments types.GetAppLogsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetAppLogs(arguments.AppID, arguments.DeploymentID, arguments.Component, arguments.LogType, arguments.Live, arguments.TailLines)
+			},
+		},
+		{
+			Name:        "list_app_alerts",
+			Category:    "app",
+			Description: "List the alerts configured for an app and its components",
+			Handler: func(arguments types.GetAppArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListAppAlerts(arguments.AppID)
+			},
+		},
+
+		// Registry tools
+		{
+			Name:        "list_registries",
+			Category:    "registry",
+			Description: "List all container registries",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRegistries()
+			},
+		},
+		{
+			Name:        "get_registry",
+			Category:    "registry",
+			Description: "Get details of a specific registry",
+			Handler: func(arguments types.GetRegistryArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetRegistry(arguments.RegistryName)
+			},
+		},
+		{
+			Name:        "list_repositories",
+			Category:    "registry",
+			Description: "List repositories in a registry",
+			Handler: func(arguments types.ListRepositoriesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRepositories(arguments.RegistryName, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_repository",
+			Category:    "registry",
+			Description: "Get details of a repository, including its latest tag",
+			Handler: func(arguments types.GetRepositoryArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetRepository(arguments.RegistryName, arguments.RepositoryName)
+			},
+		},
+		{
+			Name:        "list_repository_tags",
+			Category:    "registry",
+			Description: "List tags in a repository",
+			Handler: func(arguments types.ListRepositoryTagsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRepositoryTags(arguments.RegistryName, arguments.RepositoryName, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "list_repository_manifests",
+			Category:    "registry",
+			Description: "List manifests in a repository with their digests, tags and sizes",
+			Handler: func(arguments types.ListRepositoryManifestsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRepositoryManifests(arguments.RegistryName, arguments.RepositoryName, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "delete_repository_tag",
+			Category:    "registry",
+			Description: "Delete a tag from a repository",
+			Handler: func(arguments types.DeleteRepositoryTagArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteRepositoryTag(arguments.RegistryName, arguments.RepositoryName, arguments.Tag)
+			},
+			Preview: func(arguments types.DeleteRepositoryTagArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteRepositoryTag(arguments.RegistryName, arguments.RepositoryName, arguments.Tag)
+			},
+		},
+		{
+			Name:        "delete_repository_manifest",
+			Category:    "registry",
+			Description: "Delete a manifest and every tag pointing at it",
+			Handler: func(arguments types.DeleteRepositoryManifestArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteRepositoryManifest(arguments.RegistryName, arguments.RepositoryName, arguments.Digest)
+			},
+			Preview: func(arguments types.DeleteRepositoryManifestArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteRepositoryManifest(arguments.RegistryName, arguments.RepositoryName, arguments.Digest)
+			},
+		},
+		{
+			Name:        "start_registry_garbage_collection",
+			Category:    "registry",
+			Description: "Start garbage collection to reclaim storage from deleted tags and manifests",
+			Handler: func(arguments types.StartRegistryGarbageCollectionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.StartRegistryGarbageCollection(arguments.RegistryName, arguments.Type)
+			},
+		},
+		{
+			Name:        "get_registry_garbage_collection",
+			Category:    "registry",
+			Description: "Get the active garbage collection of a registry",
+			Handler: func(arguments types.GetRegistryArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetRegistryGarbageCollection(arguments.RegistryName)
+			},
+		},
+		{
+			Name:        "list_registry_garbage_collections",
+			Category:    "registry",
+			Description: "List past and active garbage collections of a registry",
+			Handler: func(arguments types.ListRegistryGarbageCollectionsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListRegistryGarbageCollections(arguments.RegistryName, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "cancel_registry_garbage_collection",
+			Category:    "registry",
+			Description: "Cancel a running registry garbage collection",
+			Handler: func(arguments types.CancelRegistryGarbageCollectionArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CancelRegistryGarbageCollection(arguments.RegistryName, arguments.UUID)
+			},
+		},
+		{
+			Name:        "get_registry_docker_credentials",
+			Category:    "registry",
+			Description: "Get a docker config.json with credentials for the registry",
+			// Issuing credentials is not a read, and push credentials can
+			// change the registry, so keep this out of read-only mode.
+			Verb: VerbWrite,
+			Handler: func(arguments types.GetRegistryDockerCredentialsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetRegistryDockerCredentials(arguments.ReadWrite, arguments.ExpirySeconds)
+			},
+		},
+		
+		// Kubernetes tools
+		{
+			Name:        "list_k8s_clusters",
+			Category:    "kubernetes",
+			Description: "List all Kubernetes clusters",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListK8SClusters(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_k8s_cluster",
+			Category:    "kubernetes",
+			Description: "Get details of a specific Kubernetes cluster",
+			Handler: func(arguments types.GetK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetK8SCluster(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "list_k8s_options",
+			Category:    "kubernetes",
+			Description: "List the Kubernetes versions, regions and node sizes available for new clusters",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListK8SOptions()
+			},
+		},
+		{
+			Name:        "create_k8s_cluster",
+			Category:    "kubernetes",
+			Description: "Create a new Kubernetes cluster; region, version and node size are checked against list_k8s_options",
+			Handler: func(arguments types.CreateK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateK8SCluster(arguments.Name, arguments.Region, arguments.Version, arguments.NodePoolSize, arguments.NodeCount, arguments.VPCUUID, arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "delete_k8s_cluster",
+			Category:    "kubernetes",
+			Description: "Delete a Kubernetes cluster",
+			Handler: func(arguments types.DeleteK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteK8SCluster(arguments.ClusterID)
+			},
+			Preview: func(arguments types.DeleteK8SClusterArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteK8SCluster(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "get_k8s_cluster_kubeconfig",
+			Category:    "kubernetes",
+			Description: "Get the kubeconfig YAML for a Kubernetes cluster",
+			// The kubeconfig carries cluster-admin credentials, so like
+			// get_registry_docker_credentials it is kept out of read-only mode.
+			Verb: VerbWrite,
+			Handler: func(arguments types.GetK8SClusterArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetK8SClusterKubeconfig(arguments.ClusterID)
+			},
+		},
+		{
+			Name:        "list_k8s_node_pools",
+			Category:    "kubernetes",
+			Description: "List the node pools of a Kubernetes cluster",
+			Handler: func(arguments types.ListK8SNodePoolsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListK8SNodePools(arguments.ClusterID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_k8s_node_pool",
+			Category:    "kubernetes",
+			Description: "Get details of a node pool, including its nodes",
+			Handler: func(arguments types.GetK8SNodePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetK8SNodePool(arguments.ClusterID, arguments.NodePoolID)
+			},
+		},
+		{
+			Name:        "create_k8s_node_pool",
+			Category:    "kubernetes",
+			Description: "Add a node pool to a Kubernetes cluster",
+			Handler: func(arguments types.CreateK8SNodePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateK8SNodePool(arguments.ClusterID, arguments.Name, arguments.Size, arguments.Count, arguments.AutoScale, arguments.MinNodes, arguments.MaxNodes, arguments.Tags, arguments.Labels, arguments.Taints)
+			},
+		},
+		{
+			Name:        "update_k8s_node_pool",
+			Category:    "kubernetes",
+			Description: "Update a node pool's name, node count, autoscaling range, labels or taints. Fields that are omitted keep their current value",
+			Handler: func(arguments types.UpdateK8SNodePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateK8SNodePool(arguments.ClusterID, arguments.NodePoolID, arguments.Name, arguments.Count, arguments.AutoScale, arguments.MinNodes, arguments.MaxNodes, arguments.Labels, arguments.Taints)
+			},
+		},
+		{
+			Name:        "delete_k8s_node_pool",
+			Category:    "kubernetes",
+			Description: "Delete a node pool and all of its nodes",
+			Handler: func(arguments types.DeleteK8SNodePoolArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteK8SNodePool(arguments.ClusterID, arguments.NodePoolID)
+			},
+			Preview: func(arguments types.DeleteK8SNodePoolArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteK8SNodePool(arguments.ClusterID, arguments.NodePoolID)
+			},
+		},
+		{
+			Name:        "recycle_k8s_node_pool_nodes",
+			Category:    "kubernetes",
+			Description: "Replace nodes in a node pool with fresh ones, draining them first unless skip_drain is set",
+			Handler: func(arguments types.RecycleK8SNodePoolNodesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.RecycleK8SNodePoolNodes(arguments.ClusterID, arguments.NodePoolID, arguments.NodeIDs, arguments.SkipDrain)
+			},
+			Preview: func(arguments types.RecycleK8SNodePoolNodesArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewRecycleK8SNodePoolNodes(arguments.ClusterID, arguments.NodePoolID, arguments.NodeIDs, arguments.SkipDrain)
+			},
+		},
+		{
+			Name:        "delete_k8s_node",
+			Category:    "kubernetes",
+			Description: "Delete a single node without replacing it, shrinking its node pool by one",
+			Handler: func(arguments types.DeleteK8SNodeArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteK8SNode(arguments.ClusterID, arguments.NodePoolID, arguments.NodeID, arguments.SkipDrain)
+			},
+			Preview: func(arguments types.DeleteK8SNodeArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteK8SNode(arguments.ClusterID, arguments.NodePoolID, arguments.NodeID, arguments.SkipDrain)
+			},
+		},
+
+		// Project tools
+		{
+			Name:        "list_projects",
+			Category:    "project",
+			Description: "List all projects",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListProjects(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_project",
+			Category:    "project",
+			Description: "Get details of a specific project or of the default project",
+			Handler: func(arguments types.GetProjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetProject(arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "create_project",
+			Category:    "project",
+			Description: "Create a new project",
+			Handler: func(arguments types.CreateProjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateProject(arguments.Name, arguments.Description, arguments.Purpose, arguments.Environment)
+			},
+		},
+		{
+			Name:        "update_project",
+			Category:    "project",
+			Description: "Change the name, description, purpose or environment of a project",
+			Handler: func(arguments types.UpdateProjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateProject(arguments.ProjectID, handlers.ProjectUpdate{
+					Name:        arguments.Name,
+					Description: arguments.Description,
+					Purpose:     arguments.Purpose,
+					Environment: arguments.Environment,
+				})
+			},
+		},
+		{
+			Name:        "set_default_project",
+			Category:    "project",
+			Description: "Make a project the default one new resources are created in",
+			Handler: func(arguments types.GetProjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.SetDefaultProject(arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "delete_project",
+			Category:    "project",
+			Description: "Delete an empty project that is not the default",
+			Handler: func(arguments types.DeleteProjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteProject(arguments.ProjectID)
+			},
+			Preview: func(arguments types.DeleteProjectArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteProject(arguments.ProjectID)
+			},
+		},
+		{
+			Name:        "list_project_resources",
+			Category:    "project",
+			Description: "List the resources in a project by URN",
+			Handler: func(arguments types.ListProjectResourcesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListProjectResources(arguments.ProjectID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "assign_project_resources",
+			Category:    "project",
+			Description: "Move resources, given by URN, into a project",
+			Handler: func(arguments types.AssignProjectResourcesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.AssignProjectResources(arguments.ProjectID, arguments.URNs)
+			},
+		},
+
+		// Monitoring tools
+		{
+			Name:        "get_droplet_metrics",
+			Category:    "monitoring",
+			Description: "Get a droplet's CPU, memory, disk, load or bandwidth over a time window, as points or as a min/avg/max/p95 summary",
+			Handler: func(arguments types.GetDropletMetricsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetDropletMetrics(arguments.DropletID, arguments.Metric, handlers.MetricsQuery{
+					Window:    arguments.Window,
+					Step:      arguments.Step,
+					End:       arguments.End,
+					Interface: arguments.Interface,
+					Direction: arguments.Direction,
+					Summary:   arguments.Summary,
+				})
+			},
+		},
+		{
+			Name:        "list_alert_policies",
+			Category:    "monitoring",
+			Description: "List all monitoring alert policies",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListAlertPolicies(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_alert_policy",
+			Category:    "monitoring",
+			Description: "Get details of a specific alert policy",
+			Handler: func(arguments types.AlertPolicyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetAlertPolicy(arguments.PolicyID)
+			},
+		},
+		{
+			Name:        "create_alert_policy",
+			Category:    "monitoring",
+			Description: "Create an alert policy that emails or posts to Slack when a metric crosses a threshold",
+			Handler: func(arguments types.CreateAlertPolicyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateAlertPolicy(handlers.AlertPolicyOptions{
+					Type:        arguments.Type,
+					Description: arguments.Description,
+					Compare:     arguments.Compare,
+					Value:       arguments.Value,
+					Window:      arguments.Window,
+					Entities:    arguments.Entities,
+					Tags:        arguments.Tags,
+					Emails:      arguments.Emails,
+					Slack:       arguments.Slack,
+					Enabled:     arguments.Enabled,
+				})
+			},
+		},
+		{
+			Name:        "update_alert_policy",
+			Category:    "monitoring",
+			Description: "Change the metric, threshold, window, watched resources, destinations or state of an alert policy",
+			Handler: func(arguments types.UpdateAlertPolicyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateAlertPolicy(arguments.PolicyID, handlers.AlertPolicyUpdate{
+					Type:        arguments.Type,
+					Description: arguments.Description,
+					Compare:     arguments.Compare,
+					Value:       arguments.Value,
+					Window:      arguments.Window,
+					Entities:    arguments.Entities,
+					Tags:        arguments.Tags,
+					Emails:      arguments.Emails,
+					Slack:       arguments.Slack,
+					Enabled:     arguments.Enabled,
+				})
+			},
+		},
+		{
+			Name:        "delete_alert_policy",
+			Category:    "monitoring",
+			Description: "Delete an alert policy",
+			Handler: func(arguments types.DeleteAlertPolicyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteAlertPolicy(arguments.PolicyID)
+			},
+			Preview: func(arguments types.DeleteAlertPolicyArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteAlertPolicy(arguments.PolicyID)
+			},
+		},
+
+		// Spaces tools
+		{
+			Name:        "list_spaces_buckets",
+			Category:    "spaces",
+			Description: "List Spaces buckets",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListSpacesBuckets()
+			},
+		},
+		{
+			Name:        "create_spaces_bucket",
+			Category:    "spaces",
+			Description: "Create a Spaces bucket in the configured region",
+			Handler: func(arguments types.CreateSpacesBucketArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateSpacesBucket(arguments.Name, arguments.ACL)
+			},
+		},
+		{
+			Name:        "delete_spaces_bucket",
+			Category:    "spaces",
+			Description: "Delete an empty Spaces bucket",
+			Handler: func(arguments types.DeleteSpacesBucketArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteSpacesBucket(arguments.Bucket)
+			},
+			Preview: func(arguments types.DeleteSpacesBucketArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteSpacesBucket(arguments.Bucket)
+			},
+		},
+		{
+			Name:        "list_spaces_objects",
+			Category:    "spaces",
+			Description: "List objects in a Spaces bucket by prefix, a page at a time, optionally grouped into directories by a delimiter",
+			Handler: func(arguments types.ListSpacesObjectsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListSpacesObjects(arguments.Bucket, client.ListObjectsOptions{
+					Prefix:    arguments.Prefix,
+					Delimiter: arguments.Delimiter,
+					Cursor:    arguments.Cursor,
+					MaxKeys:   arguments.MaxKeys,
+				})
+			},
+		},
+		{
+			Name:        "head_spaces_object",
+			Category:    "spaces",
+			Description: "Get an object's size, content type, ETag and metadata without downloading it",
+			Verb:        VerbRead,
+			Handler: func(arguments types.SpacesObjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.HeadSpacesObject(arguments.Bucket, arguments.Key)
+			},
+		},
+		{
+			Name:        "get_spaces_object",
+			Category:    "spaces",
+			Description: "Download an object from Spaces, returning text as-is and binary content base64-encoded",
+			Handler: func(arguments types.GetSpacesObjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSpacesObject(arguments.Bucket, arguments.Key, arguments.MaxBytes)
+			},
+		},
+		{
+			Name:        "put_spaces_object",
+			Category:    "spaces",
+			Description: "Upload text or base64-encoded content to a Spaces bucket",
+			Handler: func(arguments types.PutSpacesObjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PutSpacesObject(arguments.Bucket, arguments.Key, handlers.PutObjectOptions{
+					Content:     arguments.Content,
+					Encoding:    arguments.Encoding,
+					ContentType: arguments.ContentType,
+					ACL:         arguments.ACL,
+				})
+			},
+		},
+		{
+			Name:        "delete_spaces_object",
+			Category:    "spaces",
+			Description: "Delete an object from a Spaces bucket",
+			Handler: func(arguments types.DeleteSpacesObjectArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteSpacesObject(arguments.Bucket, arguments.Key)
+			},
+			Preview: func(arguments types.DeleteSpacesObjectArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteSpacesObject(arguments.Bucket, arguments.Key)
+			},
+		},
+		{
+			Name:        "presign_spaces_url",
+			Category:    "spaces",
+			Description: "Create a time-limited URL that lets anyone download (GET) or upload (PUT) one object without credentials",
+			Handler: func(arguments types.PresignSpacesURLArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PresignSpacesURL(arguments.Bucket, arguments.Key, arguments.Method, arguments.Expiry())
+			},
+		},
+		{
+			Name:        "get_spaces_bucket_cors",
+			Category:    "spaces",
+			Description: "Get the CORS rules of a Spaces bucket",
+			Handler: func(arguments types.SpacesBucketArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSpacesBucketCORS(arguments.Bucket)
+			},
+		},
+		{
+			Name:        "set_spaces_bucket_cors",
+			Category:    "spaces",
+			Description: "Replace the CORS rules of a Spaces bucket",
+			Handler: func(arguments types.SetSpacesBucketCORSArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.SetSpacesBucketCORS(arguments.Bucket, arguments.Rules)
+			},
+		},
+		{
+			Name:        "get_spaces_bucket_lifecycle",
+			Category:    "spaces",
+			Description: "Get the lifecycle rules of a Spaces bucket",
+			Handler: func(arguments types.SpacesBucketArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSpacesBucketLifecycle(arguments.Bucket)
+			},
+		},
+		{
+			Name:        "set_spaces_bucket_lifecycle",
+			Category:    "spaces",
+			Description: "Replace the lifecycle rules that expire objects or abort incomplete uploads in a Spaces bucket",
+			Handler: func(arguments types.SetSpacesBucketLifecycleArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.SetSpacesBucketLifecycle(arguments.Bucket, arguments.Rules)
+			},
+		},
+		{
+			Name:        "list_spaces_keys",
+			Category:    "spaces_key",
+			Description: "List Spaces access keys and their grants",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListSpacesKeys(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_spaces_key",
+			Category:    "spaces_key",
+			Description: "Get a Spaces access key and its grants",
+			Handler: func(arguments types.SpacesKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetSpacesKey(arguments.AccessKey)
+			},
+		},
+		{
+			Name:        "create_spaces_key",
+			Category:    "spaces_key",
+			Description: "Create a Spaces access key with read, readwrite or fullaccess grants; the secret is only shown in the response",
+			Handler: func(arguments types.CreateSpacesKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateSpacesKey(arguments.Name, arguments.Grants)
+			},
+		},
+		{
+			Name:        "update_spaces_key",
+			Category:    "spaces_key",
+			Description: "Rename a Spaces access key or replace its grants",
+			Handler: func(arguments types.UpdateSpacesKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateSpacesKey(arguments.AccessKey, handlers.SpacesKeyUpdate{
+					Name:   arguments.Name,
+					Grants: arguments.Grants,
+				})
+			},
+		},
+		{
+			Name:        "delete_spaces_key",
+			Category:    "spaces_key",
+			Description: "Delete a Spaces access key",
+			Handler: func(arguments types.DeleteSpacesKeyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteSpacesKey(arguments.AccessKey)
+			},
+			Preview: func(arguments types.DeleteSpacesKeyArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteSpacesKey(arguments.AccessKey)
+			},
+		},
+		// CDN tools
+		{
+			Name:        "list_cdn_endpoints",
+			Category:    "cdn",
+			Description: "List CDN endpoints",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListCDNEndpoints(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_cdn_endpoint",
+			Category:    "cdn",
+			Description: "Get a CDN endpoint's origin, TTL and custom domain",
+			Handler: func(arguments types.CDNEndpointArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetCDNEndpoint(arguments.EndpointID)
+			},
+		},
+		{
+			Name:        "create_cdn_endpoint",
+			Category:    "cdn",
+			Description: "Serve a Spaces bucket through the CDN, optionally on a custom domain with a certificate given by ID or name",
+			Handler: func(arguments types.CreateCDNEndpointArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateCDNEndpoint(arguments.Origin, arguments.TTL, arguments.CustomDomain, arguments.Certificate)
+			},
+		},
+		{
+			Name:        "update_cdn_endpoint",
+			Category:    "cdn",
+			Description: "Change a CDN endpoint's TTL, custom domain or certificate",
+			Handler: func(arguments types.UpdateCDNEndpointArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateCDNEndpoint(arguments.EndpointID, handlers.CDNUpdate{
+					TTL:          arguments.TTL,
+					CustomDomain: arguments.CustomDomain,
+					Certificate:  arguments.Certificate,
+				})
+			},
+		},
+		{
+			Name:        "delete_cdn_endpoint",
+			Category:    "cdn",
+			Description: "Delete a CDN endpoint; the bucket is kept",
+			Handler: func(arguments types.DeleteCDNEndpointArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteCDNEndpoint(arguments.EndpointID)
+			},
+			Preview: func(arguments types.DeleteCDNEndpointArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteCDNEndpoint(arguments.EndpointID)
+			},
+		},
+		{
+			Name:        "purge_cdn_cache",
+			Category:    "cdn",
+			Description: "Purge paths from a CDN endpoint's cache so they are fetched from the bucket again",
+			Handler: func(arguments types.PurgeCDNCacheArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.PurgeCDNCache(arguments.EndpointID, arguments.Files)
+			},
+		},
+		// Certificate tools
+		{
+			Name:        "list_certificates",
+			Category:    "certificate",
+			Description: "List TLS certificates, optionally by name",
+			Handler: func(arguments types.ListCertificatesArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListCertificates(arguments.Name, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_certificate",
+			Category:    "certificate",
+			Description: "Get a certificate's domains, expiry and state",
+			Handler: func(arguments types.CertificateArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetCertificate(arguments.CertificateID)
+			},
+		},
+		{
+			Name:        "create_certificate",
+			Category:    "certificate",
+			Description: "Create a Let's Encrypt certificate from dns_names, or upload a custom certificate and private key",
+			Handler: func(arguments types.CreateCertificateArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateCertificate(handlers.CertificateOptions{
+					Name:             arguments.Name,
+					DNSNames:         arguments.DNSNames,
+					LeafCertificate:  arguments.LeafCertificate,
+					PrivateKey:       arguments.PrivateKey,
+					CertificateChain: arguments.CertificateChain,
+				})
+			},
+		},
+		{
+			Name:        "delete_certificate",
+			Category:    "certificate",
+			Description: "Delete a certificate that no load balancer or CDN endpoint uses",
+			Handler: func(arguments types.DeleteCertificateArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteCertificate(arguments.CertificateID)
+			},
+			Preview: func(arguments types.DeleteCertificateArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteCertificate(arguments.CertificateID)
+			},
+		},
+
+		// Uptime tools
+		{
+			Name:        "list_uptime_checks",
+			Category:    "uptime",
+			Description: "List uptime checks",
+			Handler: func(arguments types.PaginationArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListUptimeChecks(arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_uptime_check",
+			Category:    "uptime",
+			Description: "Get details of a specific uptime check",
+			Handler: func(arguments types.UptimeCheckArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetUptimeCheck(arguments.CheckID)
+			},
+		},
+		{
+			Name:        "get_uptime_check_state",
+			Category:    "uptime",
+			Description: "Get the status of an uptime check in each region, whether it is up, in a regional outage or in a global outage, and its last outage",
+			Handler: func(arguments types.UptimeCheckArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetUptimeCheckState(arguments.CheckID)
+			},
+		},
+		{
+			Name:        "create_uptime_check",
+			Category:    "uptime",
+			Description: "Create an HTTP, HTTPS or ping uptime check probing from one or more regions",
+			Handler: func(arguments types.CreateUptimeCheckArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateUptimeCheck(handlers.UptimeCheckOptions{
+					Name:    arguments.Name,
+					Type:    arguments.Type,
+					Target:  arguments.Target,
+					Regions: arguments.Regions,
+					Enabled: arguments.Enabled,
+				})
+			},
+		},
+		{
+			Name:        "update_uptime_check",
+			Category:    "uptime",
+			Description: "Change the name, type, target, regions or state of an uptime check",
+			Handler: func(arguments types.UpdateUptimeCheckArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateUptimeCheck(arguments.CheckID, handlers.UptimeCheckUpdate{
+					Name:    arguments.Name,
+					Type:    arguments.Type,
+					Target:  arguments.Target,
+					Regions: arguments.Regions,
+					Enabled: arguments.Enabled,
+				})
+			},
+		},
+		{
+			Name:        "delete_uptime_check",
+			Category:    "uptime",
+			Description: "Delete an uptime check and its alerts",
+			Handler: func(arguments types.DeleteUptimeCheckArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteUptimeCheck(arguments.CheckID)
+			},
+			Preview: func(arguments types.DeleteUptimeCheckArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteUptimeCheck(arguments.CheckID)
+			},
+		},
+		{
+			Name:        "list_uptime_alerts",
+			Category:    "uptime",
+			Description: "List the alerts of an uptime check",
+			Handler: func(arguments types.ListUptimeAlertsArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListUptimeAlerts(arguments.CheckID, arguments.Page, arguments.PerPage)
+			},
+		},
+		{
+			Name:        "get_uptime_alert",
+			Category:    "uptime",
+			Description: "Get details of a specific uptime alert",
+			Handler: func(arguments types.UptimeAlertArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetUptimeAlert(arguments.CheckID, arguments.AlertID)
+			},
+		},
+		{
+			Name:        "create_uptime_alert",
+			Category:    "uptime",
+			Description: "Create an alert that emails or posts to Slack when an uptime check is down, slow or its certificate is about to expire",
+			Handler: func(arguments types.CreateUptimeAlertArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateUptimeAlert(arguments.CheckID, handlers.UptimeAlertOptions{
+					Name:       arguments.Name,
+					Type:       arguments.Type,
+					Threshold:  arguments.Threshold,
+					Comparison: arguments.Comparison,
+					Period:     arguments.Period,
+					Emails:     arguments.Emails,
+					Slack:      arguments.Slack,
+				})
+			},
+		},
+		{
+			Name:        "update_uptime_alert",
+			Category:    "uptime",
+			Description: "Change the type, threshold, period or destinations of an uptime alert",
+			Handler: func(arguments types.UpdateUptimeAlertArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateUptimeAlert(arguments.CheckID, arguments.AlertID, handlers.UptimeAlertUpdate{
+					Name:       arguments.Name,
+					Type:       arguments.Type,
+					Threshold:  arguments.Threshold,
+					Comparison: arguments.Comparison,
+					Period:     arguments.Period,
+					Emails:     arguments.Emails,
+					Slack:      arguments.Slack,
+				})
+			},
+		},
+		{
+			Name:        "delete_uptime_alert",
+			Category:    "uptime",
+			Description: "Delete an alert from an uptime check",
+			Handler: func(arguments types.DeleteUptimeAlertArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteUptimeAlert(arguments.CheckID, arguments.AlertID)
+			},
+			Preview: func(arguments types.DeleteUptimeAlertArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteUptimeAlert(arguments.CheckID, arguments.AlertID)
+			},
+		},
+
+		// Functions tools
+		{
+			Name:        "list_functions_namespaces",
+			Category:    "functions",
+			Description: "List Functions namespaces",
+			Handler: func(arguments types.EmptyArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListFunctionsNamespaces()
+			},
+		},
+		{
+			Name:        "get_functions_namespace",
+			Category:    "functions",
+			Description: "Get details of a Functions namespace by ID or label",
+			Handler: func(arguments types.FunctionsNamespaceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetFunctionsNamespace(arguments.Namespace, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "create_functions_namespace",
+			Category:    "functions",
+			Description: "Create a Functions namespace in a region",
+			Handler: func(arguments types.CreateFunctionsNamespaceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateFunctionsNamespace(arguments.Label, arguments.Region, arguments.ShowCredentials)
+			},
+		},
+		{
+			Name:        "delete_functions_namespace",
+			Category:    "functions",
+			Description: "Delete a Functions namespace with its functions and triggers",
+			Handler: func(arguments types.DeleteFunctionsNamespaceArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteFunctionsNamespace(arguments.Namespace)
+			},
+			Preview: func(arguments types.DeleteFunctionsNamespaceArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteFunctionsNamespace(arguments.Namespace)
+			},
+		},
+		{
+			Name:        "list_functions_triggers",
+			Category:    "functions",
+			Description: "List the triggers of a Functions namespace",
+			Handler: func(arguments types.ListFunctionsTriggersArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.ListFunctionsTriggers(arguments.Namespace)
+			},
+		},
+		{
+			Name:        "get_functions_trigger",
+			Category:    "functions",
+			Description: "Get details of a Functions trigger",
+			Handler: func(arguments types.FunctionsTriggerArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetFunctionsTrigger(arguments.Namespace, arguments.Trigger)
+			},
+		},
+		{
+			Name:        "create_functions_trigger",
+			Category:    "functions",
+			Description: "Create a trigger that runs a function on a cron schedule",
+			Handler: func(arguments types.CreateFunctionsTriggerArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.CreateFunctionsTrigger(arguments.Namespace, handlers.FunctionsTriggerOptions{
+					Name:     arguments.Name,
+					Function: arguments.Function,
+					Cron:     arguments.Cron,
+					Body:     arguments.Body,
+					Enabled:  arguments.Enabled,
+				})
+			},
+		},
+		{
+			Name:        "update_functions_trigger",
+			Category:    "functions",
+			Description: "Enable or disable a Functions trigger or change its schedule or parameters",
+			Handler: func(arguments types.UpdateFunctionsTriggerArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.UpdateFunctionsTrigger(arguments.Namespace, arguments.Trigger, handlers.FunctionsTriggerUpdate{
+					Enabled: arguments.Enabled,
+					Cron:    arguments.Cron,
+					Body:    arguments.Body,
+				})
+			},
+		},
+		{
+			Name:        "delete_functions_trigger",
+			Category:    "functions",
+			Description: "Delete a Functions trigger; the function is kept",
+			Handler: func(arguments types.DeleteFunctionsTriggerArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.DeleteFunctionsTrigger(arguments.Namespace, arguments.Trigger)
+			},
+			Preview: func(arguments types.DeleteFunctionsTriggerArgs) (*handlers.DeletionPreview, error) {
+				return handler.PreviewDeleteFunctionsTrigger(arguments.Namespace, arguments.Trigger)
+			},
+		},
+		{
+			Name:        "get_functions_schedule",
+			Category:    "functions",
+			Description: "Summarize which Functions triggers are scheduled and when each will next fire, soonest first",
+			Handler: func(arguments types.GetFunctionsScheduleArgs) (*mcp_golang.ToolResponse, error) {
+				return handler.GetFunctionsSchedule(arguments.Namespace)
+			},
+		},
+	}
+
+	if err := policy.Validate(tools); err != nil {
+		return err
+	}
+
+	gate := newConfirmationGate(handler, confirmationTTL)
+	registered := 0
+	for _, tool := range tools {
+		if !policy.Allows(tool) {
+			continue
+		}
+
+		if tool.Preview != nil {
+			wrapped, err := gate.wrap(tool)
+			if err != nil {
+				log.Printf("Failed to register %s tool: %v", tool.Name, err)
+				return err
+			}
+			tool.Handler = wrapped
+			tool.Description += ". " + confirmationHint
+		}
+
 		if err := server.RegisterTool(tool.Name, tool.Description, tool.Handler); err != nil {
 			log.Printf("Failed to register %s tool: %v", tool.Name, err)
 			return err
 		}
+		registered++
 	}
 
+	log.Printf("Registered %d of %d tools", registered, len(tools))
 	return nil
 }
\ No newline at end of file
@@ -1,12 +1,64 @@
 package types
 
-import "github.com/digitalocean/godo"
+import (
+	"digitalocean-mcp-server/client"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
 
 type EmptyArgs struct{}
 
+// ConfirmArgs is embedded in the arguments of destructive tools. A call without
+// a token returns a deletion preview and a token; repeating the call with the
+// token performs the deletion.
+type ConfirmArgs struct {
+	ConfirmationToken string `json:"confirmation_token,omitempty" jsonschema:"description=Token returned by a previous preview call; omit it to preview what will be deleted"`
+}
+
+func (c ConfirmArgs) GetConfirmationToken() string {
+	return c.ConfirmationToken
+}
+
+// PaginationArgs is embedded in the arguments of list tools. Without a page
+// every page is fetched; with a page only that page is returned.
+type PaginationArgs struct {
+	Page    int `json:"page,omitempty" jsonschema:"description=Page number to retrieve (starting from 1); omit to fetch all pages"`
+	PerPage int `json:"per_page,omitempty" jsonschema:"description=Number of items per page (1-200); defaults to 25 when page is set"`
+}
+
+const (
+	// DefaultWaitTimeout applies when a caller asks to wait without a timeout.
+	DefaultWaitTimeout = 5 * time.Minute
+	// MaxWaitTimeout caps how long a single tool call may block.
+	MaxWaitTimeout = 30 * time.Minute
+)
+
+// WaitArgs is embedded in the arguments of tools that start an action. With
+// wait set the tool returns only once the action has completed or errored.
+type WaitArgs struct {
+	Wait               bool `json:"wait,omitempty" jsonschema:"description=Wait for the action to finish before returning (optional)"`
+	WaitTimeoutSeconds int  `json:"wait_timeout_seconds,omitempty" jsonschema:"description=How long to wait when wait is set; defaults to 300 seconds, at most 1800 (optional)"`
+}
+
+// WaitTimeout returns how long to wait for the action, or zero when the caller
+// did not ask to wait.
+func (w WaitArgs) WaitTimeout() time.Duration {
+	if !w.Wait {
+		return 0
+	}
+	return waitTimeout(w.WaitTimeoutSeconds)
+}
+
+func waitTimeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return DefaultWaitTimeout
+	}
+	return min(time.Duration(seconds)*time.Second, MaxWaitTimeout)
+}
+
 type ListDropletsArgs struct {
-	Page    int `json:"page" jsonschema:"description=Page number to retrieve (starting from 1),default=1"`
-	PerPage int `json:"per_page" jsonschema:"description=Number of items per page (1-200),default=25"`
+	PaginationArgs
 }
 
 type GetDropletArgs struct {
@@ -14,50 +66,260 @@ type GetDropletArgs struct {
 }
 
 type CreateDropletArgs struct {
-	Name   string `json:"name" jsonschema:"description=Name of the droplet"`
-	Region string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3', 'sfo2')"`
-	Size   string `json:"size" jsonschema:"description=Size slug (e.g., 's-1vcpu-1gb')"`
-	Image  string `json:"image" jsonschema:"description=Image slug (e.g., 'ubuntu-22-04-x64')"`
+	Name       string   `json:"name,omitempty" jsonschema:"description=Name of the droplet; required unless names is set"`
+	Names      []string `json:"names,omitempty" jsonschema:"description=Create one droplet per name (up to 10) in a single request instead of using name (optional)"`
+	Region     string   `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3'); see list_regions"`
+	Size       string   `json:"size" jsonschema:"description=Size slug (e.g., 's-1vcpu-1gb'); see list_sizes"`
+	Image      string   `json:"image" jsonschema:"description=Image slug (e.g., 'ubuntu-22-04-x64') or numeric ID of a custom image or snapshot"`
+	SSHKeys    []string `json:"ssh_keys,omitempty" jsonschema:"description=SSH keys to install, by numeric ID or fingerprint (optional)"`
+	Tags       []string `json:"tags,omitempty" jsonschema:"description=Tags to apply (optional)"`
+	VPCUUID    string   `json:"vpc_uuid,omitempty" jsonschema:"description=UUID of the VPC to place the droplet in; defaults to the region's default VPC (optional)"`
+	UserData   string   `json:"user_data,omitempty" jsonschema:"description=Cloud-init user data or script, up to 64 KiB (optional)"`
+	Monitoring bool     `json:"monitoring,omitempty" jsonschema:"description=Install the metrics agent (optional)"`
+	Backups    bool     `json:"backups,omitempty" jsonschema:"description=Enable weekly backups (optional)"`
+	IPv6       bool     `json:"ipv6,omitempty" jsonschema:"description=Enable IPv6 networking (optional)"`
+	Volumes    []string `json:"volumes,omitempty" jsonschema:"description=IDs of volumes in the same region to attach; not allowed with names (optional)"`
+	ProjectID  string   `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
+	WaitArgs
 }
 
 type DeleteDropletArgs struct {
 	DropletID int `json:"droplet_id" jsonschema:"description=ID of the droplet to delete"`
+	ConfirmArgs
 }
 
 type ResizeDropletArgs struct {
 	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to resize"`
 	Size      string `json:"size" jsonschema:"description=New size slug (e.g., 's-2vcpu-2gb')"`
 	Disk      bool   `json:"disk" jsonschema:"description=Whether to resize disk (permanent, cannot be undone),default=false"`
+	WaitArgs
 }
 
 type CreateDropletSnapshotArgs struct {
 	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to snapshot"`
 	Name      string `json:"name" jsonschema:"description=Name for the snapshot"`
+	WaitArgs
+}
+
+// DropletActionArgs is shared by droplet actions that need nothing but the
+// droplet ID, such as power_on_droplet and reboot_droplet.
+type DropletActionArgs struct {
+	DropletID int `json:"droplet_id" jsonschema:"description=ID of the droplet"`
+	WaitArgs
+}
+
+type RebuildDropletArgs struct {
+	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to rebuild"`
+	Image     string `json:"image" jsonschema:"description=Image slug (e.g., 'ubuntu-24-04-x64') or numeric image ID to rebuild from"`
+	WaitArgs
+	ConfirmArgs
+}
+
+type RenameDropletArgs struct {
+	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to rename"`
+	Name      string `json:"name" jsonschema:"description=New name for the droplet"`
+	WaitArgs
+}
+
+type DisableDropletBackupsArgs struct {
+	DropletID int `json:"droplet_id" jsonschema:"description=ID of the droplet"`
+	WaitArgs
+	ConfirmArgs
+}
+
+type ChangeDropletKernelArgs struct {
+	DropletID int `json:"droplet_id" jsonschema:"description=ID of the droplet"`
+	KernelID  int `json:"kernel_id" jsonschema:"description=ID of the kernel to boot (see list_droplet_kernels)"`
+	WaitArgs
+}
+
+type ListDropletKernelsArgs struct {
+	DropletID int `json:"droplet_id" jsonschema:"description=ID of the droplet"`
+	PaginationArgs
+}
+
+type DropletTagActionArgs struct {
+	Tag string `json:"tag" jsonschema:"description=Act on every droplet with this tag"`
+	WaitArgs
+}
+
+// ConfirmDropletTagActionArgs is used by the by-tag actions that disrupt or
+// remove data, which preview the matched droplets first.
+type ConfirmDropletTagActionArgs struct {
+	Tag string `json:"tag" jsonschema:"description=Act on every droplet with this tag"`
+	WaitArgs
+	ConfirmArgs
+}
+
+type SnapshotDropletsByTagArgs struct {
+	Tag          string `json:"tag" jsonschema:"description=Snapshot every droplet with this tag"`
+	SnapshotName string `json:"snapshot_name" jsonschema:"description=Name given to each snapshot"`
+	WaitArgs
+}
+
+type DeleteDropletsByTagArgs struct {
+	Tag string `json:"tag" jsonschema:"description=Delete every droplet with this tag"`
+	ConfirmArgs
+}
+
+type GetActionArgs struct {
+	ActionID int `json:"action_id" jsonschema:"description=ID of the action"`
+}
+
+type ListActionsArgs struct {
+	DropletID int `json:"droplet_id,omitempty" jsonschema:"description=Only list actions of this droplet (optional)"`
+	PaginationArgs
+}
+
+type WaitForActionArgs struct {
+	ActionID       int `json:"action_id" jsonschema:"description=ID of the action to wait for"`
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"description=How long to wait; defaults to 300 seconds, at most 1800 (optional)"`
+}
+
+func (w WaitForActionArgs) Timeout() time.Duration {
+	return waitTimeout(w.TimeoutSeconds)
 }
 
 type GetRegistryArgs struct {
 	RegistryName string `json:"registry_name" jsonschema:"description=Name of the registry"`
 }
 
+type ListRepositoriesArgs struct {
+	RegistryName string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	PaginationArgs
+}
+
+type GetRepositoryArgs struct {
+	RegistryName   string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	RepositoryName string `json:"repository_name" jsonschema:"description=Name of the repository (e.g., 'api' or 'team/api')"`
+}
+
+type ListRepositoryTagsArgs struct {
+	RegistryName   string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	RepositoryName string `json:"repository_name" jsonschema:"description=Name of the repository"`
+	PaginationArgs
+}
+
+type ListRepositoryManifestsArgs struct {
+	RegistryName   string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	RepositoryName string `json:"repository_name" jsonschema:"description=Name of the repository"`
+	PaginationArgs
+}
+
+type DeleteRepositoryTagArgs struct {
+	RegistryName   string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	RepositoryName string `json:"repository_name" jsonschema:"description=Name of the repository"`
+	Tag            string `json:"tag" jsonschema:"description=Tag to delete (e.g., 'v1.2.0')"`
+	ConfirmArgs
+}
+
+type DeleteRepositoryManifestArgs struct {
+	RegistryName   string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	RepositoryName string `json:"repository_name" jsonschema:"description=Name of the repository"`
+	Digest         string `json:"digest" jsonschema:"description=Manifest digest to delete (e.g., 'sha256:...')"`
+	ConfirmArgs
+}
+
+type StartRegistryGarbageCollectionArgs struct {
+	RegistryName string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	Type         string `json:"type,omitempty" jsonschema:"description=What to collect: untagged_manifests_only, unreferenced_blobs_only or untagged_manifests_and_unreferenced_blobs (optional, defaults to unreferenced_blobs_only)"`
+}
+
+type ListRegistryGarbageCollectionsArgs struct {
+	RegistryName string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	PaginationArgs
+}
+
+type CancelRegistryGarbageCollectionArgs struct {
+	RegistryName string `json:"registry_name" jsonschema:"description=Name of the registry"`
+	UUID         string `json:"garbage_collection_uuid,omitempty" jsonschema:"description=UUID of the garbage collection to cancel; omit to cancel the active one (optional)"`
+}
+
+type GetRegistryDockerCredentialsArgs struct {
+	ReadWrite     bool `json:"read_write,omitempty" jsonschema:"description=Issue credentials that can push as well as pull (optional, defaults to read-only)"`
+	ExpirySeconds int  `json:"expiry_seconds,omitempty" jsonschema:"description=Lifetime of the credentials in seconds; defaults to 3600. Pass -1 only when credentials that never expire are explicitly needed (optional)"`
+}
+
 type GetK8SClusterArgs struct {
 	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the cluster"`
 }
 
 type CreateK8SClusterArgs struct {
 	Name         string `json:"name" jsonschema:"description=Name of the cluster"`
-	Region       string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3', 'sfo2')"`
-	Version      string `json:"version" jsonschema:"description=Kubernetes version (e.g., '1.28.2-do.0')"`
-	NodePoolSize string `json:"node_pool_size" jsonschema:"description=Node pool size (e.g., 's-2vcpu-2gb')"`
+	Region       string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3'); see list_k8s_options"`
+	Version      string `json:"version" jsonschema:"description=Kubernetes version slug (e.g., '1.29.1-do.0') or 'latest'; see list_k8s_options"`
+	NodePoolSize string `json:"node_pool_size" jsonschema:"description=Node pool size (e.g., 's-2vcpu-2gb'); see list_k8s_options"`
 	NodeCount    int    `json:"node_count" jsonschema:"description=Number of nodes in the pool"`
+	VPCUUID      string `json:"vpc_uuid,omitempty" jsonschema:"description=UUID of the VPC to place the cluster in; defaults to the region's default VPC (optional)"`
+	ProjectID    string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
 }
 
 type DeleteK8SClusterArgs struct {
 	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the cluster to delete"`
+	ConfirmArgs
+}
+
+type ListK8SNodePoolsArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	PaginationArgs
+}
+
+type GetK8SNodePoolArgs struct {
+	ClusterID  string `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	NodePoolID string `json:"node_pool_id" jsonschema:"description=ID of the node pool"`
+}
+
+type CreateK8SNodePoolArgs struct {
+	ClusterID string            `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	Name      string            `json:"name" jsonschema:"description=Name of the node pool"`
+	Size      string            `json:"size" jsonschema:"description=Droplet size slug for the nodes (e.g., 's-2vcpu-4gb')"`
+	Count     int               `json:"count" jsonschema:"description=Number of nodes"`
+	AutoScale bool              `json:"auto_scale,omitempty" jsonschema:"description=Enable the cluster autoscaler for this pool (optional)"`
+	MinNodes  int               `json:"min_nodes,omitempty" jsonschema:"description=Minimum number of nodes when auto_scale is enabled (optional)"`
+	MaxNodes  int               `json:"max_nodes,omitempty" jsonschema:"description=Maximum number of nodes when auto_scale is enabled (optional)"`
+	Tags      []string          `json:"tags,omitempty" jsonschema:"description=Tags applied to the nodes (optional)"`
+	Labels    map[string]string `json:"labels,omitempty" jsonschema:"description=Kubernetes labels applied to the nodes (optional)"`
+	Taints    []string          `json:"taints,omitempty" jsonschema:"description=Kubernetes taints as key=value:Effect where Effect is NoSchedule, PreferNoSchedule or NoExecute (optional)"`
+}
+
+type UpdateK8SNodePoolArgs struct {
+	ClusterID  string            `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	NodePoolID string            `json:"node_pool_id" jsonschema:"description=ID of the node pool to update"`
+	Name       string            `json:"name,omitempty" jsonschema:"description=New name for the node pool (optional)"`
+	Count      *int              `json:"count,omitempty" jsonschema:"description=New number of nodes (optional)"`
+	AutoScale  *bool             `json:"auto_scale,omitempty" jsonschema:"description=Enable or disable autoscaling (optional)"`
+	MinNodes   *int              `json:"min_nodes,omitempty" jsonschema:"description=Minimum number of nodes when autoscaling (optional)"`
+	MaxNodes   *int              `json:"max_nodes,omitempty" jsonschema:"description=Maximum number of nodes when autoscaling (optional)"`
+	Labels     map[string]string `json:"labels,omitempty" jsonschema:"description=Replaces the pool's Kubernetes labels (optional)"`
+	Taints     *[]string         `json:"taints,omitempty" jsonschema:"description=Replaces the pool's taints, written as key=value:Effect; pass an empty list to remove all taints (optional)"`
+}
+
+type DeleteK8SNodePoolArgs struct {
+	ClusterID  string `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	NodePoolID string `json:"node_pool_id" jsonschema:"description=ID of the node pool to delete"`
+	ConfirmArgs
+}
+
+type RecycleK8SNodePoolNodesArgs struct {
+	ClusterID  string   `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	NodePoolID string   `json:"node_pool_id" jsonschema:"description=ID of the node pool"`
+	NodeIDs    []string `json:"node_ids,omitempty" jsonschema:"description=IDs of the nodes to replace; omit to replace every node in the pool (optional)"`
+	SkipDrain  bool     `json:"skip_drain,omitempty" jsonschema:"description=Delete nodes without draining them first (optional)"`
+	ConfirmArgs
+}
+
+type DeleteK8SNodeArgs struct {
+	ClusterID  string `json:"cluster_id" jsonschema:"description=ID of the cluster"`
+	NodePoolID string `json:"node_pool_id" jsonschema:"description=ID of the node pool"`
+	NodeID     string `json:"node_id" jsonschema:"description=ID of the node to delete"`
+	SkipDrain  bool   `json:"skip_drain,omitempty" jsonschema:"description=Delete the node without draining it first (optional)"`
+	ConfirmArgs
 }
 
 // Volume-related args
 type ListVolumesArgs struct {
 	Region string `json:"region,omitempty" jsonschema:"description=Filter volumes by region (optional)"`
+	PaginationArgs
 }
 
 type GetVolumeArgs struct {
@@ -66,29 +328,34 @@ type GetVolumeArgs struct {
 
 type CreateVolumeArgs struct {
 	Name          string `json:"name" jsonschema:"description=Name of the volume"`
-	Region        string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3', 'sfo2')"`
+	Region        string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3'); see list_regions with feature 'storage'"`
 	SizeGigaBytes int64  `json:"size_gigabytes" jsonschema:"description=Size of the volume in gigabytes"`
 	Description   string `json:"description,omitempty" jsonschema:"description=Description of the volume (optional)"`
+	ProjectID     string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
 }
 
 type DeleteVolumeArgs struct {
 	VolumeID string `json:"volume_id" jsonschema:"description=ID of the volume to delete"`
+	ConfirmArgs
 }
 
 type AttachVolumeArgs struct {
 	VolumeID  string `json:"volume_id" jsonschema:"description=ID of the volume to attach"`
 	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to attach to"`
+	WaitArgs
 }
 
 type DetachVolumeArgs struct {
 	VolumeID  string `json:"volume_id" jsonschema:"description=ID of the volume to detach"`
 	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet to detach from"`
+	WaitArgs
 }
 
 type ResizeVolumeArgs struct {
 	VolumeID      string `json:"volume_id" jsonschema:"description=ID of the volume to resize"`
 	SizeGigaBytes int64  `json:"size_gigabytes" jsonschema:"description=New size in gigabytes"`
 	Region        string `json:"region" jsonschema:"description=Region slug"`
+	WaitArgs
 }
 
 type CreateVolumeSnapshotArgs struct {
@@ -100,6 +367,7 @@ type CreateVolumeSnapshotArgs struct {
 // Snapshot-related args
 type ListSnapshotsArgs struct {
 	ResourceType string `json:"resource_type,omitempty" jsonschema:"description=Filter by resource type: 'droplet' or 'volume' (optional)"`
+	PaginationArgs
 }
 
 type GetSnapshotArgs struct {
@@ -108,12 +376,14 @@ type GetSnapshotArgs struct {
 
 type DeleteSnapshotArgs struct {
 	SnapshotID string `json:"snapshot_id" jsonschema:"description=ID of the snapshot to delete"`
+	ConfirmArgs
 }
 
 // Image-related args
 type ListImagesArgs struct {
 	Type     string `json:"type,omitempty" jsonschema:"description=Image type: 'distribution', 'application', 'user' (optional)"`
 	IsPublic bool   `json:"is_public,omitempty" jsonschema:"description=Whether to include public images (optional)"`
+	PaginationArgs
 }
 
 type GetImageArgs struct {
@@ -127,15 +397,18 @@ type UpdateImageArgs struct {
 
 type DeleteImageArgs struct {
 	ImageID string `json:"image_id" jsonschema:"description=ID of the image to delete"`
+	ConfirmArgs
 }
 
 type TransferImageArgs struct {
 	ImageID    string `json:"image_id" jsonschema:"description=ID of the image to transfer"`
 	RegionSlug string `json:"region_slug" jsonschema:"description=Region slug to transfer to"`
+	WaitArgs
 }
 
 type ConvertImageToSnapshotArgs struct {
 	ImageID string `json:"image_id" jsonschema:"description=ID of the image to convert"`
+	WaitArgs
 }
 
 // Floating IP-related args
@@ -146,19 +419,56 @@ type GetFloatingIPArgs struct {
 type CreateFloatingIPArgs struct {
 	Region    string `json:"region,omitempty" jsonschema:"description=Region slug for reserved IP (required if no droplet_id)"`
 	DropletID int    `json:"droplet_id,omitempty" jsonschema:"description=Droplet ID to assign to (optional)"`
+	ProjectID string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
 }
 
 type DeleteFloatingIPArgs struct {
 	IP string `json:"ip" jsonschema:"description=Floating IP address to delete"`
+	ConfirmArgs
 }
 
 type AssignFloatingIPArgs struct {
 	IP        string `json:"ip" jsonschema:"description=Floating IP address"`
 	DropletID int    `json:"droplet_id" jsonschema:"description=Droplet ID to assign to"`
+	WaitArgs
 }
 
 type UnassignFloatingIPArgs struct {
 	IP string `json:"ip" jsonschema:"description=Floating IP address to unassign"`
+	WaitArgs
+}
+
+// Reserved IP-related args
+type ListReservedIPsArgs struct {
+	IPVersion string `json:"ip_version,omitempty" jsonschema:"description=ipv4 or ipv6; lists both when omitted (optional)"`
+	PaginationArgs
+}
+
+type ReservedIPArgs struct {
+	IP string `json:"ip" jsonschema:"description=Reserved IPv4 or IPv6 address"`
+}
+
+type CreateReservedIPArgs struct {
+	IPVersion string `json:"ip_version,omitempty" jsonschema:"description=ipv4 or ipv6; defaults to ipv4 (optional)"`
+	Region    string `json:"region,omitempty" jsonschema:"description=Region slug to reserve the IP in; required for IPv6 and for IPv4 without droplet_id"`
+	DropletID int    `json:"droplet_id,omitempty" jsonschema:"description=Droplet ID to reserve an IPv4 address for and assign it to (optional)"`
+	ProjectID string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
+}
+
+type DeleteReservedIPArgs struct {
+	IP string `json:"ip" jsonschema:"description=Reserved IPv4 or IPv6 address to release"`
+	ConfirmArgs
+}
+
+type AssignReservedIPArgs struct {
+	IP        string `json:"ip" jsonschema:"description=Reserved IPv4 or IPv6 address"`
+	DropletID int    `json:"droplet_id" jsonschema:"description=Droplet ID to assign to; it must be in the IP's region"`
+	WaitArgs
+}
+
+type UnassignReservedIPArgs struct {
+	IP string `json:"ip" jsonschema:"description=Reserved IPv4 or IPv6 address to unassign"`
+	WaitArgs
 }
 
 // Load Balancer-related args
@@ -170,8 +480,10 @@ type CreateLoadBalancerArgs struct {
 	Name            string                  `json:"name" jsonschema:"description=Name of the load balancer"`
 	Algorithm       string                  `json:"algorithm" jsonschema:"description=Load balancing algorithm: 'round_robin', 'least_connections'"`
 	Region          string                  `json:"region" jsonschema:"description=Region slug"`
-	ForwardingRules []godo.ForwardingRule   `json:"forwarding_rules" jsonschema:"description=Forwarding rules configuration"`
+	ForwardingRules []godo.ForwardingRule   `json:"forwarding_rules" jsonschema:"description=Forwarding rules configuration; certificate_id may be a certificate ID or name"`
 	DropletIDs      []int                   `json:"droplet_ids,omitempty" jsonschema:"description=Droplet IDs to add (optional)"`
+	VPCUUID         string                  `json:"vpc_uuid,omitempty" jsonschema:"description=UUID of the VPC to place the load balancer in; defaults to the region's default VPC (optional)"`
+	ProjectID       string                  `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
 }
 
 type UpdateLoadBalancerArgs struct {
@@ -179,12 +491,13 @@ type UpdateLoadBalancerArgs struct {
 	Name            string                  `json:"name" jsonschema:"description=Name of the load balancer"`
 	Algorithm       string                  `json:"algorithm" jsonschema:"description=Load balancing algorithm"`
 	Region          string                  `json:"region" jsonschema:"description=Region slug"`
-	ForwardingRules []godo.ForwardingRule   `json:"forwarding_rules" jsonschema:"description=Forwarding rules configuration"`
+	ForwardingRules []godo.ForwardingRule   `json:"forwarding_rules" jsonschema:"description=Forwarding rules configuration; certificate_id may be a certificate ID or name"`
 	DropletIDs      []int                   `json:"droplet_ids,omitempty" jsonschema:"description=Droplet IDs (optional)"`
 }
 
 type DeleteLoadBalancerArgs struct {
 	LoadBalancerID string `json:"load_balancer_id" jsonschema:"description=ID of the load balancer to delete"`
+	ConfirmArgs
 }
 
 type AddDropletsToLoadBalancerArgs struct {
@@ -199,7 +512,7 @@ type RemoveDropletsFromLoadBalancerArgs struct {
 
 type AddForwardingRulesToLoadBalancerArgs struct {
 	LoadBalancerID  string                `json:"load_balancer_id" jsonschema:"description=ID of the load balancer"`
-	ForwardingRules []godo.ForwardingRule `json:"forwarding_rules" jsonschema:"description=Forwarding rules to add"`
+	ForwardingRules []godo.ForwardingRule `json:"forwarding_rules" jsonschema:"description=Forwarding rules to add; certificate_id may be a certificate ID or name"`
 }
 
 type RemoveForwardingRulesFromLoadBalancerArgs struct {
@@ -229,6 +542,7 @@ type UpdateFirewallArgs struct {
 
 type DeleteFirewallArgs struct {
 	FirewallID string `json:"firewall_id" jsonschema:"description=ID of the firewall to delete"`
+	ConfirmArgs
 }
 
 type AddDropletsToFirewallArgs struct {
@@ -261,4 +575,723 @@ type RemoveRulesFromFirewallArgs struct {
 	FirewallID    string              `json:"firewall_id" jsonschema:"description=ID of the firewall"`
 	InboundRules  []godo.InboundRule  `json:"inbound_rules,omitempty" jsonschema:"description=Inbound rules to remove (optional)"`
 	OutboundRules []godo.OutboundRule `json:"outbound_rules,omitempty" jsonschema:"description=Outbound rules to remove (optional)"`
-}
\ No newline at end of file
+}
+// Domain-related args
+type GetDomainArgs struct {
+	Name string `json:"name" jsonschema:"description=Domain name (e.g., 'example.com')"`
+}
+
+type CreateDomainArgs struct {
+	Name      string `json:"name" jsonschema:"description=Domain name to add (e.g., 'example.com')"`
+	IPAddress string `json:"ip_address,omitempty" jsonschema:"description=IP address for an A record on the domain apex (optional)"`
+	ProjectID string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
+}
+
+type DeleteDomainArgs struct {
+	Name string `json:"name" jsonschema:"description=Domain name to delete"`
+	ConfirmArgs
+}
+
+type ListDomainRecordsArgs struct {
+	Domain string `json:"domain" jsonschema:"description=Domain name (e.g., 'example.com')"`
+	Name   string `json:"name,omitempty" jsonschema:"description=Only return records with this name, relative ('www', '@') or fully qualified ('www.example.com') (optional)"`
+	Type   string `json:"type,omitempty" jsonschema:"description=Only return records of this type: A, AAAA, CAA, CNAME, MX, NS, SOA, SRV or TXT (optional)"`
+	PaginationArgs
+}
+
+type GetDomainRecordArgs struct {
+	Domain   string `json:"domain" jsonschema:"description=Domain name (e.g., 'example.com')"`
+	RecordID int    `json:"record_id" jsonschema:"description=ID of the record"`
+}
+
+type CreateDomainRecordArgs struct {
+	Domain   string `json:"domain" jsonschema:"description=Domain name (e.g., 'example.com')"`
+	Type     string `json:"type" jsonschema:"description=Record type: A, AAAA, CAA, CNAME, MX, NS, SRV or TXT"`
+	Name     string `json:"name" jsonschema:"description=Host name relative to the domain; use '@' for the apex"`
+	Data     string `json:"data" jsonschema:"description=Record value, e.g. an IP address, a host name ending in '.' or TXT content"`
+	TTL      int    `json:"ttl,omitempty" jsonschema:"description=Time to live in seconds; defaults to 1800 (optional)"`
+	Priority int    `json:"priority,omitempty" jsonschema:"description=Priority for MX and SRV records (optional)"`
+	Port     int    `json:"port,omitempty" jsonschema:"description=Port for SRV records (optional)"`
+	Weight   int    `json:"weight,omitempty" jsonschema:"description=Weight for SRV records (optional)"`
+	Flags    int    `json:"flags,omitempty" jsonschema:"description=Flags for CAA records, 0-255 (optional)"`
+	Tag      string `json:"tag,omitempty" jsonschema:"description=Tag for CAA records: issue, issuewild or iodef (optional)"`
+}
+
+type UpdateDomainRecordArgs struct {
+	Domain   string `json:"domain" jsonschema:"description=Domain name (e.g., 'example.com')"`
+	RecordID int    `json:"record_id" jsonschema:"description=ID of the record to update"`
+	Name     string `json:"name,omitempty" jsonschema:"description=New host name (optional)"`
+	Data     string `json:"data,omitempty" jsonschema:"description=New record value (optional)"`
+	TTL      *int   `json:"ttl,omitempty" jsonschema:"description=New time to live in seconds (optional)"`
+	Priority *int   `json:"priority,omitempty" jsonschema:"description=New priority for MX and SRV records (optional)"`
+	Port     *int   `json:"port,omitempty" jsonschema:"description=New port for SRV records (optional)"`
+	Weight   *int   `json:"weight,omitempty" jsonschema:"description=New weight for SRV records (optional)"`
+	Flags    *int   `json:"flags,omitempty" jsonschema:"description=New flags for CAA records (optional)"`
+	Tag      string `json:"tag,omitempty" jsonschema:"description=New tag for CAA records (optional)"`
+}
+
+type DeleteDomainRecordArgs struct {
+	Domain   string `json:"domain" jsonschema:"description=Domain name (e.g., 'example.com')"`
+	RecordID int    `json:"record_id" jsonschema:"description=ID of the record to delete"`
+	ConfirmArgs
+}
+
+// Tag-related args
+type GetTagArgs struct {
+	Name string `json:"name" jsonschema:"description=Name of the tag"`
+}
+
+type CreateTagArgs struct {
+	Name string `json:"name" jsonschema:"description=Name of the tag (letters, numbers, colons, dashes and underscores)"`
+}
+
+type DeleteTagArgs struct {
+	Name string `json:"name" jsonschema:"description=Name of the tag to delete"`
+	ConfirmArgs
+}
+
+type TagResourcesArgs struct {
+	Name      string          `json:"name" jsonschema:"description=Name of the tag"`
+	Resources []godo.Resource `json:"resources" jsonschema:"description=Resources as {resource_id, resource_type}; resource_type is droplet, volume, image, database or load_balancer"`
+}
+
+type UntagResourcesArgs struct {
+	Name      string          `json:"name" jsonschema:"description=Name of the tag"`
+	Resources []godo.Resource `json:"resources" jsonschema:"description=Resources as {resource_id, resource_type}; resource_type is droplet, volume, image, database or load_balancer"`
+}
+
+// VPC-related args
+type GetVPCArgs struct {
+	VPCID string `json:"vpc_id" jsonschema:"description=ID of the VPC"`
+}
+
+type CreateVPCArgs struct {
+	Name        string `json:"name" jsonschema:"description=Name of the VPC"`
+	Region      string `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3')"`
+	Description string `json:"description,omitempty" jsonschema:"description=Description of the VPC (optional)"`
+	IPRange     string `json:"ip_range,omitempty" jsonschema:"description=Private IPv4 range in CIDR notation, /16 to /28 (e.g., '10.10.10.0/24'); assigned automatically when omitted (optional)"`
+}
+
+type UpdateVPCArgs struct {
+	VPCID       string  `json:"vpc_id" jsonschema:"description=ID of the VPC to update"`
+	Name        *string `json:"name,omitempty" jsonschema:"description=New name (optional)"`
+	Description *string `json:"description,omitempty" jsonschema:"description=New description; an empty string clears it (optional)"`
+	MakeDefault bool    `json:"make_default,omitempty" jsonschema:"description=Make this VPC the default for its region (optional)"`
+}
+
+type DeleteVPCArgs struct {
+	VPCID string `json:"vpc_id" jsonschema:"description=ID of the VPC to delete"`
+	ConfirmArgs
+}
+
+type ListVPCMembersArgs struct {
+	VPCID        string `json:"vpc_id" jsonschema:"description=ID of the VPC"`
+	ResourceType string `json:"resource_type,omitempty" jsonschema:"description=Only return members of this type: droplet, load_balancer or kubernetes (optional)"`
+	PaginationArgs
+}
+
+type ListVPCPeeringsArgs struct {
+	VPCID string `json:"vpc_id,omitempty" jsonschema:"description=Only return peerings of this VPC (optional)"`
+	PaginationArgs
+}
+
+type GetVPCPeeringArgs struct {
+	PeeringID string `json:"peering_id" jsonschema:"description=ID of the VPC peering"`
+}
+
+type CreateVPCPeeringArgs struct {
+	Name   string   `json:"name" jsonschema:"description=Name of the peering"`
+	VPCIDs []string `json:"vpc_ids" jsonschema:"description=IDs of the two VPCs to connect; their IP ranges must not overlap"`
+}
+
+type UpdateVPCPeeringArgs struct {
+	PeeringID string `json:"peering_id" jsonschema:"description=ID of the VPC peering"`
+	Name      string `json:"name" jsonschema:"description=New name of the peering"`
+}
+
+type DeleteVPCPeeringArgs struct {
+	PeeringID string `json:"peering_id" jsonschema:"description=ID of the VPC peering to delete"`
+	ConfirmArgs
+}
+
+// Database-related args
+type ShowCredentialsArgs struct {
+	ShowCredentials bool `json:"show_credentials,omitempty" jsonschema:"description=Return passwords and connection URIs unmasked; leave unset unless the credentials are needed (optional)"`
+}
+
+type DatabaseClusterArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+}
+
+type ListDatabaseClusterItemsArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	PaginationArgs
+}
+
+type ListDatabaseClusterCredentialsArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	PaginationArgs
+	ShowCredentialsArgs
+}
+
+type GetDatabaseClusterArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	ShowCredentialsArgs
+}
+
+type CreateDatabaseClusterArgs struct {
+	Name           string                             `json:"name" jsonschema:"description=Name of the cluster"`
+	Engine         string                             `json:"engine" jsonschema:"description=Engine: pg, mysql, redis, valkey, mongodb, kafka or opensearch"`
+	Version        string                             `json:"version,omitempty" jsonschema:"description=Engine version (e.g., '16' for PostgreSQL); defaults to the latest (optional)"`
+	Size           string                             `json:"size" jsonschema:"description=Node size slug (e.g., 'db-s-1vcpu-1gb')"`
+	Region         string                             `json:"region" jsonschema:"description=Region slug (e.g., 'nyc3')"`
+	NumNodes       int                                `json:"num_nodes,omitempty" jsonschema:"description=Number of nodes, 1-3; defaults to 1 (optional)"`
+	VPCUUID        string                             `json:"vpc_uuid,omitempty" jsonschema:"description=UUID of the VPC to place the cluster in; defaults to the region's default VPC (optional)"`
+	Tags           []string                           `json:"tags,omitempty" jsonschema:"description=Tags to apply (optional)"`
+	StorageSizeMib uint64                             `json:"storage_size_mib,omitempty" jsonschema:"description=Additional storage in MiB (optional)"`
+	TrustedSources []*godo.DatabaseCreateFirewallRule `json:"trusted_sources,omitempty" jsonschema:"description=Sources allowed to connect, as {type, value} with type ip_addr, droplet, k8s, tag or app (optional)"`
+	ProjectID      string                             `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
+	ShowCredentialsArgs
+}
+
+type ResizeDatabaseClusterArgs struct {
+	ClusterID      string  `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Size           string  `json:"size,omitempty" jsonschema:"description=New node size slug (optional)"`
+	NumNodes       *int    `json:"num_nodes,omitempty" jsonschema:"description=New number of nodes, 1-3 (optional)"`
+	StorageSizeMib *uint64 `json:"storage_size_mib,omitempty" jsonschema:"description=New additional storage in MiB; storage cannot shrink (optional)"`
+}
+
+type DeleteDatabaseClusterArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster to delete"`
+	ConfirmArgs
+}
+
+type CreateDatabaseArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the database"`
+}
+
+type DeleteDatabaseArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the database to delete"`
+	ConfirmArgs
+}
+
+type GetDatabaseUserArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the user"`
+	ShowCredentialsArgs
+}
+
+type CreateDatabaseUserArgs struct {
+	ClusterID       string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name            string `json:"name" jsonschema:"description=Name of the user"`
+	MySQLAuthPlugin string `json:"mysql_auth_plugin,omitempty" jsonschema:"description=MySQL only: mysql_native_password or caching_sha2_password (optional)"`
+	ShowCredentialsArgs
+}
+
+type ResetDatabaseUserPasswordArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the user whose password is regenerated"`
+	ShowCredentialsArgs
+}
+
+type DeleteDatabaseUserArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the user to delete"`
+	ConfirmArgs
+}
+
+type CreateDatabasePoolArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the PostgreSQL cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the pool"`
+	Database  string `json:"database" jsonschema:"description=Database the pool connects to"`
+	User      string `json:"user" jsonschema:"description=User the pool connects as"`
+	Size      int    `json:"size" jsonschema:"description=Number of backend connections"`
+	Mode      string `json:"mode" jsonschema:"description=Pool mode: session, transaction or statement"`
+	ShowCredentialsArgs
+}
+
+type UpdateDatabasePoolArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the PostgreSQL cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the pool to update"`
+	Database  string `json:"database,omitempty" jsonschema:"description=New database (optional)"`
+	User      string `json:"user,omitempty" jsonschema:"description=New user (optional)"`
+	Size      *int   `json:"size,omitempty" jsonschema:"description=New number of backend connections (optional)"`
+	Mode      string `json:"mode,omitempty" jsonschema:"description=New pool mode: session, transaction or statement (optional)"`
+}
+
+type DeleteDatabasePoolArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the PostgreSQL cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the pool to delete"`
+	ConfirmArgs
+}
+
+type CreateDatabaseReplicaArgs struct {
+	ClusterID string   `json:"cluster_id" jsonschema:"description=ID of the primary cluster"`
+	Name      string   `json:"name" jsonschema:"description=Name of the replica"`
+	Region    string   `json:"region,omitempty" jsonschema:"description=Region slug; defaults to the primary's region (optional)"`
+	Size      string   `json:"size,omitempty" jsonschema:"description=Node size slug; defaults to the primary's size (optional)"`
+	VPCUUID   string   `json:"vpc_uuid,omitempty" jsonschema:"description=UUID of the VPC to place the replica in (optional)"`
+	Tags      []string `json:"tags,omitempty" jsonschema:"description=Tags to apply (optional)"`
+	ShowCredentialsArgs
+}
+
+type DeleteDatabaseReplicaArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the primary cluster"`
+	Name      string `json:"name" jsonschema:"description=Name of the replica to delete"`
+	ConfirmArgs
+}
+
+type UpdateDatabaseMaintenanceWindowArgs struct {
+	ClusterID string `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Day       string `json:"day" jsonschema:"description=Day of the week (e.g., 'sunday')"`
+	Hour      string `json:"hour" jsonschema:"description=Start time in UTC, 24-hour HH:MM (e.g., '03:00')"`
+}
+
+type UpdateDatabaseFirewallRulesArgs struct {
+	ClusterID string                             `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Rules     []*godo.DatabaseCreateFirewallRule `json:"rules" jsonschema:"description=Complete list of trusted sources as {type, value}, type ip_addr, droplet, k8s, tag or app; replaces the current rules and an empty list allows all sources"`
+}
+
+type UpdateDatabaseConfigArgs struct {
+	ClusterID string                 `json:"cluster_id" jsonschema:"description=ID of the database cluster"`
+	Config    map[string]interface{} `json:"config" jsonschema:"description=Engine settings to change, keyed as returned by get_database_config; other settings are kept"`
+}
+
+// App Platform-related args
+type GetAppArgs struct {
+	AppID string `json:"app_id" jsonschema:"description=ID of the app"`
+}
+
+type CreateAppArgs struct {
+	Spec      string `json:"spec" jsonschema:"description=App spec as YAML or JSON, in the format of .do/app.yaml"`
+	ProjectID string `json:"project_id,omitempty" jsonschema:"description=ID of the project to put the new resource in; defaults to the default project (optional)"`
+}
+
+type UpdateAppArgs struct {
+	AppID                   string `json:"app_id" jsonschema:"description=ID of the app"`
+	Spec                    string `json:"spec" jsonschema:"description=Complete new app spec as YAML or JSON; it replaces the current spec"`
+	UpdateAllSourceVersions bool   `json:"update_all_source_versions,omitempty" jsonschema:"description=Also fetch the latest commit or image of unchanged components (optional)"`
+}
+
+type DeleteAppArgs struct {
+	AppID string `json:"app_id" jsonschema:"description=ID of the app to delete"`
+	ConfirmArgs
+}
+
+type ListAppDeploymentsArgs struct {
+	AppID string `json:"app_id" jsonschema:"description=ID of the app"`
+	PaginationArgs
+}
+
+type AppDeploymentArgs struct {
+	AppID        string `json:"app_id" jsonschema:"description=ID of the app"`
+	DeploymentID string `json:"deployment_id" jsonschema:"description=ID of the deployment"`
+}
+
+type CreateAppDeploymentArgs struct {
+	AppID      string `json:"app_id" jsonschema:"description=ID of the app"`
+	ForceBuild bool   `json:"force_build,omitempty" jsonschema:"description=Rebuild every component even if its source is unchanged (optional)"`
+}
+
+type GetAppLogsArgs struct {
+	AppID        string `json:"app_id" jsonschema:"description=ID of the app"`
+	LogType      string `json:"log_type" jsonschema:"description=Kind of logs: build, deploy, run or run_restarted"`
+	DeploymentID string `json:"deployment_id,omitempty" jsonschema:"description=Deployment to get logs of; defaults to the latest deployment for build logs and the active one otherwise (optional)"`
+	Component    string `json:"component,omitempty" jsonschema:"description=Only logs of this component (optional)"`
+	Live         bool   `json:"live,omitempty" jsonschema:"description=Return a URL that streams new log lines instead of the URLs of stored logs (optional)"`
+	TailLines    int    `json:"tail_lines,omitempty" jsonschema:"description=Number of lines from the end to include; defaults to all (optional)"`
+}
+
+// SSH key-related args
+type SSHKeyArgs struct {
+	Key string `json:"key" jsonschema:"description=Numeric ID or MD5 fingerprint of the SSH key"`
+}
+
+type GetSSHKeyFingerprintArgs struct {
+	PublicKey string `json:"public_key" jsonschema:"description=Public key in authorized_keys format (e.g., 'ssh-ed25519 AAAA... user@host')"`
+}
+
+type CreateSSHKeyArgs struct {
+	Name      string `json:"name" jsonschema:"description=Name of the SSH key"`
+	PublicKey string `json:"public_key" jsonschema:"description=Public key in authorized_keys format (e.g., 'ssh-ed25519 AAAA... user@host')"`
+}
+
+type UpdateSSHKeyArgs struct {
+	Key  string `json:"key" jsonschema:"description=Numeric ID or MD5 fingerprint of the SSH key"`
+	Name string `json:"name" jsonschema:"description=New name of the SSH key"`
+}
+
+type DeleteSSHKeyArgs struct {
+	Key string `json:"key" jsonschema:"description=Numeric ID or MD5 fingerprint of the SSH key to delete"`
+	ConfirmArgs
+}
+
+// Account and billing-related args
+type InvoiceArgs struct {
+	InvoiceUUID string `json:"invoice_uuid" jsonschema:"description=UUID of the invoice"`
+}
+
+type GetInvoiceArgs struct {
+	InvoiceUUID string `json:"invoice_uuid" jsonschema:"description=UUID of the invoice"`
+	PaginationArgs
+}
+
+// Region and size-related args
+type ListRegionsArgs struct {
+	Available bool   `json:"available,omitempty" jsonschema:"description=Only list regions that accept new resources (optional)"`
+	Feature   string `json:"feature,omitempty" jsonschema:"description=Only list regions offering a feature (e.g., 'storage', 'backups', 'ipv6') (optional)"`
+}
+
+type ListSizesArgs struct {
+	Available       bool    `json:"available,omitempty" jsonschema:"description=Only list sizes that can be created (optional)"`
+	Region          string  `json:"region,omitempty" jsonschema:"description=Only list sizes offered in this region (optional)"`
+	MinVCPUs        int     `json:"min_vcpus,omitempty" jsonschema:"description=Minimum number of vCPUs (optional)"`
+	MinMemoryMB     int     `json:"min_memory_mb,omitempty" jsonschema:"description=Minimum memory in MB (e.g., 2048 for 2 GB) (optional)"`
+	MaxPriceMonthly float64 `json:"max_price_monthly,omitempty" jsonschema:"description=Maximum monthly price in USD (optional)"`
+}
+
+// Project-related args
+type GetProjectArgs struct {
+	ProjectID string `json:"project_id" jsonschema:"description=ID of the project, or 'default' for the default project"`
+}
+
+type CreateProjectArgs struct {
+	Name        string `json:"name" jsonschema:"description=Name of the project"`
+	Purpose     string `json:"purpose" jsonschema:"description=What the project is for (e.g., 'Web Application', 'Service or API')"`
+	Description string `json:"description,omitempty" jsonschema:"description=Description of the project (optional)"`
+	Environment string `json:"environment,omitempty" jsonschema:"description=Environment: Development, Staging or Production (optional)"`
+}
+
+type UpdateProjectArgs struct {
+	ProjectID   string  `json:"project_id" jsonschema:"description=ID of the project to update"`
+	Name        *string `json:"name,omitempty" jsonschema:"description=New name (optional)"`
+	Description *string `json:"description,omitempty" jsonschema:"description=New description; an empty string clears it (optional)"`
+	Purpose     *string `json:"purpose,omitempty" jsonschema:"description=New purpose (optional)"`
+	Environment *string `json:"environment,omitempty" jsonschema:"description=New environment: Development, Staging or Production (optional)"`
+}
+
+type DeleteProjectArgs struct {
+	ProjectID string `json:"project_id" jsonschema:"description=ID of the project to delete; it must be empty and not the default"`
+	ConfirmArgs
+}
+
+type ListProjectResourcesArgs struct {
+	ProjectID string `json:"project_id" jsonschema:"description=ID of the project, or 'default' for the default project"`
+	PaginationArgs
+}
+
+type AssignProjectResourcesArgs struct {
+	ProjectID string   `json:"project_id" jsonschema:"description=ID of the project to move the resources to"`
+	URNs      []string `json:"resources" jsonschema:"description=Resource URNs as do:<type>:<id> (e.g., 'do:droplet:123', 'do:volume:<uuid>', 'do:domain:example.com')"`
+}
+
+// Monitoring-related args
+type GetDropletMetricsArgs struct {
+	DropletID int    `json:"droplet_id" jsonschema:"description=ID of the droplet"`
+	Metric    string `json:"metric" jsonschema:"description=Metric: cpu, memory or disk (percent used), load_1, load_5, load_15 or bandwidth (Mbps)"`
+	Window    string `json:"window,omitempty" jsonschema:"description=How far back to look, as a duration (e.g., '30m', '6h', '7d'); defaults to 1h (optional)"`
+	End       string `json:"end,omitempty" jsonschema:"description=End of the window in RFC 3339 (e.g., '2024-05-01T12:00:00Z'); defaults to now (optional)"`
+	Step      string `json:"step,omitempty" jsonschema:"description=Average the points into buckets of this length (e.g., '5m'); defaults to the API's resolution; not allowed with summary (optional)"`
+	Summary   bool   `json:"summary,omitempty" jsonschema:"description=Return min, avg, max, p95 and the last value per series instead of the points (optional)"`
+	Interface string `json:"interface,omitempty" jsonschema:"description=For bandwidth: public or private; defaults to public (optional)"`
+	Direction string `json:"direction,omitempty" jsonschema:"description=For bandwidth: inbound or outbound; defaults to outbound (optional)"`
+}
+
+type AlertPolicyArgs struct {
+	PolicyID string `json:"policy_id" jsonschema:"description=UUID of the alert policy"`
+}
+
+type CreateAlertPolicyArgs struct {
+	Type        string              `json:"type" jsonschema:"description=Metric to watch (e.g., 'v1/insights/droplet/cpu', 'v1/insights/droplet/memory_utilization_percent', 'v1/insights/droplet/load_5')"`
+	Description string              `json:"description" jsonschema:"description=Description shown in notifications"`
+	Compare     string              `json:"compare" jsonschema:"description=GreaterThan or LessThan"`
+	Value       float32             `json:"value" jsonschema:"description=Threshold to compare the metric against"`
+	Window      string              `json:"window" jsonschema:"description=How long the threshold must be crossed: 5m, 10m, 30m or 1h"`
+	Entities    []string            `json:"entities,omitempty" jsonschema:"description=IDs of the droplets (or load balancers or database clusters) to watch (optional)"`
+	Tags        []string            `json:"tags,omitempty" jsonschema:"description=Watch droplets carrying these tags (optional)"`
+	Emails      []string            `json:"emails,omitempty" jsonschema:"description=Email addresses to notify; at least one email or Slack destination is required"`
+	Slack       []godo.SlackDetails `json:"slack,omitempty" jsonschema:"description=Slack destinations as {url, channel} (optional)"`
+	Enabled     *bool               `json:"enabled,omitempty" jsonschema:"description=Whether the policy sends alerts; defaults to true (optional)"`
+}
+
+type UpdateAlertPolicyArgs struct {
+	PolicyID    string              `json:"policy_id" jsonschema:"description=UUID of the alert policy to update"`
+	Type        *string             `json:"type,omitempty" jsonschema:"description=New metric to watch (optional)"`
+	Description *string             `json:"description,omitempty" jsonschema:"description=New description (optional)"`
+	Compare     *string             `json:"compare,omitempty" jsonschema:"description=New comparison: GreaterThan or LessThan (optional)"`
+	Value       *float32            `json:"value,omitempty" jsonschema:"description=New threshold (optional)"`
+	Window      *string             `json:"window,omitempty" jsonschema:"description=New window: 5m, 10m, 30m or 1h (optional)"`
+	Entities    []string            `json:"entities,omitempty" jsonschema:"description=Replace the watched resource IDs; an empty list clears them (optional)"`
+	Tags        []string            `json:"tags,omitempty" jsonschema:"description=Replace the watched tags; an empty list clears them (optional)"`
+	Emails      []string            `json:"emails,omitempty" jsonschema:"description=Replace the email addresses to notify (optional)"`
+	Slack       []godo.SlackDetails `json:"slack,omitempty" jsonschema:"description=Replace the Slack destinations (optional)"`
+	Enabled     *bool               `json:"enabled,omitempty" jsonschema:"description=Enable or disable the policy (optional)"`
+}
+
+type DeleteAlertPolicyArgs struct {
+	PolicyID string `json:"policy_id" jsonschema:"description=UUID of the alert policy to delete"`
+	ConfirmArgs
+}
+
+// Spaces-related args
+type SpacesBucketArgs struct {
+	Bucket string `json:"bucket" jsonschema:"description=Name of the bucket"`
+}
+
+type CreateSpacesBucketArgs struct {
+	Name string `json:"name" jsonschema:"description=Bucket name: 3-63 lowercase letters, numbers and hyphens, unique across Spaces"`
+	ACL  string `json:"acl,omitempty" jsonschema:"description=private or public-read; defaults to private (optional)"`
+}
+
+type DeleteSpacesBucketArgs struct {
+	Bucket string `json:"bucket" jsonschema:"description=Name of the bucket to delete; it must be empty"`
+	ConfirmArgs
+}
+
+type ListSpacesObjectsArgs struct {
+	Bucket    string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Prefix    string `json:"prefix,omitempty" jsonschema:"description=Only list keys starting with this prefix (e.g., 'images/') (optional)"`
+	Delimiter string `json:"delimiter,omitempty" jsonschema:"description=Roll keys up to the next delimiter into common_prefixes, like directories; usually '/' (optional)"`
+	Cursor    string `json:"cursor,omitempty" jsonschema:"description=next_cursor from the previous page (optional)"`
+	MaxKeys   int    `json:"max_keys,omitempty" jsonschema:"description=Objects per page, at most 1000; defaults to 1000 (optional)"`
+}
+
+type SpacesObjectArgs struct {
+	Bucket string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Key    string `json:"key" jsonschema:"description=Key of the object (e.g., 'images/logo.png')"`
+}
+
+type GetSpacesObjectArgs struct {
+	Bucket   string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Key      string `json:"key" jsonschema:"description=Key of the object"`
+	MaxBytes int64  `json:"max_bytes,omitempty" jsonschema:"description=Download at most this many bytes; defaults to 1048576, at most 10485760 (optional)"`
+}
+
+type PutSpacesObjectArgs struct {
+	Bucket      string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Key         string `json:"key" jsonschema:"description=Key to store the object under; an existing object is replaced"`
+	Content     string `json:"content" jsonschema:"description=Content of the object"`
+	Encoding    string `json:"encoding,omitempty" jsonschema:"description=text or base64 (for binary content); defaults to text (optional)"`
+	ContentType string `json:"content_type,omitempty" jsonschema:"description=MIME type; defaults from the key's extension (optional)"`
+	ACL         string `json:"acl,omitempty" jsonschema:"description=private or public-read; defaults to private (optional)"`
+}
+
+type DeleteSpacesObjectArgs struct {
+	Bucket string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Key    string `json:"key" jsonschema:"description=Key of the object to delete"`
+	ConfirmArgs
+}
+
+type PresignSpacesURLArgs struct {
+	Bucket           string `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Key              string `json:"key" jsonschema:"description=Key of the object"`
+	Method           string `json:"method,omitempty" jsonschema:"description=GET to download or PUT to upload; defaults to GET (optional)"`
+	ExpiresInSeconds int    `json:"expires_in_seconds,omitempty" jsonschema:"description=How long the URL works; defaults to 3600, at most 604800 (7 days) (optional)"`
+}
+
+func (p PresignSpacesURLArgs) Expiry() time.Duration {
+	return time.Duration(p.ExpiresInSeconds) * time.Second
+}
+
+type SetSpacesBucketCORSArgs struct {
+	Bucket string            `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Rules  []client.CORSRule `json:"rules" jsonschema:"description=CORS rules as {allowed_origins, allowed_methods, allowed_headers, expose_headers, max_age_seconds}; an empty list removes CORS"`
+}
+
+type SetSpacesBucketLifecycleArgs struct {
+	Bucket string                 `json:"bucket" jsonschema:"description=Name of the bucket"`
+	Rules  []client.LifecycleRule `json:"rules" jsonschema:"description=Rules as {id, prefix, enabled, expiration_days, abort_incomplete_multipart_upload_days}; an empty list removes the lifecycle configuration"`
+}
+
+type SpacesKeyArgs struct {
+	AccessKey string `json:"access_key" jsonschema:"description=Access key ID"`
+}
+
+type CreateSpacesKeyArgs struct {
+	Name   string        `json:"name" jsonschema:"description=Name of the key"`
+	Grants []*godo.Grant `json:"grants" jsonschema:"description=Grants as {bucket, permission}: read or readwrite on a bucket, or fullaccess without a bucket for all buckets"`
+}
+
+type UpdateSpacesKeyArgs struct {
+	AccessKey string        `json:"access_key" jsonschema:"description=Access key ID of the key to update"`
+	Name      *string       `json:"name,omitempty" jsonschema:"description=New name (optional)"`
+	Grants    []*godo.Grant `json:"grants,omitempty" jsonschema:"description=Replace the grants (optional)"`
+}
+
+type DeleteSpacesKeyArgs struct {
+	AccessKey string `json:"access_key" jsonschema:"description=Access key ID of the key to delete"`
+	ConfirmArgs
+}
+
+// CDN-related args
+type CDNEndpointArgs struct {
+	EndpointID string `json:"endpoint_id" jsonschema:"description=ID of the CDN endpoint"`
+}
+
+type CreateCDNEndpointArgs struct {
+	Origin       string `json:"origin" jsonschema:"description=Spaces bucket endpoint to serve (e.g., 'assets.nyc3.digitaloceanspaces.com')"`
+	TTL          uint32 `json:"ttl,omitempty" jsonschema:"description=Cache lifetime in seconds: 60, 600, 3600, 86400 or 604800; defaults to 3600 (optional)"`
+	CustomDomain string `json:"custom_domain,omitempty" jsonschema:"description=Domain to serve the endpoint on (e.g., 'static.example.com'); needs certificate (optional)"`
+	Certificate  string `json:"certificate,omitempty" jsonschema:"description=ID or name of a certificate covering custom_domain (optional)"`
+}
+
+type UpdateCDNEndpointArgs struct {
+	EndpointID   string  `json:"endpoint_id" jsonschema:"description=ID of the CDN endpoint"`
+	TTL          *uint32 `json:"ttl,omitempty" jsonschema:"description=New cache lifetime in seconds: 60, 600, 3600, 86400 or 604800 (optional)"`
+	CustomDomain *string `json:"custom_domain,omitempty" jsonschema:"description=New custom domain; empty removes it (optional)"`
+	Certificate  *string `json:"certificate,omitempty" jsonschema:"description=ID or name of a certificate covering the custom domain (optional)"`
+}
+
+type DeleteCDNEndpointArgs struct {
+	EndpointID string `json:"endpoint_id" jsonschema:"description=ID of the CDN endpoint to delete"`
+	ConfirmArgs
+}
+
+type PurgeCDNCacheArgs struct {
+	EndpointID string   `json:"endpoint_id" jsonschema:"description=ID of the CDN endpoint"`
+	Files      []string `json:"files" jsonschema:"description=Paths to purge (e.g., 'index.html', 'assets/*'); '*' purges everything"`
+}
+
+// Certificate-related args
+type ListCertificatesArgs struct {
+	Name string `json:"name,omitempty" jsonschema:"description=Only list certificates with this name (optional)"`
+	PaginationArgs
+}
+
+type CertificateArgs struct {
+	CertificateID string `json:"certificate_id" jsonschema:"description=ID of the certificate"`
+}
+
+type CreateCertificateArgs struct {
+	Name             string   `json:"name" jsonschema:"description=Name of the certificate"`
+	DNSNames         []string `json:"dns_names,omitempty" jsonschema:"description=Domains for a Let's Encrypt certificate; each must be managed by DigitalOcean DNS and may be a wildcard like '*.example.com' (optional)"`
+	LeafCertificate  string   `json:"leaf_certificate,omitempty" jsonschema:"description=PEM-encoded certificate to upload as a custom certificate (optional)"`
+	PrivateKey       string   `json:"private_key,omitempty" jsonschema:"description=PEM-encoded private key for leaf_certificate (optional)"`
+	CertificateChain string   `json:"certificate_chain,omitempty" jsonschema:"description=PEM-encoded intermediate certificates for leaf_certificate (optional)"`
+}
+
+type DeleteCertificateArgs struct {
+	CertificateID string `json:"certificate_id" jsonschema:"description=ID of the certificate to delete"`
+	ConfirmArgs
+}
+
+// Uptime-related args
+type UptimeCheckArgs struct {
+	CheckID string `json:"check_id" jsonschema:"description=ID of the uptime check"`
+}
+
+type CreateUptimeCheckArgs struct {
+	Name    string   `json:"name" jsonschema:"description=Name of the check"`
+	Type    string   `json:"type" jsonschema:"description=Check type: http, https or ping"`
+	Target  string   `json:"target" jsonschema:"description=URL to request for http and https checks (e.g., 'https://example.com/health'), or host name or IP address for ping"`
+	Regions []string `json:"regions,omitempty" jsonschema:"description=Regions to probe from: us_east, us_west, eu_west, se_asia; defaults to all so regional and global outages can be told apart (optional)"`
+	Enabled *bool    `json:"enabled,omitempty" jsonschema:"description=Whether the check runs; defaults to true (optional)"`
+}
+
+type UpdateUptimeCheckArgs struct {
+	CheckID string   `json:"check_id" jsonschema:"description=ID of the uptime check to update"`
+	Name    *string  `json:"name,omitempty" jsonschema:"description=New name (optional)"`
+	Type    *string  `json:"type,omitempty" jsonschema:"description=New check type: http, https or ping (optional)"`
+	Target  *string  `json:"target,omitempty" jsonschema:"description=New URL or host (optional)"`
+	Regions []string `json:"regions,omitempty" jsonschema:"description=Replace the regions to probe from (optional)"`
+	Enabled *bool    `json:"enabled,omitempty" jsonschema:"description=Enable or disable the check (optional)"`
+}
+
+type DeleteUptimeCheckArgs struct {
+	CheckID string `json:"check_id" jsonschema:"description=ID of the uptime check to delete"`
+	ConfirmArgs
+}
+
+type ListUptimeAlertsArgs struct {
+	CheckID string `json:"check_id" jsonschema:"description=ID of the uptime check"`
+	PaginationArgs
+}
+
+type UptimeAlertArgs struct {
+	CheckID string `json:"check_id" jsonschema:"description=ID of the uptime check"`
+	AlertID string `json:"alert_id" jsonschema:"description=ID of the alert"`
+}
+
+type CreateUptimeAlertArgs struct {
+	CheckID    string              `json:"check_id" jsonschema:"description=ID of the uptime check to alert on"`
+	Name       string              `json:"name" jsonschema:"description=Name of the alert"`
+	Type       string              `json:"type" jsonschema:"description=Alert on: latency, down (in any region), down_global (in every region) or ssl_expiry"`
+	Threshold  int                 `json:"threshold,omitempty" jsonschema:"description=Milliseconds for latency, days left for ssl_expiry; required for those types (optional)"`
+	Comparison string              `json:"comparison,omitempty" jsonschema:"description=greater_than or less_than; defaults to less_than for ssl_expiry and greater_than otherwise (optional)"`
+	Period     string              `json:"period,omitempty" jsonschema:"description=How long the condition must hold: 2m, 3m, 5m, 10m, 15m, 30m or 1h; defaults to 2m (optional)"`
+	Emails     []string            `json:"emails,omitempty" jsonschema:"description=Email addresses to notify; at least one email or Slack destination is required"`
+	Slack      []godo.SlackDetails `json:"slack,omitempty" jsonschema:"description=Slack destinations as {url, channel} (optional)"`
+}
+
+type UpdateUptimeAlertArgs struct {
+	CheckID    string              `json:"check_id" jsonschema:"description=ID of the uptime check"`
+	AlertID    string              `json:"alert_id" jsonschema:"description=ID of the alert to update"`
+	Name       *string             `json:"name,omitempty" jsonschema:"description=New name (optional)"`
+	Type       *string             `json:"type,omitempty" jsonschema:"description=New type: latency, down, down_global or ssl_expiry (optional)"`
+	Threshold  *int                `json:"threshold,omitempty" jsonschema:"description=New threshold (optional)"`
+	Comparison *string             `json:"comparison,omitempty" jsonschema:"description=New comparison: greater_than or less_than (optional)"`
+	Period     *string             `json:"period,omitempty" jsonschema:"description=New period: 2m, 3m, 5m, 10m, 15m, 30m or 1h (optional)"`
+	Emails     []string            `json:"emails,omitempty" jsonschema:"description=Replace the email addresses to notify (optional)"`
+	Slack      []godo.SlackDetails `json:"slack,omitempty" jsonschema:"description=Replace the Slack destinations (optional)"`
+}
+
+type DeleteUptimeAlertArgs struct {
+	CheckID string `json:"check_id" jsonschema:"description=ID of the uptime check"`
+	AlertID string `json:"alert_id" jsonschema:"description=ID of the alert to delete"`
+	ConfirmArgs
+}
+
+// Functions-related args
+type FunctionsNamespaceArgs struct {
+	Namespace       string `json:"namespace" jsonschema:"description=ID (e.g., 'fn-...') or label of the namespace"`
+	ShowCredentials bool   `json:"show_credentials,omitempty" jsonschema:"description=Return the namespace's API key unmasked; leave unset unless the key is needed (optional)"`
+}
+
+type CreateFunctionsNamespaceArgs struct {
+	Label           string `json:"label" jsonschema:"description=Label of the namespace"`
+	Region          string `json:"region" jsonschema:"description=Region: ams3, blr1, fra1, lon1, nyc1, sfo3, sgp1, syd1 or tor1"`
+	ShowCredentials bool   `json:"show_credentials,omitempty" jsonschema:"description=Return the namespace's API key unmasked (optional)"`
+}
+
+type DeleteFunctionsNamespaceArgs struct {
+	Namespace string `json:"namespace" jsonschema:"description=ID or label of the namespace to delete"`
+	ConfirmArgs
+}
+
+type ListFunctionsTriggersArgs struct {
+	Namespace string `json:"namespace" jsonschema:"description=ID or label of the namespace"`
+}
+
+type FunctionsTriggerArgs struct {
+	Namespace string `json:"namespace" jsonschema:"description=ID or label of the namespace"`
+	Trigger   string `json:"trigger" jsonschema:"description=Name of the trigger"`
+}
+
+type CreateFunctionsTriggerArgs struct {
+	Namespace string                 `json:"namespace" jsonschema:"description=ID or label of the namespace"`
+	Name      string                 `json:"name" jsonschema:"description=Name of the trigger"`
+	Function  string                 `json:"function" jsonschema:"description=Function to run, as 'package/function' or 'function'"`
+	Cron      string                 `json:"cron" jsonschema:"description=Five-field cron expression in UTC (e.g., '0 9 * * mon-fri')"`
+	Body      map[string]interface{} `json:"body,omitempty" jsonschema:"description=Parameters passed to the function on each run (optional)"`
+	Enabled   *bool                  `json:"enabled,omitempty" jsonschema:"description=Whether the trigger fires; defaults to true (optional)"`
+}
+
+type UpdateFunctionsTriggerArgs struct {
+	Namespace string                 `json:"namespace" jsonschema:"description=ID or label of the namespace"`
+	Trigger   string                 `json:"trigger" jsonschema:"description=Name of the trigger to update"`
+	Enabled   *bool                  `json:"enabled,omitempty" jsonschema:"description=Enable or disable the trigger (optional)"`
+	Cron      *string                `json:"cron,omitempty" jsonschema:"description=New five-field cron expression in UTC (optional)"`
+	Body      map[string]interface{} `json:"body,omitempty" jsonschema:"description=Replace the parameters passed to the function (optional)"`
+}
+
+type DeleteFunctionsTriggerArgs struct {
+	Namespace string `json:"namespace" jsonschema:"description=ID or label of the namespace"`
+	Trigger   string `json:"trigger" jsonschema:"description=Name of the trigger to delete"`
+	ConfirmArgs
+}
+
+type GetFunctionsScheduleArgs struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"description=ID or label of a namespace; defaults to every namespace (optional)"`
+}